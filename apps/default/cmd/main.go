@@ -108,10 +108,21 @@ func main() {
 	templateRepo := repository.NewTemplateRepository(ctx, dbPool, workMan)
 	templateDataRepo := repository.NewTemplateDataRepository(ctx, dbPool, workMan)
 	routeRepo := repository.NewRouteRepository(ctx, dbPool, workMan)
+	aggregateRepo := repository.NewNotificationAggregateRepository(ctx, dbPool, workMan)
+	idempotencyKeyRepo := repository.NewIdempotencyKeyRepository(ctx, dbPool, workMan)
+	rateLimitCounterRepo := repository.NewRateLimitCounterRepository(ctx, dbPool, workMan)
+	suppressionRepo := repository.NewSuppressionRepository(ctx, dbPool, workMan)
+	broadcastRepo := repository.NewBroadcastRepository(ctx, dbPool, workMan)
+	externalIDRepo := repository.NewNotificationExternalIDRepository(ctx, dbPool, workMan)
 
 	// Create business logic with all dependencies
 	notificationBusiness := business.NewNotificationBusiness(ctx, workMan, evtsMan, profileCli, tenancyCli,
-		notificationRepo, notificationStatusRepo, languageRepo, templateRepo, templateDataRepo, routeRepo)
+		notificationRepo, notificationStatusRepo, languageRepo, templateRepo, templateDataRepo, routeRepo, aggregateRepo,
+		idempotencyKeyRepo, rateLimitCounterRepo, suppressionRepo, broadcastRepo, externalIDRepo,
+		cfg.DefaultLanguageCode, cfg.IdempotencyKeyRetention)
+
+	releaseScheduler := business.NewReleaseScheduler(cfg.Name(), cfg.ScheduledReleaseInterval,
+		cfg.ScheduledReleaseBatchSize, evtsMan, notificationRepo, notificationStatusRepo, idempotencyKeyRepo, rateLimitCounterRepo)
 
 	// Setup Connect server
 	connectHandler := setupConnectServer(ctx, sm, workMan, notificationBusiness)
@@ -119,14 +130,17 @@ func main() {
 	// Runtime only — permission manifests publish on the setup Job path above.
 	serviceOptions := []frame.Option{
 		frame.WithHTTPHandler(connectHandler),
+		frame.WithSystemPrincipalAllowGlobal(cfg.Name()),
+		frame.WithBackgroundConsumer(releaseScheduler.Run),
 		frame.WithRegisterEvents(
 			events2.NewNotificationSave(ctx, evtsMan, notificationRepo),
-			events2.NewNotificationStatusSave(ctx, notificationRepo, notificationStatusRepo),
-			events2.NewNotificationInRoute(ctx, qMan, evtsMan, notificationRepo, routeRepo),
+			events2.NewNotificationStatusSave(ctx, evtsMan, notificationRepo, notificationStatusRepo, routeRepo, aggregateRepo, cfg.MaxRouteAttempts),
+			events2.NewNotificationInRoute(ctx, qMan, evtsMan, tenancyCli, notificationRepo, routeRepo, templateRepo, suppressionRepo),
 			events2.NewNotificationInQueue(ctx, qMan, evtsMan, notificationRepo, routeRepo, profileCli),
-			events2.NewNotificationOutRoute(ctx, evtsMan, profileCli, notificationRepo, routeRepo),
+			events2.NewNotificationOutRoute(ctx, evtsMan, profileCli, tenancyCli, notificationRepo, notificationStatusRepo, routeRepo, suppressionRepo),
 			events2.NewNotificationOutQueue(ctx, qMan, evtsMan, profileCli, tenancyCli,
-				notificationRepo, notificationStatusRepo, languageRepo, templateDataRepo, routeRepo)),
+				notificationRepo, notificationStatusRepo, languageRepo, templateRepo, templateDataRepo, routeRepo, cfg.DefaultLanguageCode),
+			business.NewBroadcastExpand(ctx, notificationBusiness)),
 	}
 
 	svc.Init(ctx, serviceOptions...)
@@ -149,15 +163,19 @@ func setupProfileClient(
 	}, profilev1connect.NewProfileServiceClient)
 }
 
-// setupTenancyClient creates and configures the partition client.
+// setupTenancyClient creates and configures the partition client, caching partition lookups.
 func setupTenancyClient(
 	ctx context.Context,
 	cfg aconfig.NotificationConfig) (tenancyv1connect.TenancyServiceClient, error) {
-	return connection.NewServiceClient(ctx, &cfg, apis.ServiceTarget{
+	tenancyCli, err := connection.NewServiceClient(ctx, &cfg, apis.ServiceTarget{
 		Endpoint:              cfg.TenancyServiceURI,
 		WorkloadAPITargetPath: cfg.TenancyServiceWorkloadAPITargetPath,
 		ServiceID:             servicecatalog.ServiceTenancy,
 	}, tenancyv1connect.NewTenancyServiceClient)
+	if err != nil {
+		return nil, err
+	}
+	return newPartitionCachingClient(tenancyCli, cfg.PartitionCacheTTL, cfg.PartitionCacheSize), nil
 }
 
 // setupConnectServer initialises and configures the Connect RPC server.
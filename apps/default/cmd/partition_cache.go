@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"buf.build/gen/go/antinvestor/tenancy/connectrpc/go/tenancy/v1/tenancyv1connect"
+	tenancyv1 "buf.build/gen/go/antinvestor/tenancy/protocolbuffers/go/tenancy/v1"
+	"connectrpc.com/connect"
+	"github.com/pitabwire/frame/v2/tenancy"
+	"google.golang.org/protobuf/proto"
+)
+
+type cachedPartition struct {
+	msg       *tenancyv1.GetPartitionResponse
+	fetchedAt time.Time
+}
+
+// partitionCachingClient answers partition lookups from a short lived cache. Partition
+// properties carry the delivery windows, rate limits, keyword rules and languages read for
+// every notification, while the partitions themselves rarely change. Entries are kept per
+// tenancy scope of the caller, a partition one caller may read is never answered to another,
+// and every caller gets its own copy of the partition.
+type partitionCachingClient struct {
+	tenancyv1connect.TenancyServiceClient
+	ttl        time.Duration
+	maxEntries int
+
+	mu         sync.Mutex
+	partitions map[string]*cachedPartition
+}
+
+// newPartitionCachingClient wraps the tenancy client so partitions are fetched at most once
+// per ttl and no more than maxEntries are held, a ttl or size of zero disables caching.
+func newPartitionCachingClient(tenancyCli tenancyv1connect.TenancyServiceClient, ttl time.Duration, maxEntries int) tenancyv1connect.TenancyServiceClient {
+	if tenancyCli == nil || ttl <= 0 || maxEntries <= 0 {
+		return tenancyCli
+	}
+	return &partitionCachingClient{
+		TenancyServiceClient: tenancyCli,
+		ttl:                  ttl,
+		maxEntries:           maxEntries,
+		partitions:           map[string]*cachedPartition{},
+	}
+}
+
+func (pc *partitionCachingClient) GetPartition(ctx context.Context, req *connect.Request[tenancyv1.GetPartitionRequest]) (*connect.Response[tenancyv1.GetPartitionResponse], error) {
+	key := tenancyScope(ctx) + "|" + req.Msg.GetId()
+
+	pc.mu.Lock()
+	entry, ok := pc.partitions[key]
+	pc.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < pc.ttl {
+		return connect.NewResponse(proto.CloneOf(entry.msg)), nil
+	}
+
+	resp, err := pc.TenancyServiceClient.GetPartition(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	pc.store(key, &cachedPartition{msg: proto.CloneOf(resp.Msg), fetchedAt: time.Now()})
+	return resp, nil
+}
+
+// tenancyScope describes the tenancy a lookup is made under, the system principal or the
+// tenant, partitions and access of the caller's claims.
+func tenancyScope(ctx context.Context) string {
+	var scope []string
+	if principal, ok := tenancy.SystemPrincipalFromContext(ctx); ok {
+		scope = append(scope, fmt.Sprintf("system:%s:%s:%t",
+			principal.TenantID, strings.Join(principal.PartitionIDs, ","), principal.AllowGlobal))
+	}
+	if claims := tenancy.ClaimsFromContext(ctx); claims != nil {
+		scope = append(scope, fmt.Sprintf("claims:%s:%s:%s:%t",
+			claims.TenantID, strings.Join(claims.PartitionIDs, ","), claims.AccessID, claims.Skip))
+	}
+	return strings.Join(scope, "|")
+}
+
+// store caches a partition, first dropping expired entries and then the oldest ones when the
+// cache is full.
+func (pc *partitionCachingClient) store(key string, entry *cachedPartition) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if _, ok := pc.partitions[key]; !ok && len(pc.partitions) >= pc.maxEntries {
+		for id, cached := range pc.partitions {
+			if time.Since(cached.fetchedAt) >= pc.ttl {
+				delete(pc.partitions, id)
+			}
+		}
+		for len(pc.partitions) >= pc.maxEntries {
+			oldestID := ""
+			for id, cached := range pc.partitions {
+				if oldestID == "" || cached.fetchedAt.Before(pc.partitions[oldestID].fetchedAt) {
+					oldestID = id
+				}
+			}
+			delete(pc.partitions, oldestID)
+		}
+	}
+
+	pc.partitions[key] = entry
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"buf.build/gen/go/antinvestor/tenancy/connectrpc/go/tenancy/v1/tenancyv1connect"
+	tenancyv1 "buf.build/gen/go/antinvestor/tenancy/protocolbuffers/go/tenancy/v1"
+	"connectrpc.com/connect"
+	ftenancy "github.com/pitabwire/frame/v2/tenancy"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// countingTenancy answers partition lookups and counts how often it was asked.
+type countingTenancy struct {
+	tenancyv1connect.TenancyServiceClient
+	calls int
+	err   error
+}
+
+func (ct *countingTenancy) GetPartition(_ context.Context, req *connect.Request[tenancyv1.GetPartitionRequest]) (*connect.Response[tenancyv1.GetPartitionResponse], error) {
+	ct.calls++
+	if ct.err != nil {
+		return nil, ct.err
+	}
+	return connect.NewResponse(&tenancyv1.GetPartitionResponse{Data: &tenancyv1.PartitionObject{Id: req.Msg.GetId()}}), nil
+}
+
+func TestPartitionCachingClient(t *testing.T) {
+	ctx := context.Background()
+	tenancy := &countingTenancy{}
+	cli := newPartitionCachingClient(tenancy, time.Minute, 10)
+
+	first, err := cli.GetPartition(ctx, connect.NewRequest(&tenancyv1.GetPartitionRequest{Id: "partition-1"}))
+	require.NoError(t, err)
+	for range 2 {
+		resp, err0 := cli.GetPartition(ctx, connect.NewRequest(&tenancyv1.GetPartitionRequest{Id: "partition-1"}))
+		require.NoError(t, err0)
+		require.True(t, proto.Equal(first.Msg, resp.Msg))
+		require.NotSame(t, first.Msg, resp.Msg, "every caller gets its own copy")
+	}
+	require.Equal(t, 1, tenancy.calls, "repeated lookups are answered from the cache")
+
+	first.Msg.Data.Id = "changed-by-caller"
+	resp, err := cli.GetPartition(ctx, connect.NewRequest(&tenancyv1.GetPartitionRequest{Id: "partition-1"}))
+	require.NoError(t, err)
+	require.Equal(t, "partition-1", resp.Msg.GetData().GetId(), "changing a copy leaves the cache intact")
+
+	tenantCtx := ftenancy.WithClaims(ctx, &ftenancy.Claims{TenantID: "tenant-1", PartitionIDs: []string{"partition-1"}})
+	_, err = cli.GetPartition(tenantCtx, connect.NewRequest(&tenancyv1.GetPartitionRequest{Id: "partition-1"}))
+	require.NoError(t, err)
+	require.Equal(t, 2, tenancy.calls, "callers under another tenancy scope fetch for themselves")
+
+	_, err = cli.GetPartition(ctx, connect.NewRequest(&tenancyv1.GetPartitionRequest{Id: "partition-2"}))
+	require.NoError(t, err)
+	require.Equal(t, 3, tenancy.calls)
+
+	failing := &countingTenancy{err: errors.New("tenancy unavailable")}
+	cli = newPartitionCachingClient(failing, time.Minute, 10)
+	for range 2 {
+		_, err = cli.GetPartition(ctx, connect.NewRequest(&tenancyv1.GetPartitionRequest{Id: "partition-1"}))
+		require.Error(t, err)
+	}
+	require.Equal(t, 2, failing.calls, "failed lookups are not cached")
+
+	require.Same(t, tenancyv1connect.TenancyServiceClient(tenancy), newPartitionCachingClient(tenancy, 0, 10))
+	require.Same(t, tenancyv1connect.TenancyServiceClient(tenancy), newPartitionCachingClient(tenancy, time.Minute, 0))
+}
+
+func TestPartitionCachingClientBounded(t *testing.T) {
+	ctx := context.Background()
+	tenancy := &countingTenancy{}
+	cli := newPartitionCachingClient(tenancy, time.Minute, 2)
+
+	lookup := func(partitionID string) {
+		_, err := cli.GetPartition(ctx, connect.NewRequest(&tenancyv1.GetPartitionRequest{Id: partitionID}))
+		require.NoError(t, err)
+	}
+
+	lookup("partition-1")
+	lookup("partition-2")
+	lookup("partition-3")
+	require.Len(t, cli.(*partitionCachingClient).partitions, 2, "the cache never holds more than its size")
+
+	lookup("partition-3")
+	require.Equal(t, 3, tenancy.calls, "recent partitions stay cached")
+
+	lookup("partition-1")
+	require.Equal(t, 4, tenancy.calls, "the oldest partition was evicted to make room")
+}
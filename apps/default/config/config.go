@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/pitabwire/frame/v2/config"
 )
 
@@ -14,4 +16,14 @@ type NotificationConfig struct {
 	NotificationServiceWorkloadAPITargetPath string `envDefault:"/ns/notifications/sa/service-notification" env:"NOTIFICATION_SERVICE_WORKLOAD_API_TARGET_PATH"`
 
 	DefaultLanguageCode string `envDefault:"en" env:"DEFAULT_LANGUAGE_CODE"`
+
+	PartitionCacheTTL  time.Duration `envDefault:"1m" env:"PARTITION_CACHE_TTL"`
+	PartitionCacheSize int           `envDefault:"1000" env:"PARTITION_CACHE_SIZE"`
+
+	ScheduledReleaseInterval  time.Duration `envDefault:"30s" env:"SCHEDULED_RELEASE_INTERVAL"`
+	ScheduledReleaseBatchSize int           `envDefault:"500" env:"SCHEDULED_RELEASE_BATCH_SIZE"`
+
+	MaxRouteAttempts int `envDefault:"3" env:"MAX_ROUTE_ATTEMPTS"`
+
+	IdempotencyKeyRetention time.Duration `envDefault:"24h" env:"IDEMPOTENCY_KEY_RETENTION"`
 }
@@ -0,0 +1 @@
+CREATE UNIQUE INDEX IF NOT EXISTS uq_idempotency_key ON idempotency_keys (tenant_id, partition_id, key);
@@ -0,0 +1 @@
+CREATE UNIQUE INDEX IF NOT EXISTS uq_notification_parent_step ON notifications (parent_id, parent_step) WHERE parent_step <> '' AND deleted_at IS NULL;
@@ -0,0 +1 @@
+CREATE UNIQUE INDEX IF NOT EXISTS uq_suppression ON suppressions (tenant_id, partition_id, contact, channel, sender_id);
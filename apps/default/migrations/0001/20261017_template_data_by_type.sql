@@ -0,0 +1,2 @@
+UPDATE template_data t SET deleted_at = now() FROM (SELECT id, row_number() OVER (PARTITION BY template_id, language_id, type ORDER BY created_at DESC, id DESC) AS position FROM template_data WHERE deleted_at IS NULL) d WHERE t.id = d.id AND d.position > 1;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_template_by_type ON template_data (template_id, language_id, type) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+UPDATE templates t SET revision = v.revision, state = CASE WHEN v.revision = v.revisions THEN 'published' ELSE 'retired' END, published_at = COALESCE(t.published_at, t.created_at) FROM (SELECT id, row_number() OVER (PARTITION BY tenant_id, partition_id, name ORDER BY created_at, id) AS revision, count(*) OVER (PARTITION BY tenant_id, partition_id, name) AS revisions FROM templates WHERE deleted_at IS NULL) v WHERE t.id = v.id;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_template_revision ON templates (tenant_id, partition_id, name, revision) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_template_published ON templates (tenant_id, partition_id, name) WHERE state = 'published' AND deleted_at IS NULL;
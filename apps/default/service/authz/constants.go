@@ -17,6 +17,8 @@ const (
 	PermissionNotificationStatusUpdate = "notification_status_update"
 	PermissionTemplateManage           = "template_manage"
 	PermissionTemplateView             = "template_view"
+	PermissionSuppressionManage        = "suppression_manage"
+	PermissionSuppressionView          = "suppression_view"
 )
 
 // Granted relation constants for direct permission grants in the OPL.
@@ -31,6 +33,8 @@ const (
 	GrantedNotificationStatusUpdate = "granted_notification_status_update"
 	GrantedTemplateManage           = "granted_template_manage"
 	GrantedTemplateView             = "granted_template_view"
+	GrantedSuppressionManage        = "granted_suppression_manage"
+	GrantedSuppressionView          = "granted_suppression_view"
 )
 
 // Role constants.
@@ -51,20 +55,22 @@ var RolePermissions = map[string][]string{ //nolint:gochecknoglobals // permissi
 		PermissionNotificationSend, PermissionNotificationRelease,
 		PermissionNotificationSearch, PermissionNotificationStatusView,
 		PermissionNotificationStatusUpdate, PermissionTemplateManage, PermissionTemplateView,
+		PermissionSuppressionManage, PermissionSuppressionView,
 	},
 	RoleAdmin: {
 		PermissionNotificationSend, PermissionNotificationRelease,
 		PermissionNotificationSearch, PermissionNotificationStatusView,
 		PermissionNotificationStatusUpdate, PermissionTemplateManage, PermissionTemplateView,
+		PermissionSuppressionManage, PermissionSuppressionView,
 	},
 	RoleOperator: {
 		PermissionNotificationSend, PermissionNotificationRelease,
 		PermissionNotificationSearch, PermissionNotificationStatusView,
-		PermissionTemplateView,
+		PermissionTemplateView, PermissionSuppressionView,
 	},
 	RoleViewer: {
 		PermissionNotificationSearch, PermissionNotificationStatusView,
-		PermissionTemplateView,
+		PermissionTemplateView, PermissionSuppressionView,
 	},
 	RoleMember: {
 		PermissionNotificationSearch, PermissionNotificationStatusView,
@@ -73,5 +79,6 @@ var RolePermissions = map[string][]string{ //nolint:gochecknoglobals // permissi
 		PermissionNotificationSend, PermissionNotificationRelease,
 		PermissionNotificationSearch, PermissionNotificationStatusView,
 		PermissionNotificationStatusUpdate, PermissionTemplateManage, PermissionTemplateView,
+		PermissionSuppressionManage, PermissionSuppressionView,
 	},
 }
@@ -0,0 +1,408 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+	"connectrpc.com/connect"
+	"github.com/antinvestor/service-notification/apps/default/service/events"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/pitabwire/frame/v2/data"
+	"github.com/pitabwire/frame/v2/workerpool"
+	"github.com/pitabwire/util"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// broadcastPageSize is the number of recipients expanded before progress is saved.
+const broadcastPageSize = 200
+
+// broadcastFollowInterval is how often the Broadcast stream reads back the saved expansion progress.
+const broadcastFollowInterval = time.Second
+
+// BroadcastExpandEvent is the event name for expanding a broadcast to its audience
+const BroadcastExpandEvent = "broadcast.expand"
+
+func (nb *notificationBusiness) Broadcast(ctx context.Context, req *notificationv1.BroadcastRequest) (workerpool.JobResultPipe[*notificationv1.BroadcastResponse], error) {
+
+	message := req.GetData()
+	if message == nil {
+		return nil, ErrorEmptyValueSupplied
+	}
+
+	if (len(req.GetProfileIds()) > 0) == req.GetPartitionAudience() {
+		return nil, ErrorInvalidAudience
+	}
+
+	parent, err := nb.createBroadcastParent(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := protojson.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	broadcast := &models.Broadcast{Request: string(request)}
+	broadcast.ID = parent.GetID()
+	broadcast.CopyPartitionInfo(&parent.BaseModel)
+
+	err = nb.broadcastRepo.Create(ctx, broadcast)
+	if err != nil {
+		return nil, err
+	}
+
+	// Expansion runs as an event so it outlives the caller's stream and is redelivered when it
+	// stops early, the stream only follows the progress the expansion saves.
+	err = nb.eventsMan.Emit(ctx, BroadcastExpandEvent, broadcast.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	job := workerpool.NewJob(func(ctx context.Context, resultPipe workerpool.JobResultPipe[*notificationv1.BroadcastResponse]) error {
+		return nb.followBroadcast(ctx, parent, resultPipe)
+	})
+
+	err = workerpool.SubmitJob(ctx, nb.workMan, job)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// followBroadcast streams the saved progress of a broadcast each time it changes until every
+// recipient is queued. The expansion carries on when the caller goes away.
+func (nb *notificationBusiness) followBroadcast(ctx context.Context, parent *models.Notification,
+	resultPipe workerpool.JobResultPipe[*notificationv1.BroadcastResponse]) error {
+
+	ticker := time.NewTicker(broadcastFollowInterval)
+	defer ticker.Stop()
+
+	reported := -1
+	for {
+		broadcast, err := nb.broadcastRepo.GetByID(ctx, parent.GetID())
+		if err != nil {
+			return err
+		}
+
+		if broadcast.IsExpanded() {
+			nStatus := broadcastProgressStatus(parent, broadcast, commonv1.STATUS_SUCCESSFUL, "broadcast_expanded")
+			return resultPipe.WriteResult(ctx, &notificationv1.BroadcastResponse{Data: nStatus.ToAPI()})
+		}
+
+		if broadcast.Pages != reported {
+			reported = broadcast.Pages
+			nStatus := broadcastProgressStatus(parent, broadcast, commonv1.STATUS_IN_PROCESS, "broadcast_expanding")
+			err = resultPipe.WriteResult(ctx, &notificationv1.BroadcastResponse{Data: nStatus.ToAPI()})
+			if err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExpandBroadcast queues a child notification for every recipient of a broadcast, resuming from
+// the progress saved by an earlier attempt. Children carry an idempotency key derived from the
+// broadcast and recipient, so a page queued again after a stop does not send twice.
+func (nb *notificationBusiness) ExpandBroadcast(ctx context.Context, broadcastID string) error {
+	logger := util.Log(ctx).WithField("broadcast_id", broadcastID)
+
+	broadcast, err := nb.broadcastRepo.GetByID(ctx, broadcastID)
+	if err != nil {
+		return err
+	}
+
+	if broadcast.IsExpanded() {
+		logger.Debug("broadcast already expanded")
+		return nil
+	}
+
+	parent, err := nb.notificationRepo.GetByID(ctx, broadcastID)
+	if err != nil {
+		return err
+	}
+
+	req := &notificationv1.BroadcastRequest{}
+	err = protojson.Unmarshal([]byte(broadcast.Request), req)
+	if err != nil {
+		return err
+	}
+
+	logger.WithField("cursor", broadcast.Cursor).Debug("expanding broadcast")
+
+	expandPage := func(profileIDs []string, cursor string) error {
+		nb.queueBroadcastPage(ctx, parent, req.GetData(), profileIDs, broadcast)
+		broadcast.Cursor = cursor
+
+		pageErr := nb.broadcastRepo.SaveProgress(ctx, broadcast)
+		if pageErr != nil {
+			return pageErr
+		}
+
+		nb.broadcastStatus(ctx, parent, broadcast, commonv1.STATUS_IN_PROCESS, "broadcast_expanding")
+		return nil
+	}
+
+	if req.GetPartitionAudience() {
+		err = nb.forEachPartitionProfilePage(ctx, parent.GetPartitionID(), broadcast.Cursor, expandPage)
+	} else {
+		err = forEachProfilePage(req.GetProfileIds(), broadcast.Cursor, expandPage)
+	}
+	if err != nil {
+		logger.WithError(err).Warn("broadcast expansion stopped early, resuming on redelivery")
+		return err
+	}
+
+	expandedAt := time.Now()
+	broadcast.ExpandedAt = &expandedAt
+	err = nb.broadcastRepo.SaveProgress(ctx, broadcast)
+	if err != nil {
+		return err
+	}
+
+	nb.broadcastStatus(ctx, parent, broadcast, commonv1.STATUS_SUCCESSFUL, "broadcast_expanded")
+	logger.WithFields(map[string]any{"queued": broadcast.Queued, "failed": broadcast.Failed}).Info("broadcast expanded")
+	return nil
+}
+
+// BroadcastExpand is the event handler expanding a broadcast to its audience.
+type BroadcastExpand struct {
+	notificationBusiness NotificationBusiness
+}
+
+// NewBroadcastExpand creates a new BroadcastExpand event handler
+func NewBroadcastExpand(_ context.Context, notificationBusiness NotificationBusiness) *BroadcastExpand {
+	return &BroadcastExpand{notificationBusiness: notificationBusiness}
+}
+
+func (event *BroadcastExpand) Name() string {
+	return BroadcastExpandEvent
+}
+
+func (event *BroadcastExpand) PayloadType() any {
+	pType := ""
+	return &pType
+}
+
+func (event *BroadcastExpand) Validate(_ context.Context, payload any) error {
+	if _, ok := payload.(*string); !ok {
+		return errors.New(" payload is not of type string")
+	}
+
+	return nil
+}
+
+func (event *BroadcastExpand) Execute(ctx context.Context, payload any) error {
+	return event.notificationBusiness.ExpandBroadcast(ctx, *payload.(*string))
+}
+
+// forEachProfilePage walks an explicit audience one page at a time from the offset in cursor.
+func forEachProfilePage(profileIDs []string, cursor string, consume func(profileIDs []string, cursor string) error) error {
+	start, _ := strconv.Atoi(cursor)
+	for ; start < len(profileIDs); start += broadcastPageSize {
+		end := min(start+broadcastPageSize, len(profileIDs))
+		err := consume(profileIDs[start:end], strconv.Itoa(end))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createBroadcastParent stores the record every child of a broadcast links back to.
+func (nb *notificationBusiness) createBroadcastParent(ctx context.Context, message *notificationv1.Notification) (*models.Notification, error) {
+	parent := models.NotificationFromAPI(ctx, message)
+	parent.OutBound = true
+	parent.NotificationType = models.NotificationTypeBroadcast
+	parent.RecipientProfileID = ""
+	parent.RecipientProfileType = ""
+	parent.RecipientContactID = ""
+
+	// Parents are never routed, marking them released keeps them out of Release and the scheduler.
+	releaseDate := time.Now()
+	parent.ReleasedAt = &releaseDate
+	parent.ScheduledAt = nil
+
+	language, err := nb.languageRepo.GetOrCreateByCode(ctx, message.GetLanguage())
+	if err != nil {
+		return nil, err
+	}
+	parent.LanguageID = language.GetID()
+
+	if message.GetTemplate() != "" {
+		_, err = nb.resolveTemplate(ctx, parent, message.GetTemplate())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = nb.notificationRepo.Create(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	return parent, nil
+}
+
+// queueBroadcastPage queues one child notification per recipient profile.
+func (nb *notificationBusiness) queueBroadcastPage(ctx context.Context, parent *models.Notification,
+	message *notificationv1.Notification, profileIDs []string, broadcast *models.Broadcast) {
+
+	broadcast.Pages++
+	for _, profileID := range profileIDs {
+		child, _ := proto.Clone(message).(*notificationv1.Notification)
+		child.Id = ""
+		// A key per recipient keeps a page queued again on resume from sending twice, while a
+		// key shared by every recipient would collapse the audience to its first child.
+		child.IdempotencyKey = parent.GetID() + "/" + profileID
+		child.ParentId = parent.GetID()
+		child.OutBound = true
+		child.Recipient = &commonv1.ContactLink{ProfileId: profileID}
+
+		_, err := nb.QueueOut(ctx, child)
+		if err != nil {
+			util.Log(ctx).WithError(err).WithFields(map[string]any{
+				"broadcast_id": parent.GetID(),
+				"profile_id":   profileID,
+			}).Warn("could not queue broadcast recipient")
+			broadcast.Failed++
+			continue
+		}
+		broadcast.Queued++
+	}
+}
+
+// forEachPartitionProfilePage walks the profiles of a partition one streamed page at a time,
+// starting after the profile id in cursor.
+func (nb *notificationBusiness) forEachPartitionProfilePage(ctx context.Context, partitionID string, cursor string,
+	consume func(profileIDs []string, cursor string) error) error {
+
+	extras, err := structpb.NewStruct(map[string]any{"partition_id": partitionID})
+	if err != nil {
+		return err
+	}
+
+	stream, err := nb.profileCli.Search(ctx, connect.NewRequest(&commonv1.SearchRequest{
+		Cursor: &commonv1.PageCursor{Limit: broadcastPageSize, Page: cursor},
+		Extras: extras,
+	}))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stream.Close() }()
+
+	for stream.Receive() {
+		var profileIDs []string
+		for _, profile := range stream.Msg().GetData() {
+			profileIDs = append(profileIDs, profile.GetId())
+		}
+		if len(profileIDs) == 0 {
+			continue
+		}
+
+		err = consume(profileIDs, profileIDs[len(profileIDs)-1])
+		if err != nil {
+			return err
+		}
+	}
+
+	return stream.Err()
+}
+
+// broadcastProgressStatus describes the progress of a broadcast as a status of its parent notification.
+func broadcastProgressStatus(parent *models.Notification, broadcast *models.Broadcast,
+	st commonv1.STATUS, step string) *models.NotificationStatus {
+
+	return &models.NotificationStatus{
+		NotificationID: parent.GetID(),
+		State:          int32(commonv1.STATE_ACTIVE.Number()),
+		Status:         int32(st.Number()),
+		Extra: data.JSONMap{
+			"step":   step,
+			"queued": broadcast.Queued,
+			"failed": broadcast.Failed,
+			"pages":  broadcast.Pages,
+		},
+	}
+}
+
+// broadcastStatus records the progress of a broadcast on its parent notification.
+func (nb *notificationBusiness) broadcastStatus(ctx context.Context, parent *models.Notification,
+	broadcast *models.Broadcast, st commonv1.STATUS, step string) {
+
+	nStatus := broadcastProgressStatus(parent, broadcast, st, step)
+	nStatus.GenID(ctx)
+
+	err := nb.eventsMan.Emit(ctx, events.NotificationStatusSaveEvent, nStatus)
+	if err != nil {
+		util.Log(ctx).WithError(err).WithField("broadcast_id", parent.GetID()).Warn("could not record broadcast progress")
+	}
+}
+
+func (nb *notificationBusiness) BroadcastStatus(ctx context.Context, req *commonv1.StatusRequest) (*notificationv1.BroadcastStatusResponse, error) {
+	logger := util.Log(ctx).WithField("broadcast_id", req.GetId())
+	logger.Debug("handling broadcast status request")
+
+	parent, err := nb.notificationRepo.GetByID(ctx, req.GetId())
+	if err != nil {
+		logger.WithError(err).Warn("could not get by id")
+		return nil, err
+	}
+
+	if !parent.IsBroadcast() {
+		return nil, ErrorNotABroadcast
+	}
+
+	counters, err := nb.aggregateRepo.GetByParentID(ctx, parent.GetID())
+	if err != nil {
+		logger.WithError(err).Warn("could not load broadcast aggregates")
+		return nil, err
+	}
+
+	resp := &notificationv1.BroadcastStatusResponse{
+		Id:             parent.GetID(),
+		FailuresByStep: map[string]int64{},
+	}
+
+	for _, counter := range counters {
+		if counter.Kind == models.AggregateKindFailureStep {
+			resp.FailuresByStep[counter.Key] = counter.Count
+			continue
+		}
+
+		switch counter.Key {
+		case "queued":
+			resp.Queued = counter.Count
+		case "in_process":
+			resp.InProcess = counter.Count
+		case "successful":
+			resp.Successful = counter.Count
+		case "failed":
+			resp.Failed = counter.Count
+		}
+	}
+
+	if parent.StatusID != "" {
+		nStatus, sErr := nb.notificationStatusRepo.GetByID(ctx, parent.StatusID)
+		if sErr != nil {
+			logger.WithError(sErr).Warn("unable to get by status id")
+			return nil, sErr
+		}
+		resp.Status = nStatus.ToAPI()
+	}
+
+	return resp, nil
+}
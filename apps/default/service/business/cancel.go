@@ -0,0 +1,169 @@
+package business
+
+import (
+	"context"
+	"time"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+	"github.com/antinvestor/service-notification/apps/default/service/events"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/pitabwire/frame/v2/data"
+	"github.com/pitabwire/frame/v2/workerpool"
+	"github.com/pitabwire/util"
+)
+
+const (
+	// cancelResultKey is the status extra reporting the outcome of a cancel request.
+	cancelResultKey = "cancel_result"
+
+	cancelResultCanceled = "canceled"
+	cancelResultTooLate  = "too_late"
+	cancelResultNotFound = "not_found"
+)
+
+func (nb *notificationBusiness) Cancel(ctx context.Context, cancelReq *notificationv1.CancelRequest) (workerpool.JobResultPipe[*notificationv1.CancelResponse], error) {
+
+	if len(cancelReq.GetId()) == 0 {
+		return nil, ErrorUnspecifiedID
+	}
+
+	job := workerpool.NewJob(func(ctx context.Context, resultPipe workerpool.JobResultPipe[*notificationv1.CancelResponse]) error {
+
+		logger := util.Log(ctx)
+		logger.Debug("handling cancel request")
+
+		canceledList, err := nb.notificationRepo.Cancel(ctx, time.Now(), cancelReq.GetId()...)
+		if err != nil {
+			logger.WithError(err).Warn("could not cancel notifications")
+			return err
+		}
+
+		canceledIDs := map[string]bool{}
+		var canceledStatuses []*commonv1.StatusResponse
+		for _, n := range canceledList {
+			canceledIDs[n.GetID()] = true
+
+			nStatus := models.NotificationStatus{
+				NotificationID: n.GetID(),
+				State:          int32(commonv1.STATE_DELETED.Number()),
+				Status:         int32(commonv1.STATUS_FAILED.Number()),
+				Extra: data.JSONMap{
+					"step":          "canceled",
+					cancelResultKey: cancelResultCanceled,
+				},
+			}
+			if cancelReq.GetComment() != "" {
+				nStatus.Extra["comment"] = cancelReq.GetComment()
+			}
+
+			nStatus.GenID(ctx)
+
+			err = nb.eventsMan.Emit(ctx, events.NotificationStatusSaveEvent, &nStatus)
+			if err != nil {
+				logger.WithError(err).Warn("could not emit notification status")
+				return err
+			}
+
+			canceledStatuses = append(canceledStatuses, nStatus.ToAPI())
+		}
+
+		if len(canceledStatuses) > 0 {
+			err = resultPipe.WriteResult(ctx, &notificationv1.CancelResponse{Data: canceledStatuses})
+			if err != nil {
+				return err
+			}
+		}
+
+		var remainingIDs []string
+		for _, id := range cancelReq.GetId() {
+			if !canceledIDs[id] {
+				remainingIDs = append(remainingIDs, id)
+			}
+		}
+		if len(remainingIDs) == 0 {
+			return nil
+		}
+
+		remainingStatuses, err := nb.uncancelableStatuses(ctx, remainingIDs)
+		if err != nil {
+			logger.WithError(err).Warn("could not get notification status")
+			return err
+		}
+
+		return resultPipe.WriteResult(ctx, &notificationv1.CancelResponse{Data: remainingStatuses})
+	})
+
+	err := workerpool.SubmitJob(ctx, nb.workMan, job)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// uncancelableStatuses explains, per ID, why a notification was not canceled:
+// it was canceled before, was already published to a route, or does not exist.
+func (nb *notificationBusiness) uncancelableStatuses(ctx context.Context, ids []string) ([]*commonv1.StatusResponse, error) {
+	notificationList, err := nb.notificationRepo.GetByIDList(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	var statusIDs []string
+	for _, n := range notificationList {
+		if n.StatusID != "" {
+			statusIDs = append(statusIDs, n.StatusID)
+		}
+	}
+
+	statusMap := map[string]*models.NotificationStatus{}
+	if len(statusIDs) > 0 {
+		statusList, sErr := nb.notificationStatusRepo.GetByIDList(ctx, statusIDs...)
+		if sErr != nil {
+			return nil, sErr
+		}
+		for _, s := range statusList {
+			statusMap[s.GetID()] = s
+		}
+	}
+
+	found := map[string]bool{}
+	var statuses []*commonv1.StatusResponse
+	for _, n := range notificationList {
+		found[n.GetID()] = true
+
+		nStatus, ok := statusMap[n.StatusID]
+		if !ok {
+			nStatus = &models.NotificationStatus{NotificationID: n.GetID(), State: n.State, Status: n.Status}
+		}
+
+		if !n.IsCanceled() {
+			reported := *nStatus
+			reported.Extra = nStatus.Extra.Update(data.JSONMap{
+				cancelResultKey: cancelResultTooLate,
+				"error":         "notification was already published to a route",
+			})
+			nStatus = &reported
+		}
+
+		statuses = append(statuses, nStatus.ToAPI())
+	}
+
+	for _, id := range ids {
+		if found[id] {
+			continue
+		}
+		extra := data.JSONMap{
+			cancelResultKey: cancelResultNotFound,
+			"error":         "notification does not exist",
+		}
+		statuses = append(statuses, &commonv1.StatusResponse{
+			Id:     id,
+			Status: commonv1.STATUS_UNKNOWN,
+			Extras: extra.ToProtoStruct(),
+		})
+	}
+
+	return statuses, nil
+}
@@ -1,15 +1,33 @@
 package business
 
 import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 var (
-	ErrorUnspecifiedID      = status.Error(codes.InvalidArgument, "No id was supplied")
-	ErrorEmptyValueSupplied = status.Error(codes.InvalidArgument, "Empty value supplied")
-	ErrorItemExist          = status.Error(codes.AlreadyExists, "Specified item already exists")
-	ErrorItemDoesNotExist   = status.Error(codes.NotFound, "Specified item does not exist")
+	ErrorUnspecifiedID        = status.Error(codes.InvalidArgument, "No id was supplied")
+	ErrorEmptyValueSupplied   = status.Error(codes.InvalidArgument, "Empty value supplied")
+	ErrorItemExist            = status.Error(codes.AlreadyExists, "Specified item already exists")
+	ErrorItemDoesNotExist     = status.Error(codes.NotFound, "Specified item does not exist")
+	ErrorInvalidSendAt        = status.Error(codes.InvalidArgument, "Notification send_at must be an RFC3339 timestamp")
+	ErrorInvalidAudience      = status.Error(codes.InvalidArgument, "Provide either profile ids or the partition audience")
+	ErrorNotABroadcast        = status.Error(codes.InvalidArgument, "Specified notification is not a broadcast")
+	ErrorTemplateNotPublished = status.Error(codes.FailedPrecondition, "Specified template version was never published")
+	ErrorTemplateNotDraft     = status.Error(codes.FailedPrecondition, "Only draft template versions can be changed, save a new version instead")
+	ErrorTemplateInUse        = status.Error(codes.FailedPrecondition, "Specified template is still used by unreleased notifications")
+
+	ErrorTemplateNameNotPublished = status.Error(codes.FailedPrecondition, "Specified template has no published version to send")
 
 	ErrorInitializationFail = status.Error(codes.Internal, "Internal configuration is invalid")
 )
+
+// errorFieldViolations reports an invalid argument naming each offending field.
+func errorFieldViolations(message string, violations ...*errdetails.BadRequest_FieldViolation) error {
+	st, err := status.New(codes.InvalidArgument, message).WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, message)
+	}
+	return st.Err()
+}
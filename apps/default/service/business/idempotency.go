@@ -0,0 +1,77 @@
+package business
+
+import (
+	"context"
+	"time"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/pitabwire/frame/v2/data"
+	"github.com/pitabwire/util"
+)
+
+// claimIdempotencyKey ties an idempotency key to the notification about to be queued.
+// When the key is already held by an earlier send it returns that send's original
+// status, and the caller must not queue the notification again. Otherwise it returns
+// the claimed entry so the caller can release it if queuing fails. Sends without
+// a key claim nothing.
+func (nb *notificationBusiness) claimIdempotencyKey(ctx context.Context, key string,
+	n *models.Notification, nStatus *models.NotificationStatus) (*models.IdempotencyKey, *commonv1.StatusResponse, error) {
+
+	if key == "" {
+		return nil, nil, nil
+	}
+
+	idempotencyKey := &models.IdempotencyKey{
+		Key:            key,
+		NotificationID: n.GetID(),
+		StatusID:       nStatus.GetID(),
+		ExpiresAt:      time.Now().Add(nb.idempotencyKeyRetention),
+	}
+	idempotencyKey.CopyPartitionInfo(&n.BaseModel)
+	idempotencyKey.GenID(ctx)
+
+	claimed, err := nb.idempotencyKeyRepo.Claim(ctx, idempotencyKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if claimed {
+		return idempotencyKey, nil, nil
+	}
+
+	existing, err := nb.idempotencyKeyRepo.GetByKey(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	original, err := nb.notificationStatusRepo.GetByID(ctx, existing.StatusID)
+	if err == nil {
+		return nil, original.ToAPI(), nil
+	}
+	if !data.ErrorIsNoRows(err) {
+		return nil, nil, err
+	}
+
+	// The original status is saved asynchronously, rebuild it until it lands.
+	util.Log(ctx).WithField("status_id", existing.StatusID).Debug("original status not saved yet, rebuilding it")
+	original = &models.NotificationStatus{
+		NotificationID: existing.NotificationID,
+		State:          int32(commonv1.STATE_CREATED.Number()),
+		Status:         int32(commonv1.STATUS_QUEUED.Number()),
+	}
+	original.ID = existing.StatusID
+	original.CreatedAt = existing.CreatedAt
+	return nil, original.ToAPI(), nil
+}
+
+// releaseIdempotencyKey frees a claimed key after queuing its notification failed.
+func (nb *notificationBusiness) releaseIdempotencyKey(ctx context.Context, idempotencyKey *models.IdempotencyKey) {
+	if idempotencyKey == nil {
+		return
+	}
+
+	err := nb.idempotencyKeyRepo.Release(ctx, idempotencyKey)
+	if err != nil {
+		util.Log(ctx).WithError(err).WithField("idempotency_key", idempotencyKey.Key).Warn("could not release idempotency key")
+	}
+}
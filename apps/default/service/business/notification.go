@@ -3,7 +3,9 @@ package business
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
@@ -13,6 +15,7 @@ import (
 	"github.com/antinvestor/service-notification/apps/default/service/events"
 	"github.com/antinvestor/service-notification/apps/default/service/models"
 	"github.com/antinvestor/service-notification/apps/default/service/repository"
+	"github.com/antinvestor/service-notification/pkg/constants"
 	"github.com/pitabwire/frame/v2/data"
 	fevents "github.com/pitabwire/frame/v2/events"
 	"github.com/pitabwire/frame/v2/workerpool"
@@ -25,9 +28,21 @@ type NotificationBusiness interface {
 	Status(ctx context.Context, status *commonv1.StatusRequest) (*commonv1.StatusResponse, error)
 	StatusUpdate(ctx context.Context, req *commonv1.StatusUpdateRequest) (*commonv1.StatusResponse, error)
 	Release(ctx context.Context, req *notificationv1.ReleaseRequest) (workerpool.JobResultPipe[*notificationv1.ReleaseResponse], error)
+	Cancel(ctx context.Context, req *notificationv1.CancelRequest) (workerpool.JobResultPipe[*notificationv1.CancelResponse], error)
+	Broadcast(ctx context.Context, req *notificationv1.BroadcastRequest) (workerpool.JobResultPipe[*notificationv1.BroadcastResponse], error)
+	ExpandBroadcast(ctx context.Context, broadcastID string) error
+	BroadcastStatus(ctx context.Context, req *commonv1.StatusRequest) (*notificationv1.BroadcastStatusResponse, error)
 	Search(ctx context.Context, search *commonv1.SearchRequest, consumer func(ctx context.Context, batch []*notificationv1.Notification) error) error
 	TemplateSave(ctx context.Context, req *notificationv1.TemplateSaveRequest) (*notificationv1.Template, error)
+	TemplatePublish(ctx context.Context, req *notificationv1.TemplatePublishRequest) (*notificationv1.Template, error)
+	TemplateRollback(ctx context.Context, req *notificationv1.TemplateRollbackRequest) (*notificationv1.Template, error)
+	TemplateUpdate(ctx context.Context, req *notificationv1.TemplateUpdateRequest) (*notificationv1.Template, error)
+	TemplateDelete(ctx context.Context, req *notificationv1.TemplateDeleteRequest) ([]string, error)
+	TemplatePreview(ctx context.Context, req *notificationv1.TemplatePreviewRequest) (*notificationv1.TemplatePreviewResponse, error)
 	TemplateSearch(ctx context.Context, search *notificationv1.TemplateSearchRequest, consumer func(ctx context.Context, batch []*notificationv1.Template) error) error
+	SuppressionAdd(ctx context.Context, req *notificationv1.SuppressionAddRequest) (*notificationv1.Suppression, error)
+	SuppressionRemove(ctx context.Context, req *notificationv1.SuppressionRemoveRequest) ([]string, error)
+	SuppressionSearch(ctx context.Context, search *notificationv1.SuppressionSearchRequest, consumer func(ctx context.Context, batch []*notificationv1.Suppression) error) error
 }
 
 func NewNotificationBusiness(_ context.Context,
@@ -39,6 +54,14 @@ func NewNotificationBusiness(_ context.Context,
 	templateRepo repository.TemplateRepository,
 	templateDataRepo repository.TemplateDataRepository,
 	routeRepo repository.RouteRepository,
+	aggregateRepo repository.NotificationAggregateRepository,
+	idempotencyKeyRepo repository.IdempotencyKeyRepository,
+	rateLimitCounterRepo repository.RateLimitCounterRepository,
+	suppressionRepo repository.SuppressionRepository,
+	broadcastRepo repository.BroadcastRepository,
+	externalIDRepo repository.NotificationExternalIDRepository,
+	defaultLanguageCode string,
+	idempotencyKeyRetention time.Duration,
 ) NotificationBusiness {
 	return &notificationBusiness{
 		workMan:                workMan,
@@ -51,6 +74,15 @@ func NewNotificationBusiness(_ context.Context,
 		templateRepo:           templateRepo,
 		templateDataRepo:       templateDataRepo,
 		routeRepo:              routeRepo,
+		aggregateRepo:          aggregateRepo,
+		idempotencyKeyRepo:     idempotencyKeyRepo,
+		rateLimitCounterRepo:   rateLimitCounterRepo,
+		suppressionRepo:        suppressionRepo,
+		broadcastRepo:          broadcastRepo,
+		externalIDRepo:         externalIDRepo,
+
+		defaultLanguageCode:     defaultLanguageCode,
+		idempotencyKeyRetention: idempotencyKeyRetention,
 	}
 }
 
@@ -65,6 +97,15 @@ type notificationBusiness struct {
 	templateRepo           repository.TemplateRepository
 	templateDataRepo       repository.TemplateDataRepository
 	routeRepo              repository.RouteRepository
+	aggregateRepo          repository.NotificationAggregateRepository
+	idempotencyKeyRepo     repository.IdempotencyKeyRepository
+	rateLimitCounterRepo   repository.RateLimitCounterRepository
+	suppressionRepo        repository.SuppressionRepository
+	broadcastRepo          repository.BroadcastRepository
+	externalIDRepo         repository.NotificationExternalIDRepository
+
+	defaultLanguageCode     string
+	idempotencyKeyRetention time.Duration
 }
 
 func (nb *notificationBusiness) QueueOut(ctx context.Context, message *notificationv1.Notification) (*commonv1.StatusResponse, error) {
@@ -72,10 +113,19 @@ func (nb *notificationBusiness) QueueOut(ctx context.Context, message *notificat
 
 	logger.Debug("handling queue out request")
 
+	_, err := models.ParseSendAt(message.GetExtras())
+	if err != nil {
+		return nil, ErrorInvalidSendAt
+	}
+
 	n := models.NotificationFromAPI(ctx, message)
 	n.OutBound = true
-	if message.AutoRelease {
-		releaseDate := time.Now()
+
+	releaseDate := time.Now()
+	switch {
+	case n.ScheduledAt != nil && n.ScheduledAt.After(releaseDate):
+		// Left unreleased, the release scheduler picks it up once it is due.
+	case message.GetAutoRelease() || n.ScheduledAt != nil:
 		n.ReleasedAt = &releaseDate
 	}
 
@@ -88,19 +138,21 @@ func (nb *notificationBusiness) QueueOut(ctx context.Context, message *notificat
 
 	n.LanguageID = language.GetID()
 
-	templateID := ""
+	n.TemplateID = ""
 	if message.GetTemplate() != "" {
-		t, err0 := nb.templateRepo.GetByName(ctx, message.GetTemplate())
+		template, err0 := nb.resolveTemplate(ctx, n, message.GetTemplate())
 		if err0 != nil {
 			logger.WithError(err0).Warn("could not get template")
 			return nil, err0
 		}
 
-		templateID = t.GetID()
+		err = checkTemplatePayload(template, n.Payload)
+		if err != nil {
+			logger.WithError(err).Debug("payload does not match template variables")
+			return nil, err
+		}
 	}
 
-	n.TemplateID = templateID
-
 	nStatus := models.NotificationStatus{
 		NotificationID: n.GetID(),
 		State:          int32(commonv1.STATE_CREATED.Number()),
@@ -109,10 +161,50 @@ func (nb *notificationBusiness) QueueOut(ctx context.Context, message *notificat
 
 	nStatus.GenID(ctx)
 
+	idempotencyKey, original, err := nb.claimIdempotencyKey(ctx, message.GetIdempotencyKey(), n, &nStatus)
+	if err != nil {
+		logger.WithError(err).Warn("could not claim idempotency key")
+		return nil, err
+	}
+	if original != nil {
+		logger.WithField("notification_id", original.GetId()).Debug("idempotency key reused, returning original status")
+		return original, nil
+	}
+
+	rateLimit, err := nb.applyRateLimits(ctx, n, message.GetTemplate())
+	if err != nil {
+		logger.WithError(err).Warn("could not apply rate limits")
+		nb.releaseIdempotencyKey(ctx, idempotencyKey)
+		return nil, err
+	}
+
+	if rateLimit.rejectedBy != nil {
+		logger.WithField("scope", rateLimit.rejectedBy.Scope).Info("notification rejected by rate limit")
+		return nb.rejectRateLimited(ctx, n, &nStatus, rateLimit.rejectedBy)
+	}
+
+	if rateLimit.deferUntil != nil {
+		nStatus.Extra = data.JSONMap{
+			"step":  "rate_limit_deferred",
+			"scope": rateLimit.deferredBy.Scope,
+		}
+		// Only sends released automatically are moved to the window they fit in, a manual
+		// send keeps waiting for Release and its status records the window instead.
+		if n.IsReleased() || n.IsScheduled() {
+			n.ReleasedAt = nil
+			n.ScheduledAt = rateLimit.deferUntil
+			nStatus.Extra["scheduled_at"] = rateLimit.deferUntil.Format(time.RFC3339)
+		} else {
+			nStatus.Extra["deferred_until"] = rateLimit.deferUntil.Format(time.RFC3339)
+		}
+		events.RecordRateLimited(ctx, n, rateLimit.deferredBy.Scope, events.RateLimitActionDefer)
+	}
+
 	// Queue out message for further processing
 	err = nb.eventsMan.Emit(ctx, events.NotificationSaveEvent, n)
 	if err != nil {
 		logger.WithError(err).Warn("could not emit event save")
+		nb.releaseIdempotencyKey(ctx, idempotencyKey)
 		return nil, err
 	}
 
@@ -145,6 +237,12 @@ func (nb *notificationBusiness) QueueIn(ctx context.Context, message *notificati
 
 	n.LanguageID = language.GetID()
 
+	err = nb.inboundRoute(ctx, n)
+	if err != nil {
+		logger.WithError(err).WithField("route_id", n.RouteID).Warn("could not get inbound route")
+		return nil, err
+	}
+
 	nStatus := models.NotificationStatus{
 		NotificationID: n.GetID(),
 		State:          int32(commonv1.STATE_CREATED.Number()),
@@ -169,6 +267,27 @@ func (nb *notificationBusiness) QueueIn(ctx context.Context, message *notificati
 	return nStatus.ToAPI(), nil
 }
 
+// inboundRoute places a received message in the partition of the route it arrived on,
+// replacing the partition of the caller. Integrations receive for every partition with
+// their own credentials, so only the route tells whose message it is. Messages picked up by a transmit only route, such as replies to a carrier shortcode,
+// are left unrouted so they are handed to the partition's receiving routes instead.
+func (nb *notificationBusiness) inboundRoute(ctx context.Context, n *models.Notification) error {
+	if n.RouteID == "" {
+		return nil
+	}
+
+	route, err := nb.routeRepo.GetByID(ctx, n.RouteID)
+	if err != nil {
+		return err
+	}
+
+	n.CopyPartitionInfo(&route.BaseModel)
+	if route.Mode == models.RouteModeTransmit {
+		n.RouteID = ""
+	}
+	return nil
+}
+
 func (nb *notificationBusiness) Status(ctx context.Context, statusReq *commonv1.StatusRequest) (*commonv1.StatusResponse, error) {
 	logger := util.Log(ctx).WithField("notification_id", statusReq.GetId())
 	logger.Debug("handling status check request")
@@ -191,6 +310,17 @@ func (nb *notificationBusiness) StatusUpdate(ctx context.Context, statusReq *com
 	logger := util.Log(ctx).WithField("notification_id", statusReq.GetId())
 	logger.Debug("handling status update request")
 
+	if statusReq.GetId() == "" && statusReq.GetExternalId() != "" {
+		// Delivery reports only carry the identifier the gateway gave one of the message parts.
+		notificationID, err := nb.externalIDRepo.GetNotificationID(ctx, statusReq.GetExternalId())
+		if err != nil {
+			logger.WithError(err).WithField("external_id", statusReq.GetExternalId()).
+				Warn("could not match external id to a notification")
+			return nil, err
+		}
+		statusReq.Id = notificationID
+	}
+
 	n, err := nb.notificationRepo.GetByID(ctx, statusReq.GetId())
 	if err != nil {
 		logger.WithError(err).Warn("could not get by id")
@@ -205,6 +335,12 @@ func (nb *notificationBusiness) StatusUpdate(ctx context.Context, statusReq *com
 		Extra:          statusReq.GetExtras().AsMap(),
 	}
 
+	err = nb.externalIDRepo.Record(ctx, n, statusExternalIDs(statusReq.GetExternalId(), nStatus.Extra)...)
+	if err != nil {
+		logger.WithError(err).Warn("could not record external ids")
+		return nil, err
+	}
+
 	nStatus.GenID(ctx)
 
 	// Queue out notification status for further processing
@@ -217,6 +353,28 @@ func (nb *notificationBusiness) StatusUpdate(ctx context.Context, statusReq *com
 	return nStatus.ToAPI(), nil
 }
 
+// statusExternalIDs collects the gateway identifiers a status update reports for a notification.
+func statusExternalIDs(externalID string, extras map[string]any) []string {
+	seen := map[string]bool{}
+	var externalIDs []string
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			externalIDs = append(externalIDs, id)
+		}
+	}
+
+	add(externalID)
+	if messageIDs, ok := extras[constants.StatusExtraMessageIDs].([]any); ok {
+		for _, id := range messageIDs {
+			if idStr, isStr := id.(string); isStr {
+				add(idStr)
+			}
+		}
+	}
+	return externalIDs
+}
+
 func (nb *notificationBusiness) Release(ctx context.Context, releaseReq *notificationv1.ReleaseRequest) (workerpool.JobResultPipe[*notificationv1.ReleaseResponse], error) {
 
 	job := workerpool.NewJob(func(ctx context.Context, resultPipe workerpool.JobResultPipe[*notificationv1.ReleaseResponse]) error {
@@ -232,18 +390,33 @@ func (nb *notificationBusiness) Release(ctx context.Context, releaseReq *notific
 
 		var releasedStatusIDs []string
 		var notificationsToUpdate []*models.Notification
+		deferrals := map[string]time.Time{}
 
 		releaseDate := time.Now()
 
 		for _, n := range notificationList {
 
-			if n.IsReleased() {
+			// Canceled notifications are reported as they are and never released again.
+			if n.IsReleased() || n.IsCanceled() {
 				releasedStatusIDs = append(releasedStatusIDs, n.StatusID)
+				continue
+			}
+
+			deferredUntil, deferErr := nb.rateLimitDeferral(ctx, n)
+			if deferErr != nil {
+				logger.WithError(deferErr).WithField("notification_id", n.GetID()).Warn("could not check rate limit deferral")
+				return deferErr
+			}
+
+			// A send its rate limit deferred goes out with the window it was counted in, the
+			// scheduler releases it then.
+			if deferredUntil != nil {
+				n.ScheduledAt = deferredUntil
+				deferrals[n.GetID()] = *deferredUntil
 			} else {
 				n.ReleasedAt = &releaseDate
-				notificationsToUpdate = append(notificationsToUpdate, n)
 			}
-
+			notificationsToUpdate = append(notificationsToUpdate, n)
 		}
 
 		var statusesToRelease []*commonv1.StatusResponse
@@ -260,6 +433,13 @@ func (nb *notificationBusiness) Release(ctx context.Context, releaseReq *notific
 				State:          int32(commonv1.STATE_ACTIVE.Number()),
 				Status:         int32(commonv1.STATUS_QUEUED.Number()),
 			}
+			if deferredUntil, ok := deferrals[n.GetID()]; ok {
+				nStatus.Extra = data.JSONMap{
+					"step":           "rate_limit_deferred",
+					"deferred_until": deferredUntil.Format(time.RFC3339),
+					"scheduled_at":   deferredUntil.Format(time.RFC3339),
+				}
+			}
 
 			nStatus.GenID(ctx)
 
@@ -353,7 +533,7 @@ func (nb *notificationBusiness) convertNotificationsToAPI(
 	}
 
 	for _, not := range notificationList {
-		status := statusMap[not.ID]
+		status := statusMap[not.StatusID]
 		language := languageMap[not.LanguageID]
 
 		// Convert the payment model to the API response format
@@ -566,6 +746,18 @@ func (nb *notificationBusiness) TemplateSave(ctx context.Context, req *notificat
 
 	logger.Debug("handling template save request")
 
+	var contents []templateContent
+	for key, val := range req.GetData().AsMap() {
+		detail, _ := val.(string)
+		contents = append(contents, templateContent{field: "data." + key, detail: detail})
+	}
+	slices.SortFunc(contents, func(a, b templateContent) int { return strings.Compare(a.field, b.field) })
+
+	err := checkTemplateVariables(req.GetExtra().AsMap(), contents)
+	if err != nil {
+		return nil, err
+	}
+
 	language, err := nb.languageRepo.GetOrCreateByCode(ctx, req.GetLanguageCode())
 
 	if err != nil {
@@ -576,10 +768,12 @@ func (nb *notificationBusiness) TemplateSave(ctx context.Context, req *notificat
 	template := &models.Template{
 		Name:  req.GetName(),
 		Extra: req.GetExtra().AsMap(),
+		State: models.TemplateStateDraft,
 	}
 
-	err = nb.templateRepo.Create(ctx, template)
+	err = nb.templateRepo.CreateRevision(ctx, template)
 	if err != nil {
+		logger.WithError(err).Warn("could not save template version")
 		return nil, err
 	}
 
@@ -597,6 +791,14 @@ func (nb *notificationBusiness) TemplateSave(ctx context.Context, req *notificat
 		}
 	}
 
+	if req.GetPublish() {
+		err = nb.templateRepo.Publish(ctx, template)
+		if err != nil {
+			logger.WithError(err).Warn("could not publish template")
+			return nil, err
+		}
+	}
+
 	template, err = nb.templateRepo.GetByID(ctx, template.GetID())
 	if err != nil {
 		logger.WithError(err).Debug("could not get existing template")
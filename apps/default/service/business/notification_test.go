@@ -2,17 +2,31 @@ package business_test
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
 	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+	"buf.build/gen/go/antinvestor/tenancy/connectrpc/go/tenancy/v1/tenancyv1connect"
+	tenancyv1 "buf.build/gen/go/antinvestor/tenancy/protocolbuffers/go/tenancy/v1"
+	"connectrpc.com/connect"
+	"github.com/antinvestor/service-notification/apps/default/service/business"
+	"github.com/antinvestor/service-notification/apps/default/service/events"
 	"github.com/antinvestor/service-notification/apps/default/service/models"
 	"github.com/antinvestor/service-notification/apps/default/tests"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/pitabwire/frame/v2/data"
+	fevents "github.com/pitabwire/frame/v2/events"
 	"github.com/pitabwire/frame/v2/frametests"
 	"github.com/pitabwire/frame/v2/frametests/definition"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
@@ -46,6 +60,65 @@ func (nts *NotificationTestSuite) TestNewNotificationBusiness() {
 	})
 }
 
+func (nts *NotificationTestSuite) Test_notificationBusiness_QueueOutIdempotent() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		svc, ctx, resources := nts.CreateService(t, dep)
+
+		message := func() *notificationv1.Notification {
+			return &notificationv1.Notification{
+				Language:       "en",
+				Recipient:      &commonv1.ContactLink{ContactId: "epochTesting"},
+				Data:           "Hello we are just testing retried sends",
+				IdempotencyKey: "order-1234-receipt",
+			}
+		}
+
+		first, err := resources.NotificationBusiness.QueueOut(ctx, message())
+		require.NoError(t, err)
+
+		retried, err := resources.NotificationBusiness.QueueOut(ctx, message())
+		require.NoError(t, err)
+		require.Equal(t, first.GetId(), retried.GetId(), "a retried send returns the original notification")
+		require.Equal(t, first.GetExtras().AsMap()["StatusID"], retried.GetExtras().AsMap()["StatusID"])
+
+		other := message()
+		other.IdempotencyKey = "order-1235-receipt"
+		fresh, err := resources.NotificationBusiness.QueueOut(ctx, other)
+		require.NoError(t, err)
+		require.NotEqual(t, first.GetId(), fresh.GetId(), "a different key queues a new notification")
+
+		key, err := resources.IdempotencyKeyRepo.GetByKey(ctx, "order-1235-receipt")
+		require.NoError(t, err)
+		require.NoError(t, resources.IdempotencyKeyRepo.Release(ctx, key))
+
+		// A send taking over the expired key keeps the entry's id, releasing its own claim after
+		// queuing fails frees the key again.
+		takeover := &models.IdempotencyKey{Key: "order-1235-receipt", NotificationID: "takeover-notification", ExpiresAt: time.Now().Add(time.Hour)}
+		takeover.CopyPartitionInfo(&key.BaseModel)
+		takeover.GenID(ctx)
+		claimed, err := resources.IdempotencyKeyRepo.Claim(ctx, takeover)
+		require.NoError(t, err)
+		require.True(t, claimed)
+		require.NoError(t, resources.IdempotencyKeyRepo.Release(ctx, takeover))
+		_, err = resources.IdempotencyKeyRepo.GetByKey(ctx, "order-1235-receipt")
+		require.Error(t, err, "the released takeover frees the key")
+
+		scheduler := business.NewReleaseScheduler(svc.Name(), time.Minute, 10, svc.EventsManager(),
+			resources.NotificationRepo, resources.NotificationStatusRepo, resources.IdempotencyKeyRepo, resources.RateLimitCounterRepo)
+		purged, err := scheduler.PurgeExpired(ctx)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, purged, int64(1))
+
+		_, err = resources.IdempotencyKeyRepo.GetByID(ctx, key.GetID())
+		require.Error(t, err, "expired keys are purged")
+
+		_, err = resources.IdempotencyKeyRepo.GetByKey(ctx, "order-1234-receipt")
+		require.NoError(t, err, "unexpired keys are kept")
+	})
+}
+
 func (nts *NotificationTestSuite) Test_notificationBusiness_QueueIn() {
 
 	testCases := []struct {
@@ -84,6 +157,16 @@ func (nts *NotificationTestSuite) Test_notificationBusiness_QueueIn() {
 				Status: commonv1.STATUS_UNKNOWN,
 			},
 		},
+		{name: "UnknownRouteQueueIn",
+			message: &notificationv1.Notification{
+				Language:  "en",
+				Source:    &commonv1.ContactLink{Detail: "+256700123456"},
+				Recipient: &commonv1.ContactLink{Detail: "22384"},
+				RouteId:   "missingroute",
+				Data:      "STOP",
+			},
+			wantErr: true,
+		},
 	}
 
 	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
@@ -110,6 +193,116 @@ func (nts *NotificationTestSuite) Test_notificationBusiness_QueueIn() {
 	})
 }
 
+func (nts *NotificationTestSuite) Test_notificationBusiness_QueueInRoutePartition() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		_, ctx, resources := nts.CreateService(t, dep)
+
+		route := &models.Route{Name: "shortcode.route", RouteType: models.RouteTypeSMSForm, Mode: models.RouteModeTransmit}
+		route.TenantID = "route_tenant"
+		route.PartitionID = "route_partition"
+		require.NoError(t, resources.RouteRepo.Create(ctx, route))
+
+		// The integration submits with its own claims, the message belongs to the route's partition.
+		integrationCtx := nts.WithAuthClaims(ctx, "integration_tenant", "integration_partition", "integration_profile")
+		got, err := resources.NotificationBusiness.QueueIn(integrationCtx, &notificationv1.Notification{
+			Language:  "en",
+			Source:    &commonv1.ContactLink{Detail: "+256700123456"},
+			Recipient: &commonv1.ContactLink{Detail: "22384"},
+			RouteId:   route.GetID(),
+			Type:      "sms",
+			Data:      "HELLO",
+		})
+		require.NoError(t, err)
+
+		var saved *models.Notification
+		require.Eventually(t, func() bool {
+			saved, err = resources.NotificationRepo.GetByID(ctx, got.GetId())
+			return err == nil
+		}, 10*time.Second, 100*time.Millisecond)
+
+		require.Equal(t, "route_tenant", saved.TenantID)
+		require.Equal(t, "route_partition", saved.PartitionID)
+		require.Empty(t, saved.RouteID, "replies picked up by a transmit route are handed to the receiving routes")
+	})
+}
+
+func (nts *NotificationTestSuite) Test_routeRepository_CreateDisabled() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		_, ctx, resources := nts.CreateService(t, dep)
+
+		disabled := false
+		route := &models.Route{Name: "disabled.route", RouteType: models.RouteTypeSMSForm, Mode: models.RouteModeTransmit, Enabled: &disabled}
+		require.NoError(t, resources.RouteRepo.Create(ctx, route))
+
+		saved, err := resources.RouteRepo.GetByID(ctx, route.GetID())
+		require.NoError(t, err)
+		require.False(t, saved.IsEnabled(), "a route created disabled stays disabled")
+
+		route = &models.Route{Name: "default.route", RouteType: models.RouteTypeSMSForm, Mode: models.RouteModeTransmit}
+		require.NoError(t, resources.RouteRepo.Create(ctx, route))
+
+		saved, err = resources.RouteRepo.GetByID(ctx, route.GetID())
+		require.NoError(t, err)
+		require.True(t, saved.IsEnabled(), "routes are enabled unless created otherwise")
+	})
+}
+
+func (nts *NotificationTestSuite) Test_notificationRepository_UpdateStatus() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		_, ctx, resources := nts.CreateService(t, dep)
+
+		n := &models.Notification{
+			Message:          "Hello we are just testing status moves",
+			NotificationType: "sms",
+			OutBound:         true,
+			Status:           int32(commonv1.STATUS_QUEUED.Number()),
+		}
+		require.NoError(t, resources.NotificationRepo.Create(ctx, n))
+
+		first := *n
+		first.Status = int32(commonv1.STATUS_IN_PROCESS.Number())
+		changed, err := resources.NotificationRepo.UpdateStatus(ctx, &first, int32(commonv1.STATUS_QUEUED.Number()))
+		require.NoError(t, err)
+		require.True(t, changed)
+
+		second := *n
+		second.Status = int32(commonv1.STATUS_SUCCESSFUL.Number())
+		changed, err = resources.NotificationRepo.UpdateStatus(ctx, &second, int32(commonv1.STATUS_QUEUED.Number()))
+		require.NoError(t, err)
+		require.False(t, changed, "a status read before a concurrent move leaves the row untouched")
+
+		saved, err := resources.NotificationRepo.GetByID(ctx, n.GetID())
+		require.NoError(t, err)
+		require.EqualValues(t, commonv1.STATUS_IN_PROCESS, saved.Status)
+		require.Equal(t, saved.Version, first.Version)
+	})
+}
+
+func (nts *NotificationTestSuite) Test_notificationBusiness_QueueOutInvalidSendAt() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		_, ctx, resources := nts.CreateService(t, dep)
+
+		extras, err := structpb.NewStruct(map[string]any{models.ExtraKeySendAt: "tomorrow at noon"})
+		require.NoError(t, err)
+
+		_, err = resources.NotificationBusiness.QueueOut(ctx, &notificationv1.Notification{
+			Language:  "en",
+			Recipient: &commonv1.ContactLink{ContactId: "epochTesting"},
+			Data:      "Hello we are just testing schedules",
+			Extras:    extras,
+		})
+		require.ErrorIs(t, err, business.ErrorInvalidSendAt, "an unreadable send time must not send the message immediately")
+	})
+}
+
 func (nts *NotificationTestSuite) Test_notificationBusiness_QueueOut() {
 
 	testcases := []struct {
@@ -316,6 +509,957 @@ func (nts *NotificationTestSuite) Test_notificationBusiness_Release() {
 	})
 }
 
+func (nts *NotificationTestSuite) Test_notificationBusiness_Cancel() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		_, ctx, resources := nts.CreateService(t, dep)
+
+		releasedAt := time.Now()
+		scheduledAt := time.Now().Add(time.Hour)
+
+		ids := map[string]string{}
+		for name, status := range map[string]commonv1.STATUS{
+			"scheduled":   commonv1.STATUS_QUEUED,
+			"dispatching": commonv1.STATUS_QUEUED,
+			"published":   commonv1.STATUS_IN_PROCESS,
+		} {
+			n := models.Notification{
+				SenderContactID:  "epochTesting",
+				Message:          "Hello we are just testing cancellations",
+				NotificationType: "email",
+				OutBound:         true,
+				State:            int32(commonv1.STATE_ACTIVE.Number()),
+				Status:           int32(status.Number()),
+				LanguageID:       "9bsv0s23l8og00vgjqa0",
+			}
+			if name == "scheduled" {
+				n.ScheduledAt = &scheduledAt
+			} else {
+				n.ReleasedAt = &releasedAt
+				n.RouteID = "test-route"
+			}
+			if name == "dispatching" {
+				n.DispatchedAt = &releasedAt
+			}
+			n.AccessID = "testingAccessData"
+			n.PartitionID = "test_partition-id"
+			n.TenantID = "test_tenant-id"
+
+			require.NoError(t, resources.NotificationRepo.Create(ctx, &n))
+			ids[name] = n.GetID()
+		}
+
+		_, err := resources.NotificationBusiness.Cancel(ctx, &notificationv1.CancelRequest{})
+		require.Error(t, err, "a cancel request needs ids")
+
+		resultPipe, err := resources.NotificationBusiness.Cancel(ctx, &notificationv1.CancelRequest{
+			Id:      []string{ids["scheduled"], ids["dispatching"], ids["published"], "missingnotification"},
+			Comment: "wrong campaign",
+		})
+		require.NoError(t, err)
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		results := map[string]any{}
+		for {
+			result, ok := resultPipe.ReadResult(timeoutCtx)
+			if !ok {
+				break
+			}
+			require.False(t, result.IsError(), "unexpected cancel error: %v", result.Error())
+			for _, st := range result.Item().GetData() {
+				results[st.GetId()] = st.GetExtras().AsMap()["cancel_result"]
+			}
+		}
+
+		require.Equal(t, map[string]any{
+			ids["scheduled"]:      "canceled",
+			ids["dispatching"]:    "too_late",
+			ids["published"]:      "too_late",
+			"missingnotification": "not_found",
+		}, results)
+
+		canceled, err := resources.NotificationRepo.GetByID(ctx, ids["scheduled"])
+		require.NoError(t, err)
+		require.True(t, canceled.IsCanceled())
+		require.Nil(t, canceled.ScheduledAt, "canceled notifications leave the release schedule")
+
+		claimed, err := resources.NotificationRepo.ClaimDispatch(ctx, canceled, time.Now())
+		require.NoError(t, err)
+		require.False(t, claimed, "canceled notifications are never dispatched")
+
+		published, err := resources.NotificationRepo.GetByID(ctx, ids["published"])
+		require.NoError(t, err)
+		require.False(t, published.IsCanceled())
+	})
+}
+
+func (nts *NotificationTestSuite) Test_notificationBusiness_Suppression() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		_, ctx, resources := nts.CreateService(t, dep)
+
+		_, err := resources.NotificationBusiness.SuppressionAdd(ctx, &notificationv1.SuppressionAddRequest{
+			Data: &notificationv1.Suppression{Channel: "sms"},
+		})
+		require.Error(t, err, "a suppression needs a contact")
+
+		added, err := resources.NotificationBusiness.SuppressionAdd(ctx, &notificationv1.SuppressionAddRequest{
+			Data: &notificationv1.Suppression{
+				Contact: "+256 (700) 123-456",
+				Channel: "sms",
+				Reason:  "opt_out",
+				Source:  "test",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "+256700123456", added.GetContact())
+
+		// Reporting the same opt out again keeps a single suppression.
+		again, err := resources.NotificationBusiness.SuppressionAdd(ctx, &notificationv1.SuppressionAddRequest{
+			Data: &notificationv1.Suppression{Contact: "+256700123456", Channel: "sms", Reason: "unsubscribed"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, added.GetId(), again.GetId())
+
+		matched, err := resources.SuppressionRepo.Match(ctx, "+256700123456", "sms", "any-sender")
+		require.NoError(t, err)
+		require.NotNil(t, matched)
+		require.Equal(t, "unsubscribed", matched.Reason)
+
+		matched, err = resources.SuppressionRepo.Match(ctx, "+256700123456", "email", "any-sender")
+		require.NoError(t, err)
+		require.Nil(t, matched, "suppressions only apply to their channel")
+
+		// An opt out from one sender ID leaves the contact reachable from others, on every route.
+		fromShortcode, err := resources.NotificationBusiness.SuppressionAdd(ctx, &notificationv1.SuppressionAddRequest{
+			Data: &notificationv1.Suppression{Contact: "+256700654321", Channel: "sms", SenderId: "12345", Reason: "opt_out"},
+		})
+		require.NoError(t, err)
+
+		matched, err = resources.SuppressionRepo.Match(ctx, "+256700654321", "sms", "12345")
+		require.NoError(t, err)
+		require.NotNil(t, matched)
+		require.Equal(t, fromShortcode.GetId(), matched.GetID())
+
+		matched, err = resources.SuppressionRepo.Match(ctx, "+256700654321", "sms", "67890")
+		require.NoError(t, err)
+		require.Nil(t, matched, "sender suppressions only apply to their sender")
+
+		removed, err := resources.NotificationBusiness.SuppressionRemove(ctx, &notificationv1.SuppressionRemoveRequest{
+			Contact: "+256700654321", Channel: "sms", SenderId: "12345",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{fromShortcode.GetId()}, removed)
+
+		var found []*notificationv1.Suppression
+		err = resources.NotificationBusiness.SuppressionSearch(ctx, &notificationv1.SuppressionSearchRequest{Query: "+256700", Count: 10},
+			func(_ context.Context, batch []*notificationv1.Suppression) error {
+				found = append(found, batch...)
+				return nil
+			})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+
+		removed, err = resources.NotificationBusiness.SuppressionRemove(ctx, &notificationv1.SuppressionRemoveRequest{Id: added.GetId()})
+		require.NoError(t, err)
+		require.Equal(t, []string{added.GetId()}, removed)
+
+		matched, err = resources.SuppressionRepo.Match(ctx, "+256700123456", "sms", "any-sender")
+		require.NoError(t, err)
+		require.Nil(t, matched)
+	})
+}
+
+// rateLimitTenancy answers partition lookups with the given rate limit rules.
+type rateLimitTenancy struct {
+	tenancyv1connect.TenancyServiceClient
+	rules []any
+}
+
+func (rt *rateLimitTenancy) GetPartition(_ context.Context, req *connect.Request[tenancyv1.GetPartitionRequest]) (*connect.Response[tenancyv1.GetPartitionResponse], error) {
+	properties, err := structpb.NewStruct(map[string]any{events.RateLimitProperty: rt.rules})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&tenancyv1.GetPartitionResponse{
+		Data: &tenancyv1.PartitionObject{Id: req.Msg.GetId(), Properties: properties},
+	}), nil
+}
+
+func (nts *NotificationTestSuite) Test_notificationBusiness_RateLimits() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		svc, ctx, resources := nts.CreateService(t, dep)
+
+		rateLimited := func(partitionID string, rules ...any) (context.Context, business.NotificationBusiness) {
+			nb := business.NewNotificationBusiness(ctx, svc.WorkManager(), svc.EventsManager(), nil,
+				&rateLimitTenancy{rules: rules}, resources.NotificationRepo, resources.NotificationStatusRepo,
+				resources.LanguageRepo, resources.TemplateRepo, resources.TemplateDataRepo, resources.RouteRepo,
+				resources.AggregateRepo, resources.IdempotencyKeyRepo, resources.RateLimitCounterRepo,
+				resources.SuppressionRepo, resources.BroadcastRepo, resources.ExternalIDRepo, "en", time.Hour)
+			return nts.WithAuthClaims(ctx, "rate_limit_tenant", partitionID, "rate_limit_profile"), nb
+		}
+
+		message := func(contact string, autoRelease bool) *notificationv1.Notification {
+			return &notificationv1.Notification{
+				Language:    "en",
+				Recipient:   &commonv1.ContactLink{ContactId: contact},
+				Data:        "Hello we are just testing rate limits",
+				AutoRelease: autoRelease,
+			}
+		}
+
+		t.Run("Reject", func(t *testing.T) {
+			limitedCtx, nb := rateLimited("rate_limit_reject",
+				map[string]any{"scope": "recipient", "limit": 1, "window": "1h"})
+
+			first, err := nb.QueueOut(limitedCtx, message("+256700000001", true))
+			require.NoError(t, err)
+			require.Equal(t, commonv1.STATUS_QUEUED, first.GetStatus())
+
+			second, err := nb.QueueOut(limitedCtx, message("+256700000001", true))
+			require.NoError(t, err)
+			require.Equal(t, commonv1.STATUS_FAILED, second.GetStatus())
+			require.Equal(t, "rate_limited", second.GetExtras().AsMap()["step"])
+
+			other, err := nb.QueueOut(limitedCtx, message("+256700000002", true))
+			require.NoError(t, err)
+			require.Equal(t, commonv1.STATUS_QUEUED, other.GetStatus(), "other recipients have their own limit")
+		})
+
+		t.Run("Defer", func(t *testing.T) {
+			limitedCtx, nb := rateLimited("rate_limit_defer",
+				map[string]any{"scope": "partition", "limit": 1, "window": "1h", "action": "defer"})
+
+			_, err := nb.QueueOut(limitedCtx, message("+256700000003", true))
+			require.NoError(t, err)
+
+			deferred, err := nb.QueueOut(limitedCtx, message("+256700000004", true))
+			require.NoError(t, err)
+			extras := deferred.GetExtras().AsMap()
+			require.Equal(t, "rate_limit_deferred", extras["step"])
+			require.NotEmpty(t, extras["scheduled_at"], "automatic sends move to the next free window")
+
+			require.Eventually(t, func() bool {
+				n, gErr := resources.NotificationRepo.GetByID(limitedCtx, deferred.GetId())
+				return gErr == nil && n.IsScheduled() && !n.IsReleased() && n.ScheduledAt.After(time.Now())
+			}, 10*time.Second, 100*time.Millisecond)
+
+			manual, err := nb.QueueOut(limitedCtx, message("+256700000005", false))
+			require.NoError(t, err)
+			extras = manual.GetExtras().AsMap()
+			require.Equal(t, "rate_limit_deferred", extras["step"])
+			require.NotEmpty(t, extras["deferred_until"])
+			require.NotContains(t, extras, "scheduled_at", "manual sends keep waiting for their release")
+
+			require.Eventually(t, func() bool {
+				n, gErr := resources.NotificationRepo.GetByID(limitedCtx, manual.GetId())
+				if gErr != nil || n.IsScheduled() || n.IsReleased() {
+					return false
+				}
+				statuses, sErr := resources.NotificationStatusRepo.GetByNotificationID(limitedCtx, manual.GetId())
+				return sErr == nil && len(statuses) > 0
+			}, 10*time.Second, 100*time.Millisecond)
+
+			resultPipe, err := nb.Release(limitedCtx, &notificationv1.ReleaseRequest{Id: []string{manual.GetId()}})
+			require.NoError(t, err)
+			result, ok := resultPipe.ReadResult(limitedCtx)
+			require.True(t, ok)
+			require.False(t, result.IsError())
+			require.Len(t, result.Item().GetData(), 1)
+			require.Equal(t, extras["deferred_until"], result.Item().GetData()[0].GetExtras().AsMap()["scheduled_at"],
+				"releasing a deferred send leaves it to the scheduler for its window")
+
+			require.Eventually(t, func() bool {
+				n, gErr := resources.NotificationRepo.GetByID(limitedCtx, manual.GetId())
+				return gErr == nil && !n.IsReleased() && n.IsScheduled() && n.ScheduledAt.After(time.Now())
+			}, 10*time.Second, 100*time.Millisecond)
+		})
+
+		t.Run("GiveBack", func(t *testing.T) {
+			limitedCtx, nb := rateLimited("rate_limit_give_back",
+				map[string]any{"scope": "partition", "limit": 10, "window": "1h"},
+				map[string]any{"scope": "recipient", "limit": 1, "window": "1h"})
+
+			_, err := nb.QueueOut(limitedCtx, message("+256700000006", true))
+			require.NoError(t, err)
+
+			rejected, err := nb.QueueOut(limitedCtx, message("+256700000006", true))
+			require.NoError(t, err)
+			require.Equal(t, commonv1.STATUS_FAILED, rejected.GetStatus())
+
+			partitionRule := &events.RateLimitRule{Scope: events.RateLimitScopePartition, Window: time.Hour}
+			partitionKey := partitionRule.CounterKey(&models.Notification{BaseModel: data.BaseModel{PartitionID: "rate_limit_give_back"}}, "")
+
+			counted, err := resources.RateLimitCounterRepo.CountBy(limitedCtx, map[string]any{"key": partitionKey, "count": 1})
+			require.NoError(t, err)
+			require.EqualValues(t, 1, counted, "a rejected send gives back the slots it already took")
+		})
+	})
+}
+
+// templateVariables declares string template variables, the required ones marked with a leading "!".
+func templateVariables(t *testing.T, names ...string) *structpb.Struct {
+	variables := map[string]any{}
+	for _, name := range names {
+		required := strings.HasPrefix(name, "!")
+		variables[strings.TrimPrefix(name, "!")] = map[string]any{"type": "string", "required": required}
+	}
+
+	extra, err := structpb.NewStruct(map[string]any{models.TemplateExtraVariables: variables})
+	require.NoError(t, err)
+	return extra
+}
+
+func (nts *NotificationTestSuite) Test_notificationBusiness_TemplateVersions() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		_, ctx, resources := nts.CreateService(t, dep)
+
+		save := func(text string) *notificationv1.Template {
+			content, err := structpb.NewStruct(map[string]any{"text": text})
+			require.NoError(t, err)
+
+			saved, err := resources.NotificationBusiness.TemplateSave(ctx, &notificationv1.TemplateSaveRequest{
+				Name:         "template.versioning.test",
+				LanguageCode: "en",
+				Data:         content,
+				Extra:        templateVariables(t, "code"),
+			})
+			require.NoError(t, err)
+			require.Equal(t, models.TemplateStateDraft, saved.GetState())
+			return saved
+		}
+
+		first := save("first {{.code}}")
+		second := save("second {{.code}}")
+		require.Equal(t, int32(1), first.GetVersion())
+		require.Equal(t, int32(2), second.GetVersion())
+
+		published, err := resources.TemplateRepo.GetByName(ctx, "template.versioning.test")
+		require.NoError(t, err)
+		require.Empty(t, published.GetID(), "drafts are not rendered")
+
+		_, err = resources.NotificationBusiness.TemplateRollback(ctx, &notificationv1.TemplateRollbackRequest{
+			Name: "template.versioning.test", Version: 2,
+		})
+		require.Error(t, err, "a draft was never live to roll back to")
+
+		_, err = resources.NotificationBusiness.QueueOut(ctx, &notificationv1.Notification{
+			Language:  "en",
+			Recipient: &commonv1.ContactLink{ContactId: "epochTesting"},
+			Template:  "template.versioning.test",
+		})
+		require.Equal(t, codes.FailedPrecondition, status.Code(err), "a template only drafted has nothing to send")
+
+		_, err = resources.NotificationBusiness.QueueOut(ctx, &notificationv1.Notification{
+			Language:  "en",
+			Recipient: &commonv1.ContactLink{ContactId: "epochTesting"},
+			Template:  "template.versioning.unknown",
+		})
+		require.Equal(t, codes.NotFound, status.Code(err))
+
+		_, err = resources.NotificationBusiness.TemplatePublish(ctx, &notificationv1.TemplatePublishRequest{Id: first.GetId()})
+		require.NoError(t, err)
+
+		queued, err := resources.NotificationBusiness.QueueOut(ctx, &notificationv1.Notification{
+			Language:  "en",
+			Recipient: &commonv1.ContactLink{ContactId: "epochTesting"},
+			Template:  "template.versioning.test",
+		})
+		require.NoError(t, err)
+
+		n, err := resources.NotificationRepo.GetByID(ctx, queued.GetId())
+		require.NoError(t, err)
+		require.Equal(t, first.GetId(), n.TemplateID)
+		require.False(t, n.TemplatePinned)
+
+		live, err := resources.NotificationBusiness.TemplatePublish(ctx, &notificationv1.TemplatePublishRequest{Id: second.GetId()})
+		require.NoError(t, err)
+		require.Equal(t, models.TemplateStatePublished, live.GetState())
+		require.NotEmpty(t, live.GetPublishedAt())
+
+		retired, err := resources.TemplateRepo.GetByID(ctx, first.GetId())
+		require.NoError(t, err)
+		require.Equal(t, models.TemplateStateRetired, retired.State)
+
+		pinned, err := resources.NotificationBusiness.QueueOut(ctx, &notificationv1.Notification{
+			Language:  "en",
+			Recipient: &commonv1.ContactLink{ContactId: "epochTesting"},
+			Template:  first.GetId(),
+		})
+		require.NoError(t, err)
+
+		n, err = resources.NotificationRepo.GetByID(ctx, pinned.GetId())
+		require.NoError(t, err)
+		require.Equal(t, first.GetId(), n.TemplateID)
+		require.Equal(t, 1, n.TemplateRevision)
+		require.True(t, n.TemplatePinned, "a version id pins the notification to it")
+
+		rolledBack, err := resources.NotificationBusiness.TemplateRollback(ctx, &notificationv1.TemplateRollbackRequest{
+			Name: "template.versioning.test",
+		})
+		require.NoError(t, err)
+		require.Equal(t, first.GetId(), rolledBack.GetId())
+
+		published, err = resources.TemplateRepo.GetByName(ctx, "template.versioning.test")
+		require.NoError(t, err)
+		require.Equal(t, first.GetId(), published.GetID())
+
+		retired, err = resources.TemplateRepo.GetByID(ctx, second.GetId())
+		require.NoError(t, err)
+		require.Equal(t, models.TemplateStateRetired, retired.State)
+	})
+}
+
+func (nts *NotificationTestSuite) Test_notificationBusiness_TemplateRevisions() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		_, ctx, resources := nts.CreateService(t, dep)
+
+		content, err := structpb.NewStruct(map[string]any{"text": "hello {{.name}}"})
+		require.NoError(t, err)
+		saveReq := &notificationv1.TemplateSaveRequest{
+			Name:         "template.revisions.test",
+			LanguageCode: "en",
+			Data:         content,
+			Extra:        templateVariables(t, "name"),
+		}
+
+		const saves = 4
+		versions := make(chan int32, saves)
+		errs := make(chan error, saves)
+		var wg sync.WaitGroup
+		for range saves {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				saved, saveErr := resources.NotificationBusiness.TemplateSave(ctx, saveReq)
+				if saveErr != nil {
+					errs <- saveErr
+					return
+				}
+				versions <- saved.GetVersion()
+			}()
+		}
+		wg.Wait()
+		close(versions)
+		close(errs)
+
+		for saveErr := range errs {
+			require.NoError(t, saveErr)
+		}
+		var taken []int32
+		for version := range versions {
+			taken = append(taken, version)
+		}
+		require.ElementsMatch(t, []int32{1, 2, 3, 4}, taken, "concurrent saves each take their own revision")
+
+		revisions, err := resources.TemplateRepo.GetRevisions(ctx, "template.revisions.test")
+		require.NoError(t, err)
+		_, err = resources.NotificationBusiness.TemplateDelete(ctx, &notificationv1.TemplateDeleteRequest{Id: revisions[0].GetID()})
+		require.NoError(t, err)
+
+		saved, err := resources.NotificationBusiness.TemplateSave(ctx, saveReq)
+		require.NoError(t, err)
+		require.Equal(t, int32(5), saved.GetVersion(), "deleted revisions are not reused")
+	})
+}
+
+func (nts *NotificationTestSuite) Test_notificationBusiness_TemplateUpdateDelete() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		_, ctx, resources := nts.CreateService(t, dep)
+
+		content, err := structpb.NewStruct(map[string]any{"text": "hello {{.name}}"})
+		require.NoError(t, err)
+
+		draft, err := resources.NotificationBusiness.TemplateSave(ctx, &notificationv1.TemplateSaveRequest{
+			Name:         "template.update.test",
+			LanguageCode: "en",
+			Data:         content,
+			Extra:        templateVariables(t, "name"),
+		})
+		require.NoError(t, err)
+
+		updated, err := resources.NotificationBusiness.TemplateUpdate(ctx, &notificationv1.TemplateUpdateRequest{
+			Id: draft.GetId(),
+			Data: []*notificationv1.TemplateDataChange{
+				{LanguageCode: "en", Type: "text", Detail: "hi {{.name}}"},
+				{LanguageCode: "fr", Type: "text", Detail: "salut {{.name}}"},
+				{LanguageCode: "fr", Type: "email", Detail: "bonjour {{.name}}"},
+			},
+		})
+		require.NoError(t, err)
+
+		details := map[string]string{}
+		for _, tData := range updated.GetData() {
+			details[tData.GetLanguage().GetCode()+"/"+tData.GetType()] = tData.GetDetail()
+		}
+		require.Equal(t, map[string]string{
+			"en/text":  "hi {{.name}}",
+			"fr/text":  "salut {{.name}}",
+			"fr/email": "bonjour {{.name}}",
+		}, details, "replacing content keeps a single row per language and type")
+
+		updated, err = resources.NotificationBusiness.TemplateUpdate(ctx, &notificationv1.TemplateUpdateRequest{
+			Id:   draft.GetId(),
+			Data: []*notificationv1.TemplateDataChange{{LanguageCode: "fr", Type: "email", Remove: true}},
+		})
+		require.NoError(t, err)
+		require.Len(t, updated.GetData(), 2)
+
+		_, err = resources.NotificationBusiness.TemplateUpdate(ctx, &notificationv1.TemplateUpdateRequest{
+			Id:   draft.GetId(),
+			Data: []*notificationv1.TemplateDataChange{{LanguageCode: "fr", Type: "email", Remove: true}},
+		})
+		require.Error(t, err, "removing missing content fails")
+
+		_, err = resources.NotificationBusiness.TemplateUpdate(ctx, &notificationv1.TemplateUpdateRequest{
+			Id: draft.GetId(),
+			Data: []*notificationv1.TemplateDataChange{
+				{LanguageCode: "en", Type: "text", Detail: "half applied {{.name}}"},
+				{LanguageCode: "fr", Type: "email", Remove: true},
+			},
+		})
+		require.Error(t, err)
+
+		unchanged, err := resources.TemplateDataRepo.GetByTemplateID(ctx, draft.GetId())
+		require.NoError(t, err)
+		for _, tData := range unchanged {
+			require.NotContains(t, tData.Detail, "half applied", "a failed update changes nothing")
+		}
+
+		_, err = resources.NotificationBusiness.TemplatePublish(ctx, &notificationv1.TemplatePublishRequest{Id: draft.GetId()})
+		require.NoError(t, err)
+
+		_, err = resources.NotificationBusiness.TemplateUpdate(ctx, &notificationv1.TemplateUpdateRequest{
+			Id:   draft.GetId(),
+			Data: []*notificationv1.TemplateDataChange{{LanguageCode: "en", Type: "text", Detail: "live edit"}},
+		})
+		require.Error(t, err, "published versions can not be changed")
+
+		_, err = resources.NotificationBusiness.QueueOut(ctx, &notificationv1.Notification{
+			Language:  "en",
+			Recipient: &commonv1.ContactLink{ContactId: "epochTesting"},
+			Template:  "template.update.test",
+		})
+		require.NoError(t, err)
+
+		_, err = resources.NotificationBusiness.TemplateDelete(ctx, &notificationv1.TemplateDeleteRequest{Id: draft.GetId()})
+		require.Error(t, err, "an unreleased notification still renders the template")
+
+		unused, err := resources.NotificationBusiness.TemplateSave(ctx, &notificationv1.TemplateSaveRequest{
+			Name:         "template.update.test",
+			LanguageCode: "en",
+			Data:         content,
+			Extra:        templateVariables(t, "name"),
+		})
+		require.NoError(t, err)
+
+		removed, err := resources.NotificationBusiness.TemplateDelete(ctx, &notificationv1.TemplateDeleteRequest{Id: unused.GetId()})
+		require.NoError(t, err)
+		require.Len(t, removed, 2, "the version and its content are removed")
+		require.Equal(t, unused.GetId(), removed[0])
+
+		_, err = resources.TemplateRepo.GetByID(ctx, unused.GetId())
+		require.Error(t, err)
+	})
+}
+
+func (nts *NotificationTestSuite) Test_notificationBusiness_TemplatePreview() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		_, ctx, resources := nts.CreateService(t, dep)
+
+		notificationsBefore, err := resources.NotificationRepo.Count(ctx)
+		require.NoError(t, err)
+
+		save := func(text string, publish bool) *notificationv1.Template {
+			content, contentErr := structpb.NewStruct(map[string]any{"text": text})
+			require.NoError(t, contentErr)
+
+			saved, saveErr := resources.NotificationBusiness.TemplateSave(ctx, &notificationv1.TemplateSaveRequest{
+				Name:         "template.preview.test",
+				LanguageCode: "en",
+				Data:         content,
+				Extra:        templateVariables(t, "!code", "!expiryDate"),
+				Publish:      publish,
+			})
+			require.NoError(t, saveErr)
+			return saved
+		}
+
+		save("code {{.code}} expires {{.expiryDate}}", true)
+		draft := save("draft {{.code}}", false)
+
+		payload, err := structpb.NewStruct(map[string]any{"code": "1234", "expiryDate": "tomorrow"})
+		require.NoError(t, err)
+
+		preview, err := resources.NotificationBusiness.TemplatePreview(ctx, &notificationv1.TemplatePreviewRequest{
+			Template:     "template.preview.test",
+			LanguageCode: "en",
+			Payload:      payload,
+		})
+		require.NoError(t, err)
+		require.Empty(t, preview.GetErrors())
+		require.Equal(t, "code 1234 expires tomorrow", preview.GetRendered()["text"], "a name previews its published version")
+		require.Equal(t, int32(1), preview.GetTemplate().GetVersion())
+		require.Equal(t, "en", preview.GetLanguageCode())
+
+		preview, err = resources.NotificationBusiness.TemplatePreview(ctx, &notificationv1.TemplatePreviewRequest{
+			Template:     "template.preview.test",
+			LanguageCode: "sw-KE",
+			Payload:      payload,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "code 1234 expires tomorrow", preview.GetRendered()["text"],
+			"a language without content previews the one notifications fall back to")
+		require.Equal(t, "en", preview.GetLanguageCode())
+
+		partial, err := structpb.NewStruct(map[string]any{"code": "1234"})
+		require.NoError(t, err)
+
+		preview, err = resources.NotificationBusiness.TemplatePreview(ctx, &notificationv1.TemplatePreviewRequest{
+			Template: "template.preview.test",
+			Payload:  partial,
+		})
+		require.NoError(t, err)
+		require.NotContains(t, preview.GetRendered(), "text", "content missing variables is not rendered")
+
+		var reported []string
+		for _, previewErr := range preview.GetErrors() {
+			reported = append(reported, previewErr.GetType()+":"+previewErr.GetVariable())
+		}
+		require.ElementsMatch(t, []string{":expiryDate", "text:expiryDate"}, reported,
+			"the required variable is reported against the payload and the content it keeps from rendering")
+
+		preview, err = resources.NotificationBusiness.TemplatePreview(ctx, &notificationv1.TemplatePreviewRequest{
+			Template: draft.GetId(),
+			Payload:  partial,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "draft 1234", preview.GetRendered()["text"])
+
+		_, err = resources.NotificationBusiness.TemplatePreview(ctx, &notificationv1.TemplatePreviewRequest{
+			Template: "template.preview.unknown",
+			Payload:  payload,
+		})
+		require.Error(t, err)
+
+		notificationsAfter, err := resources.NotificationRepo.Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, notificationsBefore, notificationsAfter, "previews do not create notifications")
+	})
+}
+
+// fieldViolations returns the fields a business error reports as invalid.
+func fieldViolations(t *testing.T, err error) []string {
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+
+	var fields []string
+	for _, detail := range st.Details() {
+		if badRequest, isBadRequest := detail.(*errdetails.BadRequest); isBadRequest {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields = append(fields, violation.GetField())
+			}
+		}
+	}
+	return fields
+}
+
+func (nts *NotificationTestSuite) Test_notificationBusiness_TemplateVariables() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		_, ctx, resources := nts.CreateService(t, dep)
+
+		content, err := structpb.NewStruct(map[string]any{"sms": "code {{.code}}, balance {{.balance}}"})
+		require.NoError(t, err)
+
+		_, err = resources.NotificationBusiness.TemplateSave(ctx, &notificationv1.TemplateSaveRequest{
+			Name:         "template.variables.test",
+			LanguageCode: "en",
+			Data:         content,
+			Extra:        templateVariables(t, "!code"),
+		})
+		require.Error(t, err, "balance is not declared")
+		require.Equal(t, []string{"data.sms"}, fieldViolations(t, err))
+
+		_, err = resources.NotificationBusiness.TemplateSave(ctx, &notificationv1.TemplateSaveRequest{
+			Name:         "template.variables.undeclared",
+			LanguageCode: "en",
+			Data:         content,
+		})
+		require.NoError(t, err, "templates declaring no variables are not checked")
+
+		support, err := structpb.NewStruct(map[string]any{"sms": "code {{.code}}, call {{.support_phone}}"})
+		require.NoError(t, err)
+		_, err = resources.NotificationBusiness.TemplateSave(ctx, &notificationv1.TemplateSaveRequest{
+			Name:         "template.variables.support",
+			LanguageCode: "en",
+			Data:         support,
+			Extra:        templateVariables(t, "!code"),
+		})
+		require.NoError(t, err, "support contacts need no declaration")
+
+		invalid, err := structpb.NewStruct(map[string]any{
+			models.TemplateExtraVariables: map[string]any{"code": map[string]any{"type": "uuid"}},
+		})
+		require.NoError(t, err)
+		_, err = resources.NotificationBusiness.TemplateSave(ctx, &notificationv1.TemplateSaveRequest{
+			Name:         "template.variables.test",
+			LanguageCode: "en",
+			Data:         content,
+			Extra:        invalid,
+		})
+		require.Equal(t, []string{"extra.variables"}, fieldViolations(t, err))
+
+		extra, err := structpb.NewStruct(map[string]any{
+			models.TemplateExtraVariables: map[string]any{
+				"code":    map[string]any{"type": "string", "required": true},
+				"balance": map[string]any{"type": "money"},
+			},
+		})
+		require.NoError(t, err)
+		_, err = resources.NotificationBusiness.TemplateSave(ctx, &notificationv1.TemplateSaveRequest{
+			Name:         "template.variables.test",
+			LanguageCode: "en",
+			Data:         content,
+			Extra:        extra,
+			Publish:      true,
+		})
+		require.NoError(t, err)
+
+		notificationsBefore, err := resources.NotificationRepo.Count(ctx)
+		require.NoError(t, err)
+
+		queue := func(payload map[string]any) error {
+			payloadStruct, structErr := structpb.NewStruct(payload)
+			require.NoError(t, structErr)
+
+			_, queueErr := resources.NotificationBusiness.QueueOut(ctx, &notificationv1.Notification{
+				Language:  "en",
+				Recipient: &commonv1.ContactLink{ContactId: "epochTesting"},
+				Template:  "template.variables.test",
+				Payload:   payloadStruct,
+			})
+			return queueErr
+		}
+
+		err = queue(map[string]any{"balance": "KES 100"})
+		require.Equal(t, []string{"payload.code"}, fieldViolations(t, err))
+
+		err = queue(map[string]any{"code": "1234", "balance": "plenty"})
+		require.Equal(t, []string{"payload.balance"}, fieldViolations(t, err))
+
+		notificationsAfter, err := resources.NotificationRepo.Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, notificationsBefore, notificationsAfter, "rejected payloads queue nothing")
+
+		require.NoError(t, queue(map[string]any{"code": "1234"}), "optional variables may be left out")
+
+		draft, err := resources.NotificationBusiness.TemplateSave(ctx, &notificationv1.TemplateSaveRequest{
+			Name:         "template.variables.test",
+			LanguageCode: "en",
+			Data:         content,
+			Extra:        extra,
+		})
+		require.NoError(t, err)
+
+		_, err = resources.NotificationBusiness.TemplateUpdate(ctx, &notificationv1.TemplateUpdateRequest{
+			Id:    draft.GetId(),
+			Extra: templateVariables(t, "!code"),
+		})
+		require.Equal(t, []string{"data.sms"}, fieldViolations(t, err), "the content still reads balance")
+
+		_, err = resources.NotificationBusiness.TemplateUpdate(ctx, &notificationv1.TemplateUpdateRequest{
+			Id:    draft.GetId(),
+			Extra: templateVariables(t, "!code"),
+			Data:  []*notificationv1.TemplateDataChange{{LanguageCode: "en", Type: "sms", Detail: "code {{.code}}"}},
+		})
+		require.NoError(t, err, "content replaced in the same update is checked as replaced")
+	})
+}
+
+func (nts *NotificationTestSuite) Test_notificationBusiness_Broadcast() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		_, ctx, resources := nts.CreateService(t, dep)
+
+		message := &notificationv1.Notification{
+			Language: "en",
+			Data:     "Hello everyone, we are just testing broadcasts",
+			Type:     "sms",
+		}
+
+		_, err := resources.NotificationBusiness.Broadcast(ctx, &notificationv1.BroadcastRequest{Data: message})
+		require.Error(t, err, "a broadcast needs an audience")
+
+		_, err = resources.NotificationBusiness.Broadcast(ctx, &notificationv1.BroadcastRequest{
+			Data: message, ProfileIds: []string{"profile-a"}, PartitionAudience: true})
+		require.Error(t, err, "a broadcast takes a single kind of audience")
+
+		resultPipe, err := resources.NotificationBusiness.Broadcast(ctx, &notificationv1.BroadcastRequest{
+			Data:       message,
+			ProfileIds: []string{"profile-a", "profile-b", "profile-c"},
+		})
+		require.NoError(t, err)
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		var last *commonv1.StatusResponse
+		for {
+			result, ok := resultPipe.ReadResult(timeoutCtx)
+			if !ok {
+				break
+			}
+			require.False(t, result.IsError(), "unexpected broadcast error: %v", result.Error())
+			last = result.Item().GetData()
+		}
+
+		require.NotNil(t, last)
+		require.Equal(t, commonv1.STATUS_SUCCESSFUL, last.GetStatus())
+		extras := last.GetExtras().AsMap()
+		require.Equal(t, "broadcast_expanded", extras["step"])
+		require.EqualValues(t, 3, extras["queued"])
+		require.EqualValues(t, 0, extras["failed"])
+
+		// An expansion that stopped before saving its last page resumes from the saved
+		// cursor, recipients queued again keep their original child.
+		broadcast, err := resources.BroadcastRepo.GetByID(ctx, last.GetId())
+		require.NoError(t, err)
+		broadcast.Cursor = ""
+		broadcast.ExpandedAt = nil
+		require.NoError(t, resources.BroadcastRepo.SaveProgress(ctx, broadcast))
+
+		require.NoError(t, resources.NotificationBusiness.ExpandBroadcast(ctx, broadcast.GetID()))
+
+		children, err := resources.NotificationRepo.CountBy(ctx, map[string]any{"parent_id": broadcast.GetID()})
+		require.NoError(t, err)
+		require.EqualValues(t, 3, children)
+
+		broadcast, err = resources.BroadcastRepo.GetByID(ctx, broadcast.GetID())
+		require.NoError(t, err)
+		require.True(t, broadcast.IsExpanded())
+	})
+}
+
+func (nts *NotificationTestSuite) Test_releaseScheduler_ReleaseDue() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		svc, ctx, resources := nts.CreateService(t, dep)
+
+		due := time.Now().Add(-time.Minute)
+		later := time.Now().Add(time.Hour)
+
+		scheduled := map[string]*time.Time{"due": &due, "later": &later}
+		ids := map[string]string{}
+		for name, at := range scheduled {
+			n := models.Notification{
+				SenderContactID:  "epochTesting",
+				Message:          "Hello we are just testing scheduled releases",
+				NotificationType: "email",
+				OutBound:         true,
+				State:            int32(commonv1.STATE_CREATED.Number()),
+				LanguageID:       "9bsv0s23l8og00vgjqa0",
+				ScheduledAt:      at,
+			}
+			n.AccessID = "testingAccessData"
+			n.PartitionID = "test_partition-id"
+			n.TenantID = "test_tenant-id"
+
+			require.NoError(t, resources.NotificationRepo.Create(ctx, &n))
+			ids[name] = n.GetID()
+		}
+
+		scheduler := business.NewReleaseScheduler(svc.Name(), time.Minute, 10, svc.EventsManager(),
+			resources.NotificationRepo, resources.NotificationStatusRepo, resources.IdempotencyKeyRepo, resources.RateLimitCounterRepo)
+
+		released, err := scheduler.ReleaseDue(ctx)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, released, 1)
+
+		dueN, err := resources.NotificationRepo.GetByID(ctx, ids["due"])
+		require.NoError(t, err)
+		require.True(t, dueN.IsReleased(), "due notification should be released")
+
+		// The release is saved before routing is asked to pick the notification up.
+		releasedStatus, err := resources.NotificationStatusRepo.GetByID(ctx, dueN.StatusID)
+		require.NoError(t, err)
+		require.Equal(t, "released_on_schedule", releasedStatus.Extra["step"])
+
+		laterN, err := resources.NotificationRepo.GetByID(ctx, ids["later"])
+		require.NoError(t, err)
+		require.False(t, laterN.IsReleased(), "future notification should stay scheduled")
+		require.True(t, laterN.IsScheduled())
+
+		released, err = scheduler.ReleaseDue(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 0, released, "already released notifications must not be claimed again")
+	})
+}
+
+// failingRouteEmitter refuses to hand notifications over for routing.
+type failingRouteEmitter struct {
+	fevents.Manager
+}
+
+func (fe *failingRouteEmitter) Emit(ctx context.Context, name string, payload any) error {
+	if name == events.NotificationOutRouteEvent {
+		return errors.New("queue unavailable")
+	}
+	return fe.Manager.Emit(ctx, name, payload)
+}
+
+func (nts *NotificationTestSuite) Test_releaseScheduler_ReleaseDueHandOverFails() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		svc, ctx, resources := nts.CreateService(t, dep)
+
+		due := time.Now().Add(-time.Minute)
+		n := models.Notification{
+			SenderContactID:  "epochTesting",
+			Message:          "Hello we are just testing failed releases",
+			NotificationType: "email",
+			OutBound:         true,
+			State:            int32(commonv1.STATE_CREATED.Number()),
+			LanguageID:       "9bsv0s23l8og00vgjqa0",
+			ScheduledAt:      &due,
+		}
+		n.AccessID = "testingAccessData"
+		n.PartitionID = "test_partition-id"
+		n.TenantID = "test_tenant-id"
+		require.NoError(t, resources.NotificationRepo.Create(ctx, &n))
+
+		scheduler := business.NewReleaseScheduler(svc.Name(), time.Minute, 10,
+			&failingRouteEmitter{Manager: svc.EventsManager()}, resources.NotificationRepo, resources.NotificationStatusRepo, resources.IdempotencyKeyRepo, resources.RateLimitCounterRepo)
+
+		_, err := scheduler.ReleaseDue(ctx)
+		require.Error(t, err)
+
+		saved, err := resources.NotificationRepo.GetByID(ctx, n.GetID())
+		require.NoError(t, err)
+		require.False(t, saved.IsReleased(), "a notification that was not handed over goes back on the schedule")
+		require.True(t, saved.IsScheduled())
+	})
+}
+
 func (nts *NotificationTestSuite) Test_notificationBusiness_Search() {
 
 	templateExtra, _ := structpb.NewStruct(map[string]any{"template_id": "9bsv0s23l8og00vemail"})
@@ -396,6 +1540,12 @@ func (nts *NotificationTestSuite) Test_notificationBusiness_Search() {
 				t.Logf("Received batch of %d notifications, total so far: %d", len(notifications), resultCount)
 
 				require.GreaterOrEqual(t, resultCount, tt.leastCount, "Search() expected at least %d results", tt.leastCount)
+
+				for _, found := range notifications {
+					if found.GetId() == n.GetID() {
+						require.Equal(t, nStatus.GetID(), found.GetStatus().GetId(), "each notification carries its own status")
+					}
+				}
 			})
 		}
 
@@ -561,6 +1711,57 @@ func (nts *NotificationTestSuite) Test_notificationBusiness_StatusUpdate() {
 	})
 }
 
+func (nts *NotificationTestSuite) Test_notificationBusiness_StatusUpdateByExternalID() {
+
+	nts.WithTestDependancies(nts.T(), func(t *testing.T, dep *definition.DependencyOption) {
+
+		_, ctx, resources := nts.CreateService(t, dep)
+
+		releaseDate := time.Now()
+		n := models.Notification{
+			SenderContactID:  "epochTesting",
+			Message:          "A message long enough to be sent in several parts",
+			NotificationType: "sms",
+			State:            int32(commonv1.STATE_ACTIVE.Number()),
+			ReleasedAt:       &releaseDate,
+		}
+		n.AccessID = "testingAccessData"
+		n.PartitionID = "test_partition-id"
+		require.NoError(t, resources.NotificationRepo.Create(ctx, &n))
+
+		submitExtras, err := structpb.NewStruct(map[string]any{
+			constants.StatusExtraMessageIDs: []any{"part-1", "part-2", "part-3"},
+		})
+		require.NoError(t, err)
+
+		_, err = resources.NotificationBusiness.StatusUpdate(ctx, &commonv1.StatusUpdateRequest{
+			Id:         n.GetID(),
+			State:      commonv1.STATE_ACTIVE,
+			Status:     commonv1.STATUS_QUEUED,
+			ExternalId: "part-1",
+			Extras:     submitExtras,
+		})
+		require.NoError(t, err)
+
+		// A delivery report for a later part only knows the identifier the gateway gave that part.
+		got, err := resources.NotificationBusiness.StatusUpdate(ctx, &commonv1.StatusUpdateRequest{
+			State:      commonv1.STATE_INACTIVE,
+			Status:     commonv1.STATUS_SUCCESSFUL,
+			ExternalId: "part-2",
+		})
+		require.NoError(t, err)
+		require.Equal(t, n.GetID(), got.GetId())
+		require.Equal(t, "part-2", got.GetExternalId())
+
+		_, err = resources.NotificationBusiness.StatusUpdate(ctx, &commonv1.StatusUpdateRequest{
+			State:      commonv1.STATE_INACTIVE,
+			Status:     commonv1.STATUS_SUCCESSFUL,
+			ExternalId: "unknown-part",
+		})
+		require.Error(t, err)
+	})
+}
+
 // func (nts *NotificationTestSuite) Test_notificationBusiness_TemplateSearch() {
 //
 //	t := nts.T()
@@ -0,0 +1,177 @@
+package business
+
+import (
+	"context"
+	"time"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	tenancyv1 "buf.build/gen/go/antinvestor/tenancy/protocolbuffers/go/tenancy/v1"
+	"connectrpc.com/connect"
+	"github.com/antinvestor/service-notification/apps/default/service/events"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/pitabwire/frame/v2/data"
+	"github.com/pitabwire/util"
+)
+
+// rateLimitMaxDeferWindows bounds how many windows ahead a deferred send may be pushed
+// before it is rejected instead.
+const rateLimitMaxDeferWindows = 24
+
+// rateLimitTaken is one window slot counted against a rule.
+type rateLimitTaken struct {
+	key         string
+	windowStart time.Time
+}
+
+// rateLimitDecision is the outcome of applying the partition rate limits to a send.
+type rateLimitDecision struct {
+	rejectedBy *events.RateLimitRule
+	deferredBy *events.RateLimitRule
+	deferUntil *time.Time
+}
+
+// applyRateLimits counts a send against every partition rate limit that applies to it.
+// Limits are best effort, a send is let through when the partition rules cannot be loaded.
+func (nb *notificationBusiness) applyRateLimits(ctx context.Context, n *models.Notification, templateName string) (*rateLimitDecision, error) {
+	decision := &rateLimitDecision{}
+
+	rules := nb.partitionRateLimits(ctx, n.PartitionID)
+	if len(rules) == 0 {
+		return decision, nil
+	}
+
+	at := time.Now()
+	if n.IsScheduled() && n.ScheduledAt.After(at) {
+		at = *n.ScheduledAt
+	}
+
+	var taken []rateLimitTaken
+	for _, rule := range rules {
+		key := rule.CounterKey(n, templateName)
+		if key == "" {
+			continue
+		}
+
+		windows := 1
+		if rule.Action == events.RateLimitActionDefer {
+			windows = rateLimitMaxDeferWindows
+		}
+
+		fitted := false
+		windowStart := rule.WindowStart(at)
+		for i := 0; i < windows && !fitted; i++ {
+			counter := &models.RateLimitCounter{Key: key, WindowStart: windowStart, WindowEnd: windowStart.Add(rule.Window)}
+			counter.CopyPartitionInfo(&n.BaseModel)
+			counter.GenID(ctx)
+
+			ok, err := nb.rateLimitCounterRepo.Take(ctx, counter, rule.Limit)
+			if err != nil {
+				nb.giveBackRateLimits(ctx, taken)
+				return nil, err
+			}
+			if !ok {
+				windowStart = windowStart.Add(rule.Window)
+				continue
+			}
+
+			fitted = true
+			taken = append(taken, rateLimitTaken{key: key, windowStart: windowStart})
+			if windowStart.After(at) && (decision.deferUntil == nil || windowStart.After(*decision.deferUntil)) {
+				deferUntil := windowStart
+				decision.deferUntil = &deferUntil
+				decision.deferredBy = rule
+			}
+		}
+
+		if !fitted {
+			nb.giveBackRateLimits(ctx, taken)
+			return &rateLimitDecision{rejectedBy: rule}, nil
+		}
+	}
+
+	return decision, nil
+}
+
+// rateLimitDeferral returns the window a send waiting for Release was deferred to by its rate
+// limit, or nil once that window has started. Sends released automatically are scheduled for
+// the window straight away and never carry one.
+func (nb *notificationBusiness) rateLimitDeferral(ctx context.Context, n *models.Notification) (*time.Time, error) {
+	statuses, err := nb.notificationStatusRepo.GetByNotificationID(ctx, n.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	var deferredUntil *time.Time
+	for _, nStatus := range statuses {
+		until, parseErr := time.Parse(time.RFC3339, nStatus.Extra.GetString("deferred_until"))
+		if parseErr != nil {
+			continue
+		}
+		if deferredUntil == nil || until.After(*deferredUntil) {
+			deferredUntil = &until
+		}
+	}
+
+	if deferredUntil == nil || !deferredUntil.After(time.Now()) {
+		return nil, nil
+	}
+	return deferredUntil, nil
+}
+
+// partitionRateLimits loads the rate limit rules configured on a partition.
+func (nb *notificationBusiness) partitionRateLimits(ctx context.Context, partitionID string) []*events.RateLimitRule {
+	if partitionID == "" || nb.tenancyCli == nil {
+		return nil
+	}
+
+	resp, err := nb.tenancyCli.GetPartition(ctx, connect.NewRequest(&tenancyv1.GetPartitionRequest{Id: partitionID}))
+	if err != nil {
+		util.Log(ctx).WithError(err).WithField("partition_id", partitionID).Warn("could not load partition rate limits")
+		return nil
+	}
+
+	return events.RateLimitRulesFromProperties(
+		(&data.JSONMap{}).FromProtoStruct(resp.Msg.GetData().GetProperties()))
+}
+
+// giveBackRateLimits returns the slots already taken by a send that ended up rejected.
+func (nb *notificationBusiness) giveBackRateLimits(ctx context.Context, taken []rateLimitTaken) {
+	for _, t := range taken {
+		err := nb.rateLimitCounterRepo.Give(ctx, t.key, t.windowStart)
+		if err != nil {
+			util.Log(ctx).WithError(err).WithField("rate_limit_key", t.key).Warn("could not give back rate limit slot")
+		}
+	}
+}
+
+// rejectRateLimited stores a send refused by a rate limit as failed, without ever releasing it.
+func (nb *notificationBusiness) rejectRateLimited(ctx context.Context, n *models.Notification,
+	nStatus *models.NotificationStatus, rule *events.RateLimitRule) (*commonv1.StatusResponse, error) {
+
+	n.ReleasedAt = nil
+	n.ScheduledAt = nil
+	n.State = int32(commonv1.STATE_INACTIVE.Number())
+
+	err := nb.notificationRepo.Create(ctx, n)
+	if err != nil && !data.ErrorIsDuplicateKey(err) {
+		return nil, err
+	}
+
+	nStatus.State = int32(commonv1.STATE_INACTIVE.Number())
+	nStatus.Status = int32(commonv1.STATUS_FAILED.Number())
+	nStatus.Extra = data.JSONMap{
+		"step":   "rate_limited",
+		"error":  "rate limit exceeded",
+		"scope":  rule.Scope,
+		"limit":  rule.Limit,
+		"window": rule.Window.String(),
+	}
+
+	err = nb.eventsMan.Emit(ctx, events.NotificationStatusSaveEvent, nStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	events.RecordRateLimited(ctx, n, rule.Scope, events.RateLimitActionReject)
+	return nStatus.ToAPI(), nil
+}
@@ -0,0 +1,180 @@
+package business
+
+import (
+	"context"
+	"time"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	"github.com/antinvestor/service-notification/apps/default/service/events"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/antinvestor/service-notification/apps/default/service/repository"
+	"github.com/pitabwire/frame/v2/data"
+	fevents "github.com/pitabwire/frame/v2/events"
+	"github.com/pitabwire/frame/v2/security"
+	"github.com/pitabwire/frame/v2/tenancy"
+	"github.com/pitabwire/util"
+)
+
+// ReleaseScheduler periodically releases outbound notifications whose
+// scheduled send time has passed and hands them over for routing, purging
+// expired idempotency keys and ended rate limit windows on the same tick.
+//
+// Claiming due notifications happens in a single database statement that
+// skips rows locked by other replicas, so any number of instances may run
+// the scheduler concurrently without releasing a notification twice.
+type ReleaseScheduler struct {
+	serviceName            string
+	interval               time.Duration
+	batchSize              int
+	eventMan               fevents.Manager
+	notificationRepo       repository.NotificationRepository
+	notificationStatusRepo repository.NotificationStatusRepository
+	idempotencyKeyRepo     repository.IdempotencyKeyRepository
+	rateLimitCounterRepo   repository.RateLimitCounterRepository
+}
+
+// NewReleaseScheduler creates a scheduler that checks for due notifications every interval.
+func NewReleaseScheduler(serviceName string, interval time.Duration, batchSize int,
+	eventMan fevents.Manager, notificationRepo repository.NotificationRepository,
+	notificationStatusRepo repository.NotificationStatusRepository, idempotencyKeyRepo repository.IdempotencyKeyRepository,
+	rateLimitCounterRepo repository.RateLimitCounterRepository) *ReleaseScheduler {
+
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	return &ReleaseScheduler{
+		serviceName:            serviceName,
+		interval:               interval,
+		batchSize:              batchSize,
+		eventMan:               eventMan,
+		notificationRepo:       notificationRepo,
+		notificationStatusRepo: notificationStatusRepo,
+		idempotencyKeyRepo:     idempotencyKeyRepo,
+		rateLimitCounterRepo:   rateLimitCounterRepo,
+	}
+}
+
+// Run blocks releasing due notifications on every tick until ctx is done.
+func (rs *ReleaseScheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(rs.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			released, err := rs.ReleaseDue(ctx)
+			if err != nil {
+				util.Log(ctx).WithError(err).WithField("released", released).
+					Warn("could not release scheduled notifications")
+			}
+
+			purged, err := rs.PurgeExpired(ctx)
+			if err != nil {
+				util.Log(ctx).WithError(err).Warn("could not purge expired send records")
+			} else if purged > 0 {
+				util.Log(ctx).WithField("purged", purged).Debug("purged expired send records")
+			}
+		}
+	}
+}
+
+// ReleaseDue releases all notifications scheduled up to now, returning how many were released.
+func (rs *ReleaseScheduler) ReleaseDue(ctx context.Context) (int, error) {
+	systemCtx := tenancy.WithSystemPrincipal(ctx, tenancy.SystemPrincipal{
+		ServiceName: rs.serviceName,
+		Reason:      "release scheduled notifications",
+		AllowGlobal: true,
+	})
+
+	released := 0
+	for {
+		notifications, err := rs.notificationRepo.ReleaseDue(systemCtx, time.Now(), rs.batchSize)
+		if err != nil {
+			return released, err
+		}
+
+		for i, n := range notifications {
+			err = rs.handOver(ctx, n)
+			if err != nil {
+				// Claimed rows that were not handed over go back on the schedule, otherwise
+				// they stay marked released without ever being routed.
+				pending := make([]string, 0, len(notifications)-i)
+				for _, unsent := range notifications[i:] {
+					pending = append(pending, unsent.GetID())
+				}
+				if unreleaseErr := rs.notificationRepo.Unrelease(systemCtx, pending...); unreleaseErr != nil {
+					util.Log(ctx).WithError(unreleaseErr).WithField("notifications", pending).
+						Error("could not return unsent notifications to the schedule")
+				}
+				return released, err
+			}
+			released++
+		}
+
+		if len(notifications) < rs.batchSize {
+			return released, nil
+		}
+	}
+}
+
+// PurgeExpired deletes the idempotency keys past their retention and the rate limit counters
+// of ended windows in every partition, returning how many records were removed.
+func (rs *ReleaseScheduler) PurgeExpired(ctx context.Context) (int64, error) {
+	systemCtx := tenancy.WithSystemPrincipal(ctx, tenancy.SystemPrincipal{
+		ServiceName: rs.serviceName,
+		Reason:      "purge expired send records",
+		AllowGlobal: true,
+	})
+
+	now := time.Now()
+	keys, err := rs.idempotencyKeyRepo.PurgeExpired(systemCtx, now)
+	if err != nil {
+		return keys, err
+	}
+
+	counters, err := rs.rateLimitCounterRepo.PurgeEnded(systemCtx, now)
+	return keys + counters, err
+}
+
+func (rs *ReleaseScheduler) handOver(ctx context.Context, n *models.Notification) error {
+	claims := &security.AuthenticationClaims{
+		TenantID:    n.TenantID,
+		PartitionID: n.PartitionID,
+		AccessID:    n.AccessID,
+	}
+	tenantCtx := claims.ClaimsToContext(ctx)
+
+	nStatus := models.NotificationStatus{
+		NotificationID: n.GetID(),
+		State:          int32(commonv1.STATE_ACTIVE.Number()),
+		Status:         int32(commonv1.STATUS_QUEUED.Number()),
+		Extra: data.JSONMap{
+			"step": "released_on_schedule",
+		},
+	}
+	nStatus.GenID(tenantCtx)
+
+	// The release is stored before the notification is handed over, so it can never land
+	// after a status its routing saved and move the notification back to queued.
+	err := rs.notificationStatusRepo.Create(tenantCtx, &nStatus)
+	if err != nil {
+		return err
+	}
+
+	previous := n.Status
+	n.StatusID = nStatus.GetID()
+	n.State = nStatus.State
+	n.Status = nStatus.Status
+	_, err = rs.notificationRepo.UpdateStatus(tenantCtx, n, previous)
+	if err != nil {
+		return err
+	}
+
+	return rs.eventMan.Emit(tenantCtx, events.NotificationOutRouteEvent, n.GetID())
+}
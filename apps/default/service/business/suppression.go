@@ -0,0 +1,110 @@
+package business
+
+import (
+	"context"
+
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/pitabwire/frame/v2/data"
+	"github.com/pitabwire/util"
+)
+
+func (nb *notificationBusiness) SuppressionAdd(ctx context.Context, req *notificationv1.SuppressionAddRequest) (*notificationv1.Suppression, error) {
+	logger := util.Log(ctx).WithField("channel", req.GetData().GetChannel())
+	logger.Debug("handling suppression add request")
+
+	suppression := models.SuppressionFromAPI(ctx, req.GetData())
+	if suppression.Contact == "" {
+		return nil, ErrorEmptyValueSupplied
+	}
+
+	// Opt outs reported by integrations arrive on a route, which carries the partition they belong to.
+	if suppression.RouteID != "" {
+		route, err := nb.routeRepo.GetByID(ctx, suppression.RouteID)
+		if err != nil {
+			logger.WithError(err).WithField("route_id", suppression.RouteID).Warn("could not get route")
+			return nil, err
+		}
+		suppression.CopyPartitionInfo(&route.BaseModel)
+	}
+
+	err := nb.suppressionRepo.Upsert(ctx, suppression)
+	if err != nil {
+		logger.WithError(err).Warn("could not save suppression")
+		return nil, err
+	}
+
+	return suppression.ToAPI(), nil
+}
+
+func (nb *notificationBusiness) SuppressionRemove(ctx context.Context, req *notificationv1.SuppressionRemoveRequest) ([]string, error) {
+	logger := util.Log(ctx).WithField("suppression_id", req.GetId())
+	logger.Debug("handling suppression remove request")
+
+	suppression := &models.Suppression{
+		Contact:  models.NormalizeContact(req.GetContact()),
+		Channel:  req.GetChannel(),
+		SenderID: req.GetSenderId(),
+	}
+	suppression.ID = req.GetId()
+
+	if suppression.ID == "" && suppression.Contact == "" {
+		return nil, ErrorUnspecifiedID
+	}
+
+	removed, err := nb.suppressionRepo.Remove(ctx, suppression)
+	if err != nil {
+		logger.WithError(err).Warn("could not remove suppression")
+		return nil, err
+	}
+
+	return removed, nil
+}
+
+func (nb *notificationBusiness) SuppressionSearch(ctx context.Context, search *notificationv1.SuppressionSearchRequest, consumer func(ctx context.Context, batch []*notificationv1.Suppression) error) error {
+
+	logger := util.Log(ctx)
+	logger.Debug("handling suppression search request")
+
+	searchOpts := []data.SearchOption{
+		data.WithSearchLimit(int(search.GetCount())),
+		data.WithSearchOffset(int(search.GetPage())),
+	}
+
+	filters := map[string]any{}
+	if search.GetQuery() != "" {
+		filters["contact LIKE ?"] = models.NormalizeContact(search.GetQuery()) + "%"
+	}
+	if search.GetChannel() != "" {
+		filters["channel = ?"] = search.GetChannel()
+	}
+	if len(filters) > 0 {
+		searchOpts = append(searchOpts, data.WithSearchFiltersAndByValue(filters))
+	}
+
+	suppressionList, err := nb.suppressionRepo.Search(ctx, data.NewSearchQuery(searchOpts...))
+	if err != nil {
+		return err
+	}
+
+	for {
+		res, ok := suppressionList.ReadResult(ctx)
+		if !ok {
+			return nil
+		}
+
+		if res.IsError() {
+			return res.Error()
+		}
+
+		var batch []*notificationv1.Suppression
+		for _, s := range res.Item() {
+			batch = append(batch, s.ToAPI())
+		}
+
+		err = consumer(ctx, batch)
+		if err != nil {
+			return err
+		}
+	}
+}
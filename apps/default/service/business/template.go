@@ -0,0 +1,461 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+	"github.com/antinvestor/service-notification/apps/default/service/events"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/antinvestor/service-notification/apps/default/service/repository"
+	"github.com/antinvestor/service-notification/pkg/templating"
+	"github.com/pitabwire/frame/v2/data"
+	"github.com/pitabwire/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (nb *notificationBusiness) TemplatePublish(ctx context.Context, req *notificationv1.TemplatePublishRequest) (*notificationv1.Template, error) {
+	logger := util.Log(ctx).WithField("template_id", req.GetId())
+	logger.Debug("handling template publish request")
+
+	if req.GetId() == "" {
+		return nil, ErrorUnspecifiedID
+	}
+
+	template, err := nb.templateRepo.GetByID(ctx, req.GetId())
+	if err != nil {
+		logger.WithError(err).Warn("could not get template")
+		return nil, err
+	}
+
+	return nb.publishTemplate(ctx, template)
+}
+
+func (nb *notificationBusiness) TemplateRollback(ctx context.Context, req *notificationv1.TemplateRollbackRequest) (*notificationv1.Template, error) {
+	logger := util.Log(ctx).WithFields(map[string]any{
+		"template_name": req.GetName(),
+		"version":       req.GetVersion(),
+	})
+	logger.Debug("handling template rollback request")
+
+	if req.GetName() == "" {
+		return nil, ErrorEmptyValueSupplied
+	}
+
+	revisions, err := nb.templateRepo.GetRevisions(ctx, req.GetName())
+	if err != nil {
+		logger.WithError(err).Warn("could not get template versions")
+		return nil, err
+	}
+
+	var target *models.Template
+	for _, revision := range revisions {
+		if req.GetVersion() != 0 {
+			if revision.Revision == int(req.GetVersion()) {
+				target = revision
+				break
+			}
+			continue
+		}
+
+		// Without a version the rollback goes to the version published before the current one.
+		if revision.State != models.TemplateStateRetired || revision.PublishedAt == nil {
+			continue
+		}
+		if target == nil || revision.PublishedAt.After(*target.PublishedAt) {
+			target = revision
+		}
+	}
+
+	if target == nil {
+		return nil, ErrorItemDoesNotExist
+	}
+
+	// Rolling back only reinstates versions that were live before, drafts are published instead.
+	if target.PublishedAt == nil {
+		return nil, ErrorTemplateNotPublished
+	}
+
+	return nb.publishTemplate(ctx, target)
+}
+
+func (nb *notificationBusiness) TemplateUpdate(ctx context.Context, req *notificationv1.TemplateUpdateRequest) (*notificationv1.Template, error) {
+	logger := util.Log(ctx).WithField("template_id", req.GetId())
+	logger.Debug("handling template update request")
+
+	if req.GetId() == "" {
+		return nil, ErrorUnspecifiedID
+	}
+
+	template, err := nb.templateRepo.GetByID(ctx, req.GetId())
+	if err != nil {
+		logger.WithError(err).Warn("could not get template")
+		return nil, err
+	}
+
+	// Published versions are what notifications render, changes go into a new version instead.
+	if template.State != models.TemplateStateDraft {
+		return nil, ErrorTemplateNotDraft
+	}
+
+	extra := map[string]any(template.Extra)
+	if req.GetExtra() != nil {
+		extra = req.GetExtra().AsMap()
+	}
+
+	contents, changes, err := nb.updatedTemplateContents(ctx, template, req.GetData())
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkTemplateVariables(extra, contents)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Extra = extra
+	err = nb.templateRepo.Change(ctx, template, req.GetExtra() != nil, changes)
+	switch {
+	case errors.Is(err, repository.ErrTemplateNotDraft):
+		return nil, ErrorTemplateNotDraft
+	case errors.Is(err, repository.ErrTemplateDataMissing):
+		return nil, ErrorItemDoesNotExist
+	case data.ErrorIsDuplicateKey(err):
+		return nil, ErrorItemExist
+	case err != nil:
+		logger.WithError(err).Warn("could not change template")
+		return nil, err
+	}
+
+	apiTemplates, err := nb.convertTemplatesToAPI(ctx, nil, []*models.Template{template})
+	if err != nil {
+		return nil, err
+	}
+	return apiTemplates[0], nil
+}
+
+// templateContent is a template body together with the request field it is reported under.
+type templateContent struct {
+	field  string
+	detail string
+}
+
+// updatedTemplateContents works out the content a template version is left with once the
+// changes are applied, together with the changes resolved to their languages.
+func (nb *notificationBusiness) updatedTemplateContents(ctx context.Context, template *models.Template, changes []*notificationv1.TemplateDataChange) ([]templateContent, []*repository.TemplateDataChange, error) {
+	existing, err := nb.templateDataRepo.GetByTemplateID(ctx, template.GetID())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contents := map[string]templateContent{}
+	var order []string
+	for _, tData := range existing {
+		key := tData.LanguageID + "/" + tData.Type
+		contents[key] = templateContent{field: "data." + tData.Type, detail: tData.Detail}
+		order = append(order, key)
+	}
+
+	resolved := make([]*repository.TemplateDataChange, 0, len(changes))
+	for i, change := range changes {
+		if change.GetLanguageCode() == "" || change.GetType() == "" {
+			return nil, nil, ErrorEmptyValueSupplied
+		}
+
+		language, langErr := nb.languageRepo.GetOrCreateByCode(ctx, change.GetLanguageCode())
+		if langErr != nil {
+			return nil, nil, langErr
+		}
+
+		resolved = append(resolved, &repository.TemplateDataChange{
+			LanguageID: language.GetID(),
+			Type:       change.GetType(),
+			Detail:     change.GetDetail(),
+			Remove:     change.GetRemove(),
+		})
+
+		key := language.GetID() + "/" + change.GetType()
+		if change.GetRemove() {
+			delete(contents, key)
+			continue
+		}
+		if _, ok := contents[key]; !ok {
+			order = append(order, key)
+		}
+		contents[key] = templateContent{field: fmt.Sprintf("data[%d].detail", i), detail: change.GetDetail()}
+	}
+
+	updated := make([]templateContent, 0, len(contents))
+	for _, key := range order {
+		if content, ok := contents[key]; ok {
+			updated = append(updated, content)
+			delete(contents, key)
+		}
+	}
+	return updated, resolved, nil
+}
+
+// checkTemplateVariables rejects template content reading payload variables its extras do not
+// declare, naming the offending fields. Templates declaring no variables are not checked, and
+// support contacts need no declaration.
+func checkTemplateVariables(extra map[string]any, contents []templateContent) error {
+	schema, err := templating.ParseSchema(extra[models.TemplateExtraVariables])
+	if err != nil {
+		return errorFieldViolations("Template variables are not declared properly",
+			&errdetails.BadRequest_FieldViolation{Field: "extra." + models.TemplateExtraVariables, Description: err.Error()})
+	}
+	if schema == nil {
+		return nil
+	}
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, content := range contents {
+		undeclared, parseErr := schema.Undeclared(content.detail)
+		if parseErr != nil {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: content.field, Description: parseErr.Error()})
+			continue
+		}
+		for _, name := range undeclared {
+			if strings.HasPrefix(name, events.SupportContactPrefix) {
+				continue
+			}
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       content.field,
+				Description: fmt.Sprintf("reads variable %q that is not declared in extra.%s", name, models.TemplateExtraVariables),
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		return errorFieldViolations("Template reads undeclared variables", violations...)
+	}
+	return nil
+}
+
+func (nb *notificationBusiness) TemplateDelete(ctx context.Context, req *notificationv1.TemplateDeleteRequest) ([]string, error) {
+	logger := util.Log(ctx).WithField("template_id", req.GetId())
+	logger.Debug("handling template delete request")
+
+	if req.GetId() == "" {
+		return nil, ErrorUnspecifiedID
+	}
+
+	template, err := nb.templateRepo.GetByID(ctx, req.GetId())
+	if err != nil {
+		logger.WithError(err).Warn("could not get template")
+		return nil, err
+	}
+
+	removed, err := nb.templateRepo.Remove(ctx, template)
+	if errors.Is(err, repository.ErrTemplateInUse) {
+		logger.Debug("template is still in use")
+		return nil, ErrorTemplateInUse
+	}
+	if err != nil {
+		logger.WithError(err).Warn("could not delete template")
+		return nil, err
+	}
+
+	return removed, nil
+}
+
+func (nb *notificationBusiness) TemplatePreview(ctx context.Context, req *notificationv1.TemplatePreviewRequest) (*notificationv1.TemplatePreviewResponse, error) {
+	logger := util.Log(ctx).WithFields(map[string]any{
+		"template":      req.GetTemplate(),
+		"language_code": req.GetLanguageCode(),
+	})
+	logger.Debug("handling template preview request")
+
+	if req.GetTemplate() == "" {
+		return nil, ErrorEmptyValueSupplied
+	}
+
+	template, _, err := nb.findTemplate(ctx, req.GetTemplate())
+	if err != nil {
+		logger.WithError(err).Warn("could not get template")
+		return nil, err
+	}
+	if template == nil {
+		return nil, ErrorItemDoesNotExist
+	}
+
+	languageCode := req.GetLanguageCode()
+	if languageCode == "" {
+		languageCode = nb.defaultLanguageCode
+	}
+
+	partitionID := req.GetPartitionId()
+	if partitionID == "" {
+		partitionID = template.PartitionID
+	}
+
+	// Previews render the content a notification in the language would go out with, falling
+	// back the same way when the template has none in it.
+	tmplDataList, language, err := events.TemplateDataInLanguage(ctx, nb.languageRepo, nb.templateDataRepo, nb.tenancyCli,
+		template.GetID(), partitionID, languageCode, nb.defaultLanguageCode)
+	if err != nil {
+		logger.WithError(err).Warn("could not get template data")
+		return nil, ErrorItemDoesNotExist
+	}
+
+	supportContacts, err := events.SupportContacts(ctx, nb.tenancyCli, partitionID)
+	if err != nil {
+		logger.WithError(err).WithField("partition_id", partitionID).Warn("could not get support contacts")
+		return nil, err
+	}
+
+	payload := req.GetPayload().AsMap()
+	rendered := map[string]string{}
+	var previewErrors []*notificationv1.TemplatePreviewError
+
+	schema, err := template.VariableSchema()
+	if err != nil {
+		return nil, err
+	}
+	if schema != nil {
+		for _, fieldErr := range schema.Validate(payload) {
+			previewErrors = append(previewErrors, &notificationv1.TemplatePreviewError{
+				Variable: fieldErr.Field,
+				Message:  fieldErr.Error(),
+			})
+		}
+		payload = schema.WithDefaults(payload)
+	}
+	payload = events.WithSupportContacts(payload, supportContacts)
+
+	for _, tData := range tmplDataList {
+		missing, parseErr := templating.Missing(tData.Detail, payload)
+		if parseErr != nil {
+			previewErrors = append(previewErrors, &notificationv1.TemplatePreviewError{Type: tData.Type, Message: parseErr.Error()})
+			continue
+		}
+
+		for _, variable := range missing {
+			previewErrors = append(previewErrors, &notificationv1.TemplatePreviewError{
+				Type:     tData.Type,
+				Variable: variable,
+				Message:  fmt.Sprintf("payload does not supply %q", variable),
+			})
+		}
+		if len(missing) > 0 {
+			continue
+		}
+
+		_, renderErr := events.RenderTemplateData([]*models.TemplateData{tData}, payload, rendered)
+		if renderErr != nil {
+			previewErrors = append(previewErrors, &notificationv1.TemplatePreviewError{Type: tData.Type, Message: renderErr.Error()})
+		}
+	}
+
+	// Previews are for no channel in particular, the subject shown is the one the content
+	// carrying it goes out with.
+	subject, err := events.RenderSubject(tmplDataList, payload, "")
+	if err != nil {
+		previewErrors = append(previewErrors, &notificationv1.TemplatePreviewError{Type: events.SubjectKey, Message: err.Error()})
+	} else if subject != "" {
+		rendered[events.SubjectKey] = subject
+	}
+
+	apiTemplates, err := nb.convertTemplatesToAPI(ctx, language, []*models.Template{template})
+	if err != nil {
+		return nil, err
+	}
+
+	return &notificationv1.TemplatePreviewResponse{
+		Template:        apiTemplates[0],
+		LanguageCode:    language.Code,
+		Rendered:        rendered,
+		SupportContacts: supportContacts,
+		Errors:          previewErrors,
+	}, nil
+}
+
+func (nb *notificationBusiness) publishTemplate(ctx context.Context, template *models.Template) (*notificationv1.Template, error) {
+	err := nb.templateRepo.Publish(ctx, template)
+	if err != nil {
+		util.Log(ctx).WithError(err).WithField("template_id", template.GetID()).Warn("could not publish template")
+		return nil, err
+	}
+
+	apiTemplates, err := nb.convertTemplatesToAPI(ctx, nil, []*models.Template{template})
+	if err != nil {
+		return nil, err
+	}
+	return apiTemplates[0], nil
+}
+
+// resolveTemplate links a notification to the template it is rendered with. A template name
+// follows whichever version of it is published when the notification goes out, while the id
+// of a specific version pins the notification to that version. A name none of whose versions
+// is published yet has nothing to render and is refused.
+func (nb *notificationBusiness) resolveTemplate(ctx context.Context, n *models.Notification, reference string) (*models.Template, error) {
+	template, pinned, err := nb.findTemplate(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		revisions, revErr := nb.templateRepo.GetRevisions(ctx, reference)
+		if revErr != nil {
+			return nil, revErr
+		}
+		if len(revisions) > 0 {
+			return nil, ErrorTemplateNameNotPublished
+		}
+		return nil, ErrorItemDoesNotExist
+	}
+
+	n.TemplateID = template.GetID()
+	n.TemplateRevision = template.Revision
+	n.TemplatePinned = pinned
+	return template, nil
+}
+
+// checkTemplatePayload rejects a payload lacking variables the template requires or holding
+// values of another type than declared. Templates declaring no variables take any payload.
+func checkTemplatePayload(template *models.Template, payload map[string]any) error {
+	if template == nil {
+		return nil
+	}
+
+	schema, err := template.VariableSchema()
+	if err != nil || schema == nil {
+		return err
+	}
+
+	fieldErrors := schema.Validate(payload)
+	if len(fieldErrors) == 0 {
+		return nil
+	}
+
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(fieldErrors))
+	for _, fieldErr := range fieldErrors {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       "payload." + fieldErr.Field,
+			Description: fieldErr.Reason,
+		})
+	}
+	return errorFieldViolations("Payload does not match the template variables", violations...)
+}
+
+// findTemplate looks a template up by name, returning its published version, or by the id
+// of a specific version in which case pinned is set. It returns nil when neither matches.
+func (nb *notificationBusiness) findTemplate(ctx context.Context, reference string) (*models.Template, bool, error) {
+	template, err := nb.templateRepo.GetByName(ctx, reference)
+	if err != nil {
+		return nil, false, err
+	}
+	if template.GetID() != "" {
+		return template, false, nil
+	}
+
+	template, err = nb.templateRepo.GetByID(ctx, reference)
+	if err != nil {
+		if data.ErrorIsNoRows(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return template, true, nil
+}
@@ -0,0 +1,139 @@
+package events
+
+import (
+	"context"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/pitabwire/util"
+)
+
+// defaultFailureStep groups failures reported by integrations, which carry no step.
+const defaultFailureStep = "delivery"
+
+// aggregateStatusKey maps a status to the bucket it is counted under,
+// statuses outside the tracked buckets return an empty key.
+func aggregateStatusKey(status commonv1.STATUS) string {
+	switch status {
+	case commonv1.STATUS_QUEUED:
+		return "queued"
+	case commonv1.STATUS_IN_PROCESS:
+		return "in_process"
+	case commonv1.STATUS_SUCCESSFUL:
+		return "successful"
+	case commonv1.STATUS_FAILED:
+		return "failed"
+	default:
+		return ""
+	}
+}
+
+// aggregateFailureStep returns the step a failure is grouped under, or an empty string when the
+// failure is not final because the notification is rerouted or sent on its next channel, whose
+// outcome is counted instead.
+func aggregateFailureStep(n *models.Notification, nStatus *models.NotificationStatus) string {
+	if commonv1.STATUS(nStatus.Status) != commonv1.STATUS_FAILED || isReroutable(n, nStatus) || needsChannelFallback(n, nStatus) {
+		return ""
+	}
+	step, _ := nStatus.Extra["step"].(string)
+	switch step {
+	case "":
+		return defaultFailureStep
+	case stepChannelFallback:
+		return ""
+	default:
+		return step
+	}
+}
+
+// aggregateCounterChanges lists the counters to adjust when a child notification moves from one
+// status to another. A child fails once, so a final failure replacing one already counted,
+// given by previousFinal, adds no failure step.
+func aggregateCounterChanges(n *models.Notification, previous commonv1.STATUS, previousFinal bool,
+	nStatus *models.NotificationStatus) []*models.NotificationAggregate {
+	var changes []*models.NotificationAggregate
+
+	current := commonv1.STATUS(nStatus.Status)
+	if previous != current {
+		if key := aggregateStatusKey(previous); key != "" {
+			changes = append(changes, &models.NotificationAggregate{Kind: models.AggregateKindStatus, Key: key, Count: -1})
+		}
+		if key := aggregateStatusKey(current); key != "" {
+			changes = append(changes, &models.NotificationAggregate{Kind: models.AggregateKindStatus, Key: key, Count: 1})
+		}
+	}
+
+	if step := aggregateFailureStep(n, nStatus); step != "" && !previousFinal {
+		changes = append(changes, &models.NotificationAggregate{Kind: models.AggregateKindFailureStep, Key: step, Count: 1})
+	}
+
+	return changes
+}
+
+// trackParentAggregate keeps the running counters of a parent notification in step with the
+// status changes of its children. A notification sent on a fallback channel stands in for the
+// child that fell back to it, so the parent counts the outcome of the delivery once whichever
+// channel it ended on.
+func (e *NotificationStatusSave) trackParentAggregate(ctx context.Context, n *models.Notification,
+	previous *models.Notification, nStatus *models.NotificationStatus) {
+
+	if e.aggregateRepo == nil || n.ParentID == "" {
+		return
+	}
+
+	logger := util.Log(ctx).WithField("notification_id", n.GetID())
+
+	child, err := e.fallbackOrigin(ctx, n)
+	if err != nil {
+		logger.WithError(err).Warn("could not find the notification a fallback stands in for")
+		return
+	}
+	if child.ParentID == "" {
+		return
+	}
+
+	previousStatus := commonv1.STATUS(previous.Status)
+	if child != n && aggregateStatusKey(previousStatus) == "" {
+		// The child is counted failed until its fallback takes over.
+		previousStatus = commonv1.STATUS_FAILED
+	}
+
+	previousFinal := false
+	if previousStatus == commonv1.STATUS_FAILED && previous.StatusID != "" {
+		replaced, replacedErr := e.notificationStatusRepo.GetByID(ctx, previous.StatusID)
+		if replacedErr != nil {
+			logger.WithError(replacedErr).WithField("status_id", previous.StatusID).Warn("could not get replaced status")
+		} else {
+			previousFinal = aggregateFailureStep(n, replaced) != ""
+		}
+	}
+
+	for _, counter := range aggregateCounterChanges(n, previousStatus, previousFinal, nStatus) {
+		counter.ParentID = child.ParentID
+		counter.CopyPartitionInfo(&child.BaseModel)
+		counter.GenID(ctx)
+
+		err = e.aggregateRepo.Increment(ctx, counter)
+		if err != nil {
+			logger.WithError(err).WithFields(map[string]any{
+				"parent_id": child.ParentID,
+				"kind":      counter.Kind,
+				"key":       counter.Key,
+			}).Warn("could not update parent aggregate")
+		}
+	}
+}
+
+// fallbackOrigin returns the notification n was sent on a fallback channel for, following
+// fallbacks of fallbacks, or n itself when it is no fallback.
+func (e *NotificationStatusSave) fallbackOrigin(ctx context.Context, n *models.Notification) (*models.Notification, error) {
+	origin := n
+	for origin.ParentStep == stepChannelFallback {
+		parent, err := e.NotificationRepo.GetByID(ctx, origin.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		origin = parent
+	}
+	return origin, nil
+}
@@ -0,0 +1,63 @@
+package events
+
+import (
+	"testing"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/pitabwire/frame/v2/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateCounterChanges(t *testing.T) {
+	n := &models.Notification{ParentID: "broadcast", OutBound: true}
+	status := func(st commonv1.STATUS, extra data.JSONMap) *models.NotificationStatus {
+		return &models.NotificationStatus{Status: int32(st), Extra: extra}
+	}
+	summary := func(changes []*models.NotificationAggregate) map[string]int64 {
+		out := map[string]int64{}
+		for _, c := range changes {
+			out[c.Kind+":"+c.Key] += c.Count
+		}
+		return out
+	}
+
+	require.Equal(t, map[string]int64{"status:queued": 1},
+		summary(aggregateCounterChanges(n, commonv1.STATUS_UNKNOWN, false, status(commonv1.STATUS_QUEUED, nil))))
+
+	require.Equal(t, map[string]int64{"status:queued": -1, "status:successful": 1},
+		summary(aggregateCounterChanges(n, commonv1.STATUS_QUEUED, false, status(commonv1.STATUS_SUCCESSFUL, nil))))
+
+	require.Empty(t, aggregateCounterChanges(n, commonv1.STATUS_QUEUED, false, status(commonv1.STATUS_QUEUED, nil)),
+		"repeated statuses leave the counters unchanged")
+
+	require.Equal(t, map[string]int64{"status:in_process": -1, "status:failed": 1, "failure_step:delivery": 1},
+		summary(aggregateCounterChanges(n, commonv1.STATUS_IN_PROCESS, false, status(commonv1.STATUS_FAILED, nil))))
+
+	require.Equal(t, map[string]int64{"failure_step:publish_to_queue": 1},
+		summary(aggregateCounterChanges(n, commonv1.STATUS_FAILED, false,
+			status(commonv1.STATUS_FAILED, data.JSONMap{"step": "publish_to_queue"}))),
+		"a final failure replacing a rerouted one is counted")
+
+	require.Empty(t, aggregateCounterChanges(n, commonv1.STATUS_FAILED, true,
+		status(commonv1.STATUS_FAILED, data.JSONMap{"step": "publish_to_queue"})),
+		"a child that already failed for good is not counted again")
+
+	require.Equal(t, map[string]int64{"status:in_process": -1, "status:failed": 1},
+		summary(aggregateCounterChanges(n, commonv1.STATUS_IN_PROCESS, false,
+			status(commonv1.STATUS_FAILED, data.JSONMap{constants.StatusExtraReroute: true}))),
+		"rerouted failures are not final and carry no failure step")
+
+	withFallback := &models.Notification{
+		ParentID: "broadcast", OutBound: true, NotificationType: models.RouteTypeEmailForm,
+		Payload: data.JSONMap{models.PayloadKeyChannels: []any{"email", "sms"}},
+	}
+	require.Equal(t, map[string]int64{"status:in_process": -1, "status:failed": 1},
+		summary(aggregateCounterChanges(withFallback, commonv1.STATUS_IN_PROCESS, false,
+			status(commonv1.STATUS_FAILED, data.JSONMap{"step": constants.StatusStepSubmit}))),
+		"failures sent on the next channel are not final")
+	require.Empty(t, aggregateCounterChanges(withFallback, commonv1.STATUS_FAILED, false,
+		status(commonv1.STATUS_FAILED, data.JSONMap{"step": stepChannelFallback})),
+		"handing over to the next channel is no failure of its own")
+}
@@ -0,0 +1,126 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/pitabwire/frame/v2/data"
+	"github.com/pitabwire/util"
+)
+
+const stepChannelFallback = "channel_fallback"
+
+// channelFallbackSteps are the terminal delivery failures a notification moves on to its next
+// channel after, a gateway refusing the message or reporting it undelivered. Failures the
+// service decides itself, such as content that cannot be rendered, would end the same way on
+// any channel, and a spam complaint is an answer from the recipient, so neither falls back.
+var channelFallbackSteps = map[string]bool{
+	constants.StatusStepSubmit:         true,
+	constants.StatusStepDeliveryReport: true,
+	"reroute_exhausted":                true,
+}
+
+// needsChannelFallback reports whether a status is a terminal delivery failure of an
+// outbound notification that still has preferred channels left to try.
+func needsChannelFallback(n *models.Notification, nStatus *models.NotificationStatus) bool {
+	if !n.OutBound || n.IsBroadcast() || n.IsCanceled() || commonv1.STATUS(nStatus.Status) != commonv1.STATUS_FAILED {
+		return false
+	}
+	// Reroutable failures are retried on another route of the same channel first.
+	if isReroutable(n, nStatus) {
+		return false
+	}
+	step, _ := nStatus.Extra[constants.StatusExtraStep].(string)
+	if !channelFallbackSteps[step] {
+		return false
+	}
+	return len(n.FallbackChannels()) > 0
+}
+
+// fallbackToNextChannel sends a failed notification again on the next preferred
+// channel as a child notification linked back through ParentID.
+func (e *NotificationStatusSave) fallbackToNextChannel(ctx context.Context, n *models.Notification) error {
+	channels := n.FallbackChannels()
+
+	payload := data.JSONMap{}
+	for k, v := range n.Payload {
+		payload[k] = v
+	}
+	remaining := make([]any, 0, len(channels))
+	for _, channel := range channels {
+		remaining = append(remaining, channel)
+	}
+	payload[models.PayloadKeyChannels] = remaining
+
+	releaseDate := time.Now()
+	child := &models.Notification{
+		ParentID:             n.GetID(),
+		ParentStep:           stepChannelFallback,
+		SenderProfileID:      n.SenderProfileID,
+		SenderProfileType:    n.SenderProfileType,
+		SenderContactID:      n.SenderContactID,
+		RecipientProfileID:   n.RecipientProfileID,
+		RecipientProfileType: n.RecipientProfileType,
+		OutBound:             true,
+		LanguageID:           n.LanguageID,
+		TemplateID:           n.TemplateID,
+		NotificationType:     channels[0],
+		Message:              n.Message,
+		Payload:              payload,
+		ReleasedAt:           &releaseDate,
+		Priority:             n.Priority,
+	}
+	child.CopyPartitionInfo(&n.BaseModel)
+	child.GenID(ctx)
+
+	childStatus := models.NotificationStatus{
+		NotificationID: child.GetID(),
+		State:          int32(commonv1.STATE_CREATED),
+		Status:         int32(commonv1.STATUS_QUEUED),
+		Extra: data.JSONMap{
+			"step":      stepChannelFallback,
+			"parent_id": n.GetID(),
+			"channel":   channels[0],
+		},
+	}
+	childStatus.GenID(ctx)
+
+	// A notification falls back once, the unique parent step makes storing the child the
+	// claim so a failure reported again for the parent, even concurrently, is already covered
+	// by the child sent on the next channel.
+	err := e.NotificationRepo.Create(ctx, child)
+	if err != nil {
+		if data.ErrorIsDuplicateKey(err) {
+			util.Log(ctx).WithField("notification_id", n.GetID()).Debug("notification already fell back to the next channel")
+			return nil
+		}
+		return err
+	}
+
+	err = e.eventMan.Emit(ctx, NotificationStatusSaveEvent, &childStatus)
+	if err != nil {
+		return err
+	}
+
+	err = e.eventMan.Emit(ctx, NotificationOutRouteEvent, child.GetID())
+	if err != nil {
+		return err
+	}
+
+	parentStatus := models.NotificationStatus{
+		NotificationID: n.GetID(),
+		State:          int32(commonv1.STATE_INACTIVE),
+		Status:         int32(commonv1.STATUS_FAILED),
+		Extra: data.JSONMap{
+			"step":     stepChannelFallback,
+			"child_id": child.GetID(),
+			"channel":  channels[0],
+		},
+	}
+	parentStatus.GenID(ctx)
+
+	return e.eventMan.Emit(ctx, NotificationStatusSaveEvent, &parentStatus)
+}
@@ -0,0 +1,56 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/pitabwire/frame/v2/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNeedsChannelFallback(t *testing.T) {
+	failed := func(extra data.JSONMap) *models.NotificationStatus {
+		return &models.NotificationStatus{Status: int32(commonv1.STATUS_FAILED), Extra: extra}
+	}
+
+	n := &models.Notification{
+		OutBound:         true,
+		NotificationType: models.RouteTypeEmailForm,
+		Payload:          data.JSONMap{models.PayloadKeyChannels: []any{"email", "sms"}},
+	}
+
+	require.Equal(t, []string{"sms"}, n.FallbackChannels())
+	require.True(t, needsChannelFallback(n, failed(data.JSONMap{"error": "mailbox unavailable", "step": constants.StatusStepSubmit})))
+	require.True(t, needsChannelFallback(n, failed(data.JSONMap{"step": constants.StatusStepDeliveryReport})))
+	require.True(t, needsChannelFallback(n, failed(data.JSONMap{"step": "reroute_exhausted"})))
+
+	require.False(t, needsChannelFallback(n, failed(data.JSONMap{constants.StatusExtraReroute: true})),
+		"reroutable failures try another route before another channel")
+	require.False(t, needsChannelFallback(n, failed(data.JSONMap{"step": stepChannelFallback})))
+	require.False(t, needsChannelFallback(n, failed(data.JSONMap{"error": "unreported step"})),
+		"only failures integrations report as terminal fall back")
+
+	for _, step := range []string{
+		"format_outbound_notification", "validate_recipient", "validate_route", "route_notification", "complaint",
+		"canceled", "attachment_fetch", "rate_limited", "suppressed", "sms_segmentation",
+	} {
+		require.False(t, needsChannelFallback(n, failed(data.JSONMap{"step": step})), "%s must not fall back", step)
+	}
+
+	canceledAt := time.Now()
+	canceled := *n
+	canceled.CanceledAt = &canceledAt
+	require.False(t, needsChannelFallback(&canceled, failed(data.JSONMap{"step": constants.StatusStepSubmit})),
+		"canceled notifications are never sent on another channel")
+	require.False(t, needsChannelFallback(n, &models.NotificationStatus{Status: int32(commonv1.STATUS_SUCCESSFUL)}))
+
+	n.NotificationType = models.RouteTypeSMSForm
+	require.Empty(t, n.FallbackChannels(), "last preferred channel has nothing to fall back to")
+	require.False(t, needsChannelFallback(n, failed(data.JSONMap{"step": constants.StatusStepSubmit})))
+
+	n.NotificationType = ""
+	require.Equal(t, []string{"sms"}, n.FallbackChannels(), "unrouted notifications start on the first channel")
+}
@@ -0,0 +1,167 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+)
+
+// DeliveryWindowProperty is the partition or profile property holding the
+// delivery window, for example:
+//
+//	{"timezone": "Africa/Lagos", "start": "08:00", "end": "20:00", "days": ["mon", "tue"]}
+//
+// A profile window overrides the partition window field by field, so a profile
+// may only carry its own timezone and still inherit the partition hours.
+const DeliveryWindowProperty = "delivery_window"
+
+// DeliveryWindow is the daily time span in which non urgent notifications may be sent.
+type DeliveryWindow struct {
+	Timezone string
+	Start    string
+	End      string
+	Days     []string
+}
+
+// deliveryWindowFromProperties reads a window from partition or profile properties.
+func deliveryWindowFromProperties(properties map[string]any) *DeliveryWindow {
+	raw, ok := properties[DeliveryWindowProperty].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	w := &DeliveryWindow{}
+	w.Timezone, _ = raw["timezone"].(string)
+	w.Start, _ = raw["start"].(string)
+	w.End, _ = raw["end"].(string)
+
+	if days, dOk := raw["days"].([]any); dOk {
+		for _, d := range days {
+			if day, sOk := d.(string); sOk {
+				w.Days = append(w.Days, strings.ToLower(strings.TrimSpace(day)))
+			}
+		}
+	}
+
+	return w
+}
+
+// mergeDeliveryWindows overlays the fields set on override onto base.
+func mergeDeliveryWindows(base, override *DeliveryWindow) *DeliveryWindow {
+	if base == nil {
+		return override
+	}
+	if override == nil {
+		return base
+	}
+
+	merged := *base
+	if override.Timezone != "" {
+		merged.Timezone = override.Timezone
+	}
+	if override.Start != "" {
+		merged.Start = override.Start
+	}
+	if override.End != "" {
+		merged.End = override.End
+	}
+	if len(override.Days) > 0 {
+		merged.Days = override.Days
+	}
+	return &merged
+}
+
+// bypassesDeliveryWindow reports whether a notification may be sent outside the delivery
+// window, either because it is marked transactional or its priority is urgent.
+func bypassesDeliveryWindow(n *models.Notification) bool {
+	return n.Transactional || notificationv1.PRIORITY(n.Priority) == notificationv1.PRIORITY_HIGH
+}
+
+// NextSlot returns the earliest time at or after now that falls inside the window.
+// A window without start and end hours is always open.
+func (w *DeliveryWindow) NextSlot(now time.Time) (time.Time, error) {
+	if w == nil || (w.Start == "" && w.End == "") {
+		return now, nil
+	}
+
+	loc := time.UTC
+	if w.Timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(w.Timezone)
+		if err != nil {
+			return now, fmt.Errorf("invalid delivery window timezone %q: %w", w.Timezone, err)
+		}
+	}
+
+	start, err := parseClock(w.Start, 0)
+	if err != nil {
+		return now, err
+	}
+	end, err := parseClock(w.End, 24*time.Hour)
+	if err != nil {
+		return now, err
+	}
+	if start == end {
+		return now, nil
+	}
+
+	local := now.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	// Check yesterday's window too, it may still be open when it runs past midnight.
+	for offset := -1; offset <= 7; offset++ {
+		day := midnight.AddDate(0, 0, offset)
+		if !w.allowsDay(day.Weekday()) {
+			continue
+		}
+
+		opens := clockOn(day, start)
+		closes := clockOn(day, end)
+		if end < start {
+			closes = clockOn(day.AddDate(0, 0, 1), end)
+		}
+
+		if !local.Before(opens) && local.Before(closes) {
+			return now, nil
+		}
+		if opens.After(local) {
+			return opens, nil
+		}
+	}
+
+	return now, fmt.Errorf("delivery window %s-%s allows no day of the week", w.Start, w.End)
+}
+
+func (w *DeliveryWindow) allowsDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	name := strings.ToLower(day.String()[:3])
+	for _, d := range w.Days {
+		if strings.HasPrefix(d, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// clockOn returns the time the wall clock reads clock after midnight of day, so windows still
+// open at their configured hour on days lengthened or shortened by daylight saving changes.
+func clockOn(day time.Time, clock time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, int(clock/time.Minute), 0, 0, day.Location())
+}
+
+// parseClock turns an HH:MM string into the duration since midnight.
+func parseClock(clock string, fallback time.Duration) (time.Duration, error) {
+	if clock == "" {
+		return fallback, nil
+	}
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid delivery window time %q: %w", clock, err)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,93 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveryWindowNextSlot(t *testing.T) {
+	lagos, err := time.LoadLocation("Africa/Lagos")
+	require.NoError(t, err)
+
+	daytime := &DeliveryWindow{Timezone: "Africa/Lagos", Start: "08:00", End: "20:00"}
+	overnight := &DeliveryWindow{Timezone: "Africa/Lagos", Start: "22:00", End: "06:00"}
+	weekdays := &DeliveryWindow{Timezone: "Africa/Lagos", Start: "08:00", End: "20:00", Days: []string{"mon", "tue", "wed", "thu", "fri"}}
+
+	// 2026-10-16 is a Friday.
+	testcases := []struct {
+		name   string
+		window *DeliveryWindow
+		now    time.Time
+		want   time.Time
+	}{
+		{name: "inside window", window: daytime,
+			now:  time.Date(2026, 10, 16, 12, 0, 0, 0, lagos),
+			want: time.Date(2026, 10, 16, 12, 0, 0, 0, lagos)},
+		{name: "before window opens", window: daytime,
+			now:  time.Date(2026, 10, 16, 2, 0, 0, 0, lagos),
+			want: time.Date(2026, 10, 16, 8, 0, 0, 0, lagos)},
+		{name: "after window closes", window: daytime,
+			now:  time.Date(2026, 10, 16, 21, 30, 0, 0, lagos),
+			want: time.Date(2026, 10, 17, 8, 0, 0, 0, lagos)},
+		{name: "timezone conversion", window: daytime,
+			now:  time.Date(2026, 10, 16, 6, 30, 0, 0, time.UTC),
+			want: time.Date(2026, 10, 16, 8, 0, 0, 0, lagos)},
+		{name: "overnight window after midnight", window: overnight,
+			now:  time.Date(2026, 10, 16, 3, 0, 0, 0, lagos),
+			want: time.Date(2026, 10, 16, 3, 0, 0, 0, lagos)},
+		{name: "overnight window midday", window: overnight,
+			now:  time.Date(2026, 10, 16, 12, 0, 0, 0, lagos),
+			want: time.Date(2026, 10, 16, 22, 0, 0, 0, lagos)},
+		{name: "weekend skips to monday", window: weekdays,
+			now:  time.Date(2026, 10, 16, 21, 0, 0, 0, lagos),
+			want: time.Date(2026, 10, 19, 8, 0, 0, 0, lagos)},
+		{name: "no hours always open", window: &DeliveryWindow{Timezone: "Africa/Lagos"},
+			now:  time.Date(2026, 10, 16, 2, 0, 0, 0, lagos),
+			want: time.Date(2026, 10, 16, 2, 0, 0, 0, lagos)},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, slotErr := tt.window.NextSlot(tt.now)
+			require.NoError(t, slotErr)
+			require.True(t, tt.want.Equal(got), "NextSlot() = %v, want %v", got, tt.want)
+		})
+	}
+
+	// 2026-11-01 is 25 hours long in New York, the window still opens at eight on the wall clock.
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	got, err := (&DeliveryWindow{Timezone: "America/New_York", Start: "08:00", End: "20:00"}).
+		NextSlot(time.Date(2026, 10, 31, 21, 0, 0, 0, newYork))
+	require.NoError(t, err)
+	require.True(t, time.Date(2026, 11, 1, 8, 0, 0, 0, newYork).Equal(got), "NextSlot() = %v across daylight saving", got)
+
+	_, err = (&DeliveryWindow{Timezone: "Mars/Olympus", Start: "08:00", End: "20:00"}).NextSlot(time.Now())
+	require.Error(t, err)
+}
+
+func TestDeliveryWindowMergeAndPriority(t *testing.T) {
+	partition := deliveryWindowFromProperties(map[string]any{
+		DeliveryWindowProperty: map[string]any{"timezone": "Africa/Lagos", "start": "08:00", "end": "20:00"},
+	})
+	profile := deliveryWindowFromProperties(map[string]any{
+		DeliveryWindowProperty: map[string]any{"timezone": "Africa/Nairobi"},
+	})
+
+	merged := mergeDeliveryWindows(partition, profile)
+	require.Equal(t, "Africa/Nairobi", merged.Timezone)
+	require.Equal(t, "08:00", merged.Start)
+	require.Equal(t, "20:00", merged.End)
+
+	require.Nil(t, deliveryWindowFromProperties(map[string]any{}))
+
+	require.True(t, bypassesDeliveryWindow(&models.Notification{Priority: int32(notificationv1.PRIORITY_HIGH)}))
+	require.False(t, bypassesDeliveryWindow(&models.Notification{Priority: int32(notificationv1.PRIORITY_LOW)}))
+	require.False(t, bypassesDeliveryWindow(&models.Notification{Priority: int32(notificationv1.PRIORITY_VERY_LOW)}))
+	require.True(t, bypassesDeliveryWindow(&models.Notification{Priority: int32(notificationv1.PRIORITY_LOW), Transactional: true}),
+		"transactional messages such as one time codes go out whatever their priority")
+}
@@ -0,0 +1,151 @@
+package events
+
+import (
+	"regexp"
+	"strings"
+)
+
+// KeywordRuleProperty is the partition property holding inbound keyword rules, for example:
+//
+//	[{"name": "help", "keyword": "HELP", "action": "reply", "template": "sms.help"},
+//	 {"name": "join", "match": "regex", "keyword": "^join\\s+\\w+", "action": "route", "route_id": "..."}]
+//
+// Rules are tried in order and the first match wins. When no partition rule matches,
+// STOP and UNSUBSCRIBE still opt the sender out.
+const KeywordRuleProperty = "inbound_keyword_rules"
+
+const (
+	KeywordMatchPrefix = "prefix"
+	KeywordMatchRegex  = "regex"
+
+	KeywordActionUnsubscribe = "unsubscribe"
+	KeywordActionReply       = "reply"
+	KeywordActionRoute       = "route"
+)
+
+// KeywordUnsubscribeConfirmation is sent after an opt out when the rule has no template or message of its own.
+const KeywordUnsubscribeConfirmation = "You have been unsubscribed and will not receive further messages from us."
+
+// KeywordRule acts on inbound messages whose text starts with, or matches, a keyword.
+type KeywordRule struct {
+	Name     string
+	Match    string
+	Keyword  string
+	Action   string
+	Template string
+	Message  string
+	RouteID  string
+
+	pattern *regexp.Regexp
+}
+
+// defaultKeywordRules honour the standard opt out keywords in every partition.
+var defaultKeywordRules = []*KeywordRule{
+	{Name: "stop", Match: KeywordMatchPrefix, Keyword: "STOP", Action: KeywordActionUnsubscribe},
+	{Name: "unsubscribe", Match: KeywordMatchPrefix, Keyword: "UNSUBSCRIBE", Action: KeywordActionUnsubscribe},
+}
+
+// KeywordRulesFromProperties reads the valid keyword rules from partition properties.
+func KeywordRulesFromProperties(properties map[string]any) []*KeywordRule {
+	rawRules, ok := properties[KeywordRuleProperty].([]any)
+	if !ok {
+		return nil
+	}
+
+	var rules []*KeywordRule
+	for _, r := range rawRules {
+		raw, rOk := r.(map[string]any)
+		if !rOk {
+			continue
+		}
+
+		rule := &KeywordRule{Match: KeywordMatchPrefix}
+		rule.Name, _ = raw["name"].(string)
+		rule.Keyword, _ = raw["keyword"].(string)
+		rule.Template, _ = raw["template"].(string)
+		rule.Message, _ = raw["message"].(string)
+		rule.RouteID, _ = raw["route_id"].(string)
+		if match, mOk := raw["match"].(string); mOk && match != "" {
+			rule.Match = strings.ToLower(match)
+		}
+		if action, aOk := raw["action"].(string); aOk {
+			rule.Action = strings.ToLower(action)
+		}
+		if rule.Name == "" {
+			rule.Name = strings.ToLower(rule.Keyword)
+		}
+
+		if rule.compile() {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+func (r *KeywordRule) compile() bool {
+	if strings.TrimSpace(r.Keyword) == "" {
+		return false
+	}
+
+	switch r.Action {
+	case KeywordActionUnsubscribe:
+	case KeywordActionReply:
+		if r.Template == "" && r.Message == "" {
+			return false
+		}
+	case KeywordActionRoute:
+		if r.RouteID == "" {
+			return false
+		}
+	default:
+		return false
+	}
+
+	switch r.Match {
+	case KeywordMatchPrefix:
+		return true
+	case KeywordMatchRegex:
+		pattern, err := regexp.Compile("(?i)" + r.Keyword)
+		if err != nil {
+			return false
+		}
+		r.pattern = pattern
+		return true
+	default:
+		return false
+	}
+}
+
+// Matches reports whether the message text triggers the rule. Both kinds of match ignore
+// case, and a prefix keyword must make up the whole first word of the message.
+func (r *KeywordRule) Matches(text string) bool {
+	text = strings.TrimSpace(text)
+
+	if r.Match == KeywordMatchRegex {
+		return r.pattern != nil && r.pattern.MatchString(text)
+	}
+
+	keyword := strings.TrimSpace(r.Keyword)
+	if len(text) < len(keyword) || !strings.EqualFold(text[:len(keyword)], keyword) {
+		return false
+	}
+	rest := text[len(keyword):]
+	return rest == "" || strings.TrimLeft(rest, " \t\r\n.,!") != rest
+}
+
+// MatchKeywordRule returns the first partition rule matching the text, falling back to
+// the standard opt out keywords, or nil when the message is not a keyword.
+func MatchKeywordRule(rules []*KeywordRule, text string) *KeywordRule {
+	for _, rule := range rules {
+		if rule.Matches(text) {
+			return rule
+		}
+	}
+	for _, rule := range defaultKeywordRules {
+		if rule.Matches(text) {
+			return rule
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeywordRulesFromProperties(t *testing.T) {
+	var properties map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{"inbound_keyword_rules": [
+		{"name": "help", "keyword": "HELP", "action": "reply", "template": "sms.help"},
+		{"match": "regex", "keyword": "^join\\s+\\w+", "action": "route", "route_id": "route-join"},
+		{"keyword": "INFO", "action": "reply"},
+		{"keyword": "JOIN", "action": "route"},
+		{"match": "regex", "keyword": "([", "action": "unsubscribe"},
+		{"keyword": "QUIT", "action": "forward"}
+	]}`), &properties))
+
+	rules := KeywordRulesFromProperties(properties)
+	require.Len(t, rules, 2, "rules missing a reply, a route, a valid pattern or a known action are dropped")
+
+	require.Equal(t, "help", rules[0].Name)
+	require.Equal(t, KeywordMatchPrefix, rules[0].Match)
+	require.Equal(t, "^join\\s+\\w+", rules[1].Name, "rules are named after their keyword by default")
+	require.Equal(t, "route-join", rules[1].RouteID)
+
+	require.Nil(t, KeywordRulesFromProperties(map[string]any{}))
+}
+
+func TestKeywordRuleMatches(t *testing.T) {
+	prefix := &KeywordRule{Match: KeywordMatchPrefix, Keyword: "STOP", Action: KeywordActionUnsubscribe}
+	require.True(t, prefix.compile())
+
+	require.True(t, prefix.Matches("stop"))
+	require.True(t, prefix.Matches("  Stop please"))
+	require.True(t, prefix.Matches("STOP."))
+	require.False(t, prefix.Matches("STOPPED"), "the keyword must be a whole word")
+	require.False(t, prefix.Matches("please stop"))
+	require.False(t, prefix.Matches("ST"))
+
+	regex := &KeywordRule{Match: KeywordMatchRegex, Keyword: `^join\s+\w+`, Action: KeywordActionRoute, RouteID: "route-join"}
+	require.True(t, regex.compile())
+
+	require.True(t, regex.Matches("JOIN football"))
+	require.False(t, regex.Matches("join"))
+}
+
+func TestMatchKeywordRule(t *testing.T) {
+	stop := &KeywordRule{Name: "stop-custom", Match: KeywordMatchPrefix, Keyword: "STOP", Action: KeywordActionUnsubscribe, Message: "Bye"}
+	require.True(t, stop.compile())
+
+	require.Equal(t, stop, MatchKeywordRule([]*KeywordRule{stop}, "stop"), "partition rules win over the defaults")
+
+	rule := MatchKeywordRule(nil, "Unsubscribe")
+	require.NotNil(t, rule, "opt out keywords work without partition rules")
+	require.Equal(t, KeywordActionUnsubscribe, rule.Action)
+
+	require.Nil(t, MatchKeywordRule(nil, "hello there"))
+}
@@ -0,0 +1,95 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"buf.build/gen/go/antinvestor/tenancy/connectrpc/go/tenancy/v1/tenancyv1connect"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/antinvestor/service-notification/apps/default/service/repository"
+	"github.com/pitabwire/frame/v2/data"
+)
+
+// PartitionDefaultLanguageProperty is the partition property naming the language templates fall
+// back to when they have no content in the language of a notification, for example "sw".
+const PartitionDefaultLanguageProperty = "default_language"
+
+// languageFallbacks lists the language codes a notification may be rendered in, most specific
+// first: the exact locale, its base language, the partition default and the service default.
+func languageFallbacks(code, partitionDefault, serviceDefault string) []string {
+	candidates := []string{code}
+
+	if base, _, found := strings.Cut(strings.ReplaceAll(code, "_", "-"), "-"); found {
+		candidates = append(candidates, base)
+	}
+	candidates = append(candidates, partitionDefault, serviceDefault)
+
+	seen := map[string]struct{}{}
+	fallbacks := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "" {
+			continue
+		}
+		key := strings.ToLower(candidate)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		fallbacks = append(fallbacks, candidate)
+	}
+	return fallbacks
+}
+
+// TemplateDataInLanguage returns the content of a template version in the requested language.
+// Without any it falls back to the base language of the locale, the default language of the
+// partition and then the default language of the service, returning the language the content
+// was found in.
+func TemplateDataInLanguage(ctx context.Context, languageRepo repository.LanguageRepository,
+	templateDataRepo repository.TemplateDataRepository, tenancyCli tenancyv1connect.TenancyServiceClient,
+	templateID, partitionID, languageCode, defaultLanguageCode string) ([]*models.TemplateData, *models.Language, error) {
+
+	inLanguage := func(code string) ([]*models.TemplateData, *models.Language, error) {
+		language, err := languageRepo.GetByCode(ctx, code)
+		if err != nil {
+			if data.ErrorIsNoRows(err) {
+				return nil, nil, nil
+			}
+			return nil, nil, err
+		}
+
+		tmplDataList, err := templateDataRepo.GetByTemplateIDAndLanguage(ctx, language.GetID(), templateID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return tmplDataList, language, nil
+	}
+
+	tmplDataList, language, err := inLanguage(languageCode)
+	if err != nil || len(tmplDataList) > 0 {
+		return tmplDataList, language, err
+	}
+
+	// The partition is only asked for its default once the requested language has no content.
+	properties, err := partitionProperties(ctx, tenancyCli, partitionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, code := range languageFallbacks(languageCode, properties.GetString(PartitionDefaultLanguageProperty), defaultLanguageCode) {
+		if strings.EqualFold(code, languageCode) {
+			continue
+		}
+
+		tmplDataList, language, err = inLanguage(code)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(tmplDataList) > 0 {
+			return tmplDataList, language, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("template %s has no content in %s or any fallback language", templateID, languageCode)
+}
@@ -0,0 +1,29 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLanguageFallbacks(t *testing.T) {
+	tests := []struct {
+		name             string
+		code             string
+		partitionDefault string
+		serviceDefault   string
+		want             []string
+	}{
+		{name: "full chain", code: "sw-KE", partitionDefault: "fr", serviceDefault: "en", want: []string{"sw-KE", "sw", "fr", "en"}},
+		{name: "underscore locale", code: "pt_BR", serviceDefault: "en", want: []string{"pt_BR", "pt", "en"}},
+		{name: "base language", code: "sw", partitionDefault: "sw", serviceDefault: "en", want: []string{"sw", "en"}},
+		{name: "defaults agree", code: "sw-KE", partitionDefault: "EN", serviceDefault: "en", want: []string{"sw-KE", "sw", "EN"}},
+		{name: "no defaults", code: "en", want: []string{"en"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, languageFallbacks(tt.code, tt.partitionDefault, tt.serviceDefault))
+		})
+	}
+}
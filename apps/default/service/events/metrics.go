@@ -35,6 +35,10 @@ var (
 		"notifications_failed_total",
 		"Notifications that failed processing or delivery",
 	)
+	notificationsRateLimitedTotal = businessMetrics.Counter(
+		"notifications_rate_limited_total",
+		"Outbound notifications rejected or deferred by a rate limit",
+	)
 	notificationsSendDuration = businessMetrics.Histogram(
 		"notifications_send_duration_ms",
 		"Time from notification creation to dispatch on a delivery route",
@@ -93,3 +97,13 @@ func recordStatusMetrics(ctx context.Context, n *models.Notification, nStatus *m
 		// Other statuses are not lifecycle transitions we report on.
 	}
 }
+
+// RecordRateLimited counts an outbound notification held back by a rate limit
+// rule, tagged with the rule scope and whether it was rejected or deferred.
+func RecordRateLimited(ctx context.Context, n *models.Notification, scope, action string) {
+	notificationsRateLimitedTotal.Add(ctx, 1,
+		attribute.String("channel", notificationChannel(n)),
+		attribute.String("scope", scope),
+		attribute.String("action", action),
+	)
+}
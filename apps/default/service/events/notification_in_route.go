@@ -4,10 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
 	profilev1 "buf.build/gen/go/antinvestor/profile/protocolbuffers/go/profile/v1"
+	"buf.build/gen/go/antinvestor/tenancy/connectrpc/go/tenancy/v1/tenancyv1connect"
+	tenancyv1 "buf.build/gen/go/antinvestor/tenancy/protocolbuffers/go/tenancy/v1"
+	"connectrpc.com/connect"
 	"github.com/antinvestor/service-notification/apps/default/service/models"
 	"github.com/antinvestor/service-notification/apps/default/service/repository"
 	"github.com/pitabwire/frame/v2/data"
@@ -34,18 +39,28 @@ type NotificationInRoute struct {
 	qMan     queue.Manager
 	eventMan events.Manager
 
+	tenancyCli       tenancyv1connect.TenancyServiceClient
 	notificationRepo repository.NotificationRepository
 	routeRepo        repository.RouteRepository
+	templateRepo     repository.TemplateRepository
+	suppressionRepo  repository.SuppressionRepository
+	routeBalancer    *routeBalancer
 }
 
 // NewNotificationInRoute creates a new NotificationInRoute event handler
-func NewNotificationInRoute(ctx context.Context, qMan queue.Manager, eventMan events.Manager, notificationRepo repository.NotificationRepository, routeRepo repository.RouteRepository) *NotificationInRoute {
+func NewNotificationInRoute(ctx context.Context, qMan queue.Manager, eventMan events.Manager, tenancyCli tenancyv1connect.TenancyServiceClient,
+	notificationRepo repository.NotificationRepository, routeRepo repository.RouteRepository,
+	templateRepo repository.TemplateRepository, suppressionRepo repository.SuppressionRepository) *NotificationInRoute {
 
 	return &NotificationInRoute{
 		qMan:             qMan,
 		eventMan:         eventMan,
+		tenancyCli:       tenancyCli,
 		notificationRepo: notificationRepo,
 		routeRepo:        routeRepo,
+		templateRepo:     templateRepo,
+		suppressionRepo:  suppressionRepo,
+		routeBalancer:    newRouteBalancer(),
 	}
 }
 
@@ -78,7 +93,30 @@ func (e *NotificationInRoute) Execute(ctx context.Context, payload any) error {
 		return err
 	}
 
-	route, err := routeNotification(ctx, e.routeRepo, models.RouteModeReceive, n)
+	rule := MatchKeywordRule(e.partitionKeywordRules(ctx, n.PartitionID), n.Message)
+	if rule != nil {
+		logger = logger.WithField("keyword_rule", rule.Name)
+
+		if n.Payload == nil {
+			n.Payload = data.JSONMap{}
+		}
+		n.Payload["keyword_rule"] = rule.Name
+
+		if rule.Action == KeywordActionRoute {
+			n.RouteID = rule.RouteID
+		} else {
+			err = e.handleKeyword(ctx, n, rule)
+			if err != nil {
+				logger.WithError(err).Error("could not handle inbound keyword")
+				return err
+			}
+
+			logger.Debug("inbound keyword handled")
+			return nil
+		}
+	}
+
+	route, err := routeNotification(ctx, e.routeBalancer, e.routeRepo, models.RouteModeReceive, n)
 	if err != nil {
 		logger.WithError(err).Error("could not route notification")
 
@@ -109,7 +147,7 @@ func (e *NotificationInRoute) Execute(ctx context.Context, payload any) error {
 
 	n.RouteID = route.ID
 
-	_, err = e.notificationRepo.Update(ctx, n, "route_id")
+	_, err = e.notificationRepo.Update(ctx, n, "route_id", "payload")
 	if err != nil {
 		logger.WithError(err).Error("could not save routed notification to database")
 		return err
@@ -143,7 +181,151 @@ func (e *NotificationInRoute) Execute(ctx context.Context, payload any) error {
 	return nil
 }
 
-func routeNotification(ctx context.Context, routeRepository repository.RouteRepository, routeMode string, notification *models.Notification) (*models.Route, error) {
+// partitionKeywordRules loads the inbound keyword rules configured on a partition.
+func (e *NotificationInRoute) partitionKeywordRules(ctx context.Context, partitionID string) []*KeywordRule {
+	if partitionID == "" || e.tenancyCli == nil {
+		return nil
+	}
+
+	resp, err := e.tenancyCli.GetPartition(ctx, connect.NewRequest(&tenancyv1.GetPartitionRequest{Id: partitionID}))
+	if err != nil {
+		util.Log(ctx).WithError(err).WithField("partition_id", partitionID).Warn("could not load partition keyword rules")
+		return nil
+	}
+
+	return KeywordRulesFromProperties(
+		(&data.JSONMap{}).FromProtoStruct(resp.Msg.GetData().GetProperties()))
+}
+
+// handleKeyword answers an inbound keyword on behalf of the partition, opting the
+// sender out first when the keyword asks for it. The message is not passed on to consumers.
+func (e *NotificationInRoute) handleKeyword(ctx context.Context, n *models.Notification, rule *KeywordRule) error {
+	sender := inboundSenderContact(n)
+
+	if rule.Action == KeywordActionUnsubscribe {
+		if sender == "" {
+			util.Log(ctx).WithField("notification_id", n.GetID()).Warn("inbound opt out has no sender contact to suppress")
+		} else {
+			// The short code the keyword was sent to is not the sender contact sends are matched
+			// by, so the opt out covers the channel and keeps the short code as a detail.
+			suppression := &models.Suppression{
+				Contact: models.NormalizeContact(sender),
+				Channel: n.NotificationType,
+				Reason:  "opt_out",
+				Source:  "keyword:" + rule.Name,
+				Extra:   data.JSONMap{"notification_id": n.GetID(), "short_code": n.Payload.GetString("to")},
+			}
+			suppression.CopyPartitionInfo(&n.BaseModel)
+			suppression.GenID(ctx)
+
+			err := e.suppressionRepo.Upsert(ctx, suppression)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err := e.queueKeywordReply(ctx, n, rule, sender)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.notificationRepo.Update(ctx, n, "payload")
+	if err != nil {
+		return err
+	}
+
+	nStatus := models.NotificationStatus{
+		NotificationID: n.GetID(),
+		State:          int32(commonv1.STATE_INACTIVE),
+		Status:         int32(commonv1.STATUS_SUCCESSFUL),
+		Extra: data.JSONMap{
+			"step":         "keyword_" + rule.Action,
+			"keyword_rule": rule.Name,
+		},
+	}
+	nStatus.GenID(ctx)
+
+	return e.eventMan.Emit(ctx, NotificationStatusSaveEvent, &nStatus)
+}
+
+// stepKeywordReply is the parent step of replies to inbound keywords, only the service creates
+// notifications at it.
+const stepKeywordReply = "keyword_reply"
+
+// queueKeywordReply sends the rule's template or message back to whoever sent the keyword. Rules
+// without either only reply when they opt the sender out, confirming it.
+func (e *NotificationInRoute) queueKeywordReply(ctx context.Context, n *models.Notification, rule *KeywordRule, sender string) error {
+	reply := &models.Notification{
+		ParentID:           n.GetID(),
+		ParentStep:         stepKeywordReply,
+		SenderContactID:    n.RecipientContactID,
+		RecipientProfileID: n.SenderProfileID,
+		RecipientContactID: n.SenderContactID,
+		NotificationType:   n.NotificationType,
+		LanguageID:         n.LanguageID,
+		Message:            rule.Message,
+		OutBound:           true,
+	}
+	if reply.RecipientContactID == "" {
+		reply.RecipientContactID = sender
+	}
+
+	if rule.Template != "" {
+		template, err := e.templateRepo.GetByName(ctx, rule.Template)
+		if err != nil {
+			return err
+		}
+		reply.TemplateID = template.GetID()
+	}
+	if reply.TemplateID == "" && reply.Message == "" {
+		// Only an opt out has something to confirm without content of its own.
+		if rule.Action != KeywordActionUnsubscribe {
+			util.Log(ctx).WithFields(map[string]any{
+				"notification_id": n.GetID(),
+				"keyword_rule":    rule.Name,
+				"template":        rule.Template,
+			}).Warn("keyword rule has no published template or message to reply with, no reply sent")
+			return nil
+		}
+		reply.Message = KeywordUnsubscribeConfirmation
+	}
+
+	releasedAt := time.Now()
+	reply.ReleasedAt = &releasedAt
+	reply.CopyPartitionInfo(&n.BaseModel)
+	reply.GenID(ctx)
+
+	err := e.eventMan.Emit(ctx, NotificationSaveEvent, reply)
+	if err != nil {
+		return err
+	}
+
+	nStatus := models.NotificationStatus{
+		NotificationID: reply.GetID(),
+		State:          int32(commonv1.STATE_CREATED),
+		Status:         int32(commonv1.STATUS_QUEUED),
+		Extra: data.JSONMap{
+			"step":         stepKeywordReply,
+			"keyword_rule": rule.Name,
+		},
+	}
+	nStatus.GenID(ctx)
+
+	return e.eventMan.Emit(ctx, NotificationStatusSaveEvent, &nStatus)
+}
+
+// inboundSenderContact returns the contact an inbound message came from, integrations
+// pass the raw number or address in the "from" payload field.
+func inboundSenderContact(n *models.Notification) string {
+	if from := n.Payload.GetString("from"); from != "" {
+		return from
+	}
+	return n.SenderContactID
+}
+
+// routeNotification resolves the route a notification travels on, skipping any excluded routes.
+func routeNotification(ctx context.Context, balancer *routeBalancer, routeRepository repository.RouteRepository, routeMode string, notification *models.Notification, excludedRouteIDs ...string) (*models.Route, error) {
 
 	if notification.RouteID != "" {
 		route, err := routeRepository.GetByID(ctx, notification.RouteID)
@@ -159,16 +341,19 @@ func routeNotification(ctx context.Context, routeRepository repository.RouteRepo
 		return nil, err
 	}
 
+	if len(excludedRouteIDs) > 0 {
+		routes = slices.DeleteFunc(routes, func(route *models.Route) bool {
+			return slices.Contains(excludedRouteIDs, route.GetID())
+		})
+	}
+
 	if len(routes) == 0 {
 		return nil, fmt.Errorf("no routes matched for notification : %s", notification.GetID())
 	}
 
-	route := routes[0]
-	if len(routes) > 1 {
-		route, err = selectRoute(ctx, routes)
-		if err != nil {
-			return nil, err
-		}
+	route, err := selectRoute(ctx, balancer, routes)
+	if err != nil {
+		return nil, err
 	}
 
 	return route, nil
@@ -195,11 +380,12 @@ func loadRoute(ctx context.Context, qMan queue.Manager, routeRepository reposito
 
 }
 
-func selectRoute(_ context.Context, routes []*models.Route) (*models.Route, error) {
-	// TODO: find a simple way of routing message mostly by settings
-	// or contact and profile preferences
-	if len(routes) == 0 {
-		return nil, errors.New("no routes matched for notification")
+// selectRoute picks one of the routes matching a notification, balancing by
+// weight within the highest priority tier that still has healthy routes.
+func selectRoute(_ context.Context, balancer *routeBalancer, routes []*models.Route) (*models.Route, error) {
+	route := balancer.pick(routes, time.Now())
+	if route == nil {
+		return nil, errors.New("no routes matched for notification : all matching routes are disabled")
 	}
-	return routes[0], nil
+	return route, nil
 }
@@ -1,12 +1,14 @@
 package events
 
 import (
-	"bytes"
 	"context"
 	"errors"
-	"text/template"
+	"fmt"
+	"strconv"
+	"time"
 
 	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
 	"buf.build/gen/go/antinvestor/profile/connectrpc/go/profile/v1/profilev1connect"
 	"buf.build/gen/go/antinvestor/tenancy/connectrpc/go/tenancy/v1/tenancyv1connect"
 	tenancyv1 "buf.build/gen/go/antinvestor/tenancy/protocolbuffers/go/tenancy/v1"
@@ -14,6 +16,8 @@ import (
 	"github.com/antinvestor/service-notification/apps/default/service/models"
 	"github.com/antinvestor/service-notification/apps/default/service/repository"
 	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/antinvestor/service-notification/pkg/sms"
+	"github.com/antinvestor/service-notification/pkg/templating"
 	"github.com/pitabwire/frame/v2"
 	"github.com/pitabwire/frame/v2/data"
 	"github.com/pitabwire/frame/v2/events"
@@ -35,16 +39,20 @@ type NotificationOutQueue struct {
 	notificationRepo       repository.NotificationRepository
 	notificationStatusRepo repository.NotificationStatusRepository
 	languageRepo           repository.LanguageRepository
+	templateRepo           repository.TemplateRepository
 	templateDataRepo       repository.TemplateDataRepository
 	routeRepo              repository.RouteRepository
+
+	defaultLanguageCode string
 }
 
 // NewNotificationOutQueue creates a new NotificationOutQueue event handler
 func NewNotificationOutQueue(ctx context.Context, qMan queue.Manager, eventMan events.Manager,
 	profileCli profilev1connect.ProfileServiceClient, tenancyCli tenancyv1connect.TenancyServiceClient,
 	notificationRepo repository.NotificationRepository, notificationStatusRepo repository.NotificationStatusRepository,
-	languageRepo repository.LanguageRepository, templateDataRepo repository.TemplateDataRepository,
-	routeRepo repository.RouteRepository) *NotificationOutQueue {
+	languageRepo repository.LanguageRepository, templateRepo repository.TemplateRepository,
+	templateDataRepo repository.TemplateDataRepository, routeRepo repository.RouteRepository,
+	defaultLanguageCode string) *NotificationOutQueue {
 
 	return &NotificationOutQueue{
 		qMan:                   qMan,
@@ -54,8 +62,10 @@ func NewNotificationOutQueue(ctx context.Context, qMan queue.Manager, eventMan e
 		notificationRepo:       notificationRepo,
 		notificationStatusRepo: notificationStatusRepo,
 		languageRepo:           languageRepo,
+		templateRepo:           templateRepo,
 		templateDataRepo:       templateDataRepo,
 		routeRepo:              routeRepo,
+		defaultLanguageCode:    defaultLanguageCode,
 	}
 }
 
@@ -89,6 +99,11 @@ func (event *NotificationOutQueue) Execute(ctx context.Context, payload any) err
 		return err
 	}
 
+	if n.IsCanceled() {
+		logger.Debug("notification was canceled, skipping dispatch")
+		return nil
+	}
+
 	nStatus, err := event.notificationStatusRepo.GetByID(ctx, n.StatusID)
 	if err != nil {
 		logger.WithError(err).WithField("status_id", n.StatusID).Warn("could not get status")
@@ -107,7 +122,7 @@ func (event *NotificationOutQueue) Execute(ctx context.Context, payload any) err
 		return err
 	}
 
-	templateMap, err = event.formatOutboundNotification(ctx, logger, n, templateMap)
+	templateMap, renderLanguage, err := event.formatOutboundNotification(ctx, logger, n, templateMap)
 	if err != nil {
 		logger.WithError(err).Error("could not format outbound notification")
 
@@ -127,6 +142,40 @@ func (event *NotificationOutQueue) Execute(ctx context.Context, payload any) err
 
 	apiNotification := n.ToAPI(nStatus, language, templateMap)
 
+	deliveryExtra := data.JSONMap{"step": "queued_for_delivery"}
+	if n.TemplateID != "" && n.Message == "" {
+		deliveryExtra["template_id"] = n.TemplateID
+		deliveryExtra["template_version"] = n.TemplateRevision
+		deliveryExtra["language"] = language.Code
+		if renderLanguage != nil {
+			deliveryExtra["language"] = renderLanguage.Code
+			deliveryExtra["language_requested"] = language.Code
+		}
+	}
+	if n.NotificationType == models.RouteTypeSMSForm {
+		smsExtra, smsErr := event.segmentSMS(ctx, logger, n, apiNotification)
+		if smsErr != nil {
+			logger.WithError(smsErr).Error("sms is longer than its template allows")
+
+			nStatus = &models.NotificationStatus{
+				NotificationID: n.GetID(),
+				State:          int32(commonv1.STATE_INACTIVE),
+				Status:         int32(commonv1.STATUS_FAILED),
+				Extra: data.JSONMap{
+					"error": smsErr.Error(),
+					"step":  "sms_segmentation",
+				},
+			}
+
+			nStatus.GenID(ctx)
+			return event.eventMan.Emit(ctx, NotificationStatusSaveEvent, nStatus)
+		}
+
+		for k, v := range smsExtra {
+			deliveryExtra[k] = v
+		}
+	}
+
 	binaryProto, err := proto.Marshal(apiNotification)
 	if err != nil {
 		logger.WithError(err).Error("could not marshal notification")
@@ -170,6 +219,18 @@ func (event *NotificationOutQueue) Execute(ctx context.Context, payload any) err
 		return event.eventMan.Emit(ctx, NotificationStatusSaveEvent, nStatus)
 	}
 
+	// Claiming the dispatch and canceling both update the row conditionally, so a notification
+	// canceled while it was being prepared is never published and one being published stays.
+	claimed, err := event.notificationRepo.ClaimDispatch(ctx, n, time.Now())
+	if err != nil {
+		logger.WithError(err).Error("could not claim notification dispatch")
+		return err
+	}
+	if !claimed {
+		logger.Debug("notification was canceled or already dispatched, skipping dispatch")
+		return nil
+	}
+
 	// Queue a message for further processing by peripheral services
 	err = event.qMan.Publish(ctx, n.RouteID, binaryProto, metadata)
 	if err != nil {
@@ -177,6 +238,7 @@ func (event *NotificationOutQueue) Execute(ctx context.Context, payload any) err
 		logger.WithError(err).Error("could not publish to external queue")
 
 		if !frame.ErrorIsNotFound(err) {
+			event.releaseDispatch(ctx, n)
 			// Other publish error, not recoverable
 			nStatus = &models.NotificationStatus{
 				NotificationID: n.GetID(),
@@ -196,6 +258,7 @@ func (event *NotificationOutQueue) Execute(ctx context.Context, payload any) err
 		route, loadErr := loadRoute(ctx, event.qMan, event.routeRepo, n.RouteID)
 		if loadErr != nil {
 			logger.WithError(loadErr).Error("could not load route")
+			event.releaseDispatch(ctx, n)
 			nStatus = &models.NotificationStatus{
 				NotificationID: n.GetID(),
 				State:          int32(commonv1.STATE_INACTIVE),
@@ -215,6 +278,7 @@ func (event *NotificationOutQueue) Execute(ctx context.Context, payload any) err
 		err = event.qMan.Publish(ctx, n.RouteID, binaryProto, metadata)
 		if err != nil {
 			logger.WithError(err).Error("could not publish to external queue after route load")
+			event.releaseDispatch(ctx, n)
 			nStatus = &models.NotificationStatus{
 				NotificationID: n.GetID(),
 				State:          int32(commonv1.STATE_INACTIVE),
@@ -234,9 +298,7 @@ func (event *NotificationOutQueue) Execute(ctx context.Context, payload any) err
 		NotificationID: n.GetID(),
 		State:          int32(commonv1.STATE_ACTIVE),
 		Status:         int32(commonv1.STATUS_IN_PROCESS),
-		Extra: data.JSONMap{
-			"step": "queued_for_delivery",
-		},
+		Extra:          deliveryExtra,
 	}
 
 	nStatus.GenID(ctx)
@@ -252,73 +314,310 @@ func (event *NotificationOutQueue) Execute(ctx context.Context, payload any) err
 	return nil
 }
 
-func (event *NotificationOutQueue) formatOutboundNotification(ctx context.Context, logger *util.LogEntry, n *models.Notification, templateMap map[string]string) (map[string]string, error) {
+// releaseDispatch gives up the dispatch claim of a notification that could not be published,
+// the redelivered event or the reroute that follows claims it again.
+func (event *NotificationOutQueue) releaseDispatch(ctx context.Context, n *models.Notification) {
+	err := event.notificationRepo.ReleaseDispatch(ctx, n)
+	if err != nil {
+		util.Log(ctx).WithError(err).WithField("notification_id", n.GetID()).Warn("could not release notification dispatch")
+	}
+}
+
+// formatOutboundNotification renders the notification into its template map. When the template
+// had no content in the language of the notification it also returns the fallback language
+// the content was rendered in.
+func (event *NotificationOutQueue) formatOutboundNotification(ctx context.Context, logger *util.LogEntry, n *models.Notification, templateMap map[string]string) (map[string]string, *models.Language, error) {
 
 	if n.Message != "" {
 		templateMap = map[string]string{"default": n.Message}
-		return templateMap, nil
+		return templateMap, nil, nil
 	}
 
 	if n.TemplateID == "" {
-		return nil, errors.New("no template id specified")
+		return nil, nil, errors.New("no template id specified")
+	}
+
+	tmpl, err := event.followPublishedTemplate(ctx, n)
+	if err != nil {
+		logger.WithError(err).WithField("template_id", n.TemplateID).Error("could not resolve published template")
+		return nil, nil, err
 	}
 
-	tmplDataList, err0 := event.templateDataRepo.GetByTemplateIDAndLanguage(ctx, n.LanguageID, n.TemplateID)
-	if err0 != nil {
-		logger.WithError(err0).WithFields(map[string]any{
+	payload, err := templatePayload(tmpl, n.Payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tmplDataList, fallback, err := event.templateDataInLanguage(ctx, n)
+	if err != nil {
+		logger.WithError(err).WithFields(map[string]any{
 			"template_id": n.TemplateID,
 			"language_id": n.LanguageID,
 		}).Error("could not get template data")
-		tmplDataList = []*models.TemplateData{}
+		return nil, nil, err
 	}
 
-	payload := n.Payload
+	payload = WithSupportContacts(payload, templateMap)
+	templateMap, err = RenderTemplateData(tmplDataList, payload, templateMap)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	for _, templateData := range tmplDataList {
+	subject, err := RenderSubject(tmplDataList, payload, n.NotificationType)
+	if err != nil {
+		return nil, nil, err
+	}
+	if subject != "" {
+		templateMap[SubjectKey] = subject
+	}
+	return templateMap, fallback, nil
+}
 
-		tmpl, err := template.New("message_out").Parse(templateData.Detail)
-		if err != nil {
-			return nil, err
+// templateDataInLanguage returns the content of the notification template in the language of
+// the notification, or in the language it falls back to, returned when it is another one.
+func (event *NotificationOutQueue) templateDataInLanguage(ctx context.Context, n *models.Notification) ([]*models.TemplateData, *models.Language, error) {
+
+	requested, err := event.languageRepo.GetByID(ctx, n.LanguageID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tmplDataList, language, err := TemplateDataInLanguage(ctx, event.languageRepo, event.templateDataRepo, event.tenancyCli,
+		n.TemplateID, n.GetPartitionID(), requested.Code, event.defaultLanguageCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	if language.GetID() == n.LanguageID {
+		return tmplDataList, nil, nil
+	}
+	return tmplDataList, language, nil
+}
+
+// followPublishedTemplate moves a notification that is not pinned to a template version onto
+// the version of its template published now, recording and returning the version it is
+// rendered with. It returns nil when the template no longer exists.
+func (event *NotificationOutQueue) followPublishedTemplate(ctx context.Context, n *models.Notification) (*models.Template, error) {
+
+	current, err := event.templateRepo.GetByID(ctx, n.TemplateID)
+	if err != nil {
+		if data.ErrorIsNoRows(err) {
+			return nil, nil
 		}
+		return nil, err
+	}
+
+	if n.TemplatePinned {
+		return current, nil
+	}
 
-		var tmplBytes bytes.Buffer
-		err = tmpl.Execute(&tmplBytes, payload)
+	published, err := event.templateRepo.GetPublished(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+	if published == nil || published.GetID() == n.TemplateID {
+		n.TemplateRevision = current.Revision
+		return current, nil
+	}
+
+	n.TemplateID = published.GetID()
+	n.TemplateRevision = published.Revision
+
+	_, err = event.notificationRepo.Update(ctx, n, "template_id", "template_revision")
+	if err != nil {
+		return nil, err
+	}
+	return published, nil
+}
+
+// templatePayload checks the payload against the variables the rendered template version
+// declares, which may ask for more than the version the notification was queued against, and
+// blanks the optional ones it does not supply.
+func templatePayload(tmpl *models.Template, payload map[string]any) (map[string]any, error) {
+	if tmpl == nil {
+		return payload, nil
+	}
+
+	schema, err := tmpl.VariableSchema()
+	if err != nil || schema == nil {
+		return payload, err
+	}
+
+	var fieldErrs []error
+	for _, fieldErr := range schema.Validate(payload) {
+		fieldErrs = append(fieldErrs, fieldErr)
+	}
+	if len(fieldErrs) > 0 {
+		return nil, errors.Join(fieldErrs...)
+	}
+
+	return schema.WithDefaults(payload), nil
+}
+
+// segmentSMS holds the outgoing text to the size policy of its template and returns the
+// segment count and encoding to record against the delivery for billing.
+func (event *NotificationOutQueue) segmentSMS(ctx context.Context, logger *util.LogEntry, n *models.Notification, apiNotification *notificationv1.Notification) (data.JSONMap, error) {
+
+	maxSegments, policy := event.smsPolicy(ctx, logger, n)
+
+	extra := data.JSONMap{}
+	segmentation := sms.Segment(apiNotification.GetData())
+
+	if maxSegments > 0 && segmentation.Segments > maxSegments {
+		switch policy {
+		case sms.OversizeReject:
+			return nil, fmt.Errorf("message needs %d %s segments, its template allows %d",
+				segmentation.Segments, segmentation.Encoding, maxSegments)
+
+		case sms.OversizeTruncate:
+			apiNotification.Data = sms.Truncate(apiNotification.GetData(), maxSegments)
+			segmentation = sms.Segment(apiNotification.GetData())
+			extra[constants.StatusExtraSMSTruncated] = true
+
+		default:
+			logger.WithFields(map[string]any{
+				"segments":     segmentation.Segments,
+				"encoding":     segmentation.Encoding,
+				"max_segments": maxSegments,
+			}).Warn("sms is longer than its template allows")
+			extra[constants.StatusExtraSMSOversized] = true
+		}
+	}
+
+	extra[constants.StatusExtraSMSSegments] = segmentation.Segments
+	extra[constants.StatusExtraSMSEncoding] = string(segmentation.Encoding)
+	return extra, nil
+}
+
+// smsPolicy reads the segment limit and oversize policy off the notification's template.
+// Messages without a template, or whose template cannot be loaded, are not limited.
+func (event *NotificationOutQueue) smsPolicy(ctx context.Context, logger *util.LogEntry, n *models.Notification) (int, sms.OversizePolicy) {
+
+	if n.TemplateID == "" {
+		return 0, sms.OversizeWarn
+	}
+
+	tmpl, err := event.templateRepo.GetByID(ctx, n.TemplateID)
+	if err != nil {
+		logger.WithError(err).WithField("template_id", n.TemplateID).Warn("could not load template sms policy")
+		return 0, sms.OversizeWarn
+	}
+
+	maxSegments := 0
+	switch v := tmpl.Extra[models.TemplateExtraSMSMaxSegments].(type) {
+	case float64:
+		maxSegments = int(v)
+	case int:
+		maxSegments = v
+	case string:
+		maxSegments, _ = strconv.Atoi(v)
+	}
+
+	return maxSegments, sms.ParseOversizePolicy(tmpl.Extra.GetString(models.TemplateExtraSMSOversizePolicy))
+}
+
+func (event *NotificationOutQueue) extendWithSupportContacts(ctx context.Context, n *models.Notification) (map[string]string, error) {
+	return SupportContacts(ctx, event.tenancyCli, n.GetPartitionID())
+}
+
+// SubjectKey is the template map key the rendered subject of a notification goes out under.
+const SubjectKey = "subject"
+
+// RenderTemplateData renders the content of a template for each of its types into the
+// template map.
+func RenderTemplateData(tmplDataList []*models.TemplateData, payload map[string]any, templateMap map[string]string) (map[string]string, error) {
+	for _, templateData := range tmplDataList {
+		rendered, err := templating.Render(templateData.Detail, payload)
 		if err != nil {
 			return nil, err
 		}
-		templateMap[templateData.Type] = tmplBytes.String()
-		templateMap["subject"] = templateData.Subject
+		templateMap[templateData.Type] = rendered
 	}
 
 	return templateMap, nil
+}
 
+// RenderSubject renders the subject of the content a notification of the type goes out with,
+// empty when that content has none.
+func RenderSubject(tmplDataList []*models.TemplateData, payload map[string]any, notificationType string) (string, error) {
+	templateData := deliveredTemplateData(tmplDataList, notificationType)
+	if templateData == nil || templateData.Subject == "" {
+		return "", nil
+	}
+	return templating.Render(templateData.Subject, payload)
 }
 
-func (event *NotificationOutQueue) extendWithSupportContacts(ctx context.Context, n *models.Notification) (map[string]string, error) {
+// deliveredTemplateData picks the content whose subject goes out with a notification of the
+// type: the content of that type, else for channels sending several types at once, such as
+// html and text email, the first of them by type carrying a subject.
+func deliveredTemplateData(tmplDataList []*models.TemplateData, notificationType string) *models.TemplateData {
+	for _, templateData := range tmplDataList {
+		if notificationType != "" && templateData.Type == notificationType {
+			return templateData
+		}
+	}
 
-	templateMap := make(map[string]string)
+	var delivered *models.TemplateData
+	for _, templateData := range tmplDataList {
+		if templateData.Subject != "" && (delivered == nil || templateData.Type < delivered.Type) {
+			delivered = templateData
+		}
+	}
+	return delivered
+}
 
-	if n.GetPartitionID() == "" {
-		return templateMap, nil
+// SupportContactPrefix prefixes the keys support contacts are expanded under, templates may read
+// them without declaring them.
+const SupportContactPrefix = "support_"
+
+// WithSupportContacts returns a copy of the payload extended with the support contacts of the
+// partition, which take the place of any payload values under the same keys.
+func WithSupportContacts(payload map[string]any, supportContacts map[string]string) map[string]any {
+	extended := make(map[string]any, len(payload)+len(supportContacts))
+	for k, v := range payload {
+		extended[k] = v
+	}
+	for k, v := range supportContacts {
+		extended[k] = v
 	}
+	return extended
+}
+
+// SupportContacts returns the support contacts a partition publishes, keyed the way
+// templates refer to them.
+func SupportContacts(ctx context.Context, tenancyCli tenancyv1connect.TenancyServiceClient, partitionID string) (map[string]string, error) {
 
-	resp, err := event.tenancyCli.GetPartition(ctx, connect.NewRequest(&tenancyv1.GetPartitionRequest{Id: n.GetPartitionID()}))
+	templateMap := make(map[string]string)
+
+	properties, err := partitionProperties(ctx, tenancyCli, partitionID)
 	if err != nil {
 		return nil, err
 	}
 
-	partition := resp.Msg.GetData()
-	properties := (&data.JSONMap{}).FromProtoStruct(partition.GetProperties())
 	contacts, ok := properties["support_contacts"]
 	if ok {
 		supportContacts, sOk := contacts.(map[string]any)
 		if sOk {
 
 			for k, v := range supportContacts {
-				templateMap["support_"+k] = v.(string)
+				templateMap[SupportContactPrefix+k] = v.(string)
 			}
 		}
 	}
 
 	return templateMap, nil
 }
+
+// partitionProperties returns the properties of a partition, none when no partition is given.
+func partitionProperties(ctx context.Context, tenancyCli tenancyv1connect.TenancyServiceClient, partitionID string) (data.JSONMap, error) {
+
+	if partitionID == "" {
+		return data.JSONMap{}, nil
+	}
+
+	resp, err := tenancyCli.GetPartition(ctx, connect.NewRequest(&tenancyv1.GetPartitionRequest{Id: partitionID}))
+	if err != nil {
+		return nil, err
+	}
+
+	return (&data.JSONMap{}).FromProtoStruct(resp.Msg.GetData().GetProperties()), nil
+}
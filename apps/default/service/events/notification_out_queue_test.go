@@ -2,11 +2,16 @@ package events
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+
 	aconfig "github.com/antinvestor/service-notification/apps/default/config"
 	"github.com/antinvestor/service-notification/apps/default/service/models"
 	"github.com/antinvestor/service-notification/apps/default/service/repository"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/antinvestor/service-notification/pkg/sms"
 	internaltests "github.com/antinvestor/service-notification/pkg/tests"
 	"github.com/pitabwire/frame/v2"
 	"github.com/pitabwire/frame/v2/config"
@@ -31,7 +36,21 @@ func TestNotificationOutQueueSuite(t *testing.T) {
 	suite.Run(t, new(NotificationOutQueueTestSuite))
 }
 
-func (s *NotificationOutQueueTestSuite) createService(t *testing.T, depOpts *definition.DependencyOption) (context.Context, repository.TemplateDataRepository) {
+func (s *NotificationOutQueueTestSuite) createService(t *testing.T, depOpts *definition.DependencyOption) (context.Context, repository.TemplateRepository, repository.TemplateDataRepository, repository.LanguageRepository) {
+	ctx, svc := s.startService(t, depOpts)
+
+	workMan := svc.WorkManager()
+	dbPool := svc.DatastoreManager().GetPool(ctx, datastore.DefaultPoolName)
+
+	templateRepo := repository.NewTemplateRepository(ctx, dbPool, workMan)
+	templateDataRepo := repository.NewTemplateDataRepository(ctx, dbPool, workMan)
+	languageRepo := repository.NewLanguageRepository(ctx, dbPool, workMan)
+
+	return ctx, templateRepo, templateDataRepo, languageRepo
+}
+
+// startService runs a migrated service on a database of its own.
+func (s *NotificationOutQueueTestSuite) startService(t *testing.T, depOpts *definition.DependencyOption) (context.Context, *frame.Service) {
 	ctx := t.Context()
 	cfg, err := config.FromEnv[aconfig.NotificationConfig]()
 	require.NoError(t, err)
@@ -58,25 +77,20 @@ func (s *NotificationOutQueueTestSuite) createService(t *testing.T, depOpts *def
 		frame.WithDatastore(),
 		frametests.WithNoopDriver())
 
-	workMan := svc.WorkManager()
-	dbPool := svc.DatastoreManager().GetPool(ctx, datastore.DefaultPoolName)
-
 	svc.Init(ctx)
 
-	templateDataRepo := repository.NewTemplateDataRepository(ctx, dbPool, workMan)
-
 	err = repository.Migrate(ctx, svc.DatastoreManager(), "../../migrations/0001")
 	require.NoError(t, err)
 
 	err = svc.Run(ctx, "")
 	require.NoError(t, err)
 
-	return ctx, templateDataRepo
+	return ctx, svc
 }
 
 func (s *NotificationOutQueueTestSuite) Test_formatOutboundNotification_TemplateDataLookupAndRender() {
 	s.WithTestDependancies(s.T(), func(t *testing.T, dep *definition.DependencyOption) {
-		ctx, templateDataRepo := s.createService(t, dep)
+		ctx, templateRepo, templateDataRepo, _ := s.createService(t, dep)
 
 		n := &models.Notification{
 			TemplateID: "9bsv0s23l8og00vgjq90",
@@ -88,12 +102,227 @@ func (s *NotificationOutQueueTestSuite) Test_formatOutboundNotification_Template
 		}
 
 		event := &NotificationOutQueue{
+			templateRepo:     templateRepo,
 			templateDataRepo: templateDataRepo,
 		}
 
-		messageMap, err := event.formatOutboundNotification(ctx, util.Log(ctx), n, make(map[string]string))
+		messageMap, _, err := event.formatOutboundNotification(ctx, util.Log(ctx), n, make(map[string]string))
 		require.NoError(t, err)
 		require.NotEmpty(t, messageMap)
+		require.Equal(t, 1, n.TemplateRevision)
 		require.Equal(t, "Your contact verification code is : 1234 and will expire at tomorrow", messageMap["text"])
 	})
 }
+
+func (s *NotificationOutQueueTestSuite) Test_formatOutboundNotification_VariableSchema() {
+	s.WithTestDependancies(s.T(), func(t *testing.T, dep *definition.DependencyOption) {
+		ctx, templateRepo, templateDataRepo, _ := s.createService(t, dep)
+
+		tmpl := &models.Template{
+			Name: "schema.render.test",
+			Extra: data.JSONMap{
+				models.TemplateExtraVariables: map[string]any{
+					"code": map[string]any{"type": "string", "required": true},
+					"note": map[string]any{"type": "string"},
+				},
+			},
+		}
+		require.NoError(t, templateRepo.Create(ctx, tmpl))
+		require.NoError(t, templateDataRepo.Create(ctx, &models.TemplateData{
+			TemplateID: tmpl.GetID(),
+			LanguageID: "9bsv0s23l8og00vgjqa0",
+			Type:       "text",
+			Detail:     "code {{.code}}{{.note}}",
+		}))
+
+		event := &NotificationOutQueue{
+			templateRepo:     templateRepo,
+			templateDataRepo: templateDataRepo,
+		}
+
+		n := &models.Notification{
+			TemplateID:     tmpl.GetID(),
+			TemplatePinned: true,
+			LanguageID:     "9bsv0s23l8og00vgjqa0",
+			Payload:        data.JSONMap{"code": "1234"},
+		}
+
+		messageMap, _, err := event.formatOutboundNotification(ctx, util.Log(ctx), n, make(map[string]string))
+		require.NoError(t, err)
+		require.Equal(t, "code 1234", messageMap["text"], "optional variables left out render blank")
+
+		n.Payload = data.JSONMap{"note": "!"}
+		_, _, err = event.formatOutboundNotification(ctx, util.Log(ctx), n, make(map[string]string))
+		require.ErrorContains(t, err, "code is required")
+
+		supportTmpl := &models.Template{
+			Name: "support.render.test",
+			Extra: data.JSONMap{
+				models.TemplateExtraVariables: map[string]any{"code": map[string]any{"type": "string", "required": true}},
+			},
+		}
+		require.NoError(t, templateRepo.Create(ctx, supportTmpl))
+		require.NoError(t, templateDataRepo.Create(ctx, &models.TemplateData{
+			TemplateID: supportTmpl.GetID(),
+			LanguageID: "9bsv0s23l8og00vgjqa0",
+			Type:       "text",
+			Detail:     "code {{.code}}, help on {{.support_phone}}",
+		}))
+
+		n.TemplateID = supportTmpl.GetID()
+		n.Payload = data.JSONMap{"code": "1234"}
+		messageMap, _, err = event.formatOutboundNotification(ctx, util.Log(ctx), n,
+			map[string]string{SupportContactPrefix + "phone": "+256700000000"})
+		require.NoError(t, err)
+		require.Equal(t, "code 1234, help on +256700000000", messageMap["text"], "support contacts render undeclared")
+	})
+}
+
+func (s *NotificationOutQueueTestSuite) Test_formatOutboundNotification_Subject() {
+	s.WithTestDependancies(s.T(), func(t *testing.T, dep *definition.DependencyOption) {
+		ctx, templateRepo, templateDataRepo, _ := s.createService(t, dep)
+
+		tmpl := &models.Template{Name: "subject.render.test"}
+		require.NoError(t, templateRepo.Create(ctx, tmpl))
+		for _, content := range []*models.TemplateData{
+			{Type: models.RouteTypeEmailForm, Detail: "code {{.code}}", Subject: "Your code {{.code}}"},
+			{Type: models.RouteTypeSMSForm, Detail: "code {{.code}}", Subject: "not for email"},
+		} {
+			content.TemplateID = tmpl.GetID()
+			content.LanguageID = "9bsv0s23l8og00vgjqa0"
+			require.NoError(t, templateDataRepo.Create(ctx, content))
+		}
+
+		event := &NotificationOutQueue{
+			templateRepo:     templateRepo,
+			templateDataRepo: templateDataRepo,
+		}
+
+		n := &models.Notification{
+			TemplateID:       tmpl.GetID(),
+			TemplatePinned:   true,
+			LanguageID:       "9bsv0s23l8og00vgjqa0",
+			NotificationType: models.RouteTypeEmailForm,
+			Payload:          data.JSONMap{"code": "1234"},
+		}
+
+		messageMap, _, err := event.formatOutboundNotification(ctx, util.Log(ctx), n, make(map[string]string))
+		require.NoError(t, err)
+		require.Equal(t, "Your code 1234", messageMap[SubjectKey], "the subject of the delivered content is rendered")
+
+		n.NotificationType = models.RouteTypeSMSForm
+		messageMap, _, err = event.formatOutboundNotification(ctx, util.Log(ctx), n, make(map[string]string))
+		require.NoError(t, err)
+		require.Equal(t, "not for email", messageMap[SubjectKey])
+	})
+}
+
+func (s *NotificationOutQueueTestSuite) Test_formatOutboundNotification_LanguageFallback() {
+	s.WithTestDependancies(s.T(), func(t *testing.T, dep *definition.DependencyOption) {
+		ctx, templateRepo, templateDataRepo, languageRepo := s.createService(t, dep)
+
+		languages := map[string]*models.Language{}
+		for _, code := range []string{"sw-KE", "sw", "fr", "en"} {
+			language, err := languageRepo.GetOrCreateByCode(ctx, code)
+			require.NoError(t, err)
+			languages[code] = language
+		}
+
+		tmpl := &models.Template{Name: "fallback.render.test"}
+		require.NoError(t, templateRepo.Create(ctx, tmpl))
+		require.NoError(t, templateDataRepo.Create(ctx, &models.TemplateData{
+			TemplateID: tmpl.GetID(),
+			LanguageID: languages["sw"].GetID(),
+			Type:       "text",
+			Detail:     "Habari {{.name}}",
+		}))
+
+		event := &NotificationOutQueue{
+			languageRepo:        languageRepo,
+			templateRepo:        templateRepo,
+			templateDataRepo:    templateDataRepo,
+			defaultLanguageCode: "sw",
+		}
+
+		n := &models.Notification{
+			TemplateID:     tmpl.GetID(),
+			TemplatePinned: true,
+			LanguageID:     languages["sw"].GetID(),
+			Payload:        data.JSONMap{"name": "Amani"},
+		}
+
+		messageMap, fallback, err := event.formatOutboundNotification(ctx, util.Log(ctx), n, make(map[string]string))
+		require.NoError(t, err)
+		require.Nil(t, fallback, "content in the requested language needs no fallback")
+		require.Equal(t, "Habari Amani", messageMap["text"])
+
+		n.LanguageID = languages["sw-KE"].GetID()
+		messageMap, fallback, err = event.formatOutboundNotification(ctx, util.Log(ctx), n, make(map[string]string))
+		require.NoError(t, err)
+		require.NotNil(t, fallback)
+		require.Equal(t, "sw", fallback.Code, "a locale falls back to its base language")
+		require.Equal(t, "Habari Amani", messageMap["text"])
+
+		n.LanguageID = languages["fr"].GetID()
+		_, fallback, err = event.formatOutboundNotification(ctx, util.Log(ctx), n, make(map[string]string))
+		require.NoError(t, err)
+		require.Equal(t, "sw", fallback.Code, "any other language falls back to the service default")
+
+		event.defaultLanguageCode = "en"
+		_, _, err = event.formatOutboundNotification(ctx, util.Log(ctx), n, make(map[string]string))
+		require.ErrorContains(t, err, "no content in fr")
+	})
+}
+
+func (s *NotificationOutQueueTestSuite) Test_segmentSMS_TemplatePolicy() {
+	s.WithTestDependancies(s.T(), func(t *testing.T, dep *definition.DependencyOption) {
+		ctx, templateRepo, _, _ := s.createService(t, dep)
+
+		event := &NotificationOutQueue{
+			templateRepo: templateRepo,
+		}
+
+		longText := strings.Repeat("a", 200)
+
+		for _, tc := range []struct {
+			policy   string
+			wantErr  bool
+			wantText string
+			flag     string
+		}{
+			{policy: "warn", wantText: longText, flag: constants.StatusExtraSMSOversized},
+			{policy: "truncate", wantText: longText[:160], flag: constants.StatusExtraSMSTruncated},
+			{policy: "reject", wantErr: true},
+		} {
+			tmpl := &models.Template{
+				Name: "sms.policy." + tc.policy,
+				Extra: data.JSONMap{
+					models.TemplateExtraSMSMaxSegments:    1,
+					models.TemplateExtraSMSOversizePolicy: tc.policy,
+				},
+			}
+			require.NoError(t, templateRepo.Create(ctx, tmpl))
+
+			n := &models.Notification{TemplateID: tmpl.GetID(), NotificationType: models.RouteTypeSMSForm}
+			apiNotification := &notificationv1.Notification{Data: longText}
+
+			extra, err := event.segmentSMS(ctx, util.Log(ctx), n, apiNotification)
+			if tc.wantErr {
+				require.Error(t, err, tc.policy)
+				continue
+			}
+
+			require.NoError(t, err, tc.policy)
+			require.Equal(t, tc.wantText, apiNotification.GetData(), tc.policy)
+			require.Equal(t, true, extra[tc.flag], tc.policy)
+			require.Equal(t, string(sms.EncodingGSM7), extra[constants.StatusExtraSMSEncoding])
+		}
+
+		// Messages without a template are only measured.
+		apiNotification := &notificationv1.Notification{Data: "Habari 😀"}
+		extra, err := event.segmentSMS(ctx, util.Log(ctx), &models.Notification{}, apiNotification)
+		require.NoError(t, err)
+		require.Equal(t, 1, extra[constants.StatusExtraSMSSegments])
+		require.Equal(t, string(sms.EncodingUCS2), extra[constants.StatusExtraSMSEncoding])
+	})
+}
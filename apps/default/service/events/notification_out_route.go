@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
 	"buf.build/gen/go/antinvestor/profile/connectrpc/go/profile/v1/profilev1connect"
 	profilev1 "buf.build/gen/go/antinvestor/profile/protocolbuffers/go/profile/v1"
+	"buf.build/gen/go/antinvestor/tenancy/connectrpc/go/tenancy/v1/tenancyv1connect"
+	tenancyv1 "buf.build/gen/go/antinvestor/tenancy/protocolbuffers/go/tenancy/v1"
 	"connectrpc.com/connect"
 	"github.com/antinvestor/service-notification/apps/default/service/models"
 	"github.com/antinvestor/service-notification/apps/default/service/repository"
@@ -24,18 +27,30 @@ type NotificationOutRoute struct {
 	eventMan events.Manager
 
 	profileCli       profilev1connect.ProfileServiceClient
+	tenancyCli       tenancyv1connect.TenancyServiceClient
 	notificationRepo repository.NotificationRepository
 	routeRepo        repository.RouteRepository
+	suppressionRepo  repository.SuppressionRepository
+	routeBalancer    *routeBalancer
+
+	notificationStatusRepo repository.NotificationStatusRepository
 }
 
 // NewNotificationOutRoute creates a new NotificationOutRoute event handler
-func NewNotificationOutRoute(ctx context.Context, eventMan events.Manager, profileCli profilev1connect.ProfileServiceClient, notificationRepo repository.NotificationRepository, routeRepo repository.RouteRepository) *NotificationOutRoute {
+func NewNotificationOutRoute(ctx context.Context, eventMan events.Manager, profileCli profilev1connect.ProfileServiceClient, tenancyCli tenancyv1connect.TenancyServiceClient,
+	notificationRepo repository.NotificationRepository, notificationStatusRepo repository.NotificationStatusRepository, routeRepo repository.RouteRepository,
+	suppressionRepo repository.SuppressionRepository) *NotificationOutRoute {
 
 	return &NotificationOutRoute{
 		eventMan:         eventMan,
 		profileCli:       profileCli,
+		tenancyCli:       tenancyCli,
 		notificationRepo: notificationRepo,
 		routeRepo:        routeRepo,
+		suppressionRepo:  suppressionRepo,
+		routeBalancer:    newRouteBalancer(),
+
+		notificationStatusRepo: notificationStatusRepo,
 	}
 }
 
@@ -70,6 +85,11 @@ func (event *NotificationOutRoute) Execute(ctx context.Context, payload any) err
 		return err
 	}
 
+	if n.IsCanceled() {
+		logger.Debug("notification was canceled, skipping routing")
+		return nil
+	}
+
 	var profileObj *profilev1.ProfileObject
 
 	if n.RecipientProfileID == "" {
@@ -138,7 +158,27 @@ func (event *NotificationOutRoute) Execute(ctx context.Context, payload any) err
 		profileObj = p.Msg.GetData()
 	}
 
+	deferred, err := event.deferOutsideDeliveryWindow(ctx, n, profileObj)
+	if err != nil {
+		logger.WithError(err).Error("could not apply delivery window")
+		return err
+	}
+	if deferred {
+		logger.Debug("notification deferred to next delivery window")
+		return nil
+	}
+
+	if n.RecipientProfileID == "" {
+		n.RecipientProfileID = profileObj.GetId()
+	}
+
 	contact := filterContactFromProfileByID(profileObj, n.RecipientContactID)
+	if contact == nil {
+		contact = preferredContact(profileObj, n.Channels())
+		if contact != nil {
+			n.RecipientContactID = contact.GetId()
+		}
+	}
 
 	var contactType profilev1.ContactType
 
@@ -155,7 +195,13 @@ func (event *NotificationOutRoute) Execute(ctx context.Context, payload any) err
 		n.NotificationType = models.RouteTypeAny
 	}
 
-	route, err := routeNotification(ctx, event.routeRepo, models.RouteModeTransmit, n)
+	excludedRouteIDs, err := triedRouteIDs(ctx, event.notificationStatusRepo, n)
+	if err != nil {
+		logger.WithError(err).Error("could not load routes already tried")
+		return err
+	}
+
+	route, err := routeNotification(ctx, event.routeBalancer, event.routeRepo, models.RouteModeTransmit, n, excludedRouteIDs...)
 	if err != nil {
 		logger.WithError(err).Error("could not route notification")
 
@@ -185,8 +231,21 @@ func (event *NotificationOutRoute) Execute(ctx context.Context, payload any) err
 		return err
 	}
 
+	if contact != nil {
+		suppressed, sErr := event.suppressRecipient(ctx, n, contact)
+		if sErr != nil {
+			logger.WithError(sErr).Error("could not check recipient suppression")
+			return sErr
+		}
+		if suppressed {
+			logger.Debug("recipient is suppressed, notification not sent")
+			return nil
+		}
+	}
+
 	n.RouteID = route.ID
-	_, err = event.notificationRepo.Update(ctx, n, "route_id")
+	_, err = event.notificationRepo.Update(ctx, n,
+		"route_id", "notification_type", "recipient_profile_id", "recipient_contact_id")
 	if err != nil {
 		logger.WithError(err).Error("could not save routed notification to db")
 		return err
@@ -219,3 +278,129 @@ func (event *NotificationOutRoute) Execute(ctx context.Context, payload any) err
 	logger.Debug("event handler completed successfully")
 	return nil
 }
+
+// suppressRecipient fails a notification whose recipient contact opted out of the
+// channel or of the sender it goes out from, so it is never handed over for delivery. Keyword
+// replies, such as an opt out confirmation, are still delivered. They are recognised by the step
+// the service created them at, never by a parent a caller can name.
+func (event *NotificationOutRoute) suppressRecipient(ctx context.Context, n *models.Notification, contact *profilev1.ContactObject) (bool, error) {
+	if event.suppressionRepo == nil || n.ParentStep == stepKeywordReply {
+		return false, nil
+	}
+
+	suppression, err := event.suppressionRepo.Match(ctx, models.NormalizeContact(contact.GetDetail()), n.NotificationType, n.SenderContactID)
+	if err != nil {
+		return false, err
+	}
+	if suppression == nil {
+		return false, nil
+	}
+
+	nStatus := models.NotificationStatus{
+		NotificationID: n.GetID(),
+		State:          int32(commonv1.STATE_INACTIVE),
+		Status:         int32(commonv1.STATUS_FAILED),
+		Extra: data.JSONMap{
+			"error":          "recipient contact is suppressed",
+			"step":           "suppressed",
+			"suppression_id": suppression.GetID(),
+			"reason":         suppression.Reason,
+		},
+	}
+	nStatus.GenID(ctx)
+
+	err = event.eventMan.Emit(ctx, NotificationStatusSaveEvent, &nStatus)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// deferOutsideDeliveryWindow holds back a notification that is due outside the
+// recipient's delivery window. The notification is returned to the scheduled
+// state so the release scheduler picks it up again at the next allowed slot.
+func (event *NotificationOutRoute) deferOutsideDeliveryWindow(ctx context.Context, n *models.Notification, profileObj *profilev1.ProfileObject) (bool, error) {
+	if bypassesDeliveryWindow(n) {
+		return false, nil
+	}
+
+	var partitionWindow *DeliveryWindow
+	if n.PartitionID != "" && event.tenancyCli != nil {
+		resp, err := event.tenancyCli.GetPartition(ctx, connect.NewRequest(&tenancyv1.GetPartitionRequest{Id: n.PartitionID}))
+		if err != nil {
+			return false, err
+		}
+		partitionWindow = deliveryWindowFromProperties(
+			(&data.JSONMap{}).FromProtoStruct(resp.Msg.GetData().GetProperties()))
+	}
+
+	var profileWindow *DeliveryWindow
+	if profileObj.GetProperties() != nil {
+		profileWindow = deliveryWindowFromProperties(profileObj.GetProperties().AsMap())
+	}
+
+	window := mergeDeliveryWindows(partitionWindow, profileWindow)
+	if window == nil {
+		return false, nil
+	}
+
+	now := time.Now()
+	nextSlot, err := window.NextSlot(now)
+	if err != nil {
+		// A misconfigured window should not hold messages back indefinitely.
+		util.Log(ctx).WithError(err).Warn("ignoring invalid delivery window")
+		return false, nil
+	}
+	if !nextSlot.After(now) {
+		return false, nil
+	}
+
+	n.ReleasedAt = nil
+	n.ScheduledAt = &nextSlot
+	_, err = event.notificationRepo.Update(ctx, n, "released_at", "scheduled_at")
+	if err != nil {
+		return false, err
+	}
+
+	nStatus := models.NotificationStatus{
+		NotificationID: n.GetID(),
+		State:          int32(commonv1.STATE_CHECKED),
+		Status:         int32(commonv1.STATUS_QUEUED),
+		Extra: data.JSONMap{
+			"step":         "deferred_to_delivery_window",
+			"scheduled_at": nextSlot.Format(time.RFC3339),
+			"timezone":     window.Timezone,
+		},
+	}
+	nStatus.GenID(ctx)
+
+	err = event.eventMan.Emit(ctx, NotificationStatusSaveEvent, &nStatus)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// preferredContact picks the profile contact for the first preferred channel the profile can be reached on.
+func preferredContact(profile *profilev1.ProfileObject, channels []string) *profilev1.ContactObject {
+	for _, channel := range channels {
+		var contactType profilev1.ContactType
+		switch channel {
+		case models.RouteTypeSMSForm:
+			contactType = profilev1.ContactType_MSISDN
+		case models.RouteTypeEmailForm:
+			contactType = profilev1.ContactType_EMAIL
+		default:
+			continue
+		}
+
+		for _, contact := range profile.GetContacts() {
+			if contact.GetType() == contactType {
+				return contact
+			}
+		}
+	}
+	return nil
+}
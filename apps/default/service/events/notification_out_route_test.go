@@ -0,0 +1,131 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	profilev1 "buf.build/gen/go/antinvestor/profile/protocolbuffers/go/profile/v1"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/antinvestor/service-notification/apps/default/service/repository"
+	"github.com/pitabwire/frame/v2/data"
+	"github.com/pitabwire/frame/v2/datastore"
+	"github.com/pitabwire/frame/v2/events"
+	"github.com/pitabwire/frame/v2/frametests/definition"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingEvents records the events emitted instead of publishing them, failing to emit the
+// event named by fail.
+type recordingEvents struct {
+	events.Manager
+
+	fail string
+
+	mu      sync.Mutex
+	emitted []any
+}
+
+func (re *recordingEvents) Emit(_ context.Context, name string, payload any) error {
+	if name == re.fail {
+		return errors.New("could not emit " + name)
+	}
+	re.mu.Lock()
+	defer re.mu.Unlock()
+	re.emitted = append(re.emitted, payload)
+	return nil
+}
+
+func (s *NotificationOutQueueTestSuite) Test_suppressRecipient() {
+	s.WithTestDependancies(s.T(), func(t *testing.T, dep *definition.DependencyOption) {
+		ctx, svc := s.startService(t, dep)
+		dbPool := svc.DatastoreManager().GetPool(ctx, datastore.DefaultPoolName)
+
+		suppressionRepo := repository.NewSuppressionRepository(ctx, dbPool, svc.WorkManager())
+		notificationRepo := repository.NewNotificationRepository(ctx, dbPool, svc.WorkManager())
+
+		suppression := &models.Suppression{Contact: "+256700123456", Channel: models.RouteTypeSMSForm, Reason: "opt_out"}
+		suppression.GenID(ctx)
+		require.NoError(t, suppressionRepo.Upsert(ctx, suppression))
+
+		inbound := &models.Notification{NotificationType: models.RouteTypeSMSForm, Message: "STOP"}
+		inbound.GenID(ctx)
+		require.NoError(t, notificationRepo.Create(ctx, inbound))
+
+		recorded := &recordingEvents{}
+		event := &NotificationOutRoute{
+			eventMan:         recorded,
+			notificationRepo: notificationRepo,
+			suppressionRepo:  suppressionRepo,
+		}
+		contact := &profilev1.ContactObject{Id: "contact-1", Detail: "+256 700 123 456"}
+
+		send := &models.Notification{OutBound: true, NotificationType: models.RouteTypeSMSForm, SenderContactID: "sender-contact"}
+		send.GenID(ctx)
+		suppressed, err := event.suppressRecipient(ctx, send, contact)
+		require.NoError(t, err)
+		require.True(t, suppressed)
+		require.Len(t, recorded.emitted, 1, "the suppressed send is failed")
+
+		replyNamedByCaller := &models.Notification{OutBound: true, NotificationType: models.RouteTypeSMSForm, ParentID: inbound.GetID()}
+		replyNamedByCaller.GenID(ctx)
+		suppressed, err = event.suppressRecipient(ctx, replyNamedByCaller, contact)
+		require.NoError(t, err)
+		require.True(t, suppressed, "naming an inbound message as parent does not get past an opt out")
+
+		keywordReply := &models.Notification{
+			OutBound: true, NotificationType: models.RouteTypeSMSForm, ParentID: inbound.GetID(), ParentStep: stepKeywordReply,
+		}
+		keywordReply.GenID(ctx)
+		suppressed, err = event.suppressRecipient(ctx, keywordReply, contact)
+		require.NoError(t, err)
+		require.False(t, suppressed, "the service's own keyword replies are still delivered")
+
+		// Carrier opt outs are recorded for the channel, with the short code only as a detail, and
+		// keep the contact from being reached by any sender over any route.
+		carrierOptOut := &models.Suppression{
+			Contact: "+256700654321", Channel: models.RouteTypeSMSForm, RouteID: "route-at", Reason: "opt_out",
+			Source: "africastalking", Extra: data.JSONMap{"sender_id": "22384"},
+		}
+		carrierOptOut.GenID(ctx)
+		require.NoError(t, suppressionRepo.Upsert(ctx, carrierOptOut))
+
+		throughRoute := &models.Notification{
+			OutBound: true, NotificationType: models.RouteTypeSMSForm, RouteID: "route-other", SenderContactID: "sender-contact",
+		}
+		throughRoute.GenID(ctx)
+		suppressed, err = event.suppressRecipient(ctx, throughRoute, &profilev1.ContactObject{Id: "contact-2", Detail: "+256700654321"})
+		require.NoError(t, err)
+		require.True(t, suppressed, "a send through a route after a carrier opt out is suppressed")
+	})
+}
+
+func (s *NotificationOutQueueTestSuite) Test_queueKeywordReply() {
+	s.WithTestDependancies(s.T(), func(t *testing.T, dep *definition.DependencyOption) {
+		ctx, templateRepo, _, _ := s.createService(t, dep)
+
+		draft := &models.Template{Name: "sms.help", State: models.TemplateStateDraft}
+		require.NoError(t, templateRepo.Create(ctx, draft))
+
+		inbound := &models.Notification{NotificationType: models.RouteTypeSMSForm, SenderContactID: "contact-1"}
+		inbound.GenID(ctx)
+
+		recorded := &recordingEvents{}
+		event := &NotificationInRoute{eventMan: recorded, templateRepo: templateRepo}
+
+		help := &KeywordRule{Name: "help", Action: KeywordActionReply, Template: "sms.help"}
+		require.NoError(t, event.queueKeywordReply(ctx, inbound, help, ""))
+		require.Empty(t, recorded.emitted, "a help rule without a published template sends no reply")
+
+		stop := &KeywordRule{Name: "stop", Action: KeywordActionUnsubscribe, Template: "sms.help"}
+		require.NoError(t, event.queueKeywordReply(ctx, inbound, stop, ""))
+		require.Len(t, recorded.emitted, 2, "the reply and its status")
+
+		reply, ok := recorded.emitted[0].(*models.Notification)
+		require.True(t, ok)
+		require.Equal(t, KeywordUnsubscribeConfirmation, reply.Message)
+		require.Equal(t, stepKeywordReply, reply.ParentStep)
+		require.Equal(t, "contact-1", reply.RecipientContactID)
+	})
+}
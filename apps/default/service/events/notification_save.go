@@ -3,6 +3,7 @@ package events
 import (
 	"context"
 	"errors"
+	"time"
 
 	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
 	"github.com/antinvestor/service-notification/apps/default/service/models"
@@ -84,13 +85,19 @@ func (e *NotificationSave) Execute(ctx context.Context, payload any) error {
 			return err
 		}
 	} else {
+		extra := data.JSONMap{
+			"step": "pending_release",
+		}
+		if notification.IsScheduled() {
+			extra["step"] = "scheduled_release"
+			extra["scheduled_at"] = notification.ScheduledAt.Format(time.RFC3339)
+		}
+
 		nStatus := models.NotificationStatus{
 			NotificationID: notification.GetID(),
 			State:          int32(commonv1.STATE_CHECKED.Number()),
 			Status:         int32(commonv1.STATUS_QUEUED.Number()),
-			Extra: data.JSONMap{
-				"step": "pending_release",
-			},
+			Extra:          extra,
 		}
 
 		nStatus.GenID(ctx)
@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
 	"github.com/antinvestor/service-notification/apps/default/service/models"
 	"github.com/antinvestor/service-notification/apps/default/service/repository"
+	"github.com/antinvestor/service-notification/pkg/constants"
 	"github.com/pitabwire/frame/v2/data"
+	"github.com/pitabwire/frame/v2/events"
 	"github.com/pitabwire/util"
 )
 
@@ -16,14 +19,25 @@ const NotificationStatusSaveEvent = "notificationStatus.save"
 type NotificationStatusSave struct {
 	NotificationRepo       repository.NotificationRepository
 	notificationStatusRepo repository.NotificationStatusRepository
+	routeRepo              repository.RouteRepository
+	aggregateRepo          repository.NotificationAggregateRepository
+	eventMan               events.Manager
+
+	maxRouteAttempts int
 }
 
 // NewNotificationStatusSave creates a new NotificationStatusSave event handler
-func NewNotificationStatusSave(ctx context.Context, notificationRepo repository.NotificationRepository, notificationStatusRepo repository.NotificationStatusRepository) *NotificationStatusSave {
+func NewNotificationStatusSave(ctx context.Context, eventMan events.Manager, notificationRepo repository.NotificationRepository,
+	notificationStatusRepo repository.NotificationStatusRepository, routeRepo repository.RouteRepository,
+	aggregateRepo repository.NotificationAggregateRepository, maxRouteAttempts int) *NotificationStatusSave {
 
 	return &NotificationStatusSave{
 		NotificationRepo:       notificationRepo,
 		notificationStatusRepo: notificationStatusRepo,
+		routeRepo:              routeRepo,
+		aggregateRepo:          aggregateRepo,
+		eventMan:               eventMan,
+		maxRouteAttempts:       maxRouteAttempts,
 	}
 }
 
@@ -55,40 +69,156 @@ func (e *NotificationStatusSave) Execute(ctx context.Context, payload any) error
 	defer logger.Release()
 	logger.Debug("event handler started")
 
-	isDuplicate := false
-	err := e.notificationStatusRepo.Create(ctx, nStatus)
+	n, err := e.NotificationRepo.GetByID(ctx, nStatus.NotificationID)
 	if err != nil {
-		if data.ErrorIsDuplicateKey(err) {
-			isDuplicate = true
-			logger.Debug("notification status already exists, skipping duplicate")
-		} else {
+		logger.WithError(err).Error("could not get notification from db")
+		return err
+	}
+
+	e.annotateRouteAttempt(ctx, n, nStatus)
+
+	err = e.notificationStatusRepo.Create(ctx, nStatus)
+	if err != nil {
+		if !data.ErrorIsDuplicateKey(err) {
 			logger.WithError(err).Error("could not save notification status to db")
 			return err
 		}
-	}
 
-	n, err := e.NotificationRepo.GetByID(ctx, nStatus.NotificationID)
-	if err != nil {
-		logger.WithError(err).Error("could not get notification from db")
-		return err
+		// A redelivered status is followed up from the row stored the first time, which holds
+		// the route the failure happened on even after the notification was moved off it.
+		logger.Debug("notification status already exists, following up the stored one")
+		nStatus, err = e.notificationStatusRepo.GetByID(ctx, nStatus.GetID())
+		if err != nil {
+			logger.WithError(err).Error("could not get notification status from db")
+			return err
+		}
 	}
 
-	n.StatusID = nStatus.ID
-	n.State = nStatus.State
-	if n.TransientID == "" {
-		n.TransientID = nStatus.TransientID
+	// A notification already in this status had it applied by an earlier delivery of the event
+	// that failed before finishing, only the follow ups that can fail are driven again. The
+	// counters were adjusted with the transition itself and are not counted twice.
+	if n.StatusID != nStatus.GetID() {
+		previous, applied, storeErr := e.storeStatus(ctx, n, nStatus)
+		if storeErr != nil {
+			logger.WithError(storeErr).Error("could not save notification update to db")
+			return storeErr
+		}
+		if !applied {
+			logger.Debug("notification status applied by a concurrent delivery")
+			return nil
+		}
+
+		e.trackRouteHealth(ctx, n, nStatus)
+
+		if !n.IsBroadcast() {
+			recordStatusMetrics(ctx, n, nStatus)
+			e.trackParentAggregate(ctx, n, previous, nStatus)
+		}
 	}
 
-	_, err = e.NotificationRepo.Update(ctx, n, "status_id", "state", "transient_id")
+	err = e.rerouteFailure(ctx, n, nStatus)
 	if err != nil {
-		logger.WithError(err).Error("could not save notification update to db")
+		logger.WithError(err).Error("could not reroute failed notification")
 		return err
 	}
 
-	if !isDuplicate {
-		recordStatusMetrics(ctx, n, nStatus)
+	if needsChannelFallback(n, nStatus) {
+		err = e.fallbackToNextChannel(ctx, n)
+		if err != nil {
+			logger.WithError(err).Error("could not fall back to the next channel")
+			return err
+		}
 	}
 
 	logger.Debug("event handler completed successfully")
 	return nil
 }
+
+// statusUpdateAttempts bounds how often a status is applied again after a concurrent save
+// moved the notification first, the event is redelivered once they run out.
+const statusUpdateAttempts = 5
+
+var errStatusUpdateContended = errors.New("notification status kept changing concurrently")
+
+// storeStatus moves the notification to the saved status and returns the notification as it
+// was before, reporting whether this call applied the move. The move only applies to the status the
+// notification was read in, so concurrent saves each see the status they actually replaced and
+// parent counters are adjusted once per change.
+func (e *NotificationStatusSave) storeStatus(ctx context.Context, n *models.Notification,
+	nStatus *models.NotificationStatus) (*models.Notification, bool, error) {
+
+	for range statusUpdateAttempts {
+		if n.StatusID == nStatus.GetID() {
+			return nil, false, nil
+		}
+		previous := *n
+
+		n.StatusID = nStatus.ID
+		n.State = nStatus.State
+		n.Status = nStatus.Status
+		if n.TransientID == "" {
+			n.TransientID = nStatus.TransientID
+		}
+
+		changed, err := e.NotificationRepo.UpdateStatus(ctx, n, previous.Status)
+		if err != nil {
+			return nil, false, err
+		}
+		if changed {
+			return &previous, true, nil
+		}
+
+		current, err := e.NotificationRepo.GetByID(ctx, n.GetID())
+		if err != nil {
+			return nil, false, err
+		}
+		*n = *current
+	}
+
+	return nil, false, errStatusUpdateContended
+}
+
+// routeFailureSteps are the failure steps attributable to the route itself,
+// as opposed to problems with the notification content or recipient.
+var routeFailureSteps = map[string]bool{
+	"publish_to_queue":       true,
+	"publish_to_queue_retry": true,
+	"load_route":             true,
+}
+
+// isRouteFailure reports whether a failed status counts against the route health. Integrations
+// mark the failures of the gateway itself, such as an unreachable server or a lost bind, for
+// reroute; failures of a single recipient like an invalid number or a bounce leave the route
+// healthy.
+func isRouteFailure(nStatus *models.NotificationStatus) bool {
+	if commonv1.STATUS(nStatus.Status) != commonv1.STATUS_FAILED {
+		return false
+	}
+	if transport, _ := nStatus.Extra[constants.StatusExtraReroute].(bool); transport {
+		return true
+	}
+	step, _ := nStatus.Extra["step"].(string)
+	return routeFailureSteps[step]
+}
+
+// trackRouteHealth feeds publish and delivery outcomes back into the route
+// consecutive failure count used by route selection.
+func (e *NotificationStatusSave) trackRouteHealth(ctx context.Context, n *models.Notification, nStatus *models.NotificationStatus) {
+	if e.routeRepo == nil || !n.OutBound || n.RouteID == "" {
+		return
+	}
+
+	var err error
+	switch {
+	case commonv1.STATUS(nStatus.Status) == commonv1.STATUS_SUCCESSFUL:
+		err = e.routeRepo.RecordSuccess(ctx, n.RouteID)
+	case isRouteFailure(nStatus):
+		err = e.routeRepo.RecordFailure(ctx, n.RouteID)
+	default:
+		return
+	}
+
+	if err != nil {
+		util.Log(ctx).WithError(err).WithField("route_id", n.RouteID).Warn("could not update route health")
+	}
+}
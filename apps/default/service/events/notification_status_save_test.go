@@ -0,0 +1,118 @@
+package events
+
+import (
+	"testing"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/antinvestor/service-notification/apps/default/service/repository"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/pitabwire/frame/v2/data"
+	"github.com/pitabwire/frame/v2/datastore"
+	"github.com/pitabwire/frame/v2/frametests/definition"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *NotificationOutQueueTestSuite) Test_NotificationStatusSave_RedeliveredReroute() {
+	s.WithTestDependancies(s.T(), func(t *testing.T, dep *definition.DependencyOption) {
+		ctx, svc := s.startService(t, dep)
+		dbPool := svc.DatastoreManager().GetPool(ctx, datastore.DefaultPoolName)
+
+		notificationRepo := repository.NewNotificationRepository(ctx, dbPool, svc.WorkManager())
+		notificationStatusRepo := repository.NewNotificationStatusRepository(ctx, dbPool, svc.WorkManager())
+
+		n := &models.Notification{OutBound: true, NotificationType: models.RouteTypeSMSForm, RouteID: "route-a"}
+		n.GenID(ctx)
+		require.NoError(t, notificationRepo.Create(ctx, n))
+
+		failure := func() *models.NotificationStatus {
+			nStatus := &models.NotificationStatus{
+				NotificationID: n.GetID(),
+				State:          int32(commonv1.STATE_INACTIVE),
+				Status:         int32(commonv1.STATUS_FAILED),
+				Extra:          data.JSONMap{constants.StatusExtraReroute: true, "error": "gateway unreachable"},
+			}
+			nStatus.ID = "d3bn0s23l8og00vgjqb0"
+			return nStatus
+		}
+
+		// The first delivery moves the notification off its route but cannot hand it to the next one.
+		failing := &recordingEvents{fail: NotificationOutRouteEvent}
+		event := NewNotificationStatusSave(ctx, failing, notificationRepo, notificationStatusRepo, nil, nil, 3)
+		require.Error(t, event.Execute(ctx, failure()))
+
+		stored, err := notificationRepo.GetByID(ctx, n.GetID())
+		require.NoError(t, err)
+		require.Empty(t, stored.RouteID)
+		require.Equal(t, "d3bn0s23l8og00vgjqb0", stored.StatusID)
+
+		// The redelivered status still reroutes the notification rather than stranding it.
+		recorded := &recordingEvents{}
+		event = NewNotificationStatusSave(ctx, recorded, notificationRepo, notificationStatusRepo, nil, nil, 3)
+		require.NoError(t, event.Execute(ctx, failure()))
+		require.Len(t, recorded.emitted, 2, "the reroute status and the route event")
+		require.Equal(t, n.GetID(), recorded.emitted[1])
+
+		rerouted, ok := recorded.emitted[0].(*models.NotificationStatus)
+		require.True(t, ok)
+		require.Equal(t, int32(commonv1.STATUS_QUEUED), rerouted.Status)
+		require.Equal(t, "reroute", rerouted.Extra["step"])
+	})
+}
+
+func (s *NotificationOutQueueTestSuite) Test_NotificationStatusSave_FallbackAggregate() {
+	s.WithTestDependancies(s.T(), func(t *testing.T, dep *definition.DependencyOption) {
+		ctx, svc := s.startService(t, dep)
+		dbPool := svc.DatastoreManager().GetPool(ctx, datastore.DefaultPoolName)
+
+		notificationRepo := repository.NewNotificationRepository(ctx, dbPool, svc.WorkManager())
+		notificationStatusRepo := repository.NewNotificationStatusRepository(ctx, dbPool, svc.WorkManager())
+		aggregateRepo := repository.NewNotificationAggregateRepository(ctx, dbPool, svc.WorkManager())
+
+		broadcast := &models.Notification{OutBound: true, NotificationType: models.NotificationTypeBroadcast}
+		broadcast.GenID(ctx)
+		require.NoError(t, notificationRepo.Create(ctx, broadcast))
+
+		child := &models.Notification{
+			ParentID: broadcast.GetID(), OutBound: true, NotificationType: models.RouteTypeEmailForm, Message: "hello",
+			Payload: data.JSONMap{models.PayloadKeyChannels: []any{models.RouteTypeEmailForm, models.RouteTypeSMSForm}},
+		}
+		child.GenID(ctx)
+		require.NoError(t, notificationRepo.Create(ctx, child))
+
+		recorded := &recordingEvents{}
+		event := NewNotificationStatusSave(ctx, recorded, notificationRepo, notificationStatusRepo, nil, aggregateRepo, 3)
+		save := func(nStatus *models.NotificationStatus) {
+			nStatus.GenID(ctx)
+			require.NoError(t, event.Execute(ctx, nStatus))
+		}
+
+		save(&models.NotificationStatus{NotificationID: child.GetID(), Status: int32(commonv1.STATUS_QUEUED)})
+		save(&models.NotificationStatus{
+			NotificationID: child.GetID(), Status: int32(commonv1.STATUS_FAILED),
+			Extra: data.JSONMap{constants.StatusExtraStep: constants.StatusStepSubmit},
+		})
+
+		// The child falls back to sms, its status, route and handover events are recorded.
+		require.Len(t, recorded.emitted, 3)
+		fallbackQueued := recorded.emitted[0].(*models.NotificationStatus)
+		handover := recorded.emitted[2].(*models.NotificationStatus)
+		recorded.emitted = nil
+
+		save(fallbackQueued)
+		save(handover)
+		save(&models.NotificationStatus{NotificationID: fallbackQueued.NotificationID, Status: int32(commonv1.STATUS_SUCCESSFUL)})
+
+		counters, err := aggregateRepo.GetByParentID(ctx, broadcast.GetID())
+		require.NoError(t, err)
+
+		counts := map[string]int64{}
+		for _, counter := range counters {
+			if counter.Count != 0 {
+				counts[counter.Kind+":"+counter.Key] = counter.Count
+			}
+		}
+		require.Equal(t, map[string]int64{models.AggregateKindStatus + ":successful": 1}, counts,
+			"the child is counted once, by the outcome of its fallback")
+	})
+}
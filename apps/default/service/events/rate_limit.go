@@ -0,0 +1,114 @@
+package events
+
+import (
+	"strings"
+	"time"
+
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+)
+
+// RateLimitProperty is the partition property holding outbound rate limits, for example:
+//
+//	[{"scope": "recipient", "template": "otp.verification", "limit": 5, "window": "10m", "action": "reject"}]
+//
+// A notification must fit within every rule that applies to it.
+const RateLimitProperty = "rate_limits"
+
+const (
+	RateLimitScopePartition = "partition"
+	RateLimitScopeRecipient = "recipient"
+	RateLimitScopeTemplate  = "template"
+
+	RateLimitActionReject = "reject"
+	RateLimitActionDefer  = "defer"
+)
+
+// RateLimitRule caps how many outbound notifications a partition, a recipient or a
+// template may send within a fixed window.
+type RateLimitRule struct {
+	Scope    string
+	Template string
+	Limit    int
+	Window   time.Duration
+	Action   string
+}
+
+// RateLimitRulesFromProperties reads the valid rate limit rules from partition properties.
+func RateLimitRulesFromProperties(properties map[string]any) []*RateLimitRule {
+	rawRules, ok := properties[RateLimitProperty].([]any)
+	if !ok {
+		return nil
+	}
+
+	var rules []*RateLimitRule
+	for _, r := range rawRules {
+		raw, rOk := r.(map[string]any)
+		if !rOk {
+			continue
+		}
+
+		rule := &RateLimitRule{Action: RateLimitActionReject}
+		rule.Scope, _ = raw["scope"].(string)
+		rule.Template, _ = raw["template"].(string)
+		if action, aOk := raw["action"].(string); aOk && action != "" {
+			rule.Action = strings.ToLower(action)
+		}
+		if limit, lOk := raw["limit"].(float64); lOk {
+			rule.Limit = int(limit)
+		}
+		if window, wOk := raw["window"].(string); wOk {
+			rule.Window, _ = time.ParseDuration(window)
+		}
+
+		if rule.valid() {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+func (r *RateLimitRule) valid() bool {
+	switch r.Scope {
+	case RateLimitScopePartition, RateLimitScopeRecipient, RateLimitScopeTemplate:
+	default:
+		return false
+	}
+	if r.Action != RateLimitActionReject && r.Action != RateLimitActionDefer {
+		return false
+	}
+	return r.Limit > 0 && r.Window > 0
+}
+
+// CounterKey returns the counter a notification is counted against under this rule,
+// or an empty key when the rule does not apply to it. Recipients are identified by
+// their contact when one was given, otherwise by their profile.
+func (r *RateLimitRule) CounterKey(n *models.Notification, templateName string) string {
+	if r.Template != "" && r.Template != templateName {
+		return ""
+	}
+
+	subject := ""
+	switch r.Scope {
+	case RateLimitScopeRecipient:
+		subject = n.RecipientContactID
+		if subject == "" {
+			subject = n.RecipientProfileID
+		}
+		if subject == "" {
+			return ""
+		}
+	case RateLimitScopeTemplate:
+		if templateName == "" {
+			return ""
+		}
+		subject = templateName
+	}
+
+	return strings.Join([]string{n.PartitionID, r.Scope, subject, r.Template, r.Window.String()}, "|")
+}
+
+// WindowStart returns the start of the fixed window containing at.
+func (r *RateLimitRule) WindowStart(at time.Time) time.Time {
+	return at.Truncate(r.Window)
+}
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitRulesFromProperties(t *testing.T) {
+	var properties map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{"rate_limits": [
+		{"scope": "recipient", "template": "otp.verification", "limit": 5, "window": "10m"},
+		{"scope": "partition", "limit": 1000, "window": "1h", "action": "Defer"},
+		{"scope": "recipient", "limit": 0, "window": "1m"},
+		{"scope": "contact", "limit": 5, "window": "1m"},
+		{"scope": "template", "limit": 5, "window": "forever"}
+	]}`), &properties))
+
+	rules := RateLimitRulesFromProperties(properties)
+	require.Len(t, rules, 2, "rules with no limit, an unknown scope or a bad window are dropped")
+
+	require.Equal(t, &RateLimitRule{
+		Scope: RateLimitScopeRecipient, Template: "otp.verification", Limit: 5, Window: 10 * time.Minute, Action: RateLimitActionReject,
+	}, rules[0])
+	require.Equal(t, RateLimitActionDefer, rules[1].Action)
+
+	require.Nil(t, RateLimitRulesFromProperties(map[string]any{}))
+}
+
+func TestRateLimitRuleCounterKey(t *testing.T) {
+	n := &models.Notification{RecipientProfileID: "profile-a"}
+	n.PartitionID = "partition-a"
+
+	otp := &RateLimitRule{Scope: RateLimitScopeRecipient, Template: "otp.verification", Limit: 5, Window: 10 * time.Minute}
+	require.Empty(t, otp.CounterKey(n, "welcome"), "template bound rules skip other templates")
+	require.Contains(t, otp.CounterKey(n, "otp.verification"), "profile-a")
+
+	n.RecipientContactID = "contact-a"
+	require.Contains(t, otp.CounterKey(n, "otp.verification"), "contact-a", "contacts identify recipients when given")
+
+	perTemplate := &RateLimitRule{Scope: RateLimitScopeTemplate, Limit: 5, Window: time.Minute}
+	require.Empty(t, perTemplate.CounterKey(n, ""), "untemplated sends are not counted per template")
+
+	partition := &RateLimitRule{Scope: RateLimitScopePartition, Limit: 5, Window: time.Minute}
+	other := &models.Notification{RecipientContactID: "contact-b"}
+	other.PartitionID = "partition-a"
+	require.Equal(t, partition.CounterKey(n, ""), partition.CounterKey(other, ""), "partition rules share one counter")
+
+	at := time.Date(2026, 1, 2, 10, 17, 30, 0, time.UTC)
+	require.Equal(t, time.Date(2026, 1, 2, 10, 10, 0, 0, time.UTC), otp.WindowStart(at))
+}
@@ -0,0 +1,174 @@
+package events
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/antinvestor/service-notification/apps/default/service/repository"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/pitabwire/frame/v2/data"
+	"github.com/pitabwire/util"
+)
+
+// isReroutable reports whether a failed outbound delivery was flagged by its
+// integration as worth retrying on another route.
+func isReroutable(n *models.Notification, nStatus *models.NotificationStatus) bool {
+	if !n.OutBound || commonv1.STATUS(nStatus.Status) != commonv1.STATUS_FAILED {
+		return false
+	}
+	reroute, _ := nStatus.Extra[constants.StatusExtraReroute].(bool)
+	return reroute
+}
+
+// annotateRouteAttempt ties a reroutable failure to the route it happened on and
+// keeps the attempt history on later statuses of a rerouted notification, so the
+// latest status always shows which routes were tried.
+func (e *NotificationStatusSave) annotateRouteAttempt(ctx context.Context, n *models.Notification, nStatus *models.NotificationStatus) {
+	if isReroutable(n, nStatus) && n.RouteID != "" {
+		if nStatus.Extra == nil {
+			nStatus.Extra = data.JSONMap{}
+		}
+		nStatus.Extra[constants.StatusExtraRouteID] = n.RouteID
+	}
+
+	if n.RouteAttempt == 0 || n.StatusID == "" {
+		return
+	}
+	if _, ok := nStatus.Extra[constants.StatusExtraRouteAttempts]; ok {
+		return
+	}
+
+	previous, err := e.notificationStatusRepo.GetByID(ctx, n.StatusID)
+	if err != nil {
+		util.Log(ctx).WithError(err).WithField("status_id", n.StatusID).Debug("could not carry route attempts forward")
+		return
+	}
+
+	if attempts, ok := previous.Extra[constants.StatusExtraRouteAttempts]; ok {
+		if nStatus.Extra == nil {
+			nStatus.Extra = data.JSONMap{}
+		}
+		nStatus.Extra[constants.StatusExtraRouteAttempts] = attempts
+	}
+}
+
+// routeAttempts lists, oldest first, the routes a notification failed on and why.
+func routeAttempts(ctx context.Context, notificationStatusRepo repository.NotificationStatusRepository, notificationID string) ([]any, error) {
+	statuses, err := notificationStatusRepo.GetByNotificationID(ctx, notificationID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].CreatedAt.Before(statuses[j].CreatedAt)
+	})
+
+	attempts := []any{}
+	for _, s := range statuses {
+		if commonv1.STATUS(s.Status) != commonv1.STATUS_FAILED {
+			continue
+		}
+		if reroute, _ := s.Extra[constants.StatusExtraReroute].(bool); !reroute {
+			continue
+		}
+		routeID, _ := s.Extra[constants.StatusExtraRouteID].(string)
+		if routeID == "" {
+			continue
+		}
+
+		attempts = append(attempts, map[string]any{
+			"route_id":  routeID,
+			"error":     s.Extra["error"],
+			"failed_at": s.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return attempts, nil
+}
+
+// triedRouteIDs returns the routes a rerouted notification must not be sent through again.
+func triedRouteIDs(ctx context.Context, notificationStatusRepo repository.NotificationStatusRepository, n *models.Notification) ([]string, error) {
+	if n.RouteAttempt == 0 {
+		return nil, nil
+	}
+
+	attempts, err := routeAttempts(ctx, notificationStatusRepo, n.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	routeIDs := make([]string, 0, len(attempts))
+	for _, attempt := range attempts {
+		if routeID, ok := attempt.(map[string]any)["route_id"].(string); ok {
+			routeIDs = append(routeIDs, routeID)
+		}
+	}
+	return routeIDs, nil
+}
+
+// rerouteFailure sends a notification that failed on a route it can be rerouted from to its
+// next route, or fails it for good once every allowed attempt is used. The route is read off
+// the failure rather than the notification, which no longer holds it once a reroute started.
+func (e *NotificationStatusSave) rerouteFailure(ctx context.Context, n *models.Notification, nStatus *models.NotificationStatus) error {
+	routeID, _ := nStatus.Extra[constants.StatusExtraRouteID].(string)
+	if routeID == "" || !isReroutable(n, nStatus) {
+		return nil
+	}
+
+	attempts, err := routeAttempts(ctx, e.notificationStatusRepo, n.GetID())
+	if err != nil {
+		return err
+	}
+
+	reroute := len(attempts) < e.maxRouteAttempts
+	if reroute {
+		n.RouteID = ""
+		n.RouteAttempt = len(attempts)
+		// The next route publishes the notification again under a new dispatch claim.
+		n.DispatchedAt = nil
+
+		_, err = e.NotificationRepo.Update(ctx, n, "route_id", "route_attempt", "dispatched_at")
+		if err != nil {
+			return err
+		}
+	}
+
+	return e.emitReroute(ctx, n, attempts, reroute)
+}
+
+// emitReroute sends a failed notification back for routing, or records that
+// every allowed route attempt has been used up.
+func (e *NotificationStatusSave) emitReroute(ctx context.Context, n *models.Notification, attempts []any, reroute bool) error {
+	nStatus := models.NotificationStatus{
+		NotificationID: n.GetID(),
+		State:          int32(commonv1.STATE_ACTIVE),
+		Status:         int32(commonv1.STATUS_QUEUED),
+		Extra: data.JSONMap{
+			"step":                             "reroute",
+			constants.StatusExtraRouteAttempts: attempts,
+		},
+	}
+
+	if !reroute {
+		nStatus.State = int32(commonv1.STATE_INACTIVE)
+		nStatus.Status = int32(commonv1.STATUS_FAILED)
+		nStatus.Extra["step"] = "reroute_exhausted"
+		nStatus.Extra["error"] = "delivery failed on every route attempted"
+	}
+
+	nStatus.GenID(ctx)
+
+	err := e.eventMan.Emit(ctx, NotificationStatusSaveEvent, &nStatus)
+	if err != nil {
+		return err
+	}
+
+	if !reroute {
+		return nil
+	}
+
+	return e.eventMan.Emit(ctx, NotificationOutRouteEvent, n.GetID())
+}
@@ -0,0 +1,161 @@
+package events
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+)
+
+// routeStateTTL is how long the balancer keeps the state of a route it has not been offered,
+// so routes that were deleted or moved to another partition do not pile up.
+const routeStateTTL = time.Hour
+
+// routeState is what the balancer remembers about one route.
+type routeState struct {
+	// current is the smooth weighted round-robin counter of the route.
+	current int
+	// probedAt is when a failing route was last offered a single probe.
+	probedAt time.Time
+	seenAt   time.Time
+}
+
+// routeBalancer spreads traffic over routes of the same tier using smooth
+// weighted round-robin, so a 3:1 weighting yields an interleaved a,a,b,a
+// sequence rather than bursts to the heavier route. Each event handler holds its
+// own balancer, so every process honours the weights on its share of the traffic.
+type routeBalancer struct {
+	mu       sync.Mutex
+	routes   map[string]*routeState
+	prunedAt time.Time
+}
+
+func newRouteBalancer() *routeBalancer {
+	return &routeBalancer{routes: map[string]*routeState{}}
+}
+
+// pick chooses a route from the highest priority tier with healthy routes.
+// A failing route past its recovery interval is offered a single probe before it
+// takes a full share again. When every enabled route is unhealthy the best tier
+// is still used, a degraded route is preferable to failing every notification outright.
+func (rb *routeBalancer) pick(routes []*models.Route, now time.Time) *models.Route {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	rb.prune(now)
+
+	var enabled, healthy, recovering []*models.Route
+	for _, route := range routes {
+		if !route.IsEnabled() {
+			continue
+		}
+		enabled = append(enabled, route)
+
+		state := rb.state(route.GetID(), now)
+		switch {
+		case route.IsHealthy():
+			state.probedAt = time.Time{}
+			healthy = append(healthy, route)
+		case route.IsRecovering(now) && rb.mayProbe(state, route, now):
+			recovering = append(recovering, route)
+		}
+	}
+
+	if probe := rb.probe(recovering, healthy, now); probe != nil {
+		return probe
+	}
+
+	candidates := healthy
+	if len(candidates) == 0 {
+		candidates = enabled
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	tier := topTier(candidates)
+	if len(tier) == 1 {
+		return tier[0]
+	}
+
+	var selected *models.Route
+	total := 0
+	for _, route := range tier {
+		weight := max(route.Weight, 1)
+		total += weight
+		rb.routes[route.GetID()].current += weight
+		if selected == nil || rb.routes[route.GetID()].current > rb.routes[selected.GetID()].current {
+			selected = route
+		}
+	}
+	rb.routes[selected.GetID()].current -= total
+
+	return selected
+}
+
+// probe returns a recovering route to try once, when it would be preferred over or share a
+// tier with the healthy routes. The probe is not offered again until the route fails anew,
+// its success puts it back in rotation.
+func (rb *routeBalancer) probe(recovering, healthy []*models.Route, now time.Time) *models.Route {
+	if len(recovering) == 0 {
+		return nil
+	}
+
+	probe := topTier(recovering)[0]
+	if len(healthy) > 0 && probe.Priority > topTier(healthy)[0].Priority {
+		return nil
+	}
+
+	rb.routes[probe.GetID()].probedAt = now
+	return probe
+}
+
+// mayProbe reports whether a recovering route is due a probe: it failed again since the last
+// one, or the last one never reported back.
+func (rb *routeBalancer) mayProbe(state *routeState, route *models.Route, now time.Time) bool {
+	return state.probedAt.Before(*route.LastFailureAt) || now.Sub(state.probedAt) > models.RouteRecoveryInterval
+}
+
+func (rb *routeBalancer) state(routeID string, now time.Time) *routeState {
+	state, ok := rb.routes[routeID]
+	if !ok {
+		state = &routeState{}
+		rb.routes[routeID] = state
+	}
+	state.seenAt = now
+	return state
+}
+
+// prune forgets routes that have not been offered for routeStateTTL.
+func (rb *routeBalancer) prune(now time.Time) {
+	if now.Sub(rb.prunedAt) < routeStateTTL {
+		return
+	}
+	for routeID, state := range rb.routes {
+		if now.Sub(state.seenAt) >= routeStateTTL {
+			delete(rb.routes, routeID)
+		}
+	}
+	rb.prunedAt = now
+}
+
+// topTier returns the routes sharing the lowest priority value, in a stable order.
+func topTier(routes []*models.Route) []*models.Route {
+	best := routes[0].Priority
+	for _, route := range routes[1:] {
+		best = min(best, route.Priority)
+	}
+
+	var tier []*models.Route
+	for _, route := range routes {
+		if route.Priority == best {
+			tier = append(tier, route)
+		}
+	}
+
+	sort.SliceStable(tier, func(i, j int) bool {
+		return tier[i].GetID() < tier[j].GetID()
+	})
+	return tier
+}
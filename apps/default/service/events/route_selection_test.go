@@ -0,0 +1,107 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/pitabwire/frame/v2/data"
+	"github.com/stretchr/testify/require"
+)
+
+func testRoute(id string, priority, weight int) *models.Route {
+	r := &models.Route{Priority: priority, Weight: weight, FailureThreshold: 3}
+	r.ID = id
+	return r
+}
+
+func TestRouteBalancerWeightedRoundRobin(t *testing.T) {
+	rb := newRouteBalancer()
+	now := time.Now()
+
+	routes := []*models.Route{testRoute("a", 0, 3), testRoute("b", 0, 1)}
+
+	counts := map[string]int{}
+	var sequence []string
+	for range 8 {
+		route := rb.pick(routes, now)
+		counts[route.GetID()]++
+		sequence = append(sequence, route.GetID())
+	}
+
+	require.Equal(t, 6, counts["a"])
+	require.Equal(t, 2, counts["b"])
+	require.Equal(t, []string{"a", "a", "b", "a", "a", "a", "b", "a"}, sequence)
+}
+
+func TestRouteBalancerTiersAndHealth(t *testing.T) {
+	rb := newRouteBalancer()
+	now := time.Now()
+
+	primary := testRoute("primary", 0, 1)
+	backup := testRoute("backup", 1, 1)
+	routes := []*models.Route{backup, primary}
+
+	require.Equal(t, "primary", rb.pick(routes, now).GetID(), "lowest priority tier is preferred")
+
+	recent := now.Add(-time.Minute)
+	primary.ConsecutiveFailures = 3
+	primary.LastFailureAt = &recent
+	require.Equal(t, "backup", rb.pick(routes, now).GetID(), "failing route drops out of rotation")
+
+	stale := now.Add(-2 * models.RouteRecoveryInterval)
+	primary.LastFailureAt = &stale
+	require.Equal(t, "primary", rb.pick(routes, now).GetID(), "route is probed again after recovery interval")
+	require.Equal(t, "backup", rb.pick(routes, now).GetID(), "a recovering route gets a single probe")
+
+	later := now.Add(2 * models.RouteRecoveryInterval)
+	require.Equal(t, "primary", rb.pick(routes, later).GetID(), "a probe that never reported back is sent again")
+	require.Equal(t, "backup", rb.pick(routes, later).GetID())
+
+	primary.ConsecutiveFailures = 0
+	require.Equal(t, "primary", rb.pick(routes, later).GetID(), "a successful probe restores the route")
+	require.Equal(t, "primary", rb.pick(routes, later).GetID())
+
+	primary.ConsecutiveFailures = 3
+	primary.LastFailureAt = &stale
+	require.Equal(t, "primary", rb.pick(routes, now).GetID(), "a route failing again is probed after its next interval")
+
+	disabled := false
+	primary.Enabled = &disabled
+	backup.ConsecutiveFailures = 5
+	backup.LastFailureAt = &recent
+	require.Equal(t, "backup", rb.pick(routes, now).GetID(), "degraded route is used when nothing is healthy")
+
+	backup.Enabled = &disabled
+	require.Nil(t, rb.pick(routes, now))
+}
+
+func TestRouteBalancerPrunesRoutes(t *testing.T) {
+	rb := newRouteBalancer()
+	now := time.Now()
+
+	rb.pick([]*models.Route{testRoute("old-a", 0, 1), testRoute("old-b", 0, 1)}, now)
+	require.Len(t, rb.routes, 2)
+
+	rb.pick([]*models.Route{testRoute("a", 0, 1), testRoute("b", 0, 1)}, now.Add(routeStateTTL))
+	require.Len(t, rb.routes, 2, "routes no longer offered are forgotten")
+	require.Contains(t, rb.routes, "a")
+	require.NotContains(t, rb.routes, "old-a")
+}
+
+func TestIsRouteFailure(t *testing.T) {
+	failed := func(extra data.JSONMap) *models.NotificationStatus {
+		return &models.NotificationStatus{Status: int32(commonv1.STATUS_FAILED), Extra: extra}
+	}
+
+	require.True(t, isRouteFailure(failed(data.JSONMap{constants.StatusExtraReroute: true})), "gateway unreachable")
+	require.True(t, isRouteFailure(failed(data.JSONMap{"step": "publish_to_queue"})))
+	require.False(t, isRouteFailure(failed(data.JSONMap{"error": "invalid phone number"})), "recipient errors leave the route healthy")
+	require.False(t, isRouteFailure(failed(data.JSONMap{"step": "email_bounce"})))
+	require.False(t, isRouteFailure(&models.NotificationStatus{
+		Status: int32(commonv1.STATUS_SUCCESSFUL),
+		Extra:  data.JSONMap{constants.StatusExtraReroute: true},
+	}))
+}
@@ -109,6 +109,52 @@ func (ns *NotificationServer) Release(ctx context.Context, req *connect.Request[
 
 }
 
+// Broadcast method expands one notification to an audience and streams the expansion progress
+func (ns *NotificationServer) Broadcast(ctx context.Context, req *connect.Request[notificationv1.BroadcastRequest], stream *connect.ServerStream[notificationv1.BroadcastResponse]) error {
+
+	result, err := ns.notificationBusiness.Broadcast(ctx, req.Msg)
+	if err != nil {
+		return apperrors.CleanErr(err)
+	}
+
+	err = workerpool.ConsumeResultStream(ctx, result, func(res *notificationv1.BroadcastResponse) error {
+		return stream.Send(res)
+	})
+	if err != nil {
+		return apperrors.CleanErr(err)
+	}
+
+	return nil
+}
+
+// Cancel method withdraws queued notifications and streams the outcome for each requested notification
+func (ns *NotificationServer) Cancel(ctx context.Context, req *connect.Request[notificationv1.CancelRequest], stream *connect.ServerStream[notificationv1.CancelResponse]) error {
+
+	result, err := ns.notificationBusiness.Cancel(ctx, req.Msg)
+	if err != nil {
+		return apperrors.CleanErr(err)
+	}
+
+	err = workerpool.ConsumeResultStream(ctx, result, func(res *notificationv1.CancelResponse) error {
+		return stream.Send(res)
+	})
+	if err != nil {
+		return apperrors.CleanErr(err)
+	}
+
+	return nil
+}
+
+// BroadcastStatus method returns the aggregate delivery progress of a broadcast
+func (ns *NotificationServer) BroadcastStatus(ctx context.Context, req *connect.Request[commonv1.StatusRequest]) (*connect.Response[notificationv1.BroadcastStatusResponse], error) {
+
+	resp, err := ns.notificationBusiness.BroadcastStatus(ctx, req.Msg)
+	if err != nil {
+		return nil, apperrors.CleanErr(err)
+	}
+	return connect.NewResponse(resp), nil
+}
+
 // Receive method is for client request for particular notification responses from system
 func (ns *NotificationServer) Receive(ctx context.Context, req *connect.Request[notificationv1.ReceiveRequest], stream *connect.ServerStream[notificationv1.ReceiveResponse]) error {
 
@@ -153,6 +199,39 @@ func (ns *NotificationServer) TemplateSearch(ctx context.Context, req *connect.R
 	return nil
 }
 
+func (ns *NotificationServer) SuppressionSearch(ctx context.Context, req *connect.Request[notificationv1.SuppressionSearchRequest], stream *connect.ServerStream[notificationv1.SuppressionSearchResponse]) error {
+
+	err := ns.notificationBusiness.SuppressionSearch(ctx, req.Msg,
+		func(_ context.Context, batch []*notificationv1.Suppression) error {
+			return stream.Send(&notificationv1.SuppressionSearchResponse{Data: batch})
+		})
+	if err != nil {
+		return apperrors.CleanErr(err)
+	}
+
+	return nil
+}
+
+func (ns *NotificationServer) SuppressionAdd(ctx context.Context, req *connect.Request[notificationv1.SuppressionAddRequest]) (*connect.Response[notificationv1.SuppressionAddResponse], error) {
+
+	response, err := ns.notificationBusiness.SuppressionAdd(ctx, req.Msg)
+	if err != nil {
+		return nil, apperrors.CleanErr(err)
+	}
+
+	return connect.NewResponse(&notificationv1.SuppressionAddResponse{Data: response}), nil
+}
+
+func (ns *NotificationServer) SuppressionRemove(ctx context.Context, req *connect.Request[notificationv1.SuppressionRemoveRequest]) (*connect.Response[notificationv1.SuppressionRemoveResponse], error) {
+
+	removed, err := ns.notificationBusiness.SuppressionRemove(ctx, req.Msg)
+	if err != nil {
+		return nil, apperrors.CleanErr(err)
+	}
+
+	return connect.NewResponse(&notificationv1.SuppressionRemoveResponse{Id: removed}), nil
+}
+
 func (ns *NotificationServer) TemplateSave(ctx context.Context, req *connect.Request[notificationv1.TemplateSaveRequest]) (*connect.Response[notificationv1.TemplateSaveResponse], error) {
 
 	response, err := ns.notificationBusiness.TemplateSave(ctx, req.Msg)
@@ -163,3 +242,53 @@ func (ns *NotificationServer) TemplateSave(ctx context.Context, req *connect.Req
 
 	return connect.NewResponse(&notificationv1.TemplateSaveResponse{Data: response}), nil
 }
+
+func (ns *NotificationServer) TemplatePublish(ctx context.Context, req *connect.Request[notificationv1.TemplatePublishRequest]) (*connect.Response[notificationv1.TemplatePublishResponse], error) {
+
+	response, err := ns.notificationBusiness.TemplatePublish(ctx, req.Msg)
+	if err != nil {
+		return nil, apperrors.CleanErr(err)
+	}
+
+	return connect.NewResponse(&notificationv1.TemplatePublishResponse{Data: response}), nil
+}
+
+func (ns *NotificationServer) TemplateRollback(ctx context.Context, req *connect.Request[notificationv1.TemplateRollbackRequest]) (*connect.Response[notificationv1.TemplateRollbackResponse], error) {
+
+	response, err := ns.notificationBusiness.TemplateRollback(ctx, req.Msg)
+	if err != nil {
+		return nil, apperrors.CleanErr(err)
+	}
+
+	return connect.NewResponse(&notificationv1.TemplateRollbackResponse{Data: response}), nil
+}
+
+func (ns *NotificationServer) TemplateUpdate(ctx context.Context, req *connect.Request[notificationv1.TemplateUpdateRequest]) (*connect.Response[notificationv1.TemplateUpdateResponse], error) {
+
+	response, err := ns.notificationBusiness.TemplateUpdate(ctx, req.Msg)
+	if err != nil {
+		return nil, apperrors.CleanErr(err)
+	}
+
+	return connect.NewResponse(&notificationv1.TemplateUpdateResponse{Data: response}), nil
+}
+
+func (ns *NotificationServer) TemplateDelete(ctx context.Context, req *connect.Request[notificationv1.TemplateDeleteRequest]) (*connect.Response[notificationv1.TemplateDeleteResponse], error) {
+
+	removed, err := ns.notificationBusiness.TemplateDelete(ctx, req.Msg)
+	if err != nil {
+		return nil, apperrors.CleanErr(err)
+	}
+
+	return connect.NewResponse(&notificationv1.TemplateDeleteResponse{Id: removed}), nil
+}
+
+func (ns *NotificationServer) TemplatePreview(ctx context.Context, req *connect.Request[notificationv1.TemplatePreviewRequest]) (*connect.Response[notificationv1.TemplatePreviewResponse], error) {
+
+	response, err := ns.notificationBusiness.TemplatePreview(ctx, req.Msg)
+	if err != nil {
+		return nil, apperrors.CleanErr(err)
+	}
+
+	return connect.NewResponse(response), nil
+}
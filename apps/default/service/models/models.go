@@ -2,10 +2,13 @@ package models
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"time"
 
 	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
 	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+	"github.com/antinvestor/service-notification/pkg/templating"
 	"github.com/pitabwire/frame/v2/data"
 	"google.golang.org/protobuf/types/known/structpb"
 )
@@ -18,6 +21,30 @@ const (
 	RouteTypeAny       = "any"
 	RouteTypeEmailForm = "email"
 	RouteTypeSMSForm   = "sms"
+
+	// NotificationTypeBroadcast marks the parent record of a broadcast, it is never routed itself.
+	NotificationTypeBroadcast = "broadcast"
+
+	// ExtraKeySendAt is the notification extras key carrying an RFC3339
+	// timestamp before which an outbound notification must not be released.
+	ExtraKeySendAt = "send_at"
+
+	// ExtraKeyTransactional is the notification extras key marking a message, such as a one
+	// time code, that is sent straight away even outside the recipient's delivery window.
+	ExtraKeyTransactional = "transactional"
+
+	// PayloadKeyChannels is the notification payload key carrying the ordered
+	// list of channels to try, for example ["email", "sms"].
+	PayloadKeyChannels = "channels"
+
+	// TemplateExtraSMSMaxSegments is the template extras key limiting the segments an SMS may take.
+	TemplateExtraSMSMaxSegments = "sms_max_segments"
+	// TemplateExtraSMSOversizePolicy is the template extras key saying whether a longer SMS
+	// is sent with a warning, truncated or rejected.
+	TemplateExtraSMSOversizePolicy = "sms_oversize_policy"
+	// TemplateExtraVariables is the template extras key declaring the payload variables the
+	// template reads, for example {"code": {"type": "string", "required": true}}.
+	TemplateExtraVariables = "variables"
 )
 
 // Language Our simple table holding all the supported languages
@@ -40,30 +67,57 @@ func (l *Language) ToApi() *notificationv1.Language {
 	}
 }
 
-// Template Table holds the template details
+// Template states. Saving a template creates a draft, publishing a version makes it the one
+// rendered for its name and retires the version published before it.
+const (
+	TemplateStateDraft     = "draft"
+	TemplateStatePublished = "published"
+	TemplateStateRetired   = "retired"
+)
+
+// Template Table holds one version of a template, the versions of a template share its name.
+// Revision numbers the versions, BaseModel.Version is the row's optimistic lock.
 type Template struct {
 	data.BaseModel
 
-	Name  string `gorm:"type:varchar(255)"`
-	Extra data.JSONMap
+	Name        string `gorm:"type:varchar(255)"`
+	Revision    int    `gorm:"not null;default:1"`
+	State       string `gorm:"type:varchar(20);not null;default:published"`
+	PublishedAt *time.Time
+	Extra       data.JSONMap
+}
+
+// VariableSchema returns the payload variables the template declares, nil when it declares none.
+func (t *Template) VariableSchema() (templating.Schema, error) {
+	return templating.ParseSchema(t.Extra[TemplateExtraVariables])
 }
 
 func (t *Template) ToApi(templateDataList []*notificationv1.TemplateData) *notificationv1.Template {
 
+	publishedAt := ""
+	if t.PublishedAt != nil {
+		publishedAt = t.PublishedAt.Format(time.RFC3339)
+	}
+
 	return &notificationv1.Template{
-		Id:    t.GetID(),
-		Name:  t.Name,
-		Data:  templateDataList,
-		Extra: t.Extra.ToProtoStruct(),
+		Id:          t.GetID(),
+		Name:        t.Name,
+		Data:        templateDataList,
+		Extra:       t.Extra.ToProtoStruct(),
+		Version:     int32(t.Revision),
+		State:       t.State,
+		PublishedAt: publishedAt,
 	}
 }
 
+// TemplateData holds the content of a template version for one type in one language, the
+// uq_template_by_type index created by the migrations keeps a single live row per combination.
 type TemplateData struct {
 	data.BaseModel
 
-	TemplateID string `gorm:"type:varchar(50);unique_index:uq_template_by_type"`
-	LanguageID string `gorm:"type:varchar(50);unique_index:uq_template_by_type"`
-	Type       string `gorm:"type:varchar(10);unique_index:uq_template_by_type"`
+	TemplateID string `gorm:"type:varchar(50)"`
+	LanguageID string `gorm:"type:varchar(50)"`
+	Type       string `gorm:"type:varchar(10)"`
 	Detail     string `gorm:"type:text"`
 	Subject    string `gorm:"type:varchar(250)"`
 }
@@ -85,6 +139,9 @@ type Notification struct {
 	data.BaseModel
 
 	ParentID string `gorm:"type:varchar(50)"`
+	// ParentStep is the step a child was created from its parent at, at most one child of a
+	// parent exists per step.
+	ParentStep string `gorm:"type:varchar(50)"`
 
 	SenderProfileID   string `gorm:"type:varchar(250)"`
 	SenderProfileType string `gorm:"type:varchar(50)"`
@@ -96,27 +153,108 @@ type Notification struct {
 
 	RouteID  string `gorm:"type:varchar(50)"`
 	OutBound bool
+	// RouteAttempt counts how many routes have failed this notification so far.
+	RouteAttempt int
 
 	LanguageID string `gorm:"type:varchar(50)"`
-	TemplateID string `gorm:"type:varchar(50)"`
+	// TemplateID is the template version the notification was last rendered with, the
+	// published version of its name is rendered instead unless TemplatePinned is set.
+	TemplateID       string `gorm:"type:varchar(50)"`
+	TemplateRevision int
+	TemplatePinned   bool
 
 	NotificationType string `gorm:"type:varchar(10)"`
 	Message          string `gorm:"type:text"`
 	Payload          data.JSONMap
 
+	ScheduledAt *time.Time `gorm:"index"`
 	ReleasedAt  *time.Time
-	State       int32
-	TransientID string `gorm:"type:varchar(50)"`
-	ExternalID  string `gorm:"type:varchar(50)"`
+	CanceledAt  *time.Time
+	// DispatchedAt is when the notification was claimed for publishing to its route, a
+	// notification is canceled only before and published only once per route attempt.
+	DispatchedAt *time.Time
+	State        int32
+	TransientID  string `gorm:"type:varchar(50)"`
+	ExternalID   string `gorm:"type:varchar(50)"`
 
 	StatusID string `gorm:"type:varchar(50)"`
+	Status   int32
 	Priority int32
+	// Transactional messages are never held back by delivery windows.
+	Transactional bool
 }
 
 func (model *Notification) IsReleased() bool {
 	return model.ReleasedAt != nil && !model.ReleasedAt.IsZero()
 }
 
+// IsCanceled reports whether the notification was withdrawn before being dispatched.
+func (model *Notification) IsCanceled() bool {
+	return model.CanceledAt != nil && !model.CanceledAt.IsZero()
+}
+
+// IsBroadcast reports whether the notification is the parent record of a broadcast.
+func (model *Notification) IsBroadcast() bool {
+	return model.NotificationType == NotificationTypeBroadcast
+}
+
+// IsScheduled reports whether the notification is waiting for the release
+// scheduler to release it at a future time.
+func (model *Notification) IsScheduled() bool {
+	return !model.IsReleased() && model.ScheduledAt != nil && !model.ScheduledAt.IsZero()
+}
+
+// Channels returns the ordered channel preference list carried on the payload.
+func (model *Notification) Channels() []string {
+	raw, ok := model.Payload[PayloadKeyChannels].([]any)
+	if !ok {
+		return nil
+	}
+
+	var channels []string
+	for _, c := range raw {
+		if channel, cOk := c.(string); cOk && channel != "" {
+			channels = append(channels, strings.ToLower(channel))
+		}
+	}
+	return channels
+}
+
+// FallbackChannels returns the preferred channels left to try once the
+// channel this notification was sent on has failed.
+func (model *Notification) FallbackChannels() []string {
+	channels := model.Channels()
+	if len(channels) == 0 {
+		return nil
+	}
+
+	current := model.NotificationType
+	if current == "" || current == RouteTypeAny {
+		current = channels[0]
+	}
+
+	idx := slices.Index(channels, current)
+	if idx < 0 {
+		return slices.DeleteFunc(channels, func(c string) bool { return c == current })
+	}
+	return channels[idx+1:]
+}
+
+// ParseSendAt reads the time before which a notification must not be released from its
+// extras, nil when it carries none.
+func ParseSendAt(extras *structpb.Struct) (*time.Time, error) {
+	sendAt, ok := extras.GetFields()[ExtraKeySendAt]
+	if !ok {
+		return nil, nil
+	}
+
+	scheduledAt, err := time.Parse(time.RFC3339, sendAt.GetStringValue())
+	if err != nil {
+		return nil, err
+	}
+	return &scheduledAt, nil
+}
+
 func NotificationFromAPI(ctx context.Context, notification *notificationv1.Notification) *Notification {
 	if notification == nil {
 		return nil
@@ -132,6 +270,7 @@ func NotificationFromAPI(ctx context.Context, notification *notificationv1.Notif
 		OutBound:         notification.GetOutBound(),
 		RouteID:          notification.GetRouteId(),
 		Priority:         int32(notification.GetPriority()),
+		Transactional:    notification.GetExtras().GetFields()[ExtraKeyTransactional].GetBoolValue(),
 	}
 
 	model.ID = notification.GetId()
@@ -140,6 +279,9 @@ func NotificationFromAPI(ctx context.Context, notification *notificationv1.Notif
 		model.Payload = (&data.JSONMap{}).FromProtoStruct(notification.GetPayload())
 	}
 
+	// Callers reject an unreadable send time with ParseSendAt before they get here.
+	model.ScheduledAt, _ = ParseSendAt(notification.GetExtras())
+
 	if source := notification.GetSource(); source != nil {
 		model.SenderProfileID = source.GetProfileId()
 		model.SenderProfileType = source.GetProfileType()
@@ -171,6 +313,10 @@ func (model *Notification) ToAPI(status *NotificationStatus, language *Language,
 		extra["ReleaseDate"] = model.ReleasedAt.String()
 	}
 
+	if model.ScheduledAt != nil {
+		extra["ScheduledDate"] = model.ScheduledAt.String()
+	}
+
 	if len(message) != 0 {
 
 		if model.Message == "" {
@@ -268,4 +414,173 @@ type Route struct {
 	RouteType   string `gorm:"type:varchar(10)"`
 	Mode        string `gorm:"type:varchar(10)"`
 	Uri         string `gorm:"type:varchar(255)"`
+
+	// Weight is the share of traffic a route receives relative to other routes in its tier.
+	Weight int `gorm:"not null;default:1"`
+	// Priority groups routes into tiers, the lowest value being tried first.
+	Priority int `gorm:"not null;default:0"`
+	// Enabled is a pointer so a route can be created disabled, gorm leaves a false bool out of
+	// the insert and the column default would enable it. Unset means enabled.
+	Enabled *bool `gorm:"not null;default:true"`
+
+	// FailureThreshold is the number of consecutive failures that take the route out of rotation.
+	FailureThreshold    int `gorm:"not null;default:3"`
+	ConsecutiveFailures int `gorm:"not null;default:0"`
+	LastFailureAt       *time.Time
+}
+
+// RouteRecoveryInterval is how long an unhealthy route sits out of rotation
+// before it is offered a probe to find out whether it has recovered.
+const RouteRecoveryInterval = 5 * time.Minute
+
+// IsEnabled reports whether the route takes part in route selection at all.
+func (model *Route) IsEnabled() bool {
+	return model.Enabled == nil || *model.Enabled
+}
+
+// IsHealthy reports whether the route takes its full share in route selection.
+func (model *Route) IsHealthy() bool {
+	if !model.IsEnabled() {
+		return false
+	}
+
+	threshold := model.FailureThreshold
+	if threshold <= 0 {
+		threshold = 3
+	}
+	return model.ConsecutiveFailures < threshold
+}
+
+// IsRecovering reports whether an unhealthy route has sat out long enough to be probed.
+func (model *Route) IsRecovering(now time.Time) bool {
+	return model.IsEnabled() && !model.IsHealthy() &&
+		model.LastFailureAt != nil && now.Sub(*model.LastFailureAt) > RouteRecoveryInterval
+}
+
+const (
+	AggregateKindStatus      = "status"
+	AggregateKindFailureStep = "failure_step"
+)
+
+// NotificationAggregate is a running counter over the children of a parent
+// notification, kept current as child statuses are saved so broadcast progress
+// can be read without scanning every child.
+type NotificationAggregate struct {
+	data.BaseModel
+
+	ParentID string `gorm:"type:varchar(50);uniqueIndex:uq_aggregate_counter"`
+	Kind     string `gorm:"type:varchar(20);uniqueIndex:uq_aggregate_counter"`
+	Key      string `gorm:"type:varchar(100);uniqueIndex:uq_aggregate_counter"`
+	Count    int64
+}
+
+// Broadcast is the expansion of a broadcast to its audience, sharing its ID with the parent
+// notification. Progress is saved after every page so an expansion that stops, on an error or
+// with the replica running it, resumes from the last saved page when its event is redelivered.
+type Broadcast struct {
+	data.BaseModel
+
+	// Request is the broadcast request in protojson, children are queued from its notification.
+	Request string `gorm:"type:text"`
+	// Cursor is where expansion resumes, the offset into the profile ids of an explicit
+	// audience or the last profile id read of a partition audience.
+	Cursor     string `gorm:"type:varchar(250)"`
+	Queued     int
+	Failed     int
+	Pages      int
+	ExpandedAt *time.Time
+}
+
+// IsExpanded reports whether every recipient of the broadcast has been queued.
+func (model *Broadcast) IsExpanded() bool {
+	return model.ExpandedAt != nil && !model.ExpandedAt.IsZero()
+}
+
+// IdempotencyKey remembers the notification queued for a client supplied key, so a
+// retried send within the retention window returns the original status instead of
+// queuing a duplicate. Keys are unique per tenant and partition, enforced by the
+// uq_idempotency_key index created in the migrations.
+type IdempotencyKey struct {
+	data.BaseModel
+
+	Key            string `gorm:"type:varchar(100)"`
+	NotificationID string `gorm:"type:varchar(50)"`
+	StatusID       string `gorm:"type:varchar(50)"`
+	ExpiresAt      time.Time
+}
+
+// NotificationExternalID links a notification to an identifier a gateway gave it, one for
+// every part of a message sent in several. Delivery reports carrying only the gateway
+// identifier are matched back to their notification through it.
+type NotificationExternalID struct {
+	data.BaseModel
+
+	NotificationID string `gorm:"type:varchar(50);uniqueIndex:uq_notification_external_id"`
+	ExternalID     string `gorm:"type:varchar(255);uniqueIndex:uq_notification_external_id;index"`
+}
+
+// RateLimitCounter counts the outbound notifications sent under one rate limit
+// key within one fixed window, counters are purged once their window has ended.
+type RateLimitCounter struct {
+	data.BaseModel
+
+	Key         string    `gorm:"type:varchar(255);uniqueIndex:uq_rate_limit_window"`
+	WindowStart time.Time `gorm:"uniqueIndex:uq_rate_limit_window"`
+	WindowEnd   time.Time `gorm:"index"`
+	Count       int
+}
+
+// Suppression marks a contact that must no longer receive outbound notifications.
+// Empty Channel or SenderID values apply the suppression to every value, RouteID only records
+// the route an opt out was reported on. Uniqueness is enforced per partition by the
+// uq_suppression index in the migrations.
+type Suppression struct {
+	data.BaseModel
+
+	Contact  string `gorm:"type:varchar(255);index"`
+	Channel  string `gorm:"type:varchar(10)"`
+	SenderID string `gorm:"type:varchar(50)"`
+	RouteID  string `gorm:"type:varchar(50)"`
+	Reason   string `gorm:"type:varchar(50)"`
+	Source   string `gorm:"type:varchar(50)"`
+	Extra    data.JSONMap
+}
+
+// NormalizeContact reduces a phone number or email address to the form
+// suppressions are stored and matched in.
+func NormalizeContact(detail string) string {
+	detail = strings.ToLower(strings.TrimSpace(detail))
+	if strings.Contains(detail, "@") {
+		return detail
+	}
+	return strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(detail)
+}
+
+func SuppressionFromAPI(ctx context.Context, suppression *notificationv1.Suppression) *Suppression {
+	model := &Suppression{
+		Contact:  NormalizeContact(suppression.GetContact()),
+		Channel:  suppression.GetChannel(),
+		SenderID: suppression.GetSenderId(),
+		RouteID:  suppression.GetRouteId(),
+		Reason:   suppression.GetReason(),
+		Source:   suppression.GetSource(),
+		Extra:    suppression.GetExtras().AsMap(),
+	}
+	model.GenID(ctx)
+	return model
+}
+
+func (model *Suppression) ToAPI() *notificationv1.Suppression {
+	extra := model.Extra
+	return &notificationv1.Suppression{
+		Id:        model.GetID(),
+		Contact:   model.Contact,
+		Channel:   model.Channel,
+		SenderId:  model.SenderID,
+		RouteId:   model.RouteID,
+		Reason:    model.Reason,
+		Source:    model.Source,
+		Extras:    extra.ToProtoStruct(),
+		CreatedAt: model.CreatedAt.Format(time.RFC3339),
+	}
 }
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/pitabwire/frame/v2/datastore"
+	"github.com/pitabwire/frame/v2/datastore/pool"
+	"github.com/pitabwire/frame/v2/workerpool"
+)
+
+type BroadcastRepository interface {
+	datastore.BaseRepository[*models.Broadcast]
+	SaveProgress(ctx context.Context, broadcast *models.Broadcast) error
+}
+
+type broadcastRepository struct {
+	datastore.BaseRepository[*models.Broadcast]
+}
+
+func NewBroadcastRepository(ctx context.Context, dbPool pool.Pool, workMan workerpool.Manager) BroadcastRepository {
+	return &broadcastRepository{
+		BaseRepository: datastore.NewBaseRepository[*models.Broadcast](
+			ctx, dbPool, workMan, func() *models.Broadcast { return &models.Broadcast{} },
+		),
+	}
+}
+
+// SaveProgress stores how far the expansion of a broadcast has gone.
+func (repo *broadcastRepository) SaveProgress(ctx context.Context, broadcast *models.Broadcast) error {
+	return repo.Pool().DB(ctx, false).Exec(
+		`UPDATE broadcasts SET cursor = ?, queued = ?, failed = ?, pages = ?, expanded_at = ?, modified_at = ? WHERE id = ?`,
+		broadcast.Cursor, broadcast.Queued, broadcast.Failed, broadcast.Pages, broadcast.ExpandedAt, time.Now(), broadcast.GetID()).Error
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/pitabwire/frame/v2/datastore"
+	"github.com/pitabwire/frame/v2/datastore/pool"
+	"github.com/pitabwire/frame/v2/workerpool"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyKeyRepository interface {
+	datastore.BaseRepository[*models.IdempotencyKey]
+	Claim(ctx context.Context, idempotencyKey *models.IdempotencyKey) (bool, error)
+	GetByKey(ctx context.Context, key string) (*models.IdempotencyKey, error)
+	Release(ctx context.Context, idempotencyKey *models.IdempotencyKey) error
+	PurgeExpired(ctx context.Context, expiredBy time.Time) (int64, error)
+}
+
+type idempotencyKeyRepository struct {
+	datastore.BaseRepository[*models.IdempotencyKey]
+}
+
+func NewIdempotencyKeyRepository(ctx context.Context, dbPool pool.Pool, workMan workerpool.Manager) IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{
+		BaseRepository: datastore.NewBaseRepository[*models.IdempotencyKey](
+			ctx, dbPool, workMan, func() *models.IdempotencyKey { return &models.IdempotencyKey{} },
+		),
+	}
+}
+
+// Claim stores the key for its notification and reports whether it was claimed.
+// A key already held by an unexpired entry is left untouched and Claim returns false,
+// an expired entry is taken over by the new notification.
+func (repo *idempotencyKeyRepository) Claim(ctx context.Context, idempotencyKey *models.IdempotencyKey) (bool, error) {
+	result := repo.Pool().DB(ctx, false).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "partition_id"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"notification_id", "status_id", "expires_at", "modified_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "idempotency_keys.expires_at <= ?", Vars: []any{time.Now()}},
+		}},
+	}).Create(idempotencyKey)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetByKey returns the unexpired entry for a key in the caller's tenant and partition.
+// It reads from the primary since it follows a Claim that lost to an existing entry.
+func (repo *idempotencyKeyRepository) GetByKey(ctx context.Context, key string) (*models.IdempotencyKey, error) {
+	idempotencyKey := &models.IdempotencyKey{}
+	err := repo.Pool().DB(ctx, false).
+		First(idempotencyKey, "key = ? AND expires_at > ?", key, time.Now()).Error
+	if err != nil {
+		return nil, err
+	}
+	return idempotencyKey, nil
+}
+
+// Release expires a claimed key straight away so a retry can claim it again,
+// used when queuing the notification it was claimed for fails. The entry is matched by
+// key and notification since a claim taking over an expired entry keeps that entry's id.
+func (repo *idempotencyKeyRepository) Release(ctx context.Context, idempotencyKey *models.IdempotencyKey) error {
+	now := time.Now()
+	return repo.Pool().DB(ctx, false).Exec(
+		`UPDATE idempotency_keys SET expires_at = ?, modified_at = ?
+		WHERE tenant_id = ? AND partition_id = ? AND key = ? AND notification_id = ?`,
+		now, now, idempotencyKey.TenantID, idempotencyKey.PartitionID, idempotencyKey.Key, idempotencyKey.NotificationID).Error
+}
+
+// PurgeExpired deletes the keys that expired by expiredBy, they no longer stop a retried
+// send and are taken over on the next claim anyway.
+func (repo *idempotencyKeyRepository) PurgeExpired(ctx context.Context, expiredBy time.Time) (int64, error) {
+	result := repo.Pool().DB(ctx, false).Exec(`DELETE FROM idempotency_keys WHERE expires_at <= ?`, expiredBy)
+	return result.RowsAffected, result.Error
+}
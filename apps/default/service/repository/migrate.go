@@ -16,5 +16,8 @@ func Migrate(ctx context.Context, dbManager datastore.Manager, migrationPath str
 
 	return dbManager.Migrate(ctx, dbPool, migrationPath,
 		&models.Route{}, &models.Language{}, &models.Template{},
-		&models.TemplateData{}, &models.Notification{}, &models.NotificationStatus{})
+		&models.TemplateData{}, &models.Notification{}, &models.NotificationStatus{},
+		&models.NotificationAggregate{}, &models.IdempotencyKey{},
+		&models.RateLimitCounter{}, &models.Suppression{}, &models.Broadcast{},
+		&models.NotificationExternalID{})
 }
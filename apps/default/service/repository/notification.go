@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
 	"github.com/antinvestor/service-notification/apps/default/service/models"
 	"github.com/pitabwire/frame/v2/datastore"
 	"github.com/pitabwire/frame/v2/datastore/pool"
@@ -12,6 +14,12 @@ import (
 type NotificationRepository interface {
 	datastore.BaseRepository[*models.Notification]
 	GetByIDList(ctx context.Context, id ...string) ([]*models.Notification, error)
+	ReleaseDue(ctx context.Context, dueBy time.Time, limit int) ([]*models.Notification, error)
+	Unrelease(ctx context.Context, id ...string) error
+	Cancel(ctx context.Context, canceledAt time.Time, id ...string) ([]*models.Notification, error)
+	UpdateStatus(ctx context.Context, n *models.Notification, previousStatus int32) (bool, error)
+	ClaimDispatch(ctx context.Context, n *models.Notification, dispatchedAt time.Time) (bool, error)
+	ReleaseDispatch(ctx context.Context, n *models.Notification) error
 }
 
 type notificationRepository struct {
@@ -43,3 +51,116 @@ func (repo *notificationRepository) GetByIDList(ctx context.Context, id ...strin
 	}
 	return notifications, nil
 }
+
+// ReleaseDue atomically marks up to limit scheduled outbound notifications that
+// are due by dueBy as released and returns them. Rows are claimed with
+// FOR UPDATE SKIP LOCKED so concurrent replicas never release the same row twice.
+func (repo *notificationRepository) ReleaseDue(ctx context.Context, dueBy time.Time, limit int) ([]*models.Notification, error) {
+	var notifications []*models.Notification
+	err := repo.Pool().DB(ctx, false).Raw(
+		`UPDATE notifications SET released_at = ?, modified_at = ?, version = version + 1
+		WHERE id IN (
+			SELECT id FROM notifications
+			WHERE out_bound = true AND released_at IS NULL AND scheduled_at IS NOT NULL
+				AND scheduled_at <= ? AND canceled_at IS NULL AND deleted_at IS NULL
+			ORDER BY scheduled_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		dueBy, dueBy, dueBy, limit).Scan(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// Unrelease returns released notifications to the schedule so the next ReleaseDue claims
+// them again, used when they could not be handed over after being claimed.
+func (repo *notificationRepository) Unrelease(ctx context.Context, id ...string) error {
+	if len(id) == 0 {
+		return nil
+	}
+	return repo.Pool().DB(ctx, false).Exec(
+		`UPDATE notifications SET released_at = NULL, modified_at = ?, version = version + 1
+		WHERE id IN ? AND released_at IS NOT NULL AND scheduled_at IS NOT NULL`,
+		time.Now(), id).Error
+}
+
+// Cancel atomically marks the listed outbound notifications as canceled and returns
+// the ones it changed. Only notifications that are unreleased, or released but not
+// yet claimed for publishing to a route, are canceled; anything else is left untouched.
+func (repo *notificationRepository) Cancel(ctx context.Context, canceledAt time.Time, id ...string) ([]*models.Notification, error) {
+	var notifications []*models.Notification
+	err := repo.Pool().DB(ctx, false).Raw(
+		`UPDATE notifications SET canceled_at = ?, scheduled_at = NULL, modified_at = ?, version = version + 1
+		WHERE id IN ? AND out_bound = true AND canceled_at IS NULL AND dispatched_at IS NULL AND deleted_at IS NULL
+			AND (released_at IS NULL OR status = ?)
+		RETURNING *`,
+		canceledAt, canceledAt, id, int32(commonv1.STATUS_QUEUED)).Scan(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// UpdateStatus stores the status of n only while the notification is still in previousStatus,
+// reporting whether it changed the row. A status saved concurrently moves the row first and
+// leaves it untouched, so the caller reads the notification again before retrying.
+func (repo *notificationRepository) UpdateStatus(ctx context.Context, n *models.Notification, previousStatus int32) (bool, error) {
+	var versions []uint
+	err := repo.Pool().DB(ctx, false).Raw(
+		`UPDATE notifications SET status_id = ?, state = ?, status = ?, transient_id = ?, modified_at = ?, version = version + 1
+		WHERE id = ? AND status = ?
+		RETURNING version`,
+		n.StatusID, n.State, n.Status, n.TransientID, time.Now(), n.GetID(), previousStatus).Scan(&versions).Error
+	if err != nil {
+		return false, err
+	}
+	if len(versions) == 0 {
+		return false, nil
+	}
+	// Later updates of n are matched on its version.
+	n.Version = versions[0]
+	return true, nil
+}
+
+// ClaimDispatch marks a notification as being published to its route, reporting false when it
+// was canceled or already claimed. Claiming and canceling update the same row, so a notification
+// is either canceled or published, never both.
+func (repo *notificationRepository) ClaimDispatch(ctx context.Context, n *models.Notification, dispatchedAt time.Time) (bool, error) {
+	var versions []uint
+	err := repo.Pool().DB(ctx, false).Raw(
+		`UPDATE notifications SET dispatched_at = ?, modified_at = ?, version = version + 1
+		WHERE id = ? AND canceled_at IS NULL AND dispatched_at IS NULL AND deleted_at IS NULL
+		RETURNING version`,
+		dispatchedAt, dispatchedAt, n.GetID()).Scan(&versions).Error
+	if err != nil {
+		return false, err
+	}
+	if len(versions) == 0 {
+		return false, nil
+	}
+	n.DispatchedAt = &dispatchedAt
+	n.Version = versions[0]
+	return true, nil
+}
+
+// ReleaseDispatch gives up the dispatch claim of a notification that could not be published,
+// so a redelivery or a reroute can claim it again.
+func (repo *notificationRepository) ReleaseDispatch(ctx context.Context, n *models.Notification) error {
+	var versions []uint
+	err := repo.Pool().DB(ctx, false).Raw(
+		`UPDATE notifications SET dispatched_at = NULL, modified_at = ?, version = version + 1
+		WHERE id = ? AND dispatched_at IS NOT NULL
+		RETURNING version`,
+		time.Now(), n.GetID()).Scan(&versions).Error
+	if err != nil {
+		return err
+	}
+	n.DispatchedAt = nil
+	if len(versions) > 0 {
+		n.Version = versions[0]
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/pitabwire/frame/v2/datastore"
+	"github.com/pitabwire/frame/v2/datastore/pool"
+	"github.com/pitabwire/frame/v2/workerpool"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationAggregateRepository interface {
+	datastore.BaseRepository[*models.NotificationAggregate]
+	Increment(ctx context.Context, counter *models.NotificationAggregate) error
+	GetByParentID(ctx context.Context, parentID string) ([]*models.NotificationAggregate, error)
+}
+
+type notificationAggregateRepository struct {
+	datastore.BaseRepository[*models.NotificationAggregate]
+}
+
+func NewNotificationAggregateRepository(ctx context.Context, dbPool pool.Pool, workMan workerpool.Manager) NotificationAggregateRepository {
+	return &notificationAggregateRepository{
+		BaseRepository: datastore.NewBaseRepository[*models.NotificationAggregate](
+			ctx, dbPool, workMan, func() *models.NotificationAggregate { return &models.NotificationAggregate{} },
+		),
+	}
+}
+
+// Increment adds counter.Count to the matching counter, creating it when missing.
+// The addition happens in a single upsert so concurrent status saves never lose updates.
+func (repo *notificationAggregateRepository) Increment(ctx context.Context, counter *models.NotificationAggregate) error {
+	return repo.Pool().DB(ctx, false).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "parent_id"}, {Name: "kind"}, {Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"count": gorm.Expr("notification_aggregates.count + ?", counter.Count),
+		}),
+	}).Create(counter).Error
+}
+
+func (repo *notificationAggregateRepository) GetByParentID(ctx context.Context, parentID string) ([]*models.NotificationAggregate, error) {
+	var counters []*models.NotificationAggregate
+	err := repo.Pool().DB(ctx, true).Find(&counters, "parent_id = ?", parentID).Error
+	if err != nil {
+		return nil, err
+	}
+	return counters, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/pitabwire/frame/v2/datastore"
+	"github.com/pitabwire/frame/v2/datastore/pool"
+	"github.com/pitabwire/frame/v2/workerpool"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationExternalIDRepository interface {
+	datastore.BaseRepository[*models.NotificationExternalID]
+	Record(ctx context.Context, n *models.Notification, externalIDs ...string) error
+	GetNotificationID(ctx context.Context, externalID string) (string, error)
+}
+
+type notificationExternalIDRepository struct {
+	datastore.BaseRepository[*models.NotificationExternalID]
+}
+
+func NewNotificationExternalIDRepository(ctx context.Context, dbPool pool.Pool, workMan workerpool.Manager) NotificationExternalIDRepository {
+	return &notificationExternalIDRepository{
+		BaseRepository: datastore.NewBaseRepository[*models.NotificationExternalID](
+			ctx, dbPool, workMan, func() *models.NotificationExternalID { return &models.NotificationExternalID{} },
+		),
+	}
+}
+
+// Record links the gateway identifiers to the notification, identifiers already linked to it are skipped.
+func (repo *notificationExternalIDRepository) Record(ctx context.Context, n *models.Notification, externalIDs ...string) error {
+	if len(externalIDs) == 0 {
+		return nil
+	}
+
+	rows := make([]*models.NotificationExternalID, 0, len(externalIDs))
+	for _, externalID := range externalIDs {
+		row := &models.NotificationExternalID{NotificationID: n.GetID(), ExternalID: externalID}
+		row.CopyPartitionInfo(&n.BaseModel)
+		row.GenID(ctx)
+		rows = append(rows, row)
+	}
+
+	return repo.Pool().DB(ctx, false).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "notification_id"}, {Name: "external_id"}},
+		DoNothing: true,
+	}).Create(&rows).Error
+}
+
+// GetNotificationID returns the notification a gateway identifier was last given to.
+func (repo *notificationExternalIDRepository) GetNotificationID(ctx context.Context, externalID string) (string, error) {
+	link := &models.NotificationExternalID{}
+	err := repo.Pool().DB(ctx, true).
+		Where("external_id = ?", externalID).Order("created_at DESC").First(link).Error
+	if err != nil {
+		return "", err
+	}
+	return link.NotificationID, nil
+}
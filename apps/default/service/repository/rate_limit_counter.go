@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/pitabwire/frame/v2/datastore"
+	"github.com/pitabwire/frame/v2/datastore/pool"
+	"github.com/pitabwire/frame/v2/workerpool"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RateLimitCounterRepository interface {
+	datastore.BaseRepository[*models.RateLimitCounter]
+	Take(ctx context.Context, counter *models.RateLimitCounter, limit int) (bool, error)
+	Give(ctx context.Context, key string, windowStart time.Time) error
+	PurgeEnded(ctx context.Context, endedBy time.Time) (int64, error)
+}
+
+type rateLimitCounterRepository struct {
+	datastore.BaseRepository[*models.RateLimitCounter]
+}
+
+func NewRateLimitCounterRepository(ctx context.Context, dbPool pool.Pool, workMan workerpool.Manager) RateLimitCounterRepository {
+	return &rateLimitCounterRepository{
+		BaseRepository: datastore.NewBaseRepository[*models.RateLimitCounter](
+			ctx, dbPool, workMan, func() *models.RateLimitCounter { return &models.RateLimitCounter{} },
+		),
+	}
+}
+
+// Take counts one more send against the counter window and reports whether it fit
+// within limit. The check and the increment happen in a single upsert so concurrent
+// sends can never overshoot the limit.
+func (repo *rateLimitCounterRepository) Take(ctx context.Context, counter *models.RateLimitCounter, limit int) (bool, error) {
+	counter.Count = 1
+	result := repo.Pool().DB(ctx, false).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}, {Name: "window_start"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"count": gorm.Expr("rate_limit_counters.count + 1"),
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "rate_limit_counters.count < ?", Vars: []any{limit}},
+		}},
+	}).Create(counter)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Give hands back a send taken from a counter window, used when another rule
+// stopped the notification after this one had already counted it.
+func (repo *rateLimitCounterRepository) Give(ctx context.Context, key string, windowStart time.Time) error {
+	return repo.Pool().DB(ctx, false).Exec(
+		`UPDATE rate_limit_counters SET count = count - 1 WHERE key = ? AND window_start = ? AND count > 0`,
+		key, windowStart).Error
+}
+
+// PurgeEnded deletes the counters of windows that ended by endedBy, no send is counted
+// against them any more.
+func (repo *rateLimitCounterRepository) PurgeEnded(ctx context.Context, endedBy time.Time) (int64, error) {
+	result := repo.Pool().DB(ctx, false).Exec(`DELETE FROM rate_limit_counters WHERE window_end <= ?`, endedBy)
+	return result.RowsAffected, result.Error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/antinvestor/service-notification/apps/default/service/models"
 	"github.com/pitabwire/frame/v2/datastore"
@@ -13,6 +14,8 @@ type RouteRepository interface {
 	datastore.BaseRepository[*models.Route]
 	GetByModeTypeAndPartitionID(ctx context.Context, mode string, routeType string, partitionId string) ([]*models.Route, error)
 	GetByMode(ctx context.Context, mode string) ([]*models.Route, error)
+	RecordFailure(ctx context.Context, id string) error
+	RecordSuccess(ctx context.Context, id string) error
 }
 
 type routeRepository struct {
@@ -49,3 +52,18 @@ func (repo *routeRepository) GetByModeTypeAndPartitionID(ctx context.Context, mo
 	}
 	return routes, nil
 }
+
+// RecordFailure counts a consecutive publish or delivery failure against a route.
+// The increment happens in the database so concurrent handlers do not lose counts.
+func (repo *routeRepository) RecordFailure(ctx context.Context, id string) error {
+	return repo.Pool().DB(ctx, false).Exec(
+		"UPDATE routes SET consecutive_failures = consecutive_failures + 1, last_failure_at = ? WHERE id = ?",
+		time.Now(), id).Error
+}
+
+// RecordSuccess clears the consecutive failure count of a route, returning it to rotation.
+func (repo *routeRepository) RecordSuccess(ctx context.Context, id string) error {
+	return repo.Pool().DB(ctx, false).Exec(
+		"UPDATE routes SET consecutive_failures = 0 WHERE id = ? AND consecutive_failures > 0",
+		id).Error
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/antinvestor/service-notification/apps/default/service/models"
+	"github.com/pitabwire/frame/v2/datastore"
+	"github.com/pitabwire/frame/v2/datastore/pool"
+	"github.com/pitabwire/frame/v2/workerpool"
+	"gorm.io/gorm/clause"
+)
+
+type SuppressionRepository interface {
+	datastore.BaseRepository[*models.Suppression]
+	Upsert(ctx context.Context, suppression *models.Suppression) error
+	Match(ctx context.Context, contact, channel, senderID string) (*models.Suppression, error)
+	Remove(ctx context.Context, suppression *models.Suppression) ([]string, error)
+}
+
+type suppressionRepository struct {
+	datastore.BaseRepository[*models.Suppression]
+}
+
+func NewSuppressionRepository(ctx context.Context, dbPool pool.Pool, workMan workerpool.Manager) SuppressionRepository {
+	return &suppressionRepository{
+		BaseRepository: datastore.NewBaseRepository[*models.Suppression](
+			ctx, dbPool, workMan, func() *models.Suppression { return &models.Suppression{} },
+		),
+	}
+}
+
+// Upsert records a suppression, refreshing the reason and details when the
+// contact is already suppressed for the same channel and sender. The stored
+// row is read back so an existing suppression keeps its original ID.
+func (repo *suppressionRepository) Upsert(ctx context.Context, suppression *models.Suppression) error {
+	return repo.Pool().DB(ctx, false).Clauses(clause.Returning{}, clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "tenant_id"}, {Name: "partition_id"}, {Name: "contact"},
+			{Name: "channel"}, {Name: "sender_id"},
+		},
+		DoUpdates: clause.AssignmentColumns([]string{"route_id", "reason", "source", "extra", "modified_at"}),
+	}).Create(suppression).Error
+}
+
+// Match returns a suppression covering the contact on the channel from the sender, or nil when
+// it may be contacted. The route a send goes out on plays no part, an opt out follows the sender.
+func (repo *suppressionRepository) Match(ctx context.Context, contact, channel, senderID string) (*models.Suppression, error) {
+	var suppressions []*models.Suppression
+	err := repo.Pool().DB(ctx, true).
+		Where("contact = ? AND channel IN ? AND sender_id IN ?", contact, []string{"", channel}, []string{"", senderID}).
+		Limit(1).Find(&suppressions).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(suppressions) == 0 {
+		return nil, nil
+	}
+	return suppressions[0], nil
+}
+
+// Remove permanently deletes the suppression with the given ID, or when no ID is set
+// the one with the exact same contact, channel and sender, returning the removed IDs.
+// Suppressions are removed outright so the contact can be suppressed again later.
+func (repo *suppressionRepository) Remove(ctx context.Context, suppression *models.Suppression) ([]string, error) {
+	db := repo.Pool().DB(ctx, false).Unscoped()
+	if suppression.GetID() != "" {
+		db = db.Where("id = ?", suppression.GetID())
+	} else {
+		db = db.Where("contact = ? AND channel = ? AND sender_id = ?",
+			suppression.Contact, suppression.Channel, suppression.SenderID)
+	}
+
+	var removed []*models.Suppression
+	err := db.Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).Delete(&removed).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(removed))
+	for _, s := range removed {
+		ids = append(ids, s.GetID())
+	}
+	return ids, nil
+}
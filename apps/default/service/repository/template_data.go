@@ -13,6 +13,7 @@ type TemplateDataRepository interface {
 	datastore.BaseRepository[*models.TemplateData]
 	GetByTemplateID(ctx context.Context, templateId ...string) ([]*models.TemplateData, error)
 	GetByTemplateIDAndLanguage(ctx context.Context, languageId string, templateId ...string) ([]*models.TemplateData, error)
+	GetByTemplateLanguageAndType(ctx context.Context, templateID, languageID, dataType string) (*models.TemplateData, error)
 }
 
 type templateDataRepository struct {
@@ -44,3 +45,19 @@ func (tr *templateDataRepository) GetByTemplateIDAndLanguage(ctx context.Context
 	}
 	return templateDataList, nil
 }
+
+// GetByTemplateLanguageAndType returns the content of a template for one type in one language,
+// nil when there is none.
+func (tr *templateDataRepository) GetByTemplateLanguageAndType(ctx context.Context, templateID, languageID, dataType string) (*models.TemplateData, error) {
+	var templateDataList []*models.TemplateData
+	err := tr.Pool().DB(ctx, false).
+		Where("template_id = ? AND language_id = ? AND type = ?", templateID, languageID, dataType).
+		Limit(1).Find(&templateDataList).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(templateDataList) == 0 {
+		return nil, nil
+	}
+	return templateDataList[0], nil
+}
@@ -2,18 +2,52 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/antinvestor/service-notification/apps/default/service/models"
 	"github.com/pitabwire/frame/v2/datastore"
 	"github.com/pitabwire/frame/v2/datastore/pool"
 	"github.com/pitabwire/frame/v2/workerpool"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TemplateRepository interface {
 	datastore.BaseRepository[*models.Template]
 	GetByName(ctx context.Context, name string) (*models.Template, error)
+	GetPublished(ctx context.Context, template *models.Template) (*models.Template, error)
+	GetRevisions(ctx context.Context, name string) ([]*models.Template, error)
+	CreateRevision(ctx context.Context, template *models.Template) error
+	Publish(ctx context.Context, template *models.Template) error
+	Change(ctx context.Context, template *models.Template, updateExtra bool, changes []*TemplateDataChange) error
+	Remove(ctx context.Context, template *models.Template) ([]string, error)
 }
 
+// TemplateDataChange adds, replaces or removes the content of a template version for one
+// language and type.
+type TemplateDataChange struct {
+	LanguageID string
+	Type       string
+	Detail     string
+	Remove     bool
+}
+
+// maxRevisionAttempts bounds how often a save takes a new revision number after concurrent
+// saves of the same template claimed the previous ones.
+const maxRevisionAttempts = 5
+
+var (
+	// ErrRevisionConflict is returned when concurrent saves kept claiming the revision a save took.
+	ErrRevisionConflict = errors.New("template revision was taken by concurrent saves")
+	// ErrTemplateNotDraft is returned when changing a template version that is no longer a draft.
+	ErrTemplateNotDraft = errors.New("template version is not a draft")
+	// ErrTemplateDataMissing is returned when removing content a template version does not have.
+	ErrTemplateDataMissing = errors.New("template version has no such content")
+	// ErrTemplateInUse is returned when removing a template version unreleased notifications render.
+	ErrTemplateInUse = errors.New("template version is used by unreleased notifications")
+)
+
 type templateRepository struct {
 	datastore.BaseRepository[*models.Template]
 }
@@ -26,12 +60,232 @@ func NewTemplateRepository(ctx context.Context, dbPool pool.Pool, workMan worker
 	}
 }
 
+// GetByName returns the published version of the named template, an empty template when
+// no version of it is published.
 func (tr *templateRepository) GetByName(ctx context.Context, name string) (*models.Template, error) {
 	template := models.Template{}
 
-	err := tr.Pool().DB(ctx, true).Find(&template, "name = ?", name).Error
+	err := tr.Pool().DB(ctx, true).
+		Where("name = ? AND state = ?", name, models.TemplateStatePublished).
+		Order("revision DESC, created_at DESC").
+		Limit(1).Find(&template).Error
 	if err != nil {
 		return nil, err
 	}
 	return &template, nil
 }
+
+// GetPublished returns the published version of the template the supplied version belongs to,
+// nil when none of its versions is published.
+func (tr *templateRepository) GetPublished(ctx context.Context, template *models.Template) (*models.Template, error) {
+	var published []*models.Template
+
+	err := tr.Pool().DB(ctx, true).
+		Where("tenant_id = ? AND partition_id = ? AND name = ? AND state = ?",
+			template.TenantID, template.PartitionID, template.Name, models.TemplateStatePublished).
+		Limit(1).Find(&published).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(published) == 0 {
+		return nil, nil
+	}
+	return published[0], nil
+}
+
+// GetRevisions returns every version of the named template, the latest first.
+func (tr *templateRepository) GetRevisions(ctx context.Context, name string) ([]*models.Template, error) {
+	var templates []*models.Template
+
+	err := tr.Pool().DB(ctx, true).
+		Where("name = ?", name).
+		Order("revision DESC").
+		Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// CreateRevision saves the template as the next revision of its name. The revision is worked
+// out in the insert transaction, counting deleted versions so their numbers are never reused,
+// and is taken again when a concurrent save claimed it first.
+func (tr *templateRepository) CreateRevision(ctx context.Context, template *models.Template) error {
+	return tr.Pool().DB(ctx, false).Transaction(func(tx *gorm.DB) error {
+		for range maxRevisionAttempts {
+			var latest int
+			err := tx.Unscoped().Model(&models.Template{}).
+				Where("name = ?", template.Name).
+				Select("COALESCE(MAX(revision), 0)").
+				Scan(&latest).Error
+			if err != nil {
+				return err
+			}
+
+			template.Revision = latest + 1
+			result := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{
+					{Name: "tenant_id"}, {Name: "partition_id"}, {Name: "name"}, {Name: "revision"},
+				},
+				TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+				DoNothing:   true,
+			}).Create(template)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				return nil
+			}
+		}
+		return ErrRevisionConflict
+	})
+}
+
+// Publish makes the template version the one rendered for its name, retiring the version
+// published before it in the same transaction.
+func (tr *templateRepository) Publish(ctx context.Context, template *models.Template) error {
+	publishedAt := time.Now()
+
+	err := tr.Pool().DB(ctx, false).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Template{}).
+			Where("tenant_id = ? AND partition_id = ? AND name = ? AND state = ? AND id <> ?",
+				template.TenantID, template.PartitionID, template.Name, models.TemplateStatePublished, template.GetID()).
+			Update("state", models.TemplateStateRetired).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Template{}).
+			Where("id = ?", template.GetID()).
+			Updates(map[string]any{"state": models.TemplateStatePublished, "published_at": publishedAt}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	template.State = models.TemplateStatePublished
+	template.PublishedAt = &publishedAt
+	return nil
+}
+
+// Change applies content changes, and the extras when asked to, to a draft template version in
+// one transaction. The version is locked for the transaction so it can not be published
+// half changed.
+func (tr *templateRepository) Change(ctx context.Context, template *models.Template, updateExtra bool, changes []*TemplateDataChange) error {
+	return tr.Pool().DB(ctx, false).Transaction(func(tx *gorm.DB) error {
+		err := lockTemplate(tx, template.GetID(), models.TemplateStateDraft)
+		if err != nil {
+			return err
+		}
+
+		if updateExtra {
+			err = tx.Model(&models.Template{}).Where("id = ?", template.GetID()).
+				Update("extra", template.Extra).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, change := range changes {
+			err = changeTemplateData(tx, template, change)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// lockTemplate locks the template version for the rest of the transaction, failing when it is
+// gone or, for a non empty state, no longer in that state.
+func lockTemplate(tx *gorm.DB, templateID string, state string) error {
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", templateID)
+	if state != "" {
+		query = query.Where("state = ?", state)
+	}
+
+	var locked []*models.Template
+	err := query.Limit(1).Find(&locked).Error
+	if err != nil {
+		return err
+	}
+	if len(locked) == 0 {
+		if state != "" {
+			return ErrTemplateNotDraft
+		}
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// changeTemplateData keeps a single content row per language and type of the template version.
+func changeTemplateData(tx *gorm.DB, template *models.Template, change *TemplateDataChange) error {
+	var existing []*models.TemplateData
+	err := tx.Where("template_id = ? AND language_id = ? AND type = ?", template.GetID(), change.LanguageID, change.Type).
+		Limit(1).Find(&existing).Error
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case change.Remove:
+		if len(existing) == 0 {
+			return ErrTemplateDataMissing
+		}
+		return tx.Where("id = ?", existing[0].GetID()).Delete(&models.TemplateData{}).Error
+
+	case len(existing) > 0:
+		return tx.Model(&models.TemplateData{}).Where("id = ?", existing[0].GetID()).
+			Update("detail", change.Detail).Error
+
+	default:
+		return tx.Create(&models.TemplateData{
+			TemplateID: template.GetID(),
+			LanguageID: change.LanguageID,
+			Type:       change.Type,
+			Detail:     change.Detail,
+		}).Error
+	}
+}
+
+// Remove soft deletes the template version together with its content and returns the ids of
+// the removed rows. The version is locked while the notifications still waiting to be rendered
+// with it are counted, it is only removed when there are none.
+func (tr *templateRepository) Remove(ctx context.Context, template *models.Template) ([]string, error) {
+	removed := []string{template.GetID()}
+
+	err := tr.Pool().DB(ctx, false).Transaction(func(tx *gorm.DB) error {
+		err := lockTemplate(tx, template.GetID(), "")
+		if err != nil {
+			return err
+		}
+
+		var pending int64
+		err = tx.Model(&models.Notification{}).
+			Where("template_id = ? AND out_bound = true AND released_at IS NULL AND canceled_at IS NULL", template.GetID()).
+			Count(&pending).Error
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return ErrTemplateInUse
+		}
+
+		var templateDataList []*models.TemplateData
+		err = tx.Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+			Where("template_id = ?", template.GetID()).
+			Delete(&templateDataList).Error
+		if err != nil {
+			return err
+		}
+		for _, templateData := range templateDataList {
+			removed = append(removed, templateData.GetID())
+		}
+
+		return tx.Where("id = ?", template.GetID()).Delete(&models.Template{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
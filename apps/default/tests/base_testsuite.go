@@ -39,6 +39,12 @@ type ServiceResources struct {
 	TemplateRepo           repository.TemplateRepository
 	TemplateDataRepo       repository.TemplateDataRepository
 	RouteRepo              repository.RouteRepository
+	AggregateRepo          repository.NotificationAggregateRepository
+	SuppressionRepo        repository.SuppressionRepository
+	BroadcastRepo          repository.BroadcastRepository
+	IdempotencyKeyRepo     repository.IdempotencyKeyRepository
+	RateLimitCounterRepo   repository.RateLimitCounterRepository
+	ExternalIDRepo         repository.NotificationExternalIDRepository
 
 	// Business layer
 	NotificationBusiness business.NotificationBusiness
@@ -130,15 +136,45 @@ func (bs *BaseTestSuite) CreateService(
 	templateRepo := repository.NewTemplateRepository(ctx, dbPool, workMan)
 	templateDataRepo := repository.NewTemplateDataRepository(ctx, dbPool, workMan)
 	routeRepo := repository.NewRouteRepository(ctx, dbPool, workMan)
+	aggregateRepo := repository.NewNotificationAggregateRepository(ctx, dbPool, workMan)
+	idempotencyKeyRepo := repository.NewIdempotencyKeyRepository(ctx, dbPool, workMan)
+	rateLimitCounterRepo := repository.NewRateLimitCounterRepository(ctx, dbPool, workMan)
+	suppressionRepo := repository.NewSuppressionRepository(ctx, dbPool, workMan)
+	broadcastRepo := repository.NewBroadcastRepository(ctx, dbPool, workMan)
+	externalIDRepo := repository.NewNotificationExternalIDRepository(ctx, dbPool, workMan)
+
+	// Create business object with all dependencies
+	notificationBusiness := business.NewNotificationBusiness(
+		ctx,
+		workMan,
+		evtsMan,
+		profileCli,
+		tenancyCli,
+		notificationRepo,
+		notificationStatusRepo,
+		languageRepo,
+		templateRepo,
+		templateDataRepo,
+		routeRepo,
+		aggregateRepo,
+		idempotencyKeyRepo,
+		rateLimitCounterRepo,
+		suppressionRepo,
+		broadcastRepo,
+		externalIDRepo,
+		cfg.DefaultLanguageCode,
+		cfg.IdempotencyKeyRetention,
+	)
 
 	// Register event handlers with proper dependencies (same as main.go lines 92-98)
 	svc.Init(ctx, frame.WithRegisterEvents(
 		events.NewNotificationSave(ctx, evtsMan, notificationRepo),
-		events.NewNotificationStatusSave(ctx, notificationRepo, notificationStatusRepo),
-		events.NewNotificationInRoute(ctx, qMan, evtsMan, notificationRepo, routeRepo),
+		events.NewNotificationStatusSave(ctx, evtsMan, notificationRepo, notificationStatusRepo, routeRepo, aggregateRepo, cfg.MaxRouteAttempts),
+		events.NewNotificationInRoute(ctx, qMan, evtsMan, tenancyCli, notificationRepo, routeRepo, templateRepo, suppressionRepo),
 		events.NewNotificationInQueue(ctx, qMan, evtsMan, notificationRepo, routeRepo, profileCli),
-		events.NewNotificationOutRoute(ctx, evtsMan, profileCli, notificationRepo, routeRepo),
-		events.NewNotificationOutQueue(ctx, qMan, evtsMan, profileCli, tenancyCli, notificationRepo, notificationStatusRepo, languageRepo, templateDataRepo, routeRepo)))
+		events.NewNotificationOutRoute(ctx, evtsMan, profileCli, tenancyCli, notificationRepo, notificationStatusRepo, routeRepo, suppressionRepo),
+		events.NewNotificationOutQueue(ctx, qMan, evtsMan, profileCli, tenancyCli, notificationRepo, notificationStatusRepo, languageRepo, templateRepo, templateDataRepo, routeRepo, cfg.DefaultLanguageCode),
+		business.NewBroadcastExpand(ctx, notificationBusiness)))
 
 	// Get absolute path to migrations directory using source file location
 	// This file is in apps/default/service/tests, so migrations are at ../../migrations/0001
@@ -151,21 +187,6 @@ func (bs *BaseTestSuite) CreateService(
 	err = svc.Run(ctx, "")
 	require.NoError(t, err)
 
-	// Create business object with all dependencies
-	notificationBusiness := business.NewNotificationBusiness(
-		ctx,
-		workMan,
-		evtsMan,
-		profileCli,
-		tenancyCli,
-		notificationRepo,
-		notificationStatusRepo,
-		languageRepo,
-		templateRepo,
-		templateDataRepo,
-		routeRepo,
-	)
-
 	// Package all resources for easy reuse
 	resources := &ServiceResources{
 		NotificationRepo:       notificationRepo,
@@ -174,6 +195,12 @@ func (bs *BaseTestSuite) CreateService(
 		TemplateRepo:           templateRepo,
 		TemplateDataRepo:       templateDataRepo,
 		RouteRepo:              routeRepo,
+		AggregateRepo:          aggregateRepo,
+		SuppressionRepo:        suppressionRepo,
+		BroadcastRepo:          broadcastRepo,
+		ExternalIDRepo:         externalIDRepo,
+		IdempotencyKeyRepo:     idempotencyKeyRepo,
+		RateLimitCounterRepo:   rateLimitCounterRepo,
 		NotificationBusiness:   notificationBusiness,
 	}
 
@@ -62,6 +62,18 @@ var StatusCodeMap = map[int]string{
 	502: "RejectedByGateway",
 }
 
+// reroutableStatusCodes are send failures caused by the gateway rather than the
+// recipient, which makes them worth retrying on another route.
+var reroutableStatusCodes = map[int]bool{
+	407: true, // CouldNotRoute
+	502: true, // RejectedByGateway
+}
+
+// IsReroutable reports whether a failed send status code should be retried on another route.
+func IsReroutable(statusCode int) bool {
+	return reroutableStatusCodes[statusCode]
+}
+
 var FailureReasonOnRejectedOrFailedMap = map[string]string{
 	"InsufficientCredit":         "This occurs when the subscriber doesn’t have enough airtime for a premium subscription service/message",
 	"InvalidLinkId":              "This occurs when a message is sent with an invalid linkId for an onDemand service",
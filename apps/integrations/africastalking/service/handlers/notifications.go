@@ -8,14 +8,22 @@ import (
 
 	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
 	"buf.build/gen/go/antinvestor/notification/connectrpc/go/notification/v1/notificationv1connect"
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
 	"buf.build/gen/go/antinvestor/profile/connectrpc/go/profile/v1/profilev1connect"
+	profilev1 "buf.build/gen/go/antinvestor/profile/protocolbuffers/go/profile/v1"
 	"connectrpc.com/connect"
 	"github.com/antinvestor/service-notification/apps/integrations/africastalking/service/client"
 	"github.com/antinvestor/service-notification/pkg/apperrors"
+	"github.com/antinvestor/service-notification/pkg/constants"
 	"github.com/pitabwire/util"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
+const (
+	suppressionChannelSMS = "sms"
+	suppressionSource     = "africastalking"
+)
+
 type ATServer struct {
 	ProfileCli        profilev1connect.ProfileServiceClient
 	NotificationCli   notificationv1connect.NotificationServiceClient
@@ -89,7 +97,7 @@ func (ps *ATServer) ReceiveNotification(rw http.ResponseWriter, req *http.Reques
 	case client.SubscriptionNotifications:
 		appErr = ps.handleSubscriptionNotifications(ctx, routeID, rawIPData, payload)
 	case client.IncomingMessages:
-		appErr = ps.handleIncomingMessages(ctx, rawIPData, routeID, payload)
+		appErr = ps.handleIncomingMessages(ctx, routeID, rawIPData, payload)
 	default:
 		appErr = apperrors.ErrInvalidFormat.Extend("Could not determine notification category")
 	}
@@ -150,6 +158,7 @@ func (ps *ATServer) handleDeliveryReport(ctx context.Context, routeID, ip string
 	}
 	extraData["route"] = routeID
 	extraData["ip"] = ip
+	extraData[constants.StatusExtraStep] = constants.StatusStepDeliveryReport
 	networkCode, ok := payload["networkCode"]
 	if ok {
 		extraData["network"] = client.SupportedNetworksMap[networkCode.(int)]
@@ -191,6 +200,28 @@ func (ps *ATServer) handleDeliveryReport(ctx context.Context, routeID, ip string
 
 func (ps *ATServer) handleBulkSMSOptOut(ctx context.Context, routeID, ip string, payload map[string]any) *apperrors.Error {
 
+	phoneNumber, _ := payload["phoneNumber"].(string)
+	senderID, _ := payload["senderId"].(string)
+	if phoneNumber == "" {
+		return apperrors.ErrInvalidFormat.Extend("opt out is missing the phone number")
+	}
+
+	// Sends are matched by the sender contact of the notification, which never is the carrier
+	// sender id, so the opt out covers the channel and keeps the sender id as a detail.
+	extra, _ := structpb.NewStruct(map[string]any{"ip": ip, "sender_id": senderID})
+	_, err := ps.NotificationCli.SuppressionAdd(ctx, connect.NewRequest(&notificationv1.SuppressionAddRequest{
+		Data: &notificationv1.Suppression{
+			Contact: phoneNumber,
+			Channel: suppressionChannelSMS,
+			RouteId: routeID,
+			Reason:  "opt_out",
+			Source:  suppressionSource,
+			Extras:  extra,
+		},
+	}))
+	if err != nil {
+		return apperrors.ErrSystemFailure.Extend(err.Error())
+	}
 	return nil
 }
 
@@ -214,6 +245,41 @@ func (ps *ATServer) handleBulkSMSOptOut(ctx context.Context, routeID, ip string,
 // The type of the update. The value could either be addition or deletion.
 func (ps *ATServer) handleSubscriptionNotifications(ctx context.Context, routeID, ip string, payload map[string]any) *apperrors.Error {
 
+	phoneNumber, _ := payload["phoneNumber"].(string)
+	shortCode, _ := payload["shortCode"].(string)
+	keyword, _ := payload["keyword"].(string)
+	updateType, _ := payload["updateType"].(string)
+	if phoneNumber == "" {
+		return apperrors.ErrInvalidFormat.Extend("subscription update is missing the phone number")
+	}
+
+	// Like opt outs, unsubscribes cover the channel, the short code is kept as a detail.
+	var err error
+	switch updateType {
+	case "deletion":
+		extra, _ := structpb.NewStruct(map[string]any{"ip": ip, "keyword": keyword, "short_code": shortCode})
+		_, err = ps.NotificationCli.SuppressionAdd(ctx, connect.NewRequest(&notificationv1.SuppressionAddRequest{
+			Data: &notificationv1.Suppression{
+				Contact: phoneNumber,
+				Channel: suppressionChannelSMS,
+				RouteId: routeID,
+				Reason:  "unsubscribed",
+				Source:  suppressionSource,
+				Extras:  extra,
+			},
+		}))
+	case "addition":
+		// Subscribing again lifts an earlier unsubscribe.
+		_, err = ps.NotificationCli.SuppressionRemove(ctx, connect.NewRequest(&notificationv1.SuppressionRemoveRequest{
+			Contact: phoneNumber,
+			Channel: suppressionChannelSMS,
+		}))
+	default:
+		return apperrors.ErrInvalidFormat.Extend(fmt.Sprintf("unknown subscription update type %q", updateType))
+	}
+	if err != nil {
+		return apperrors.ErrSystemFailure.Extend(err.Error())
+	}
 	return nil
 }
 
@@ -248,5 +314,77 @@ func (ps *ATServer) handleSubscriptionNotifications(ctx context.Context, routeID
 // A unique identifier for the telco that handled the message.
 func (ps *ATServer) handleIncomingMessages(ctx context.Context, routeID, ip string, payload map[string]any) *apperrors.Error {
 
+	from, _ := payload["from"].(string)
+	to, _ := payload["to"].(string)
+	text, _ := payload["text"].(string)
+	if from == "" || to == "" {
+		return apperrors.ErrInvalidFormat.Extend("incoming message is missing the sender or the shortcode")
+	}
+
+	source, appErr := ps.resolveContactLink(ctx, from)
+	if appErr != nil {
+		return appErr
+	}
+
+	recipient, appErr := ps.resolveContactLink(ctx, to)
+	if appErr != nil {
+		return appErr
+	}
+
+	// The carrier details are kept with the message, replying to premium requests needs the linkId.
+	messageData := map[string]any{"ip": ip}
+	for _, key := range []string{"id", "from", "to", "linkId", "networkCode", "date", "cost"} {
+		if v, ok := payload[key]; ok && v != nil {
+			messageData[key] = fmt.Sprintf("%v", v)
+		}
+	}
+	messagePayload, _ := structpb.NewStruct(messageData)
+
+	stream, err := ps.NotificationCli.Receive(ctx, connect.NewRequest(&notificationv1.ReceiveRequest{
+		Data: []*notificationv1.Notification{{
+			Source:    source,
+			Recipient: recipient,
+			Type:      suppressionChannelSMS,
+			Data:      text,
+			Payload:   messagePayload,
+			RouteId:   routeID,
+			OutBound:  false,
+		}},
+	}))
+	if err != nil {
+		return apperrors.ErrSystemFailure.Extend(err.Error())
+	}
+
+	for stream.Receive() {
+		// Only the acknowledgement is needed
+	}
+	if err = stream.Err(); err != nil {
+		return apperrors.ErrSystemFailure.Extend(err.Error())
+	}
 	return nil
 }
+
+// resolveContactLink links a phone number or shortcode to the profile that owns it.
+// Numbers not yet known to the profile service are passed on with just their detail.
+func (ps *ATServer) resolveContactLink(ctx context.Context, detail string) (*commonv1.ContactLink, *apperrors.Error) {
+
+	link := &commonv1.ContactLink{Detail: detail}
+
+	resp, err := ps.ProfileCli.GetByContact(ctx, connect.NewRequest(&profilev1.GetByContactRequest{Contact: detail}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			return link, nil
+		}
+		return nil, apperrors.ErrSystemFailure.Extend(err.Error())
+	}
+
+	profile := resp.Msg.GetData()
+	link.ProfileId = profile.GetId()
+	for _, contact := range profile.GetContacts() {
+		if contact.GetDetail() == detail {
+			link.ContactId = contact.GetId()
+			break
+		}
+	}
+	return link, nil
+}
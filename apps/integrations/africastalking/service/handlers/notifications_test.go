@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	"buf.build/gen/go/antinvestor/notification/connectrpc/go/notification/v1/notificationv1connect"
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+	"buf.build/gen/go/antinvestor/profile/connectrpc/go/profile/v1/profilev1connect"
+	profilev1 "buf.build/gen/go/antinvestor/profile/protocolbuffers/go/profile/v1"
+	"connectrpc.com/connect"
+	"github.com/antinvestor/service-notification/apps/integrations/africastalking/config"
+	"github.com/antinvestor/service-notification/apps/integrations/africastalking/service/client"
+	"github.com/stretchr/testify/require"
+)
+
+// testProfiles answers contact lookups from a map of profiles keyed by contact detail.
+type testProfiles struct {
+	profilev1connect.ProfileServiceClient
+	profiles map[string]*profilev1.ProfileObject
+	err      error
+}
+
+func (tp *testProfiles) GetByContact(_ context.Context, req *connect.Request[profilev1.GetByContactRequest]) (*connect.Response[profilev1.GetByContactResponse], error) {
+	if tp.err != nil {
+		return nil, tp.err
+	}
+	profile, ok := tp.profiles[req.Msg.GetContact()]
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("profile not found"))
+	}
+	return connect.NewResponse(&profilev1.GetByContactResponse{Data: profile}), nil
+}
+
+// receivingNotifications records the notifications handed to Receive and acknowledges them.
+type receivingNotifications struct {
+	notificationv1connect.UnimplementedNotificationServiceHandler
+
+	mu           sync.Mutex
+	received     []*notificationv1.Notification
+	suppressions []*notificationv1.Suppression
+	lifted       []*notificationv1.SuppressionRemoveRequest
+}
+
+func (rn *receivingNotifications) SuppressionAdd(_ context.Context, req *connect.Request[notificationv1.SuppressionAddRequest]) (*connect.Response[notificationv1.SuppressionAddResponse], error) {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	rn.suppressions = append(rn.suppressions, req.Msg.GetData())
+	return connect.NewResponse(&notificationv1.SuppressionAddResponse{Data: req.Msg.GetData()}), nil
+}
+
+func (rn *receivingNotifications) SuppressionRemove(_ context.Context, req *connect.Request[notificationv1.SuppressionRemoveRequest]) (*connect.Response[notificationv1.SuppressionRemoveResponse], error) {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	rn.lifted = append(rn.lifted, req.Msg)
+	return connect.NewResponse(&notificationv1.SuppressionRemoveResponse{}), nil
+}
+
+func (rn *receivingNotifications) Receive(_ context.Context, req *connect.Request[notificationv1.ReceiveRequest],
+	stream *connect.ServerStream[notificationv1.ReceiveResponse]) error {
+
+	rn.mu.Lock()
+	rn.received = append(rn.received, req.Msg.GetData()...)
+	rn.mu.Unlock()
+
+	return stream.Send(&notificationv1.ReceiveResponse{Data: []*commonv1.StatusResponse{{
+		State:  commonv1.STATE_CREATED,
+		Status: commonv1.STATUS_UNKNOWN,
+	}}})
+}
+
+func (rn *receivingNotifications) notifications() []*notificationv1.Notification {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	return rn.received
+}
+
+func newTestATServer(t *testing.T, profiles *testProfiles) (*ATServer, *receivingNotifications) {
+	notifications := &receivingNotifications{}
+	_, handler := notificationv1connect.NewNotificationServiceHandler(notifications)
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	atCli, err := client.NewClient(&config.AfricasTalkingConfig{}, nil, nil)
+	require.NoError(t, err)
+
+	notificationCli := notificationv1connect.NewNotificationServiceClient(server.Client(), server.URL)
+	return NewATServer(profiles, notificationCli, atCli), notifications
+}
+
+func postCallback(srv *ATServer, routeID, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/receive/notification/"+routeID, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.NewRouterV1().ServeHTTP(rec, req)
+	return rec
+}
+
+const incomingMessageCallback = `{
+	"id": "ATXid_1",
+	"from": "+256700123456",
+	"to": "22384",
+	"text": "STOP",
+	"linkId": "link-1",
+	"networkCode": "64110",
+	"date": "2026-10-17 10:00:00"
+}`
+
+func TestCategoriseIncomingMessage(t *testing.T) {
+	atCli, err := client.NewClient(&config.AfricasTalkingConfig{}, nil, nil)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.Equal(t, client.IncomingMessages, atCli.Categorise(ctx, map[string]any{
+		"id": "ATXid_1", "from": "+256700123456", "to": "22384", "text": "STOP",
+	}))
+	require.Equal(t, client.DeliveryReport, atCli.Categorise(ctx, map[string]any{
+		"id": "ATXid_1", "status": "Success", "phoneNumber": "+256700123456",
+	}))
+	require.Empty(t, atCli.Categorise(ctx, map[string]any{"text": "STOP"}))
+}
+
+func TestReceiveIncomingMessage(t *testing.T) {
+	profiles := &testProfiles{profiles: map[string]*profilev1.ProfileObject{
+		"+256700123456": {
+			Id: "profile-1",
+			Contacts: []*profilev1.ContactObject{
+				{Id: "contact-email", Detail: "someone@example.com"},
+				{Id: "contact-msisdn", Detail: "+256700123456"},
+			},
+		},
+	}}
+	srv, notifications := newTestATServer(t, profiles)
+
+	rec := postCallback(srv, "route-1", incomingMessageCallback)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	require.Len(t, notifications.notifications(), 1)
+	received := notifications.notifications()[0]
+	require.Equal(t, "route-1", received.GetRouteId())
+	require.Equal(t, "sms", received.GetType())
+	require.Equal(t, "STOP", received.GetData())
+	require.False(t, received.GetOutBound())
+
+	require.Equal(t, "profile-1", received.GetSource().GetProfileId())
+	require.Equal(t, "contact-msisdn", received.GetSource().GetContactId())
+	require.Equal(t, "+256700123456", received.GetSource().GetDetail())
+
+	require.Empty(t, received.GetRecipient().GetProfileId(), "unknown shortcodes keep only their detail")
+	require.Equal(t, "22384", received.GetRecipient().GetDetail())
+
+	payload := received.GetPayload().AsMap()
+	require.Equal(t, "link-1", payload["linkId"])
+	require.Equal(t, "64110", payload["networkCode"])
+	require.Equal(t, "2026-10-17 10:00:00", payload["date"])
+}
+
+func TestReceiveIncomingMessageFailures(t *testing.T) {
+	t.Run("missing shortcode", func(t *testing.T) {
+		srv, notifications := newTestATServer(t, &testProfiles{})
+
+		rec := postCallback(srv, "route-1", `{"id": "ATXid_1", "from": "+256700123456", "text": "STOP"}`)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Empty(t, notifications.notifications())
+	})
+
+	t.Run("profile service unavailable", func(t *testing.T) {
+		srv, notifications := newTestATServer(t, &testProfiles{
+			err: connect.NewError(connect.CodeUnavailable, errors.New("profile service down")),
+		})
+
+		rec := postCallback(srv, "route-1", incomingMessageCallback)
+		require.Equal(t, http.StatusInternalServerError, rec.Code)
+		require.Empty(t, notifications.notifications(), "messages are not received without their sender")
+	})
+}
+
+func TestReceiveOptOuts(t *testing.T) {
+	srv, notifications := newTestATServer(t, &testProfiles{})
+
+	rec := postCallback(srv, "route-1", `{"phoneNumber": "+256700123456", "senderId": "22384", "optOutCode": "STOP"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = postCallback(srv, "route-1",
+		`{"phoneNumber": "+256700654321", "shortCode": "22385", "keyword": "news", "updateType": "deletion"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = postCallback(srv, "route-1",
+		`{"phoneNumber": "+256700654321", "shortCode": "22385", "keyword": "news", "updateType": "addition"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	notifications.mu.Lock()
+	defer notifications.mu.Unlock()
+
+	require.Len(t, notifications.suppressions, 2)
+	optOut := notifications.suppressions[0]
+	require.Equal(t, "+256700123456", optOut.GetContact())
+	require.Equal(t, "sms", optOut.GetChannel())
+	require.Empty(t, optOut.GetSenderId(), "carrier sender ids never match the sender of a send, opt outs cover the channel")
+	require.Equal(t, "22384", optOut.GetExtras().AsMap()["sender_id"])
+	require.Equal(t, "route-1", optOut.GetRouteId())
+
+	unsubscribe := notifications.suppressions[1]
+	require.Empty(t, unsubscribe.GetSenderId())
+	require.Equal(t, "22385", unsubscribe.GetExtras().AsMap()["short_code"])
+
+	require.Len(t, notifications.lifted, 1)
+	require.Equal(t, "+256700654321", notifications.lifted[0].GetContact())
+	require.Empty(t, notifications.lifted[0].GetSenderId(), "subscribing again lifts the suppression it was recorded as")
+}
@@ -12,7 +12,9 @@ import (
 	"buf.build/gen/go/antinvestor/profile/connectrpc/go/profile/v1/profilev1connect"
 	"github.com/antinvestor/service-notification/apps/integrations/africastalking/service/client"
 	"github.com/antinvestor/service-notification/pkg/apperrors"
+	"github.com/antinvestor/service-notification/pkg/constants"
 	"github.com/antinvestor/service-notification/pkg/events"
+	"github.com/antinvestor/service-notification/pkg/sms"
 	frameEvents "github.com/pitabwire/frame/v2/events"
 	"github.com/pitabwire/frame/v2/queue"
 	"github.com/pitabwire/util"
@@ -55,11 +57,15 @@ func (ms *messageToSend) Handle(ctx context.Context, headers map[string]string,
 		return nil
 	}
 
+	segmentation := sms.Segment(notification.GetData())
+
 	log = log.WithField("notification_id", notification.GetId())
 	log.WithFields(map[string]any{
 		"recipient":      notification.GetRecipient().GetProfileId(),
 		"sender":         notification.GetSource().GetProfileId(),
 		"message_length": len(notification.GetData()),
+		"segments":       segmentation.Segments,
+		"encoding":       segmentation.Encoding,
 	}).Debug("processing Africa's Talking SMS message")
 
 	resp, err := ms.africasTalkingCli.Send(ctx, headers, &notification)
@@ -67,7 +73,8 @@ func (ms *messageToSend) Handle(ctx context.Context, headers map[string]string,
 		log.WithError(err).Error("Africa's Talking API responded with error")
 
 		extrasMap := map[string]any{
-			"error": err.Error(),
+			"error":                   err.Error(),
+			constants.StatusExtraStep: constants.StatusStepSubmit,
 		}
 		extra, _ := structpb.NewStruct(extrasMap)
 
@@ -112,7 +119,14 @@ func (ms *messageToSend) Handle(ctx context.Context, headers map[string]string,
 
 	rs := resp.SMSMessageData.Recipients[0]
 
-	extrasMap := map[string]any{"status": rs.Status, "cost": rs.Cost, "status_code": strconv.Itoa(rs.StatusCode)}
+	extrasMap := map[string]any{
+		constants.StatusExtraStep:        constants.StatusStepSubmit,
+		"status":                         rs.Status,
+		"cost":                           rs.Cost,
+		"status_code":                    strconv.Itoa(rs.StatusCode),
+		constants.StatusExtraSMSSegments: segmentation.Segments,
+		constants.StatusExtraSMSEncoding: string(segmentation.Encoding),
+	}
 	extra, _ := structpb.NewStruct(extrasMap)
 	if rs.StatusCode >= 500 && rs.StatusCode < 502 {
 
@@ -137,6 +151,14 @@ func (ms *messageToSend) Handle(ctx context.Context, headers map[string]string,
 		notificationStatus = commonv1.STATUS_FAILED
 	}
 
+	if client.IsReroutable(rs.StatusCode) {
+		// The gateway could not take the message, another route may still deliver it.
+		notificationStatus = commonv1.STATUS_FAILED
+		extrasMap["error"] = client.StatusCodeMap[rs.StatusCode]
+		extrasMap[constants.StatusExtraReroute] = true
+		extra, _ = structpb.NewStruct(extrasMap)
+	}
+
 	err = ms.eventsMan.Emit(ctx, events.NotificationStatusUpdateEvent,
 		&commonv1.StatusUpdateRequest{
 			Id:         notification.GetId(),
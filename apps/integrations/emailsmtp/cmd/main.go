@@ -15,6 +15,7 @@ import (
 	"github.com/antinvestor/service-notification/apps/integrations/emailsmtp/service/queues"
 	"github.com/antinvestor/service-notification/pkg/events"
 	"github.com/pitabwire/frame/v2"
+	fclient "github.com/pitabwire/frame/v2/client"
 	"github.com/pitabwire/frame/v2/config"
 	"github.com/pitabwire/util"
 )
@@ -55,11 +56,20 @@ func main() {
 		logger.WithError(err).Fatal("could not setup profile client")
 	}
 
-	emailSMTPCli, err := client.NewClient(logger, &cfg, profileCli, settingsCli)
+	// Attachments are fetched as this service, on behalf of the partition of each notification.
+	storageCli := svc.HTTPClientManager().Client(ctx,
+		fclient.WithHTTPTimeout(cfg.EmailAttachmentFetchTimeout),
+		fclient.WithHTTPWorkloadAPITargetPath(cfg.StorageServiceWorkloadAPITargetPath))
+
+	emailSMTPCli, err := client.NewClient(logger, &cfg, profileCli, settingsCli, storageCli)
 	if err != nil {
 		logger.WithError(err).Fatal("could not setup email smtp client")
 	}
 
+	if cfg.EmailWebhookUsername == "" {
+		logger.Warn("no email webhook username configured, bounce and complaint callbacks will be refused")
+	}
+
 	// Create handlers with injected dependencies
 	implementation := handlers.NewSMTPServer(profileCli, notificationCli, emailSMTPCli)
 	messageHandler := queues.NewMessageToSend(eventsMan, profileCli, notificationCli, emailSMTPCli)
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/pitabwire/frame/v2/config"
 )
 
@@ -22,8 +24,31 @@ type EmailSMTPConfig struct {
 	QueueEmailSMTPDequeueName string `envDefault:"natifications.emailsmtp.dequeue" env:"QUEUE_NOTIFICATION_EMAIL_DEQUEUE_NAME"`
 	QueueEmailSMTPDequeueURI  string `envDefault:"mem://natifications.email.de.queue" env:"QUEUE_NOTIFICATION_EMAIL_DEQUEUE_URI"`
 
+	// Default relay, used by partitions without an SMTP connection of their own in the settings service
 	SMTPServerHOST      string `envDefault:"smtp.postmarkapp.com" env:"SMTP_SERVER_HOST"`
 	SMTPServerPORT      int    `envDefault:"587" env:"SMTP_SERVER_PORT"`
+	SMTPServerAuthMode  string `envDefault:"plain" env:"SMTP_SERVER_AUTH_MODE"`
+	SMTPServerTLSPolicy string `envDefault:"mandatory" env:"SMTP_SERVER_TLS_POLICY"`
 	SMTPServerAccessKey string `envDefault:"" env:"SMTP_SERVER_ACCESS_KEY"`
 	SMTPServerSecretKey string `envDefault:"" env:"SMTP_SERVER_SECRET_KEY"`
+	SMTPSenderAddress   string `envDefault:"" env:"SMTP_SENDER_ADDRESS"`
+	SMTPSenderName      string `envDefault:"" env:"SMTP_SENDER_NAME"`
+
+	// Sender domains partitions may send from through the default relay, as partition_id:domain entries
+	SMTPVerifiedSenderDomains []string `envDefault:"" env:"SMTP_VERIFIED_SENDER_DOMAINS"`
+
+	SettingsSMTPConnectionName string        `envDefault:"smtp_connection" env:"SETTINGS_SMTP_CONNECTION_NAME"`
+	SMTPCredentialsCacheTTL    time.Duration `envDefault:"5m" env:"SMTP_CREDENTIALS_CACHE_TTL"`
+
+	// Bounce and complaint webhook basic auth, callbacks are refused until a username is set
+	EmailWebhookUsername string `envDefault:"" env:"EMAIL_WEBHOOK_USERNAME"`
+	EmailWebhookPassword string `envDefault:"" env:"EMAIL_WEBHOOK_PASSWORD"`
+
+	// Attachments, files are fetched from the storage service by id
+	StorageServiceURI                   string        `envDefault:"" env:"STORAGE_SERVICE_URI"`
+	StorageServiceWorkloadAPITargetPath string        `envDefault:"/ns/files/sa/service-files" env:"STORAGE_SERVICE_WORKLOAD_API_TARGET_PATH"`
+	EmailAttachmentFetchTimeout         time.Duration `envDefault:"30s" env:"EMAIL_ATTACHMENT_FETCH_TIMEOUT"`
+	EmailAttachmentMaxBytes             int           `envDefault:"10485760" env:"EMAIL_ATTACHMENT_MAX_BYTES"`
+	EmailAttachmentsMaxTotalBytes       int           `envDefault:"20971520" env:"EMAIL_ATTACHMENTS_MAX_TOTAL_BYTES"`
+	EmailInlineAttachmentMaxBytes       int           `envDefault:"262144" env:"EMAIL_INLINE_ATTACHMENT_MAX_BYTES"`
 }
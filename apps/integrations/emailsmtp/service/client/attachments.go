@@ -0,0 +1,213 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/wneessen/go-mail"
+)
+
+// PayloadKeyAttachments is the notification payload key listing the files to send with an email.
+const PayloadKeyAttachments = "attachments"
+
+var (
+	// ErrAttachmentFetch is returned when an attachment does not exist, may not be read or is
+	// too large to send. Sending the email again will not change that.
+	ErrAttachmentFetch = errors.New("could not fetch attachment")
+	// ErrAttachmentUnavailable is returned when the storage service could not be reached or
+	// failed to serve a file, fetching it again later may succeed.
+	ErrAttachmentUnavailable = errors.New("attachment temporarily unavailable")
+)
+
+// Attachment is a file sent with an email, either a reference to a file held by the
+// storage service or small content carried base64 encoded in the payload. Attachments
+// with a content id are embedded inline, for html bodies to show as cid: images.
+type Attachment struct {
+	FileID      string
+	Content     string
+	Filename    string
+	ContentType string
+	ContentID   string
+
+	data []byte
+}
+
+func (a *Attachment) name() string {
+	switch {
+	case a.Filename != "":
+		return a.Filename
+	case a.ContentID != "":
+		return a.ContentID
+	default:
+		return a.FileID
+	}
+}
+
+// attachmentsFromPayload reads the attachments listed on a notification payload.
+func attachmentsFromPayload(payload map[string]any) ([]*Attachment, error) {
+	raw, ok := payload[PayloadKeyAttachments]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s must be a list", ErrAttachmentFetch, PayloadKeyAttachments)
+	}
+
+	attachments := make([]*Attachment, 0, len(list))
+	for i, item := range list {
+		fields, fOk := item.(map[string]any)
+		if !fOk {
+			return nil, fmt.Errorf("%w: attachment %d is not an object", ErrAttachmentFetch, i)
+		}
+
+		field := func(key string) string {
+			s, _ := fields[key].(string)
+			return strings.TrimSpace(s)
+		}
+
+		attachment := &Attachment{
+			FileID:      field("file_id"),
+			Content:     field("content"),
+			Filename:    field("filename"),
+			ContentType: field("content_type"),
+			ContentID:   field("content_id"),
+		}
+
+		if (attachment.FileID == "") == (attachment.Content == "") {
+			return nil, fmt.Errorf("%w: attachment %d needs either a file_id or base64 content", ErrAttachmentFetch, i)
+		}
+		if attachment.Filename == "" && attachment.ContentID == "" {
+			return nil, fmt.Errorf("%w: attachment %d needs a filename or a content_id", ErrAttachmentFetch, i)
+		}
+
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+// fetchAttachments loads the content of every attachment, holding each file and the
+// email as a whole to the configured size limits.
+func (ms *Client) fetchAttachments(ctx context.Context, headers map[string]string, attachments []*Attachment) error {
+	total := 0
+	for _, attachment := range attachments {
+		var err error
+		if attachment.Content != "" {
+			err = ms.decodeAttachment(attachment)
+		} else {
+			err = ms.downloadAttachment(ctx, headers, attachment)
+		}
+		if err != nil {
+			return err
+		}
+
+		total += len(attachment.data)
+		if total > ms.cfg.EmailAttachmentsMaxTotalBytes {
+			return fmt.Errorf("%w: attachments exceed %d bytes in total", ErrAttachmentFetch, ms.cfg.EmailAttachmentsMaxTotalBytes)
+		}
+	}
+	return nil
+}
+
+func (ms *Client) decodeAttachment(attachment *Attachment) error {
+	if len(attachment.Content) > base64.StdEncoding.EncodedLen(ms.cfg.EmailInlineAttachmentMaxBytes) {
+		return fmt.Errorf("%w: %s is over the %d bytes allowed for content in the payload",
+			ErrAttachmentFetch, attachment.name(), ms.cfg.EmailInlineAttachmentMaxBytes)
+	}
+
+	content, err := base64.StdEncoding.DecodeString(attachment.Content)
+	if err != nil {
+		return fmt.Errorf("%w: %s is not valid base64: %w", ErrAttachmentFetch, attachment.name(), err)
+	}
+	if len(content) > ms.cfg.EmailInlineAttachmentMaxBytes {
+		return fmt.Errorf("%w: %s is over the %d bytes allowed for content in the payload",
+			ErrAttachmentFetch, attachment.name(), ms.cfg.EmailInlineAttachmentMaxBytes)
+	}
+
+	attachment.data = content
+	return nil
+}
+
+// downloadAttachment reads a file from the storage service, on behalf of the partition the
+// notification belongs to so files of other partitions can not be sent.
+func (ms *Client) downloadAttachment(ctx context.Context, headers map[string]string, attachment *Attachment) error {
+	if ms.cfg.StorageServiceURI == "" {
+		return fmt.Errorf("%w: no storage service configured to resolve file %s", ErrAttachmentFetch, attachment.FileID)
+	}
+
+	fileURL, err := url.JoinPath(ms.cfg.StorageServiceURI, url.PathEscape(attachment.FileID))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAttachmentFetch, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAttachmentFetch, err)
+	}
+	req.Header.Set("X-Tenant-Id", headers[constants.TenantIDHeaderName])
+	req.Header.Set("X-Partition-Id", headers[constants.PartitionIDHeaderName])
+
+	resp, err := ms.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: file %s: %w", ErrAttachmentUnavailable, attachment.FileID, err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusForbidden:
+		return fmt.Errorf("%w: file %s: storage service responded %s", ErrAttachmentFetch, attachment.FileID, resp.Status)
+	default:
+		return fmt.Errorf("%w: file %s: storage service responded %s", ErrAttachmentUnavailable, attachment.FileID, resp.Status)
+	}
+
+	limit := ms.cfg.EmailAttachmentMaxBytes
+	content, err := io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	if err != nil {
+		return fmt.Errorf("%w: file %s: %w", ErrAttachmentUnavailable, attachment.FileID, err)
+	}
+	if len(content) > limit {
+		return fmt.Errorf("%w: file %s is over the %d bytes allowed per attachment", ErrAttachmentFetch, attachment.FileID, limit)
+	}
+
+	if attachment.ContentType == "" {
+		attachment.ContentType = resp.Header.Get("Content-Type")
+	}
+
+	attachment.data = content
+	return nil
+}
+
+// addAttachments puts the fetched attachments on the message, inline when they have a content id.
+func addAttachments(msg *mail.Msg, attachments []*Attachment) error {
+	for _, attachment := range attachments {
+		opts := []mail.FileOption{mail.WithFileName(attachment.name())}
+		if attachment.ContentType != "" {
+			opts = append(opts, mail.WithFileContentType(mail.ContentType(attachment.ContentType)))
+		}
+
+		var err error
+		if attachment.ContentID != "" {
+			// html bodies refer to the part as cid:<content id>, the header carries it in angle brackets.
+			opts = append(opts, mail.WithFileContentID("<"+strings.Trim(attachment.ContentID, "<>")+">"))
+			err = msg.EmbedReader(attachment.name(), bytes.NewReader(attachment.data), opts...)
+		} else {
+			err = msg.AttachReader(attachment.name(), bytes.NewReader(attachment.data), opts...)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrAttachmentFetch, attachment.name(), err)
+		}
+	}
+	return nil
+}
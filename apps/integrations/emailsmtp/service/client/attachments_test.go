@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/antinvestor/service-notification/apps/integrations/emailsmtp/config"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/pitabwire/util"
+	"github.com/stretchr/testify/require"
+)
+
+var attachmentHeaders = map[string]string{
+	constants.TenantIDHeaderName:    "tenant-1",
+	constants.PartitionIDHeaderName: "partition-1",
+}
+
+// newTestStorage serves files by id for partition-1 only, the way the storage service
+// refuses files of other partitions.
+func newTestStorage(t *testing.T, files map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Partition-Id") != "partition-1" || r.Header.Get("X-Tenant-Id") != "tenant-1" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		fileID := strings.TrimPrefix(r.URL.Path, "/files/")
+		if fileID == "broken" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		content, ok := files[fileID]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestAttachmentClient(t *testing.T, storage *httptest.Server) *Client {
+	cli, err := NewClient(util.Log(context.Background()), &config.EmailSMTPConfig{
+		StorageServiceURI:             storage.URL + "/files",
+		EmailAttachmentMaxBytes:       16,
+		EmailAttachmentsMaxTotalBytes: 24,
+		EmailInlineAttachmentMaxBytes: 8,
+	}, nil, nil, storage.Client())
+	require.NoError(t, err)
+	return cli
+}
+
+func TestAttachmentsFromPayload(t *testing.T) {
+	attachments, err := attachmentsFromPayload(map[string]any{
+		PayloadKeyAttachments: []any{
+			map[string]any{"file_id": "invoice", "filename": "invoice.pdf"},
+			map[string]any{"content": "aGk=", "content_id": "logo", "content_type": "image/png"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, attachments, 2)
+	require.Equal(t, "invoice.pdf", attachments[0].name())
+	require.Equal(t, "logo", attachments[1].name())
+
+	invalid := []any{
+		"not an object",
+		map[string]any{"filename": "empty.pdf"},
+		map[string]any{"file_id": "invoice", "content": "aGk=", "filename": "both.pdf"},
+		map[string]any{"file_id": "invoice"},
+	}
+	for _, item := range invalid {
+		_, err = attachmentsFromPayload(map[string]any{PayloadKeyAttachments: []any{item}})
+		require.ErrorIs(t, err, ErrAttachmentFetch)
+	}
+}
+
+func TestFetchAttachments(t *testing.T) {
+	storage := newTestStorage(t, map[string]string{
+		"invoice": "invoice content",
+		"large":   "a file over the sixteen bytes allowed",
+		"report":  "report content",
+	})
+	cli := newTestAttachmentClient(t, storage)
+	ctx := context.Background()
+
+	t.Run("fetched and decoded", func(t *testing.T) {
+		attachments := []*Attachment{
+			{FileID: "invoice", Filename: "invoice.pdf"},
+			{Content: base64.StdEncoding.EncodeToString([]byte("logo")), ContentID: "logo"},
+		}
+		require.NoError(t, cli.fetchAttachments(ctx, attachmentHeaders, attachments))
+		require.Equal(t, "invoice content", string(attachments[0].data))
+		require.Equal(t, "application/pdf", attachments[0].ContentType)
+		require.Equal(t, "logo", string(attachments[1].data))
+	})
+
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		attachments []*Attachment
+		wantErr     error
+	}{
+		{
+			name:        "missing file",
+			headers:     attachmentHeaders,
+			attachments: []*Attachment{{FileID: "missing", Filename: "missing.pdf"}},
+			wantErr:     ErrAttachmentFetch,
+		},
+		{
+			name:        "file of another partition",
+			headers:     map[string]string{constants.PartitionIDHeaderName: "partition-2"},
+			attachments: []*Attachment{{FileID: "invoice", Filename: "invoice.pdf"}},
+			wantErr:     ErrAttachmentFetch,
+		},
+		{
+			name:        "file over the size limit",
+			headers:     attachmentHeaders,
+			attachments: []*Attachment{{FileID: "large", Filename: "large.pdf"}},
+			wantErr:     ErrAttachmentFetch,
+		},
+		{
+			name:    "files over the total limit",
+			headers: attachmentHeaders,
+			attachments: []*Attachment{
+				{FileID: "invoice", Filename: "invoice.pdf"},
+				{FileID: "report", Filename: "report.pdf"},
+			},
+			wantErr: ErrAttachmentFetch,
+		},
+		{
+			name:        "content over the payload limit",
+			headers:     attachmentHeaders,
+			attachments: []*Attachment{{Content: base64.StdEncoding.EncodeToString([]byte("too long for the payload")), Filename: "a.txt"}},
+			wantErr:     ErrAttachmentFetch,
+		},
+		{
+			name:        "storage service failing",
+			headers:     attachmentHeaders,
+			attachments: []*Attachment{{FileID: "broken", Filename: "broken.pdf"}},
+			wantErr:     ErrAttachmentUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cli.fetchAttachments(ctx, tt.headers, tt.attachments)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("storage service unreachable", func(t *testing.T) {
+		unreachable := newTestStorage(t, nil)
+		unreachableCli := newTestAttachmentClient(t, unreachable)
+		unreachable.Close()
+
+		err := unreachableCli.fetchAttachments(ctx, attachmentHeaders, []*Attachment{{FileID: "invoice", Filename: "invoice.pdf"}})
+		require.ErrorIs(t, err, ErrAttachmentUnavailable)
+		require.NotErrorIs(t, err, ErrAttachmentFetch)
+	})
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"crypto/subtle"
+)
+
+// Callback categories the relay posts to the webhook, named after their RecordType.
+const (
+	DeliveryReport = "Delivery"
+	BounceReport   = "Bounce"
+	SpamComplaint  = "SpamComplaint"
+)
+
+// MetadataKeyNotificationID is the metadata key the relay echoes back from the
+// X-PM-Metadata-notification-id header set on every message sent.
+const MetadataKeyNotificationID = "notification-id"
+
+// Bounce severities, hard bounces and complaints stop further mail to the recipient.
+const (
+	BounceHard          = "hard"
+	BounceSoft          = "soft"
+	BounceInformational = "informational"
+)
+
+// bounceSeverities classifies the bounce types of the relay, types not listed are soft.
+var bounceSeverities = map[string]string{
+	"HardBounce":            BounceHard,
+	"BadEmailAddress":       BounceHard,
+	"ManuallyDeactivated":   BounceHard,
+	"SpamNotification":      BounceHard,
+	"Blocked":               BounceHard,
+	"AutoResponder":         BounceInformational,
+	"OpenRelayTest":         BounceInformational,
+	"Subscribe":             BounceInformational,
+	"ChallengeVerification": BounceInformational,
+}
+
+// Categorise works out which callback the relay posted.
+func (ms *Client) Categorise(_ context.Context, payload map[string]any) string {
+	recordType, _ := payload["RecordType"].(string)
+	switch recordType {
+	case DeliveryReport, BounceReport, SpamComplaint:
+		return recordType
+	}
+
+	// Older callbacks carry no record type, only the bounce type.
+	bounceType, _ := payload["Type"].(string)
+	switch {
+	case bounceType == SpamComplaint:
+		return SpamComplaint
+	case bounceType != "":
+		return BounceReport
+	case payload["DeliveredAt"] != nil:
+		return DeliveryReport
+	}
+
+	return ""
+}
+
+// BounceSeverity says how a bounce of the given type is handled.
+func BounceSeverity(bounceType string, inactive bool) string {
+	if severity, ok := bounceSeverities[bounceType]; ok {
+		return severity
+	}
+	// The relay stops sending to addresses it deactivated, whatever the bounce type was.
+	if inactive {
+		return BounceHard
+	}
+	return BounceSoft
+}
+
+// AuthoriseWebhook checks the basic auth credentials a callback was posted with. Every
+// callback is refused while no webhook username is configured.
+func (ms *Client) AuthoriseWebhook(username, password string, ok bool) bool {
+	if ms.cfg.EmailWebhookUsername == "" || !ok {
+		return false
+	}
+
+	usernameMatch := subtle.ConstantTimeCompare([]byte(username), []byte(ms.cfg.EmailWebhookUsername))
+	passwordMatch := subtle.ConstantTimeCompare([]byte(password), []byte(ms.cfg.EmailWebhookPassword))
+	return usernameMatch&passwordMatch == 1
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antinvestor/service-notification/apps/integrations/emailsmtp/config"
+	"github.com/pitabwire/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategorise(t *testing.T) {
+	cli, err := NewClient(util.Log(context.Background()), &config.EmailSMTPConfig{}, nil, nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		payload map[string]any
+		want    string
+	}{
+		{name: "delivery", payload: map[string]any{"RecordType": "Delivery"}, want: DeliveryReport},
+		{name: "bounce", payload: map[string]any{"RecordType": "Bounce", "Type": "HardBounce"}, want: BounceReport},
+		{name: "complaint", payload: map[string]any{"RecordType": "SpamComplaint"}, want: SpamComplaint},
+		{name: "untyped complaint", payload: map[string]any{"Type": "SpamComplaint"}, want: SpamComplaint},
+		{name: "untyped bounce", payload: map[string]any{"Type": "SoftBounce"}, want: BounceReport},
+		{name: "untyped delivery", payload: map[string]any{"DeliveredAt": "2026-10-17T10:00:00Z"}, want: DeliveryReport},
+		{name: "unknown record type", payload: map[string]any{"RecordType": "Open"}, want: ""},
+		{name: "empty", payload: map[string]any{}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, cli.Categorise(context.Background(), tt.payload))
+		})
+	}
+}
+
+func TestBounceSeverity(t *testing.T) {
+	tests := []struct {
+		bounceType string
+		inactive   bool
+		want       string
+	}{
+		{bounceType: "HardBounce", want: BounceHard},
+		{bounceType: "BadEmailAddress", want: BounceHard},
+		{bounceType: "Blocked", want: BounceHard},
+		{bounceType: "SoftBounce", want: BounceSoft},
+		{bounceType: "Transient", want: BounceSoft},
+		{bounceType: "SoftBounce", inactive: true, want: BounceHard},
+		{bounceType: "AutoResponder", want: BounceInformational},
+		{bounceType: "AutoResponder", inactive: true, want: BounceInformational},
+		{bounceType: "", want: BounceSoft},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, BounceSeverity(tt.bounceType, tt.inactive), "%s inactive=%v", tt.bounceType, tt.inactive)
+	}
+}
+
+func TestAuthoriseWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	unconfigured, err := NewClient(util.Log(ctx), &config.EmailSMTPConfig{}, nil, nil, nil)
+	require.NoError(t, err)
+	require.False(t, unconfigured.AuthoriseWebhook("", "", false), "callbacks are refused until credentials are configured")
+	require.False(t, unconfigured.AuthoriseWebhook("", "", true))
+
+	cli, err := NewClient(util.Log(ctx), &config.EmailSMTPConfig{
+		EmailWebhookUsername: "relay",
+		EmailWebhookPassword: "webhook-secret",
+	}, nil, nil, nil)
+	require.NoError(t, err)
+	require.True(t, cli.AuthoriseWebhook("relay", "webhook-secret", true))
+	require.False(t, cli.AuthoriseWebhook("relay", "wrong", true))
+	require.False(t, cli.AuthoriseWebhook("other", "webhook-secret", true))
+	require.False(t, cli.AuthoriseWebhook("", "", false))
+}
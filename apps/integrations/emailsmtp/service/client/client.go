@@ -2,7 +2,10 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -22,8 +25,12 @@ const (
 	connectionTTL = 5 * time.Minute
 )
 
+// ErrSMTPDial is returned when no connection to the SMTP server could be established.
+var ErrSMTPDial = errors.New("failed to establish SMTP connection")
+
 type connectedClient struct {
 	client      *mail.Client
+	fingerprint string
 	connectedAt time.Time
 	mu          sync.Mutex
 }
@@ -37,78 +44,88 @@ type Client struct {
 	logger      *util.LogEntry
 	profileCli  profilev1connect.ProfileServiceClient
 	settingsCli settingsv1connect.SettingsServiceClient
-	connMap     sync.Map
-	connMu      sync.Mutex
+	httpClient  *http.Client
+	credentials sync.Map
+
+	verifiedDomains map[string]map[string]bool
+	connMap         sync.Map
+	connMu          sync.Mutex
 }
 
-func NewClient(logger *util.LogEntry, cfg *config.EmailSMTPConfig, profileCli profilev1connect.ProfileServiceClient, settingsCli settingsv1connect.SettingsServiceClient) (*Client, error) {
+// NewClient creates the SMTP client. Attachments are fetched from the storage service
+// with storageCli, which authenticates as this service.
+func NewClient(logger *util.LogEntry, cfg *config.EmailSMTPConfig, profileCli profilev1connect.ProfileServiceClient, settingsCli settingsv1connect.SettingsServiceClient, storageCli *http.Client) (*Client, error) {
 
 	return &Client{
 		cfg:         cfg,
 		logger:      logger,
 		profileCli:  profileCli,
 		settingsCli: settingsCli,
+		httpClient:  storageCli,
 		connMap:     sync.Map{},
+
+		verifiedDomains: parseVerifiedSenderDomains(cfg.SMTPVerifiedSenderDomains),
 	}, nil
 }
 
-func (ms *Client) createMailClient() (*mail.Client, error) {
-	cfg := ms.cfg
-	return mail.NewClient(cfg.SMTPServerHOST,
-		mail.WithPort(cfg.SMTPServerPORT),
-		mail.WithTLSPolicy(mail.TLSMandatory),
-		mail.WithSMTPAuth(mail.SMTPAuthPlain),
-		mail.WithUsername(cfg.SMTPServerAccessKey),
-		mail.WithPassword(cfg.SMTPServerSecretKey),
-		mail.WithTimeout(15*time.Second),
-	)
+func (ms *Client) createMailClient(credentials *Credentials) (*mail.Client, error) {
+	opts, err := credentials.mailOptions()
+	if err != nil {
+		return nil, err
+	}
+	return mail.NewClient(credentials.Host, opts...)
+}
+
+// usable reports whether a pooled connection can still carry mail for the credentials.
+func (cc *connectedClient) usable(credentials *Credentials) bool {
+	return !cc.isExpired() && cc.fingerprint == credentials.fingerprint()
 }
 
-func (ms *Client) getConnectedClient(ctx context.Context, credentials map[string]string) (*connectedClient, error) {
-	partitionID := credentials[constants.PartitionIDHeaderName]
+func (ms *Client) getConnectedClient(ctx context.Context, key string, credentials *Credentials) (*connectedClient, error) {
 
-	if connObj, ok := ms.connMap.Load(partitionID); ok {
-		if conn, cok := connObj.(*connectedClient); cok && !conn.isExpired() {
+	if connObj, ok := ms.connMap.Load(key); ok {
+		if conn, cok := connObj.(*connectedClient); cok && conn.usable(credentials) {
 			return conn, nil
 		}
 		if conn, cok := connObj.(*connectedClient); cok {
 			_ = conn.client.Close()
 		}
-		ms.connMap.Delete(partitionID)
+		ms.connMap.Delete(key)
 	}
 
 	ms.connMu.Lock()
 	defer ms.connMu.Unlock()
 
-	if connObj, ok := ms.connMap.Load(partitionID); ok {
-		if conn, cok := connObj.(*connectedClient); cok && !conn.isExpired() {
+	if connObj, ok := ms.connMap.Load(key); ok {
+		if conn, cok := connObj.(*connectedClient); cok && conn.usable(credentials) {
 			return conn, nil
 		}
 		if conn, cok := connObj.(*connectedClient); cok {
 			_ = conn.client.Close()
 		}
-		ms.connMap.Delete(partitionID)
+		ms.connMap.Delete(key)
 	}
 
-	cli, err := ms.createMailClient()
+	cli, err := ms.createMailClient(credentials)
 	if err != nil {
 		return nil, err
 	}
 
 	if err := cli.DialWithContext(ctx); err != nil {
-		return nil, fmt.Errorf("failed to establish SMTP connection: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrSMTPDial, err)
 	}
 
 	conn := &connectedClient{
 		client:      cli,
+		fingerprint: credentials.fingerprint(),
 		connectedAt: time.Now(),
 	}
-	ms.connMap.Store(partitionID, conn)
+	ms.connMap.Store(key, conn)
 
 	return conn, nil
 }
 
-func (ms *Client) Send(ctx context.Context, credentials map[string]string, notification *notificationv1.Notification) error {
+func (ms *Client) Send(ctx context.Context, headers map[string]string, notification *notificationv1.Notification) error {
 
 	recipient, err := utility.PopulateContactLink(ctx, ms.profileCli, notification.GetRecipient(), profilev1.ContactType_EMAIL)
 	if err != nil {
@@ -120,6 +137,11 @@ func (ms *Client) Send(ctx context.Context, credentials map[string]string, notif
 		return err
 	}
 
+	credentials, err := ms.extractCredentials(ctx, headers)
+	if err != nil {
+		return err
+	}
+
 	extrasData := notification.GetExtras().AsMap()
 
 	if sender.GetDetail() == "" {
@@ -132,7 +154,7 @@ func (ms *Client) Send(ctx context.Context, credentials map[string]string, notif
 		}
 	}
 
-	if sender.GetDetail() == "" {
+	if sender.GetDetail() == "" && credentials.From == "" {
 		return fmt.Errorf("email sender is empty: possibly configure support contacts in partition")
 	}
 
@@ -165,25 +187,48 @@ func (ms *Client) Send(ctx context.Context, credentials map[string]string, notif
 		return fmt.Errorf("email body is empty: provide either text or html content")
 	}
 
-	conn, err := ms.getConnectedClient(ctx, credentials)
+	attachments, err := attachmentsFromPayload(notification.GetPayload().AsMap())
+	if err != nil {
+		return err
+	}
+
+	err = ms.fetchAttachments(ctx, headers, attachments)
+	if err != nil {
+		return err
+	}
+
+	key := connectionKey(headers)
+	conn, err := ms.getConnectedClient(ctx, key, credentials)
 	if err != nil {
+		ms.InvalidateCredentials(headers[constants.PartitionIDHeaderName])
 		return err
 	}
 
-	err = ms.sendEmailWithRetry(ctx, credentials, conn, notification.GetId(), sender, recipient, notificationSubject, textBody, htmlBody)
+	err = ms.sendEmailWithRetry(ctx, headers, key, conn, credentials, notification.GetId(), sender, recipient, notificationSubject, textBody, htmlBody, attachments)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (ms *Client) sendEmailWithRetry(ctx context.Context, credentials map[string]string, conn *connectedClient, messageID string, sender, recipient *commonv1.ContactLink, subject, textBody, htmlBody string) error {
+func (ms *Client) sendEmailWithRetry(ctx context.Context, headers map[string]string, key string, conn *connectedClient, credentials *Credentials, messageID string, sender, recipient *commonv1.ContactLink, subject, textBody, htmlBody string, attachments []*Attachment) error {
 	conn.mu.Lock()
 	defer conn.mu.Unlock()
 
 	msg := mail.NewMsg()
 
-	if sender != nil && sender.GetDetail() != "" {
+	switch {
+	case credentials.From != "":
+		// Relays only accept the verified sender of the account, replies still reach the sender.
+		if err := msg.FromFormat(credentials.FromName, credentials.From); err != nil {
+			return err
+		}
+		if sender != nil && sender.GetDetail() != "" && !strings.EqualFold(sender.GetDetail(), credentials.From) {
+			if err := msg.ReplyTo(sender.GetDetail()); err != nil {
+				return err
+			}
+		}
+	case sender != nil && sender.GetDetail() != "":
 		if err := msg.From(sender.GetDetail()); err != nil {
 			return err
 		}
@@ -202,13 +247,23 @@ func (ms *Client) sendEmailWithRetry(ctx context.Context, credentials map[string
 		msg.AddAlternativeString(mail.TypeTextHTML, htmlBody)
 	}
 
+	if err := addAttachments(msg, attachments); err != nil {
+		return err
+	}
+
 	err := conn.client.Send(msg)
 	if err != nil {
-		partitionID := credentials[constants.PartitionIDHeaderName]
-		ms.connMap.Delete(partitionID)
+		ms.connMap.Delete(key)
 		_ = conn.client.Close()
 
-		newConn, dialErr := ms.getConnectedClient(ctx, credentials)
+		// The relay account may have changed, read it again before reconnecting.
+		ms.InvalidateCredentials(headers[constants.PartitionIDHeaderName])
+		freshCredentials, credErr := ms.extractCredentials(ctx, headers)
+		if credErr != nil {
+			return fmt.Errorf("send failed and credentials could not be reloaded: %w (original: %v)", credErr, err)
+		}
+
+		newConn, dialErr := ms.getConnectedClient(ctx, key, freshCredentials)
 		if dialErr != nil {
 			return fmt.Errorf("send failed and reconnect failed: %w (original: %v)", dialErr, err)
 		}
@@ -0,0 +1,257 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	netmail "net/mail"
+	"strings"
+	"time"
+
+	settingsv1 "buf.build/gen/go/antinvestor/settingz/protocolbuffers/go/settings/v1"
+	"connectrpc.com/connect"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/wneessen/go-mail"
+)
+
+// TLS policies a relay can be reached with.
+const (
+	TLSPolicyMandatory     = "mandatory"
+	TLSPolicyOpportunistic = "opportunistic"
+	TLSPolicyNone          = "none"
+	TLSPolicySSL           = "ssl"
+)
+
+// Credentials describe the SMTP relay a partition sends through and the identity it sends as.
+type Credentials struct {
+	Host      string `json:"host"`
+	Port      int    `json:"port"`
+	AuthMode  string `json:"auth_mode"`
+	TLSPolicy string `json:"tls_policy"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	From      string `json:"from"`
+	FromName  string `json:"from_name"`
+}
+
+// fingerprint identifies the relay and account, connections dialed with other credentials are not reused.
+func (c *Credentials) fingerprint() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		c.Host, fmt.Sprintf("%d", c.Port), c.AuthMode, c.TLSPolicy, c.Username, c.Password}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Credentials) mailOptions() ([]mail.Option, error) {
+	opts := []mail.Option{
+		mail.WithPort(c.Port),
+		mail.WithTimeout(15 * time.Second),
+	}
+
+	switch strings.ToLower(c.TLSPolicy) {
+	case "", TLSPolicyMandatory:
+		opts = append(opts, mail.WithTLSPolicy(mail.TLSMandatory))
+	case TLSPolicyOpportunistic:
+		opts = append(opts, mail.WithTLSPolicy(mail.TLSOpportunistic))
+	case TLSPolicyNone:
+		opts = append(opts, mail.WithTLSPolicy(mail.NoTLS))
+	case TLSPolicySSL:
+		opts = append(opts, mail.WithSSL())
+	default:
+		return nil, fmt.Errorf("unsupported SMTP tls policy: %s", c.TLSPolicy)
+	}
+
+	authMode := c.AuthMode
+	if authMode == "" {
+		authMode = "plain"
+	}
+
+	var authType mail.SMTPAuthType
+	if err := authType.UnmarshalString(authMode); err != nil {
+		return nil, err
+	}
+
+	if authType != mail.SMTPAuthNoAuth {
+		opts = append(opts,
+			mail.WithSMTPAuth(authType),
+			mail.WithUsername(c.Username),
+			mail.WithPassword(c.Password))
+	}
+
+	return opts, nil
+}
+
+type cachedCredentials struct {
+	credentials *Credentials
+	fetchedAt   time.Time
+}
+
+// connectionKey names the relay connection and credentials used for a message: the
+// connection named on the route when there is one, the partition's own otherwise.
+func connectionKey(headers map[string]string) string {
+	return headers[constants.PartitionIDHeaderName] + "|" + headers[constants.APIConnectionCredentialsHeaderName]
+}
+
+func (ms *Client) defaultCredentials() *Credentials {
+	return &Credentials{
+		Host:      ms.cfg.SMTPServerHOST,
+		Port:      ms.cfg.SMTPServerPORT,
+		AuthMode:  ms.cfg.SMTPServerAuthMode,
+		TLSPolicy: ms.cfg.SMTPServerTLSPolicy,
+		Username:  ms.cfg.SMTPServerAccessKey,
+		Password:  ms.cfg.SMTPServerSecretKey,
+		From:      ms.cfg.SMTPSenderAddress,
+		FromName:  ms.cfg.SMTPSenderName,
+	}
+}
+
+// extractCredentials resolves the relay for a message, cached for a while so the settings
+// service is not asked on every send.
+func (ms *Client) extractCredentials(ctx context.Context, headers map[string]string) (*Credentials, error) {
+	key := connectionKey(headers)
+
+	if cached, ok := ms.credentials.Load(key); ok {
+		entry := cached.(*cachedCredentials)
+		if time.Since(entry.fetchedAt) < ms.cfg.SMTPCredentialsCacheTTL {
+			return entry.credentials, nil
+		}
+	}
+
+	credentials, err := ms.fetchCredentials(ctx, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	ms.credentials.Store(key, &cachedCredentials{credentials: credentials, fetchedAt: time.Now()})
+	return credentials, nil
+}
+
+// InvalidateCredentials drops the cached credentials of a partition and closes the relay
+// connections dialed with them, the next message reads them from the settings service again.
+func (ms *Client) InvalidateCredentials(partitionID string) {
+	ms.credentials.Range(func(key, _ any) bool {
+		if strings.HasPrefix(key.(string), partitionID+"|") {
+			ms.credentials.Delete(key)
+		}
+		return true
+	})
+
+	ms.connMap.Range(func(key, connObj any) bool {
+		if !strings.HasPrefix(key.(string), partitionID+"|") {
+			return true
+		}
+		ms.connMap.Delete(key)
+		if conn, ok := connObj.(*connectedClient); ok {
+			conn.mu.Lock()
+			_ = conn.client.Close()
+			conn.mu.Unlock()
+		}
+		return true
+	})
+}
+
+// parseVerifiedSenderDomains reads the partition_id:domain entries of the sender domains
+// partitions may send from through the default relay.
+func parseVerifiedSenderDomains(entries []string) map[string]map[string]bool {
+	domains := map[string]map[string]bool{}
+	for _, entry := range entries {
+		partitionID, domain, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || partitionID == "" || domain == "" {
+			continue
+		}
+		if domains[partitionID] == nil {
+			domains[partitionID] = map[string]bool{}
+		}
+		domains[partitionID][strings.ToLower(domain)] = true
+	}
+	return domains
+}
+
+// verifiedSender reports whether a partition may send from the address through the default
+// relay, the relay account vouches for every message sent through it.
+func (ms *Client) verifiedSender(partitionID, from string) bool {
+	address, err := netmail.ParseAddress(from)
+	if err != nil {
+		return false
+	}
+	_, domain, _ := strings.Cut(strings.ToLower(address.Address), "@")
+	return ms.verifiedDomains[partitionID][domain]
+}
+
+// fetchCredentials reads the relay from the settings service. A connection named on the
+// route is looked up like the other integrations do, otherwise the partition's own SMTP
+// setting is used when it has one and the service config when it does not.
+func (ms *Client) fetchCredentials(ctx context.Context, headers map[string]string) (*Credentials, error) {
+	partitionID := headers[constants.PartitionIDHeaderName]
+	connection, named := headers[constants.APIConnectionCredentialsHeaderName]
+
+	settingKey := &settingsv1.Setting{
+		Name:     connection,
+		Object:   ms.cfg.SettingsIntegrationName,
+		ObjectId: ms.cfg.SettingsIntegrationID,
+		Lang:     "",
+		Module:   ms.cfg.SettingsIntegrationName,
+	}
+
+	if !named {
+		if partitionID == "" {
+			return ms.defaultCredentials(), nil
+		}
+		settingKey.Name = ms.cfg.SettingsSMTPConnectionName
+		settingKey.ObjectId = partitionID
+	}
+
+	settingResp, err := ms.settingsCli.Get(ctx, connect.NewRequest(&settingsv1.GetRequest{Key: settingKey}))
+	if err != nil {
+		if !named && connect.CodeOf(err) == connect.CodeNotFound {
+			return ms.defaultCredentials(), nil
+		}
+		return nil, err
+	}
+
+	value := settingResp.Msg.GetData().GetValue()
+	if value == "" {
+		if !named {
+			return ms.defaultCredentials(), nil
+		}
+		return nil, fmt.Errorf("SMTP connection %s has no settings", connection)
+	}
+
+	credentials := &Credentials{}
+	err = json.Unmarshal([]byte(value), credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	// A setting naming only a sender identity keeps sending through the default relay,
+	// the default account is never handed to a relay of the tenant's choosing. The
+	// identity is only taken up when its domain was verified for the partition.
+	if credentials.Host == "" {
+		defaults := ms.defaultCredentials()
+		credentials.Host = defaults.Host
+		credentials.Port = defaults.Port
+		credentials.AuthMode = defaults.AuthMode
+		credentials.TLSPolicy = defaults.TLSPolicy
+		credentials.Username = defaults.Username
+		credentials.Password = defaults.Password
+		if credentials.From != "" && !ms.verifiedSender(partitionID, credentials.From) {
+			ms.logger.WithField("partition_id", partitionID).WithField("from", credentials.From).
+				Warn("sender address is not in a verified domain of the partition, using the default sender")
+			credentials.From = ""
+		}
+		if credentials.From == "" {
+			credentials.From = defaults.From
+			credentials.FromName = defaults.FromName
+		}
+	}
+
+	if credentials.Port == 0 {
+		credentials.Port = 587
+		if strings.ToLower(credentials.TLSPolicy) == TLSPolicySSL {
+			credentials.Port = 465
+		}
+	}
+
+	return credentials, nil
+}
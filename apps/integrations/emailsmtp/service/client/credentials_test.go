@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"buf.build/gen/go/antinvestor/settingz/connectrpc/go/settings/v1/settingsv1connect"
+	settingsv1 "buf.build/gen/go/antinvestor/settingz/protocolbuffers/go/settings/v1"
+	"connectrpc.com/connect"
+	"github.com/antinvestor/service-notification/apps/integrations/emailsmtp/config"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/pitabwire/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// testSettings answers SMTP connection lookups from a map of setting values keyed by
+// object id, counting the lookups made.
+type testSettings struct {
+	settingsv1connect.SettingsServiceClient
+	values map[string]string
+	calls  atomic.Int32
+}
+
+func (s *testSettings) Get(_ context.Context, req *connect.Request[settingsv1.GetRequest]) (*connect.Response[settingsv1.GetResponse], error) {
+	s.calls.Add(1)
+
+	value, ok := s.values[req.Msg.GetKey().GetObjectId()]
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("setting not found"))
+	}
+
+	encoded, _ := json.Marshal(map[string]any{"data": map[string]any{"value": value}})
+	resp := &settingsv1.GetResponse{}
+	if err := protojson.Unmarshal(encoded, resp); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func credentialsSetting(t *testing.T, credentials Credentials) string {
+	value, err := json.Marshal(credentials)
+	require.NoError(t, err)
+	return string(value)
+}
+
+func newTestCredentialsClient(t *testing.T, settings *testSettings) *Client {
+	cli, err := NewClient(util.Log(context.Background()), &config.EmailSMTPConfig{
+		SettingsIntegrationName:    "Email SMTP",
+		SettingsIntegrationID:      "notification.emailsmtp",
+		SettingsSMTPConnectionName: "smtp_connection",
+		SMTPCredentialsCacheTTL:    time.Minute,
+		SMTPServerHOST:             "smtp.default.test",
+		SMTPServerPORT:             587,
+		SMTPServerAccessKey:        "default-key",
+		SMTPServerSecretKey:        "default-secret",
+		SMTPSenderAddress:          "no-reply@default.test",
+		SMTPSenderName:             "Default",
+		SMTPVerifiedSenderDomains:  []string{"partition-verified:verified.test"},
+	}, nil, settings, nil)
+	require.NoError(t, err)
+	return cli
+}
+
+func partitionHeaders(partitionID string) map[string]string {
+	return map[string]string{constants.PartitionIDHeaderName: partitionID}
+}
+
+func TestExtractCredentials(t *testing.T) {
+	settings := &testSettings{values: map[string]string{
+		"partition-relay": credentialsSetting(t, Credentials{
+			Host: "smtp.tenant.test", Username: "tenant", Password: "tenant-secret", From: "hello@tenant.test",
+		}),
+		"partition-verified": credentialsSetting(t, Credentials{From: "billing@verified.test", FromName: "Billing"}),
+		"partition-spoofing": credentialsSetting(t, Credentials{From: "ceo@bank.test", FromName: "Bank"}),
+	}}
+	cli := newTestCredentialsClient(t, settings)
+	ctx := context.Background()
+
+	t.Run("own relay", func(t *testing.T) {
+		credentials, err := cli.extractCredentials(ctx, partitionHeaders("partition-relay"))
+		require.NoError(t, err)
+		require.Equal(t, "smtp.tenant.test", credentials.Host)
+		require.Equal(t, 587, credentials.Port)
+		require.Equal(t, "tenant", credentials.Username)
+		require.Equal(t, "hello@tenant.test", credentials.From)
+	})
+
+	t.Run("no setting falls back to the default relay", func(t *testing.T) {
+		credentials, err := cli.extractCredentials(ctx, partitionHeaders("partition-unknown"))
+		require.NoError(t, err)
+		require.Equal(t, cli.defaultCredentials(), credentials)
+	})
+
+	t.Run("verified sender on the default relay", func(t *testing.T) {
+		credentials, err := cli.extractCredentials(ctx, partitionHeaders("partition-verified"))
+		require.NoError(t, err)
+		require.Equal(t, "smtp.default.test", credentials.Host)
+		require.Equal(t, "default-key", credentials.Username)
+		require.Equal(t, "billing@verified.test", credentials.From)
+		require.Equal(t, "Billing", credentials.FromName)
+	})
+
+	t.Run("unverified sender on the default relay", func(t *testing.T) {
+		credentials, err := cli.extractCredentials(ctx, partitionHeaders("partition-spoofing"))
+		require.NoError(t, err)
+		require.Equal(t, "smtp.default.test", credentials.Host)
+		require.Equal(t, "no-reply@default.test", credentials.From)
+		require.Equal(t, "Default", credentials.FromName)
+	})
+
+	t.Run("domains are verified per partition", func(t *testing.T) {
+		require.True(t, cli.verifiedSender("partition-verified", "Billing <billing@Verified.test>"))
+		require.False(t, cli.verifiedSender("partition-spoofing", "billing@verified.test"))
+		require.False(t, cli.verifiedSender("partition-verified", "not an address"))
+	})
+}
+
+func TestInvalidateCredentials(t *testing.T) {
+	settings := &testSettings{values: map[string]string{
+		"partition-1": credentialsSetting(t, Credentials{Host: "smtp.one.test", Username: "one"}),
+		"partition-2": credentialsSetting(t, Credentials{Host: "smtp.two.test", Username: "two"}),
+	}}
+	cli := newTestCredentialsClient(t, settings)
+	ctx := context.Background()
+
+	_, err := cli.extractCredentials(ctx, partitionHeaders("partition-1"))
+	require.NoError(t, err)
+	_, err = cli.extractCredentials(ctx, partitionHeaders("partition-2"))
+	require.NoError(t, err)
+
+	_, err = cli.extractCredentials(ctx, partitionHeaders("partition-1"))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), settings.calls.Load(), "credentials are cached")
+
+	settings.values["partition-1"] = credentialsSetting(t, Credentials{Host: "smtp.one.test", Username: "rotated"})
+	cli.InvalidateCredentials("partition-1")
+
+	credentials, err := cli.extractCredentials(ctx, partitionHeaders("partition-1"))
+	require.NoError(t, err)
+	require.Equal(t, "rotated", credentials.Username)
+
+	_, err = cli.extractCredentials(ctx, partitionHeaders("partition-2"))
+	require.NoError(t, err)
+	require.Equal(t, int32(3), settings.calls.Load(), "only the invalidated partition is read again")
+}
@@ -8,14 +8,23 @@ import (
 
 	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
 	"buf.build/gen/go/antinvestor/notification/connectrpc/go/notification/v1/notificationv1connect"
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
 	"buf.build/gen/go/antinvestor/profile/connectrpc/go/profile/v1/profilev1connect"
 	"connectrpc.com/connect"
 	"github.com/antinvestor/service-notification/apps/integrations/emailsmtp/service/client"
 	"github.com/antinvestor/service-notification/pkg/apperrors"
+	"github.com/antinvestor/service-notification/pkg/constants"
 	"github.com/pitabwire/util"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
+const (
+	suppressionChannelEmail     = "email"
+	suppressionSource           = "emailsmtp"
+	suppressionReasonHardBounce = "hard_bounce"
+	suppressionReasonComplaint  = "complaint"
+)
+
 type SMTPServer struct {
 	ProfileCli      profilev1connect.ProfileServiceClient
 	NotificationCli notificationv1connect.NotificationServiceClient
@@ -53,12 +62,39 @@ func (ps *SMTPServer) NewRouterV1() *http.ServeMux {
 	userServeMux := http.NewServeMux()
 
 	userServeMux.HandleFunc("/receive/notification/{routeID}", ps.ReceiveNotification)
+	userServeMux.HandleFunc("POST /credentials/invalidate/{partitionID}", ps.InvalidateCredentials)
 	return userServeMux
 }
 
+// InvalidateCredentials drops what is cached of a partition's SMTP settings, for the
+// settings service or an operator to call once they changed.
+func (ps *SMTPServer) InvalidateCredentials(rw http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	if !ps.EmailSMTPCli.AuthoriseWebhook(req.BasicAuth()) {
+		rw.Header().Set("WWW-Authenticate", `Basic realm="email callbacks"`)
+		appErr := apperrors.ErrInvalidCredentials.Extend("invalidation was not posted with the webhook credentials")
+		ps.writeError(ctx, rw, appErr, appErr.ErrorCode())
+		return
+	}
+
+	partitionID := req.PathValue("partitionID")
+	ps.EmailSMTPCli.InvalidateCredentials(partitionID)
+
+	util.Log(ctx).WithField("partition_id", partitionID).Info("SMTP credentials invalidated")
+	rw.WriteHeader(http.StatusNoContent)
+}
+
 func (ps *SMTPServer) ReceiveNotification(rw http.ResponseWriter, req *http.Request) {
 	ctx := req.Context()
 
+	if !ps.EmailSMTPCli.AuthoriseWebhook(req.BasicAuth()) {
+		rw.Header().Set("WWW-Authenticate", `Basic realm="email callbacks"`)
+		appErr := apperrors.ErrInvalidCredentials.Extend("callback was not posted with the webhook credentials")
+		ps.writeError(ctx, rw, appErr, appErr.ErrorCode())
+		return
+	}
+
 	routeID := req.PathValue("routeID")
 
 	if routeID == "" {
@@ -77,12 +113,15 @@ func (ps *SMTPServer) ReceiveNotification(rw http.ResponseWriter, req *http.Requ
 	}
 
 	var appErr *apperrors.Error
-
-	metadata, ok := payload["Metadata"].(map[string]any)
-	if !ok {
+	switch ps.EmailSMTPCli.Categorise(ctx, payload) {
+	case client.DeliveryReport:
+		appErr = ps.handleDeliveryReport(ctx, routeID, payload)
+	case client.BounceReport:
+		appErr = ps.handleBounce(ctx, routeID, payload)
+	case client.SpamComplaint:
+		appErr = ps.handleSpamComplaint(ctx, routeID, payload)
+	default:
 		appErr = ps.handleIncomingMessages(ctx, routeID, payload)
-	} else {
-		appErr = ps.handleDeliveryReport(ctx, routeID, metadata, payload)
 	}
 
 	if appErr != nil {
@@ -95,37 +134,44 @@ func (ps *SMTPServer) ReceiveNotification(rw http.ResponseWriter, req *http.Requ
 	_ = json.NewEncoder(rw).Encode("successfully handled")
 }
 
-// handleDeliveryReport Sent whenever the mobile service provider confirms or rejects delivery of a message.
-//	To receive delivery reports, you need to set a delivery report callback URL. From the dashboard select SMS -> SMS Callback URLs -> Delivery Reports.
-//
-
-func (ps *SMTPServer) handleDeliveryReport(ctx context.Context, routeID string, metadata, payload map[string]any) *apperrors.Error {
+// notificationID reads the notification a callback reports on from the metadata the
+// relay echoes back, every message goes out with its notification id in the headers.
+func notificationID(payload map[string]any) (string, *apperrors.Error) {
+	metadata, _ := payload["Metadata"].(map[string]any)
+	id, _ := metadata[client.MetadataKeyNotificationID].(string)
+	if id == "" {
+		return "", apperrors.ErrDataNotFound.Extend("no notification id was found in metadata")
+	}
+	return id, nil
+}
 
-	notificationID, ok := metadata["notification-id"]
-	if !ok {
-		return apperrors.ErrDataNotFound.Extend("no notification id was found in metadata")
+// callbackExtras keeps the details of a callback on the status it results in.
+func callbackExtras(routeID string, payload map[string]any, keys ...string) map[string]any {
+	extraData := map[string]any{
+		constants.StatusExtraRouteID: routeID,
+	}
+	for _, key := range keys {
+		if v, ok := payload[key]; ok && v != nil {
+			extraData[key] = fmt.Sprintf("%v", v)
+		}
 	}
+	return extraData
+}
 
-	externalID, _ := payload["MessageID"].(string)
+func (ps *SMTPServer) updateStatus(ctx context.Context, payload map[string]any, status commonv1.STATUS, extraData map[string]any) *apperrors.Error {
 
-	internalStatus := commonv1.STATUS_SUCCESSFUL
-	reportType, _ := payload["Type"].(string)
-	switch reportType {
-	case "HardBounce", "SpamComplaint":
-		internalStatus = commonv1.STATUS_FAILED
+	id, appErr := notificationID(payload)
+	if appErr != nil {
+		return appErr
 	}
 
-	extraData := map[string]any{}
-	for k, v := range payload {
-		extraData[k] = fmt.Sprintf("%v", v)
-	}
-	extraData["route"] = routeID
+	externalID, _ := payload["MessageID"].(string)
 	extra, _ := structpb.NewStruct(extraData)
 
 	_, err := ps.NotificationCli.StatusUpdate(ctx, connect.NewRequest(&commonv1.StatusUpdateRequest{
-		Id:         fmt.Sprintf("%v", notificationID),
+		Id:         id,
 		State:      commonv1.STATE_INACTIVE,
-		Status:     internalStatus,
+		Status:     status,
 		ExternalId: externalID,
 		Extras:     extra,
 	}))
@@ -135,6 +181,130 @@ func (ps *SMTPServer) handleDeliveryReport(ctx context.Context, routeID string,
 	return nil
 }
 
+// suppress stops further mail to a recipient the relay can no longer deliver to, or
+// who reported our mail as spam. The address is suppressed for every sender on the email
+// channel, the route the callback arrived on is only recorded.
+func (ps *SMTPServer) suppress(ctx context.Context, routeID, reason string, payload map[string]any) *apperrors.Error {
+
+	recipient, _ := payload["Email"].(string)
+	if recipient == "" {
+		return apperrors.ErrInvalidFormat.Extend("callback is missing the recipient email")
+	}
+
+	extra, _ := structpb.NewStruct(callbackExtras(routeID, payload, "Type", "TypeCode", "Description", "MessageID"))
+	_, err := ps.NotificationCli.SuppressionAdd(ctx, connect.NewRequest(&notificationv1.SuppressionAddRequest{
+		Data: &notificationv1.Suppression{
+			Contact: recipient,
+			Channel: suppressionChannelEmail,
+			RouteId: routeID,
+			Reason:  reason,
+			Source:  suppressionSource,
+			Extras:  extra,
+		},
+	}))
+	if err != nil {
+		return apperrors.ErrSystemFailure.Extend(err.Error())
+	}
+	return nil
+}
+
+// handleDeliveryReport Sent once the receiving mail server accepted a message.
+//
+// # Delivery callback contents
+//
+// MessageID String
+// The id the relay gave the message.
+//
+// Recipient String
+// The address the message was delivered to.
+//
+// DeliveredAt String
+// When the receiving server accepted the message.
+//
+// Details String
+// The response of the receiving server.
+//
+// Metadata Object
+// The X-PM-Metadata-* headers of the message, notification-id among them.
+func (ps *SMTPServer) handleDeliveryReport(ctx context.Context, routeID string, payload map[string]any) *apperrors.Error {
+
+	extraData := callbackExtras(routeID, payload, "Recipient", "DeliveredAt", "Details")
+	return ps.updateStatus(ctx, payload, commonv1.STATUS_SUCCESSFUL, extraData)
+}
+
+// handleBounce Sent when a message bounced. Hard bounces fail the notification and
+// suppress the address, soft bounces only fail it and informational ones such as
+// auto replies mean the message did arrive.
+//
+// # Bounce callback contents
+//
+// Type String
+// The kind of bounce, HardBounce, SoftBounce, AutoResponder and so on.
+//
+// TypeCode Integer
+// The numeric code of the bounce type.
+//
+// Email String
+// The address that bounced.
+//
+// Inactive Boolean
+// Whether the relay deactivated the address and will not send to it again.
+//
+// Description, Details String
+// What the receiving server reported.
+//
+// BouncedAt String
+// When the bounce was received.
+//
+// Metadata Object
+// The X-PM-Metadata-* headers of the message, notification-id among them.
+func (ps *SMTPServer) handleBounce(ctx context.Context, routeID string, payload map[string]any) *apperrors.Error {
+
+	bounceType, _ := payload["Type"].(string)
+	inactive, _ := payload["Inactive"].(bool)
+	severity := client.BounceSeverity(bounceType, inactive)
+
+	extraData := callbackExtras(routeID, payload, "Type", "TypeCode", "Email", "Description", "Details", "BouncedAt")
+	extraData["bounce"] = severity
+	extraData[constants.StatusExtraStep] = constants.StatusStepDeliveryReport
+
+	status := commonv1.STATUS_FAILED
+	switch severity {
+	case client.BounceInformational:
+		status = commonv1.STATUS_SUCCESSFUL
+	case client.BounceHard:
+		extraData["error"] = fmt.Sprintf("email hard bounced: %v", payload["Description"])
+	default:
+		extraData["error"] = fmt.Sprintf("email soft bounced: %v", payload["Description"])
+	}
+
+	appErr := ps.updateStatus(ctx, payload, status, extraData)
+	if appErr != nil {
+		return appErr
+	}
+
+	if severity == client.BounceHard {
+		return ps.suppress(ctx, routeID, suppressionReasonHardBounce, payload)
+	}
+	return nil
+}
+
+// handleSpamComplaint Sent when a recipient marked a message as spam, it carries the
+// same fields as a bounce. The recipient is not mailed again.
+func (ps *SMTPServer) handleSpamComplaint(ctx context.Context, routeID string, payload map[string]any) *apperrors.Error {
+
+	extraData := callbackExtras(routeID, payload, "Type", "Email", "Description", "BouncedAt")
+	extraData["error"] = "recipient reported the email as spam"
+	extraData[constants.StatusExtraStep] = "complaint"
+
+	appErr := ps.updateStatus(ctx, payload, commonv1.STATUS_FAILED, extraData)
+	if appErr != nil {
+		return appErr
+	}
+
+	return ps.suppress(ctx, routeID, suppressionReasonComplaint, payload)
+}
+
 // handleIncomingMessages Sent whenever a message is sent to any of your registered shortcodes.
 // To receive incoming messages, you need to set an incoming messages callback URL. From the dashboard select SMS -> SMS Callback URLs -> Incoming Messages.
 //
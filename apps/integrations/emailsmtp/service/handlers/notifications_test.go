@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	"buf.build/gen/go/antinvestor/notification/connectrpc/go/notification/v1/notificationv1connect"
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+	"connectrpc.com/connect"
+	"github.com/antinvestor/service-notification/apps/integrations/emailsmtp/config"
+	"github.com/antinvestor/service-notification/apps/integrations/emailsmtp/service/client"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/pitabwire/util"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingNotifications records the status updates and suppressions callbacks result in.
+type recordingNotifications struct {
+	notificationv1connect.UnimplementedNotificationServiceHandler
+
+	mu           sync.Mutex
+	statuses     []*commonv1.StatusUpdateRequest
+	suppressions []*notificationv1.Suppression
+}
+
+func (rn *recordingNotifications) StatusUpdate(_ context.Context, req *connect.Request[commonv1.StatusUpdateRequest]) (*connect.Response[commonv1.StatusUpdateResponse], error) {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	rn.statuses = append(rn.statuses, req.Msg)
+	return connect.NewResponse(&commonv1.StatusUpdateResponse{}), nil
+}
+
+func (rn *recordingNotifications) SuppressionAdd(_ context.Context, req *connect.Request[notificationv1.SuppressionAddRequest]) (*connect.Response[notificationv1.SuppressionAddResponse], error) {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	rn.suppressions = append(rn.suppressions, req.Msg.GetData())
+	return connect.NewResponse(&notificationv1.SuppressionAddResponse{Data: req.Msg.GetData()}), nil
+}
+
+func (rn *recordingNotifications) recorded() ([]*commonv1.StatusUpdateRequest, []*notificationv1.Suppression) {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	return rn.statuses, rn.suppressions
+}
+
+func newTestSMTPServer(t *testing.T, cfg *config.EmailSMTPConfig) (*SMTPServer, *recordingNotifications) {
+	notifications := &recordingNotifications{}
+	_, handler := notificationv1connect.NewNotificationServiceHandler(notifications)
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	emailCli, err := client.NewClient(util.Log(context.Background()), cfg, nil, nil, nil)
+	require.NoError(t, err)
+
+	notificationCli := notificationv1connect.NewNotificationServiceClient(server.Client(), server.URL)
+	return NewSMTPServer(nil, notificationCli, emailCli), notifications
+}
+
+func webhookConfig() *config.EmailSMTPConfig {
+	return &config.EmailSMTPConfig{
+		EmailWebhookUsername: "relay",
+		EmailWebhookPassword: "webhook-secret",
+	}
+}
+
+func postCallback(srv *SMTPServer, username, password, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/receive/notification/route-1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+	rec := httptest.NewRecorder()
+	srv.NewRouterV1().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReceiveCallbacks(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		wantStatus    commonv1.STATUS
+		wantSuppress  string
+		wantExtraStep string
+	}{
+		{
+			name: "delivery",
+			body: `{"RecordType": "Delivery", "MessageID": "pm-1", "Recipient": "someone@example.com",
+				"DeliveredAt": "2026-10-17T10:00:00Z", "Metadata": {"notification-id": "notification-1"}}`,
+			wantStatus: commonv1.STATUS_SUCCESSFUL,
+		},
+		{
+			name: "hard bounce",
+			body: `{"RecordType": "Bounce", "Type": "HardBounce", "MessageID": "pm-1", "Email": "someone@example.com",
+				"Description": "mailbox does not exist", "Metadata": {"notification-id": "notification-1"}}`,
+			wantStatus:    commonv1.STATUS_FAILED,
+			wantSuppress:  suppressionReasonHardBounce,
+			wantExtraStep: constants.StatusStepDeliveryReport,
+		},
+		{
+			name: "soft bounce",
+			body: `{"RecordType": "Bounce", "Type": "SoftBounce", "MessageID": "pm-1", "Email": "someone@example.com",
+				"Description": "mailbox full", "Metadata": {"notification-id": "notification-1"}}`,
+			wantStatus:    commonv1.STATUS_FAILED,
+			wantExtraStep: constants.StatusStepDeliveryReport,
+		},
+		{
+			name: "auto responder",
+			body: `{"RecordType": "Bounce", "Type": "AutoResponder", "MessageID": "pm-1", "Email": "someone@example.com",
+				"Metadata": {"notification-id": "notification-1"}}`,
+			wantStatus:    commonv1.STATUS_SUCCESSFUL,
+			wantExtraStep: constants.StatusStepDeliveryReport,
+		},
+		{
+			name: "spam complaint",
+			body: `{"RecordType": "SpamComplaint", "Type": "SpamComplaint", "MessageID": "pm-1", "Email": "someone@example.com",
+				"Metadata": {"notification-id": "notification-1"}}`,
+			wantStatus:    commonv1.STATUS_FAILED,
+			wantSuppress:  suppressionReasonComplaint,
+			wantExtraStep: "complaint",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, notifications := newTestSMTPServer(t, webhookConfig())
+
+			rec := postCallback(srv, "relay", "webhook-secret", tt.body)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+			statuses, suppressions := notifications.recorded()
+			require.Len(t, statuses, 1)
+			require.Equal(t, "notification-1", statuses[0].GetId())
+			require.Equal(t, "pm-1", statuses[0].GetExternalId())
+			require.Equal(t, tt.wantStatus, statuses[0].GetStatus())
+			require.Equal(t, commonv1.STATE_INACTIVE, statuses[0].GetState())
+
+			extras := statuses[0].GetExtras().AsMap()
+			require.Equal(t, "route-1", extras[constants.StatusExtraRouteID])
+			if tt.wantExtraStep != "" {
+				require.Equal(t, tt.wantExtraStep, extras[constants.StatusExtraStep])
+			}
+
+			if tt.wantSuppress == "" {
+				require.Empty(t, suppressions)
+				return
+			}
+			require.Len(t, suppressions, 1)
+			require.Equal(t, "someone@example.com", suppressions[0].GetContact())
+			require.Equal(t, suppressionChannelEmail, suppressions[0].GetChannel())
+			require.Equal(t, tt.wantSuppress, suppressions[0].GetReason())
+			require.Equal(t, "route-1", suppressions[0].GetRouteId())
+		})
+	}
+}
+
+func TestReceiveCallbackFailures(t *testing.T) {
+	delivery := `{"RecordType": "Delivery", "MessageID": "pm-1", "Metadata": {"notification-id": "notification-1"}}`
+
+	t.Run("no webhook credentials configured", func(t *testing.T) {
+		srv, notifications := newTestSMTPServer(t, &config.EmailSMTPConfig{})
+
+		rec := postCallback(srv, "", "", delivery)
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+		statuses, _ := notifications.recorded()
+		require.Empty(t, statuses)
+	})
+
+	t.Run("wrong credentials", func(t *testing.T) {
+		srv, notifications := newTestSMTPServer(t, webhookConfig())
+
+		rec := postCallback(srv, "relay", "wrong", delivery)
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+		require.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+		statuses, _ := notifications.recorded()
+		require.Empty(t, statuses)
+	})
+
+	t.Run("no notification id", func(t *testing.T) {
+		srv, _ := newTestSMTPServer(t, webhookConfig())
+
+		rec := postCallback(srv, "relay", "webhook-secret", `{"RecordType": "Delivery", "MessageID": "pm-1"}`)
+		require.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("hard bounce without recipient", func(t *testing.T) {
+		srv, notifications := newTestSMTPServer(t, webhookConfig())
+
+		rec := postCallback(srv, "relay", "webhook-secret",
+			`{"RecordType": "Bounce", "Type": "HardBounce", "Metadata": {"notification-id": "notification-1"}}`)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		_, suppressions := notifications.recorded()
+		require.Empty(t, suppressions)
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		srv, _ := newTestSMTPServer(t, webhookConfig())
+
+		rec := postCallback(srv, "relay", "webhook-secret", `{"RecordType":`)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestInvalidateCredentials(t *testing.T) {
+	srv, _ := newTestSMTPServer(t, webhookConfig())
+
+	post := func(username, password string) int {
+		req := httptest.NewRequest(http.MethodPost, "/credentials/invalidate/partition-1", nil)
+		req.SetBasicAuth(username, password)
+		rec := httptest.NewRecorder()
+		srv.NewRouterV1().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusNoContent, post("relay", "webhook-secret"))
+	require.Equal(t, http.StatusUnauthorized, post("relay", "wrong"))
+
+	req := httptest.NewRequest(http.MethodGet, "/credentials/invalidate/partition-1", nil)
+	req.SetBasicAuth("relay", "webhook-secret")
+	rec := httptest.NewRecorder()
+	srv.NewRouterV1().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -11,6 +11,7 @@ import (
 	"buf.build/gen/go/antinvestor/profile/connectrpc/go/profile/v1/profilev1connect"
 	"github.com/antinvestor/service-notification/apps/integrations/emailsmtp/service/client"
 	"github.com/antinvestor/service-notification/pkg/apperrors"
+	"github.com/antinvestor/service-notification/pkg/constants"
 	"github.com/antinvestor/service-notification/pkg/events"
 	frameEvents "github.com/pitabwire/frame/v2/events"
 	"github.com/pitabwire/frame/v2/queue"
@@ -68,10 +69,68 @@ func (ms *messageToSend) Handle(ctx context.Context, headers map[string]string,
 		log.WithError(err).Error("Email SMTP server responded with error")
 
 		extraData := map[string]any{
-			"error": err.Error(),
+			"error":                   err.Error(),
+			constants.StatusExtraStep: constants.StatusStepSubmit,
 		}
 		extra, _ := structpb.NewStruct(extraData)
 
+		if errors.Is(err, client.ErrAttachmentFetch) {
+			// Sending again or on another route cannot resolve the attachment.
+			extraData[constants.StatusExtraStep] = "attachment_fetch"
+			extra, _ = structpb.NewStruct(extraData)
+
+			err = ms.eventsMan.Emit(ctx, events.NotificationStatusUpdateEvent,
+				&commonv1.StatusUpdateRequest{
+					Id:         notification.GetId(),
+					State:      commonv1.STATE_INACTIVE,
+					Status:     commonv1.STATUS_FAILED,
+					ExternalId: "",
+					Extras:     extra,
+				})
+			if err != nil {
+				log.WithError(err).Warn("could not update status on notification service")
+			}
+			return nil
+		}
+
+		if errors.Is(err, client.ErrAttachmentUnavailable) {
+			// The storage service may serve the file on a later attempt, the queue delivers the message again.
+			extraData[constants.StatusExtraStep] = "attachment_fetch"
+			extra, _ = structpb.NewStruct(extraData)
+
+			emitErr := ms.eventsMan.Emit(ctx, events.NotificationStatusUpdateEvent,
+				&commonv1.StatusUpdateRequest{
+					Id:         notification.GetId(),
+					State:      commonv1.STATE_ACTIVE,
+					Status:     commonv1.STATUS_UNKNOWN,
+					ExternalId: "",
+					Extras:     extra,
+				})
+			if emitErr != nil {
+				log.WithError(emitErr).Warn("could not update status on notification service")
+			}
+			return err
+		}
+
+		if errors.Is(err, client.ErrSMTPDial) {
+			// The SMTP server is unreachable, another route may still deliver the email.
+			extraData[constants.StatusExtraReroute] = true
+			extra, _ = structpb.NewStruct(extraData)
+
+			err = ms.eventsMan.Emit(ctx, events.NotificationStatusUpdateEvent,
+				&commonv1.StatusUpdateRequest{
+					Id:         notification.GetId(),
+					State:      commonv1.STATE_INACTIVE,
+					Status:     commonv1.STATUS_FAILED,
+					ExternalId: "",
+					Extras:     extra,
+				})
+			if err != nil {
+				log.WithError(err).Warn("could not update status on notification service")
+			}
+			return nil
+		}
+
 		var appErr *apperrors.Error
 		ok := errors.As(err, &appErr)
 		if !ok || appErr.IsRetriable() {
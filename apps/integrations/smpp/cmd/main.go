@@ -5,105 +5,90 @@ import (
 
 	"buf.build/gen/go/antinvestor/notification/connectrpc/go/notification/v1/notificationv1connect"
 	"buf.build/gen/go/antinvestor/profile/connectrpc/go/profile/v1/profilev1connect"
-	"buf.build/gen/go/antinvestor/tenancy/connectrpc/go/tenancy/v1/tenancyv1connect"
+	"buf.build/gen/go/antinvestor/settingz/connectrpc/go/settings/v1/settingsv1connect"
 	apis "github.com/antinvestor/common/v2"
 	"github.com/antinvestor/common/v2/connection"
 	"github.com/antinvestor/common/v2/servicecatalog"
-	"github.com/antinvestor/service-notification/apps/integrations/smpp/config"
-	"github.com/antinvestor/service-notification/apps/integrations/smpp/service"
-	"github.com/antinvestor/service-notification/apps/integrations/smpp/service/events"
-	"github.com/antinvestor/service-notification/apps/integrations/smpp/service/models"
+	aconfig "github.com/antinvestor/service-notification/apps/integrations/smpp/config"
+	"github.com/antinvestor/service-notification/apps/integrations/smpp/service/client"
+	"github.com/antinvestor/service-notification/apps/integrations/smpp/service/handlers"
+	"github.com/antinvestor/service-notification/apps/integrations/smpp/service/queue"
+	"github.com/antinvestor/service-notification/pkg/events"
 	"github.com/pitabwire/frame/v2"
-	fconfig "github.com/pitabwire/frame/v2/config"
-	"github.com/pitabwire/frame/v2/datastore"
+	"github.com/pitabwire/frame/v2/config"
 	"github.com/pitabwire/util"
 )
 
 func main() {
-	tmpCtx := context.Background()
 
-	cfg, err := fconfig.LoadWithOIDC[config.TemplateConfig](tmpCtx)
+	ctx := context.Background()
+
+	cfg, err := config.LoadWithOIDC[aconfig.SMPPConfig](ctx)
 	if err != nil {
-		util.Log(tmpCtx).With("err", err).Error("could not process configs")
+		util.Log(ctx).With("err", err).Error("could not process configs")
 		return
 	}
 
 	if cfg.Name() == "" {
-		cfg.ServiceName = "template_service"
+		cfg.ServiceName = "integration_notification_smpp"
 	}
 
-	ctx, svc := frame.NewServiceWithContext(
-		tmpCtx,
-		frame.WithConfig(&cfg),
-		frame.WithDatastore(),
-	)
+	ctx, svc := frame.NewServiceWithContext(ctx, frame.WithConfig(&cfg))
 	defer svc.Stop(ctx)
 
-	log := util.Log(ctx)
-	dbManager := svc.DatastoreManager()
-
-	if cfg.DoDatabaseMigrate() {
-		dbPool := dbManager.GetPool(ctx, datastore.DefaultMigrationPoolName)
-		if dbPool == nil {
-			log.Fatal("database pool is nil - check DATABASE_URL environment variable")
-			return
-		}
-		err = dbManager.Migrate(ctx, dbPool, cfg.GetDatabaseMigrationPath(),
-			models.Template{})
-		if err != nil {
-			log.WithError(err).Fatal("could not migrate successfully")
-		}
-		return
+	logger := svc.Log(ctx)
+
+	eventsMan := svc.EventsManager()
+
+	notificationCli, err := setupNotificationClient(ctx, cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("could not setup notification client")
 	}
 
 	profileCli, err := setupProfileClient(ctx, cfg)
 	if err != nil {
-		log.WithError(err).Fatal("could not setup profile client")
+		logger.WithError(err).Fatal("could not setup profile client")
 	}
 
-	tenancyCli, err := setupTenancyClient(ctx, cfg)
+	settingsCli, err := setupSettingsClient(ctx, cfg)
 	if err != nil {
-		log.WithError(err).Fatal("could not setup partition client")
+		logger.WithError(err).Fatal("could not setup settings client")
 	}
 
-	notificationCli, err := setupNotificationClient(ctx, cfg)
+	deliveryServer := handlers.NewDeliveryServer(profileCli, notificationCli)
+
+	smppCli, err := client.NewClient(ctx, logger, &cfg, profileCli, settingsCli, deliveryServer)
 	if err != nil {
-		log.WithError(err).Fatal("could not setup notification client")
+		logger.WithError(err).Fatal("could not setup smpp client")
 	}
+	defer smppCli.Close()
 
-	dbPool := dbManager.GetPool(ctx, datastore.DefaultPoolName)
-	if dbPool == nil {
-		log.Fatal("database pool is nil - check DATABASE_URL environment variable")
-		return
+	err = smppCli.BindConfigured()
+	if err != nil {
+		// The binds are opened again on the first message sent through the account.
+		logger.WithError(err).Warn("could not bind the configured SMSC account")
 	}
 
-	authServiceHandlers := service.NewAuthRouterV1(svc, &cfg, profileCli, tenancyCli, notificationCli)
+	messageHandler := queue.NewMessageToSend(eventsMan, smppCli)
 
 	serviceOptions := []frame.Option{
-		frame.WithHTTPHandler(authServiceHandlers),
-		frame.WithRegisterEvents(
-			events.NewTemplateSave(ctx, dbPool),
-		),
+		frame.WithRegisterEvents(events.NewNotificationStatusUpdate(ctx, notificationCli)),
+		frame.WithRegisterSubscriber(cfg.QueueSMPPDequeueName, cfg.QueueSMPPDequeueURI, messageHandler),
 	}
 
 	svc.Init(ctx, serviceOptions...)
 
-	serverPort := cfg.Port()
-	if serverPort == "" {
-		serverPort = ":7020"
-	}
-
-	log.With("port", serverPort).Info("initiating server operations")
-	err = svc.Run(ctx, serverPort)
+	logger.Info("Initiating SMPP integration server operations")
+	err = svc.Run(ctx, "")
 	if err != nil {
-		log.WithError(err).Error("could not run Server")
+		logger.WithError(err).Error("could not run Server")
 	}
 }
 
+// setupProfileClient creates and configures the profile client.
 func setupProfileClient(
 	ctx context.Context,
-	cfg config.TemplateConfig,
-) (profilev1connect.ProfileServiceClient, error) {
+	cfg aconfig.SMPPConfig) (profilev1connect.ProfileServiceClient, error) {
 	return connection.NewServiceClient(ctx, &cfg, apis.ServiceTarget{
 		Endpoint:              cfg.ProfileServiceURI,
 		WorkloadAPITargetPath: cfg.ProfileServiceWorkloadAPITargetPath,
@@ -111,24 +96,24 @@ func setupProfileClient(
 	}, profilev1connect.NewProfileServiceClient)
 }
 
-func setupTenancyClient(
-	ctx context.Context,
-	cfg config.TemplateConfig,
-) (tenancyv1connect.TenancyServiceClient, error) {
-	return connection.NewServiceClient(ctx, &cfg, apis.ServiceTarget{
-		Endpoint:              cfg.TenancyServiceURI,
-		WorkloadAPITargetPath: cfg.TenancyServiceWorkloadAPITargetPath,
-		ServiceID:             servicecatalog.ServiceTenancy,
-	}, tenancyv1connect.NewTenancyServiceClient)
-}
-
+// setupNotificationClient creates and configures the notification client.
 func setupNotificationClient(
 	ctx context.Context,
-	cfg config.TemplateConfig,
-) (notificationv1connect.NotificationServiceClient, error) {
+	cfg aconfig.SMPPConfig) (notificationv1connect.NotificationServiceClient, error) {
 	return connection.NewServiceClient(ctx, &cfg, apis.ServiceTarget{
 		Endpoint:              cfg.NotificationServiceURI,
 		WorkloadAPITargetPath: cfg.NotificationServiceWorkloadAPITargetPath,
 		ServiceID:             servicecatalog.ServiceNotification,
 	}, notificationv1connect.NewNotificationServiceClient)
 }
+
+// setupSettingsClient creates and configures the settings client.
+func setupSettingsClient(
+	ctx context.Context,
+	cfg aconfig.SMPPConfig) (settingsv1connect.SettingsServiceClient, error) {
+	return connection.NewServiceClient(ctx, &cfg, apis.ServiceTarget{
+		Endpoint:              cfg.SettingsServiceURI,
+		WorkloadAPITargetPath: cfg.SettingsServiceWorkloadAPITargetPath,
+		ServiceID:             servicecatalog.ServiceSettings,
+	}, settingsv1connect.NewSettingsServiceClient)
+}
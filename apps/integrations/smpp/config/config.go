@@ -1,13 +1,45 @@
 package config
 
-import "github.com/pitabwire/frame/v2/config"
+import (
+	"time"
 
-type TemplateConfig struct {
+	"github.com/pitabwire/frame/v2/config"
+)
+
+type SMPPConfig struct {
 	config.ConfigurationDefault
+
+	SettingsIntegrationName string `envDefault:"SMPP" env:"SETTINGS_INTEGRATION_NAME"`
+	SettingsIntegrationID   string `envDefault:"notification.smpp" env:"SETTINGS_INTEGRATION_ID"`
+
 	ProfileServiceURI                        string `envDefault:"127.0.0.1:7005" env:"PROFILE_SERVICE_URI"`
+	SettingsServiceURI                       string `envDefault:"127.0.0.1:7005" env:"SETTINGS_SERVICE_URI"`
 	NotificationServiceURI                   string `envDefault:"127.0.0.1:7005" env:"NOTIFICATION_SERVICE_URI"`
-	TenancyServiceURI                        string `envDefault:"127.0.0.1:7003" env:"TENANCY_SERVICE_URI"`
 	ProfileServiceWorkloadAPITargetPath      string `envDefault:"/ns/profile/sa/service-profile" env:"PROFILE_SERVICE_WORKLOAD_API_TARGET_PATH"`
-	TenancyServiceWorkloadAPITargetPath      string `envDefault:"/ns/auth/sa/service-tenancy" env:"TENANCY_SERVICE_WORKLOAD_API_TARGET_PATH"`
+	SettingsServiceWorkloadAPITargetPath     string `envDefault:"/ns/profile/sa/service-settings" env:"SETTINGS_SERVICE_WORKLOAD_API_TARGET_PATH"`
 	NotificationServiceWorkloadAPITargetPath string `envDefault:"/ns/notifications/sa/service-notification" env:"NOTIFICATION_SERVICE_WORKLOAD_API_TARGET_PATH"`
+
+	// SMPP queue configuration
+	QueueSMPPDequeueName string `envDefault:"smpp.natifications.dequeue" env:"QUEUE_NOTIFICATION_SMPP_DEQUEUE_NAME"`
+	QueueSMPPDequeueURI  string `envDefault:"mem://smpp.natifications.de.queue" env:"QUEUE_NOTIFICATION_SMPP_DEQUEUE_URI"`
+
+	// Default SMSC bind, used when a route carries no connection credentials of its own
+	SMPPServerAddress    string        `envDefault:"" env:"SMPP_SERVER_ADDRESS"`
+	SMPPSystemID         string        `envDefault:"" env:"SMPP_SYSTEM_ID"`
+	SMPPPassword         string        `envDefault:"" env:"SMPP_PASSWORD"`
+	SMPPSystemType       string        `envDefault:"" env:"SMPP_SYSTEM_TYPE"`
+	SMPPSourceAddress    string        `envDefault:"" env:"SMPP_SOURCE_ADDRESS"`
+	SMPPBindPoolSize     int           `envDefault:"2" env:"SMPP_BIND_POOL_SIZE"`
+	SMPPEnquireLink      time.Duration `envDefault:"60s" env:"SMPP_ENQUIRE_LINK_INTERVAL"`
+	SMPPReadTimeout      time.Duration `envDefault:"90s" env:"SMPP_READ_TIMEOUT"`
+	SMPPRebindInterval   time.Duration `envDefault:"10s" env:"SMPP_REBIND_INTERVAL"`
+	SMPPBindIdleTimeout  time.Duration `envDefault:"30m" env:"SMPP_BIND_IDLE_TIMEOUT"`
+	SMPPSubmitTimeout    time.Duration `envDefault:"30s" env:"SMPP_SUBMIT_TIMEOUT"`
+	SMPPRegisterDelivery bool          `envDefault:"true" env:"SMPP_REGISTER_DELIVERY"`
+
+	// Route that receipts and incoming messages on the default bind are reported against
+	SMPPRouteID string `envDefault:"" env:"SMPP_ROUTE_ID"`
+
+	// How long the parts of an incoming concatenated message are held waiting for the rest
+	SMPPConcatenatedExpiry time.Duration `envDefault:"10m" env:"SMPP_CONCATENATED_EXPIRY"`
 }
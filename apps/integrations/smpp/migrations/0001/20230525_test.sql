@@ -1,4 +0,0 @@
-
-
-
-SELECT 1;
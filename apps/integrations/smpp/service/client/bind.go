@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// ErrSMPPBind is returned when no bind to the SMSC is available to take a message.
+var ErrSMPPBind = errors.New("no SMPP bind available")
+
+// ErrSubmitTimeout is returned when the SMSC did not answer a submit in time.
+var ErrSubmitTimeout = errors.New("timed out waiting for submit_sm_resp")
+
+// BindSettings tune the binds opened to an SMSC.
+type BindSettings struct {
+	PoolSize        int
+	EnquireLink     time.Duration
+	ReadTimeout     time.Duration
+	RebindInterval  time.Duration
+	OnDeliver       func(bindKey string, deliver *pdu.DeliverSM)
+	OnBindingChange func(bindKey string, err error)
+}
+
+type submitResult struct {
+	resp *pdu.SubmitSMResp
+	err  error
+}
+
+// bind is one transceiver session, tracking the submits still waiting for a response.
+type bind struct {
+	session *gosmpp.Session
+	pending sync.Map
+}
+
+func (b *bind) resolve(sequence int32, result submitResult) {
+	if ch, ok := b.pending.LoadAndDelete(sequence); ok {
+		ch.(chan submitResult) <- result
+	}
+}
+
+func (b *bind) onPDU(key string, settings BindSettings) func(pdu.PDU, bool) {
+	return func(p pdu.PDU, _ bool) {
+		switch pd := p.(type) {
+		case *pdu.SubmitSMResp:
+			b.resolve(pd.GetSequenceNumber(), submitResult{resp: pd})
+		case *pdu.GenericNack:
+			b.resolve(pd.GetSequenceNumber(), submitResult{
+				err: fmt.Errorf("generic_nack from SMSC: %s", pd.CommandStatus)})
+		case *pdu.DeliverSM:
+			if settings.OnDeliver != nil {
+				settings.OnDeliver(key, pd)
+			}
+		}
+	}
+}
+
+// submit sends a message over the bind and waits for the SMSC to accept or refuse it.
+func (b *bind) submit(ctx context.Context, sm *pdu.SubmitSM, timeout time.Duration) (*pdu.SubmitSMResp, error) {
+	sequence := sm.GetSequenceNumber()
+	ch := make(chan submitResult, 1)
+	b.pending.Store(sequence, ch)
+	defer b.pending.Delete(sequence)
+
+	err := b.session.Transceiver().Submit(sm)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSMPPBind, err)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-ch:
+		return result.resp, result.err
+	case <-timer.C:
+		return nil, ErrSubmitTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// bindPool keeps a fixed number of transceiver binds to one SMSC account open and
+// spreads submits across them. Enquire links keep idle binds alive and a bind that
+// drops is rebound in the background while the others carry the traffic.
+type bindPool struct {
+	key   string
+	binds []*bind
+	next  atomic.Uint32
+}
+
+func newBindPool(key string, auth gosmpp.Auth, settings BindSettings) (*bindPool, error) {
+	size := settings.PoolSize
+	if size <= 0 {
+		size = 1
+	}
+
+	pool := &bindPool{key: key}
+	var bindErr error
+	for range size {
+		b := &bind{}
+		session, err := gosmpp.NewSession(
+			gosmpp.TRXConnector(gosmpp.NonTLSDialer, auth),
+			gosmpp.Settings{
+				EnquireLink: settings.EnquireLink,
+				ReadTimeout: settings.ReadTimeout,
+				OnPDU:       b.onPDU(key, settings),
+				OnSubmitError: func(p pdu.PDU, err error) {
+					b.resolve(p.GetSequenceNumber(), submitResult{err: fmt.Errorf("%w: %w", ErrSMPPBind, err)})
+				},
+				OnReceivingError: func(err error) {
+					if settings.OnBindingChange != nil {
+						settings.OnBindingChange(key, err)
+					}
+				},
+				OnRebindingError: func(err error) {
+					if settings.OnBindingChange != nil {
+						settings.OnBindingChange(key, err)
+					}
+				},
+			}, settings.RebindInterval)
+		if err != nil {
+			bindErr = err
+			continue
+		}
+
+		b.session = session
+		pool.binds = append(pool.binds, b)
+	}
+
+	if len(pool.binds) == 0 {
+		return nil, fmt.Errorf("%w: %w", ErrSMPPBind, bindErr)
+	}
+	return pool, nil
+}
+
+// submit hands the message to the binds in turn until one of them can send it.
+func (p *bindPool) submit(ctx context.Context, sm *pdu.SubmitSM, timeout time.Duration) (*pdu.SubmitSMResp, error) {
+	start := p.next.Add(1)
+
+	var err error
+	for i := range len(p.binds) {
+		b := p.binds[(int(start)+i)%len(p.binds)]
+
+		var resp *pdu.SubmitSMResp
+		resp, err = b.submit(ctx, sm, timeout)
+		if !errors.Is(err, ErrSMPPBind) {
+			return resp, err
+		}
+	}
+	return nil, err
+}
+
+func (p *bindPool) close() {
+	for _, b := range p.binds {
+		_ = b.session.Close()
+	}
+}
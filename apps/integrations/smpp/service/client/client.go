@@ -0,0 +1,378 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+	"buf.build/gen/go/antinvestor/profile/connectrpc/go/profile/v1/profilev1connect"
+	profilev1 "buf.build/gen/go/antinvestor/profile/protocolbuffers/go/profile/v1"
+	"buf.build/gen/go/antinvestor/settingz/connectrpc/go/settings/v1/settingsv1connect"
+	settingsv1 "buf.build/gen/go/antinvestor/settingz/protocolbuffers/go/settings/v1"
+	"connectrpc.com/connect"
+	"github.com/antinvestor/service-notification/apps/integrations/smpp/config"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/antinvestor/service-notification/pkg/sms"
+	"github.com/antinvestor/service-notification/pkg/utility"
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/pitabwire/util"
+)
+
+// Credentials identify an SMSC account and the address messages are sent from.
+type Credentials struct {
+	Address    string `json:"smsc"`
+	SystemID   string `json:"system_id"`
+	Password   string `json:"password"`
+	SystemType string `json:"system_type"`
+	SourceAddr string `json:"source_addr"`
+}
+
+// bindKeys return the account the credentials bind to within a partition, and the key
+// of binds opened with exactly these credentials. The password only enters the key as a
+// digest, the key shows up in logs.
+func (c *Credentials) bindKeys(partitionID string) (string, string) {
+	account := strings.Join([]string{partitionID, c.Address, c.SystemID}, "|")
+	digest := sha256.Sum256([]byte(c.Password + "\x00" + c.SystemType))
+	return account, account + "|" + hex.EncodeToString(digest[:8])
+}
+
+// SubmitResult is the SMSC answer to every part of a submitted message.
+type SubmitResult struct {
+	MessageIDs []string
+	Status     data.CommandStatusType
+	Parts      int
+}
+
+type Client struct {
+	cfg    *config.SMPPConfig
+	logger *util.LogEntry
+
+	profileCli  profilev1connect.ProfileServiceClient
+	settingsCli settingsv1connect.SettingsServiceClient
+
+	bindSettings BindSettings
+	deliveries   *deliveryRouter
+	pools        map[string]*poolEntry
+	poolsMu      sync.Mutex
+}
+
+// poolEntry is the bind pool an account is currently sent through. Pools opened for the
+// configured account stay bound, those of route credentials are closed once idle.
+type poolEntry struct {
+	pool     *bindPool
+	pinned   bool
+	lastUsed time.Time
+}
+
+// NewClient creates the SMPP client. Receipts and messages the SMSC delivers over its
+// binds are passed to the delivery handler under the given context.
+func NewClient(ctx context.Context, logger *util.LogEntry, cfg *config.SMPPConfig, profileCli profilev1connect.ProfileServiceClient, settingsCli settingsv1connect.SettingsServiceClient, deliveryHandler DeliveryHandler) (*Client, error) {
+
+	cli := &Client{
+		cfg:         cfg,
+		logger:      logger,
+		profileCli:  profileCli,
+		settingsCli: settingsCli,
+		pools:       map[string]*poolEntry{},
+	}
+
+	cli.deliveries = newDeliveryRouter(ctx, deliveryHandler, cfg.SMPPConcatenatedExpiry, func(bindKey string, err error) {
+		logger.WithField("bind", bindKey).WithError(err).Warn("could not handle deliver_sm")
+	})
+
+	cli.bindSettings = BindSettings{
+		PoolSize:       cfg.SMPPBindPoolSize,
+		EnquireLink:    cfg.SMPPEnquireLink,
+		ReadTimeout:    cfg.SMPPReadTimeout,
+		RebindInterval: cfg.SMPPRebindInterval,
+		OnDeliver:      cli.deliveries.deliver,
+		OnBindingChange: func(bindKey string, err error) {
+			logger.WithField("bind", bindKey).WithError(err).Warn("SMPP bind connection error")
+		},
+	}
+
+	return cli, nil
+}
+
+// extractCredentials resolves the SMSC account for a message, from the connection
+// credentials named on the route when present and from the service config otherwise.
+func (ms *Client) extractCredentials(ctx context.Context, headers map[string]string) (*Credentials, error) {
+	connection, ok := headers[constants.APIConnectionCredentialsHeaderName]
+	if !ok {
+		if ms.cfg.SMPPServerAddress == "" {
+			return nil, fmt.Errorf("no SMPP connection credentials specified for message")
+		}
+
+		return ms.configuredCredentials(), nil
+	}
+
+	settingReq := &settingsv1.GetRequest{
+		Key: &settingsv1.Setting{
+			Name:     connection,
+			Object:   ms.cfg.SettingsIntegrationName,
+			ObjectId: ms.cfg.SettingsIntegrationID,
+			Lang:     "",
+			Module:   ms.cfg.SettingsIntegrationName,
+		},
+	}
+
+	settingResp, err := ms.settingsCli.Get(ctx, connect.NewRequest(settingReq))
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := &Credentials{}
+	err = json.Unmarshal([]byte(settingResp.Msg.GetData().GetValue()), credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	if credentials.Address == "" || credentials.SystemID == "" {
+		return nil, fmt.Errorf("SMPP connection %s is missing the smsc address or system id", connection)
+	}
+
+	return credentials, nil
+}
+
+func (ms *Client) configuredCredentials() *Credentials {
+	return &Credentials{
+		Address:    ms.cfg.SMPPServerAddress,
+		SystemID:   ms.cfg.SMPPSystemID,
+		Password:   ms.cfg.SMPPPassword,
+		SystemType: ms.cfg.SMPPSystemType,
+		SourceAddr: ms.cfg.SMPPSourceAddress,
+	}
+}
+
+// BindConfigured opens the binds of the account set in the service config, so receipts
+// and messages the SMSC delivers for it arrive from startup instead of only once a
+// message was sent through it.
+func (ms *Client) BindConfigured() error {
+	if ms.cfg.SMPPServerAddress == "" {
+		return nil
+	}
+
+	_, err := ms.getBindPool(map[string]string{}, ms.configuredCredentials(), true)
+	return err
+}
+
+// getBindPool returns the open binds for an SMSC account, binding on first use.
+// Each partition keeps its own binds of route credentials so one tenant's connection
+// issues stay its own, while the pinned binds of the configured account are shared.
+// Binds of an account whose credentials changed are closed and opened again with the
+// new ones, rather than left authenticated with the old password.
+func (ms *Client) getBindPool(headers map[string]string, credentials *Credentials, pinned bool) (*bindPool, error) {
+	partitionID := headers[constants.PartitionIDHeaderName]
+	routeID := headers[constants.RouteIDHeaderName]
+	if pinned {
+		partitionID = ""
+		if ms.cfg.SMPPRouteID != "" {
+			routeID = ms.cfg.SMPPRouteID
+		}
+	}
+
+	account, key := credentials.bindKeys(partitionID)
+
+	// Receipts and replies arriving on the binds are reported against the latest route using them.
+	if routeID != "" {
+		ms.deliveries.setRoute(key, routeID)
+	}
+
+	ms.poolsMu.Lock()
+	defer ms.poolsMu.Unlock()
+
+	now := time.Now()
+	ms.evictIdlePools(now)
+
+	entry, ok := ms.pools[account]
+	if ok && entry.pool.key == key {
+		entry.lastUsed = now
+		return entry.pool, nil
+	}
+	if ok {
+		ms.closePool(account, entry)
+	}
+
+	pool, err := newBindPool(key, gosmpp.Auth{
+		SMSC:       credentials.Address,
+		SystemID:   credentials.SystemID,
+		Password:   credentials.Password,
+		SystemType: credentials.SystemType,
+	}, ms.bindSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	ms.pools[account] = &poolEntry{pool: pool, pinned: pinned, lastUsed: now}
+	return pool, nil
+}
+
+// evictIdlePools closes the binds of route credentials no message went through for the
+// idle timeout. Callers hold poolsMu.
+func (ms *Client) evictIdlePools(now time.Time) {
+	if ms.cfg.SMPPBindIdleTimeout <= 0 {
+		return
+	}
+
+	for account, entry := range ms.pools {
+		if !entry.pinned && now.Sub(entry.lastUsed) > ms.cfg.SMPPBindIdleTimeout {
+			ms.closePool(account, entry)
+		}
+	}
+}
+
+// closePool unbinds a pool and forgets it. Callers hold poolsMu.
+func (ms *Client) closePool(account string, entry *poolEntry) {
+	delete(ms.pools, account)
+	ms.deliveries.forgetRoute(entry.pool.key)
+	entry.pool.close()
+}
+
+// Send submits the notification text to the SMSC, split into concatenated parts when it
+// does not fit a single message.
+func (ms *Client) Send(ctx context.Context, headers map[string]string, notification *notificationv1.Notification) (*SubmitResult, error) {
+
+	credentials, err := ms.extractCredentials(ctx, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	recipient, err := utility.PopulateContactLink(ctx, ms.profileCli, notification.GetRecipient(), profilev1.ContactType_MSISDN)
+	if err != nil {
+		return nil, err
+	}
+
+	if recipient.GetDetail() == "" {
+		return nil, fmt.Errorf("SMS recipient has no phone number")
+	}
+
+	submitSM, err := ms.newSubmitSM(credentials.SourceAddr, recipient.GetDetail(), notification.GetData())
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := submitSM.Split()
+	if err != nil {
+		return nil, err
+	}
+
+	_, routeCredentials := headers[constants.APIConnectionCredentialsHeaderName]
+	pool, err := ms.getBindPool(headers, credentials, !routeCredentials)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SubmitResult{Parts: len(parts)}
+	for _, part := range parts {
+		// Split parts share the original header, each needs its own sequence to be matched to its response.
+		part.AssignSequenceNumber()
+
+		resp, submitErr := pool.submit(ctx, part, ms.cfg.SMPPSubmitTimeout)
+		if submitErr != nil {
+			return result, submitErr
+		}
+
+		result.Status = resp.CommandStatus
+		if resp.CommandStatus != data.ESME_ROK {
+			return result, nil
+		}
+
+		result.MessageIDs = append(result.MessageIDs, resp.MessageID)
+	}
+
+	return result, nil
+}
+
+func (ms *Client) newSubmitSM(source, destination, message string) (*pdu.SubmitSM, error) {
+	srcAddr, err := smppAddress(source)
+	if err != nil {
+		return nil, err
+	}
+
+	destAddr, err := smppAddress(destination)
+	if err != nil {
+		return nil, err
+	}
+
+	submitSM := pdu.NewSubmitSM().(*pdu.SubmitSM)
+	submitSM.SourceAddr = srcAddr
+	submitSM.DestAddr = destAddr
+	// Encode the way segments are counted for billing, GSM-7 whenever the text allows it.
+	encoding := data.UCS2
+	if sms.DetectEncoding(message) == sms.EncodingGSM7 {
+		encoding = data.GSM7BIT
+	}
+
+	err = submitSM.Message.SetMessageWithEncoding(message, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	submitSM.ProtocolID = 0
+	submitSM.ReplaceIfPresentFlag = 0
+	submitSM.EsmClass = 0
+	if ms.cfg.SMPPRegisterDelivery {
+		submitSM.RegisteredDelivery = 1
+	}
+
+	return submitSM, nil
+}
+
+// smppAddress builds an address with the numbering plan matching its form: international
+// numbers, short codes or alphanumeric sender names.
+func smppAddress(address string) (pdu.Address, error) {
+	address = strings.TrimPrefix(strings.TrimSpace(address), "+")
+
+	numeric := address != ""
+	for _, r := range address {
+		if !unicode.IsDigit(r) {
+			numeric = false
+			break
+		}
+	}
+
+	switch {
+	case !numeric:
+		return pdu.NewAddressWithTonNpiAddr(data.GSM_TON_ALPHANUMERIC, data.GSM_NPI_UNKNOWN, address)
+	case len(address) <= 8:
+		return pdu.NewAddressWithTonNpiAddr(data.GSM_TON_NETWORK, data.GSM_NPI_E164, address)
+	default:
+		return pdu.NewAddressWithTonNpiAddr(data.GSM_TON_INTERNATIONAL, data.GSM_NPI_E164, address)
+	}
+}
+
+// Close unbinds from every SMSC.
+func (ms *Client) Close() {
+	ms.poolsMu.Lock()
+	defer ms.poolsMu.Unlock()
+
+	for account, entry := range ms.pools {
+		ms.closePool(account, entry)
+	}
+}
+
+// IsThrottled reports whether the SMSC refused the message only because it is busy,
+// so submitting it again later is expected to succeed.
+func IsThrottled(status data.CommandStatusType) bool {
+	return status == data.ESME_RTHROTTLED || status == data.ESME_RMSGQFUL
+}
+
+// IsReroutable reports whether the SMSC refused the message for reasons of its own,
+// so another route may still deliver it.
+func IsReroutable(status data.CommandStatusType) bool {
+	switch status {
+	case data.ESME_RSYSERR, data.ESME_RSUBMITFAIL, data.ESME_RPROVNOTALLWD, data.ESME_RX_T_APPN:
+		return true
+	default:
+		return false
+	}
+}
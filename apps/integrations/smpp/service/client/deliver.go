@@ -0,0 +1,276 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// Delivery receipt states, as SMSCs write them in the stat field of a receipt.
+const (
+	ReceiptStateEnroute       = "ENROUTE"
+	ReceiptStateDelivered     = "DELIVRD"
+	ReceiptStateExpired       = "EXPIRED"
+	ReceiptStateDeleted       = "DELETED"
+	ReceiptStateUndeliverable = "UNDELIV"
+	ReceiptStateAccepted      = "ACCEPTD"
+	ReceiptStateUnknown       = "UNKNOWN"
+	ReceiptStateRejected      = "REJECTD"
+)
+
+// messageStates maps the message_state TLV onto the stat names used in receipt text.
+var messageStates = map[byte]string{
+	1: ReceiptStateEnroute,
+	2: ReceiptStateDelivered,
+	3: ReceiptStateExpired,
+	4: ReceiptStateDeleted,
+	5: ReceiptStateUndeliverable,
+	6: ReceiptStateAccepted,
+	7: ReceiptStateUnknown,
+	8: ReceiptStateRejected,
+}
+
+var receiptFieldPattern = regexp.MustCompile(`(?i)\b(id|sub|dlvrd|submit date|done date|stat|err|text):`)
+
+// DeliveryReceipt is the SMSC report on what became of a message submitted earlier.
+type DeliveryReceipt struct {
+	MessageID   string
+	State       string
+	Error       string
+	SubmitDate  string
+	DoneDate    string
+	Source      string
+	Destination string
+}
+
+// InboundMessage is a message a subscriber sent to one of the bound addresses,
+// put back together when it arrived in several parts.
+type InboundMessage struct {
+	Source      string
+	Destination string
+	Text        string
+	Parts       int
+}
+
+// DeliveryHandler takes what the SMSC delivers over a bind onto the route it was opened for.
+type DeliveryHandler interface {
+	HandleReceipt(ctx context.Context, routeID string, receipt *DeliveryReceipt) error
+	HandleMessage(ctx context.Context, routeID string, message *InboundMessage) error
+}
+
+// parseDeliveryReceipt reads the receipt an SMSC sends for a message it was asked to
+// report on. The text follows the SMPP 3.4 appendix B layout, the receipted_message_id
+// and message_state parameters are preferred when the SMSC sets them.
+func parseDeliveryReceipt(deliver *pdu.DeliverSM) (*DeliveryReceipt, error) {
+	text, err := deliver.Message.GetMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &DeliveryReceipt{
+		Source:      deliver.SourceAddr.Address(),
+		Destination: deliver.DestAddr.Address(),
+	}
+
+	matches := receiptFieldPattern.FindAllStringSubmatchIndex(text, -1)
+	for i, match := range matches {
+		end := len(text)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		value := strings.TrimSpace(text[match[1]:end])
+
+		switch strings.ToLower(text[match[2]:match[3]]) {
+		case "id":
+			receipt.MessageID = value
+		case "stat":
+			receipt.State = strings.ToUpper(value)
+		case "err":
+			receipt.Error = value
+		case "submit date":
+			receipt.SubmitDate = value
+		case "done date":
+			receipt.DoneDate = value
+		}
+	}
+
+	if field, ok := deliver.OptionalParameters[pdu.TagReceiptedMessageID]; ok && len(field.Data) > 0 {
+		receipt.MessageID = strings.TrimRight(string(field.Data), "\x00")
+	}
+	if field, ok := deliver.OptionalParameters[pdu.TagMessageStateOption]; ok && len(field.Data) == 1 {
+		if state, known := messageStates[field.Data[0]]; known {
+			receipt.State = state
+		}
+	}
+
+	if receipt.MessageID == "" {
+		return nil, fmt.Errorf("delivery receipt carries no message id: %q", text)
+	}
+	return receipt, nil
+}
+
+type partialMessage struct {
+	parts    []string
+	received []bool
+	missing  int
+	started  time.Time
+}
+
+// messageAssembler holds the parts of concatenated messages until all of them arrived.
+// Messages whose remaining parts never show up are dropped after the expiry.
+type messageAssembler struct {
+	mu       sync.Mutex
+	expiry   time.Duration
+	messages map[string]*partialMessage
+}
+
+func newMessageAssembler(expiry time.Duration) *messageAssembler {
+	return &messageAssembler{
+		expiry:   expiry,
+		messages: map[string]*partialMessage{},
+	}
+}
+
+// add keeps a part and returns the whole text once the last part of the message is in.
+func (a *messageAssembler) add(key string, totalParts, partNum byte, text string) (string, bool) {
+	if totalParts <= 1 {
+		return text, true
+	}
+	if partNum == 0 || partNum > totalParts {
+		return "", false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	for k, message := range a.messages {
+		if now.Sub(message.started) > a.expiry {
+			delete(a.messages, k)
+		}
+	}
+
+	message, ok := a.messages[key]
+	if !ok || len(message.parts) != int(totalParts) {
+		message = &partialMessage{
+			parts:    make([]string, totalParts),
+			received: make([]bool, totalParts),
+			missing:  int(totalParts),
+			started:  now,
+		}
+		a.messages[key] = message
+	}
+
+	if !message.received[partNum-1] {
+		message.received[partNum-1] = true
+		message.missing--
+	}
+	message.parts[partNum-1] = text
+
+	if message.missing > 0 {
+		return "", false
+	}
+
+	delete(a.messages, key)
+	return strings.Join(message.parts, ""), true
+}
+
+// deliveryRouter turns deliver_sm PDUs into receipts and inbound messages for the route
+// each bind serves. PDUs arrive on the bind's read loop, so handling is moved off it.
+type deliveryRouter struct {
+	ctx       context.Context
+	handler   DeliveryHandler
+	assembler *messageAssembler
+	routes    sync.Map
+	onError   func(bindKey string, err error)
+}
+
+func newDeliveryRouter(ctx context.Context, handler DeliveryHandler, expiry time.Duration, onError func(bindKey string, err error)) *deliveryRouter {
+	return &deliveryRouter{
+		ctx:       ctx,
+		handler:   handler,
+		assembler: newMessageAssembler(expiry),
+		onError:   onError,
+	}
+}
+
+// setRoute records the route whose messages a bind carries.
+func (r *deliveryRouter) setRoute(bindKey, routeID string) {
+	r.routes.Store(bindKey, routeID)
+}
+
+// forgetRoute drops the route of a bind that was closed.
+func (r *deliveryRouter) forgetRoute(bindKey string) {
+	r.routes.Delete(bindKey)
+}
+
+func (r *deliveryRouter) route(bindKey string) string {
+	routeID, _ := r.routes.Load(bindKey)
+	s, _ := routeID.(string)
+	return s
+}
+
+func (r *deliveryRouter) fail(bindKey string, err error) {
+	if r.onError != nil {
+		r.onError(bindKey, err)
+	}
+}
+
+func (r *deliveryRouter) deliver(bindKey string, deliver *pdu.DeliverSM) {
+	if r.handler == nil {
+		return
+	}
+
+	routeID := r.route(bindKey)
+
+	if deliver.EsmClass&data.SM_SMSC_DLV_RCPT_TYPE != 0 {
+		receipt, err := parseDeliveryReceipt(deliver)
+		if err != nil {
+			r.fail(bindKey, err)
+			return
+		}
+
+		go func() {
+			if err = r.handler.HandleReceipt(r.ctx, routeID, receipt); err != nil {
+				r.fail(bindKey, err)
+			}
+		}()
+		return
+	}
+
+	text, err := deliver.Message.GetMessage()
+	if err != nil {
+		r.fail(bindKey, err)
+		return
+	}
+
+	message := &InboundMessage{
+		Source:      deliver.SourceAddr.Address(),
+		Destination: deliver.DestAddr.Address(),
+		Text:        text,
+		Parts:       1,
+	}
+
+	totalParts, partNum, reference, found := deliver.Message.UDH().GetConcatInfo()
+	if found && totalParts > 1 {
+		key := fmt.Sprintf("%s|%s|%s|%d", bindKey, message.Source, message.Destination, reference)
+		text, complete := r.assembler.add(key, totalParts, partNum, text)
+		if !complete {
+			return
+		}
+		message.Text = text
+		message.Parts = int(totalParts)
+	}
+
+	go func() {
+		if err = r.handler.HandleMessage(r.ctx, routeID, message); err != nil {
+			r.fail(bindKey, err)
+		}
+	}()
+}
@@ -0,0 +1,361 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/antinvestor/service-notification/apps/integrations/smpp/config"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+	"github.com/pitabwire/util"
+	"github.com/stretchr/testify/require"
+)
+
+// testSMSC stands in for an SMSC: it accepts transceiver binds, answers submits with
+// message ids of its own and pushes deliver_sm PDUs to the bound ESME on request.
+type testSMSC struct {
+	listener  net.Listener
+	mu        sync.Mutex
+	conns     []net.Conn
+	messageID atomic.Int32
+	submitted chan *pdu.SubmitSM
+	bound     chan struct{}
+}
+
+func newTestSMSC(t *testing.T) *testSMSC {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	smsc := &testSMSC{
+		listener:  listener,
+		submitted: make(chan *pdu.SubmitSM, 16),
+		bound:     make(chan struct{}, 16),
+	}
+
+	go func() {
+		for {
+			conn, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+
+			smsc.mu.Lock()
+			smsc.conns = append(smsc.conns, conn)
+			smsc.mu.Unlock()
+
+			go smsc.serve(conn)
+		}
+	}()
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+		smsc.mu.Lock()
+		defer smsc.mu.Unlock()
+		for _, conn := range smsc.conns {
+			_ = conn.Close()
+		}
+	})
+	return smsc
+}
+
+func (s *testSMSC) address() string {
+	return s.listener.Addr().String()
+}
+
+func (s *testSMSC) write(conn net.Conn, p pdu.PDU) error {
+	buf := pdu.NewBuffer(nil)
+	p.Marshal(buf)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := conn.Write(buf.Bytes())
+	return err
+}
+
+func (s *testSMSC) serve(conn net.Conn) {
+	for {
+		p, err := pdu.Parse(conn)
+		if err != nil {
+			return
+		}
+
+		switch pd := p.(type) {
+		case *pdu.BindRequest:
+			_ = s.write(conn, pdu.NewBindResp(*pd))
+			s.bound <- struct{}{}
+		case *pdu.EnquireLink, *pdu.Unbind:
+			_ = s.write(conn, pd.GetResponse())
+		case *pdu.SubmitSM:
+			resp := pd.GetResponse().(*pdu.SubmitSMResp)
+			resp.MessageID = fmt.Sprintf("msg-%d", s.messageID.Add(1))
+			_ = s.write(conn, resp)
+			s.submitted <- pd
+		}
+	}
+}
+
+// deliver pushes a deliver_sm to the most recently bound ESME.
+func (s *testSMSC) deliver(t *testing.T, deliver *pdu.DeliverSM) {
+	s.mu.Lock()
+	conn := s.conns[len(s.conns)-1]
+	s.mu.Unlock()
+
+	require.NoError(t, s.write(conn, deliver))
+}
+
+type receivedDelivery struct {
+	routeID string
+	receipt *DeliveryReceipt
+	message *InboundMessage
+}
+
+type recordingHandler struct {
+	received chan receivedDelivery
+}
+
+func (h *recordingHandler) HandleReceipt(_ context.Context, routeID string, receipt *DeliveryReceipt) error {
+	h.received <- receivedDelivery{routeID: routeID, receipt: receipt}
+	return nil
+}
+
+func (h *recordingHandler) HandleMessage(_ context.Context, routeID string, message *InboundMessage) error {
+	h.received <- receivedDelivery{routeID: routeID, message: message}
+	return nil
+}
+
+func (h *recordingHandler) next(t *testing.T) receivedDelivery {
+	select {
+	case d := <-h.received:
+		return d
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "nothing was delivered to the handler")
+		return receivedDelivery{}
+	}
+}
+
+func newTestDeliverSM(t *testing.T, esmClass byte, text string) *pdu.DeliverSM {
+	deliver := pdu.NewDeliverSM().(*pdu.DeliverSM)
+	deliver.EsmClass = esmClass
+	require.NoError(t, deliver.SourceAddr.SetAddress("254700000001"))
+	require.NoError(t, deliver.DestAddr.SetAddress("20880"))
+	require.NoError(t, deliver.Message.SetMessageWithEncoding(text, data.GSM7BIT))
+	return deliver
+}
+
+func newTestBindPool(t *testing.T, smsc *testSMSC, poolSize int, router *deliveryRouter) *bindPool {
+	pool, err := newBindPool("partition|smsc|system", gosmpp.Auth{
+		SMSC:     smsc.address(),
+		SystemID: "system",
+		Password: "secret",
+	}, BindSettings{
+		PoolSize:       poolSize,
+		EnquireLink:    time.Second,
+		ReadTimeout:    5 * time.Second,
+		RebindInterval: time.Second,
+		OnDeliver:      router.deliver,
+	})
+	require.NoError(t, err)
+	t.Cleanup(pool.close)
+
+	for range poolSize {
+		select {
+		case <-smsc.bound:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "the pool did not bind to the SMSC")
+		}
+	}
+	return pool
+}
+
+func TestBindPoolSubmit(t *testing.T) {
+	smsc := newTestSMSC(t)
+	pool := newTestBindPool(t, smsc, 2, newDeliveryRouter(context.Background(), nil, time.Minute, nil))
+
+	for range 3 {
+		submitSM := pdu.NewSubmitSM().(*pdu.SubmitSM)
+		require.NoError(t, submitSM.DestAddr.SetAddress("254700000001"))
+		require.NoError(t, submitSM.Message.SetMessageWithEncoding("hello", data.GSM7BIT))
+
+		resp, err := pool.submit(context.Background(), submitSM, 5*time.Second)
+		require.NoError(t, err)
+		require.Equal(t, data.ESME_ROK, resp.CommandStatus)
+		require.NotEmpty(t, resp.MessageID)
+
+		submitted := <-smsc.submitted
+		require.Equal(t, submitSM.GetSequenceNumber(), submitted.GetSequenceNumber())
+	}
+}
+
+func TestBindPoolCredentials(t *testing.T) {
+	smsc := newTestSMSC(t)
+	ctx := context.Background()
+	cli, err := NewClient(ctx, util.Log(ctx), &config.SMPPConfig{
+		SMPPBindPoolSize:    1,
+		SMPPEnquireLink:     time.Second,
+		SMPPReadTimeout:     5 * time.Second,
+		SMPPRebindInterval:  time.Second,
+		SMPPBindIdleTimeout: time.Minute,
+	}, nil, nil, nil)
+	require.NoError(t, err)
+	t.Cleanup(cli.Close)
+
+	headers := map[string]string{constants.PartitionIDHeaderName: "partition"}
+	credentials := &Credentials{Address: smsc.address(), SystemID: "system", Password: "secret"}
+
+	first, err := cli.getBindPool(headers, credentials, false)
+	require.NoError(t, err)
+	require.NotContains(t, first.key, "secret", "passwords stay out of bind keys")
+
+	again, err := cli.getBindPool(headers, credentials, false)
+	require.NoError(t, err)
+	require.Same(t, first, again)
+
+	rotated := *credentials
+	rotated.Password = "rotated"
+	second, err := cli.getBindPool(headers, &rotated, false)
+	require.NoError(t, err)
+	require.NotSame(t, first, second, "a changed password binds again")
+	require.Len(t, cli.pools, 1, "the binds of the old password are closed")
+
+	pinned, err := cli.getBindPool(map[string]string{constants.PartitionIDHeaderName: "other"}, credentials, true)
+	require.NoError(t, err)
+
+	cli.poolsMu.Lock()
+	for _, entry := range cli.pools {
+		entry.lastUsed = time.Now().Add(-2 * time.Minute)
+	}
+	cli.evictIdlePools(time.Now())
+	require.Len(t, cli.pools, 1, "idle binds of route credentials are closed")
+	for _, entry := range cli.pools {
+		require.Same(t, pinned, entry.pool, "binds of the configured account stay open")
+	}
+	cli.poolsMu.Unlock()
+}
+
+func TestBindConfigured(t *testing.T) {
+	smsc := newTestSMSC(t)
+	handler := &recordingHandler{received: make(chan receivedDelivery, 4)}
+	ctx := context.Background()
+	cli, err := NewClient(ctx, util.Log(ctx), &config.SMPPConfig{
+		SMPPServerAddress:  smsc.address(),
+		SMPPSystemID:       "system",
+		SMPPPassword:       "secret",
+		SMPPRouteID:        "route-default",
+		SMPPBindPoolSize:   1,
+		SMPPEnquireLink:    time.Second,
+		SMPPReadTimeout:    5 * time.Second,
+		SMPPRebindInterval: time.Second,
+	}, nil, nil, handler)
+	require.NoError(t, err)
+	t.Cleanup(cli.Close)
+
+	require.NoError(t, cli.BindConfigured())
+	select {
+	case <-smsc.bound:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the configured account was not bound at startup")
+	}
+
+	smsc.deliver(t, newTestDeliverSM(t, data.SM_SMSC_DLV_RCPT_TYPE,
+		"id:msg-7 sub:001 dlvrd:001 submit date:2610171000 done date:2610171001 stat:DELIVRD err:000 text:"))
+	delivered := handler.next(t)
+	require.Equal(t, "route-default", delivered.routeID)
+	require.Equal(t, "msg-7", delivered.receipt.MessageID)
+
+	headers := map[string]string{constants.PartitionIDHeaderName: "partition", constants.RouteIDHeaderName: "route-1"}
+	pool, err := cli.getBindPool(headers, cli.configuredCredentials(), true)
+	require.NoError(t, err)
+	require.Len(t, cli.pools, 1, "every partition sends through the binds opened at startup")
+	require.Equal(t, "route-default", cli.deliveries.route(pool.key))
+}
+
+func TestDeliveryReceipts(t *testing.T) {
+	smsc := newTestSMSC(t)
+	handler := &recordingHandler{received: make(chan receivedDelivery, 4)}
+	router := newDeliveryRouter(context.Background(), handler, time.Minute, nil)
+	router.setRoute("partition|smsc|system", "route-smpp")
+	newTestBindPool(t, smsc, 1, router)
+
+	smsc.deliver(t, newTestDeliverSM(t, data.SM_SMSC_DLV_RCPT_TYPE,
+		"id:msg-7 sub:001 dlvrd:001 submit date:2310171200 done date:2310171201 stat:DELIVRD err:000 text:hello"))
+
+	delivered := handler.next(t)
+	require.Equal(t, "route-smpp", delivered.routeID)
+	require.NotNil(t, delivered.receipt)
+	require.Equal(t, "msg-7", delivered.receipt.MessageID)
+	require.Equal(t, ReceiptStateDelivered, delivered.receipt.State)
+	require.Equal(t, "000", delivered.receipt.Error)
+	require.Equal(t, "2310171200", delivered.receipt.SubmitDate)
+	require.Equal(t, "2310171201", delivered.receipt.DoneDate)
+
+	// The receipted_message_id and message_state parameters win over the receipt text.
+	withTLV := newTestDeliverSM(t, data.SM_SMSC_DLV_RCPT_TYPE, "id:0 stat:ENROUTE err:000")
+	withTLV.RegisterOptionalParam(pdu.Field{Tag: pdu.TagReceiptedMessageID, Data: []byte("msg-8\x00")})
+	withTLV.RegisterOptionalParam(pdu.Field{Tag: pdu.TagMessageStateOption, Data: []byte{5}})
+	smsc.deliver(t, withTLV)
+
+	delivered = handler.next(t)
+	require.Equal(t, "msg-8", delivered.receipt.MessageID)
+	require.Equal(t, ReceiptStateUndeliverable, delivered.receipt.State)
+}
+
+func TestInboundMessages(t *testing.T) {
+	smsc := newTestSMSC(t)
+	handler := &recordingHandler{received: make(chan receivedDelivery, 4)}
+	router := newDeliveryRouter(context.Background(), handler, time.Minute, nil)
+	router.setRoute("partition|smsc|system", "route-smpp")
+	newTestBindPool(t, smsc, 1, router)
+
+	smsc.deliver(t, newTestDeliverSM(t, data.SM_ESM_DEFAULT, "STOP"))
+
+	delivered := handler.next(t)
+	require.Equal(t, "route-smpp", delivered.routeID)
+	require.NotNil(t, delivered.message)
+	require.Equal(t, "STOP", delivered.message.Text)
+	require.Equal(t, "254700000001", delivered.message.Source)
+	require.Equal(t, "20880", delivered.message.Destination)
+	require.Equal(t, 1, delivered.message.Parts)
+
+	// Parts of a concatenated message are held until all of them arrived, in any order.
+	for _, part := range []struct {
+		num  byte
+		text string
+	}{{2, "world"}, {1, "hello "}} {
+		deliver := newTestDeliverSM(t, data.SM_UDH_GSM, part.text)
+		deliver.Message.SetUDH(pdu.UDH{pdu.NewIEConcatMessage(2, part.num, 42)})
+		smsc.deliver(t, deliver)
+	}
+
+	delivered = handler.next(t)
+	require.Equal(t, "hello world", delivered.message.Text)
+	require.Equal(t, 2, delivered.message.Parts)
+
+	select {
+	case extra := <-handler.received:
+		require.FailNow(t, "parts must not be delivered on their own", "%+v", extra)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestMessageAssemblerExpiry(t *testing.T) {
+	assembler := newMessageAssembler(10 * time.Millisecond)
+
+	_, complete := assembler.add("ref", 2, 1, "hello ")
+	require.False(t, complete)
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, complete = assembler.add("ref", 2, 2, "world")
+	require.False(t, complete, "parts held past the expiry are dropped")
+
+	text, complete := assembler.add("ref", 2, 1, "hello ")
+	require.True(t, complete)
+	require.Equal(t, "hello world", text)
+}
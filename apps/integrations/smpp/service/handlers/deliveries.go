@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	"buf.build/gen/go/antinvestor/notification/connectrpc/go/notification/v1/notificationv1connect"
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+	"buf.build/gen/go/antinvestor/profile/connectrpc/go/profile/v1/profilev1connect"
+	profilev1 "buf.build/gen/go/antinvestor/profile/protocolbuffers/go/profile/v1"
+	"connectrpc.com/connect"
+	"github.com/antinvestor/service-notification/apps/integrations/smpp/service/client"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const notificationTypeSMS = "sms"
+
+// DeliveryServer reports what the SMSC delivers over the binds to the notification service.
+type DeliveryServer struct {
+	ProfileCli      profilev1connect.ProfileServiceClient
+	NotificationCli notificationv1connect.NotificationServiceClient
+}
+
+func NewDeliveryServer(
+	profileCli profilev1connect.ProfileServiceClient,
+	notificationCli notificationv1connect.NotificationServiceClient,
+) *DeliveryServer {
+	return &DeliveryServer{
+		ProfileCli:      profileCli,
+		NotificationCli: notificationCli,
+	}
+}
+
+// receiptStatus maps a delivery receipt state onto the notification status it stands for
+// and whether further receipts are still expected for the message.
+func receiptStatus(state string) (commonv1.STATUS, commonv1.STATE) {
+	switch state {
+	case client.ReceiptStateDelivered:
+		return commonv1.STATUS_SUCCESSFUL, commonv1.STATE_INACTIVE
+	case client.ReceiptStateEnroute, client.ReceiptStateAccepted:
+		return commonv1.STATUS_QUEUED, commonv1.STATE_ACTIVE
+	case client.ReceiptStateExpired, client.ReceiptStateDeleted,
+		client.ReceiptStateUndeliverable, client.ReceiptStateRejected:
+		return commonv1.STATUS_FAILED, commonv1.STATE_INACTIVE
+	default:
+		return commonv1.STATUS_UNKNOWN, commonv1.STATE_ACTIVE
+	}
+}
+
+// HandleReceipt updates the notification the receipt reports on, found by the message id
+// the SMSC returned when it was submitted.
+func (ds *DeliveryServer) HandleReceipt(ctx context.Context, routeID string, receipt *client.DeliveryReceipt) error {
+
+	status, state := receiptStatus(receipt.State)
+
+	extraData := map[string]any{
+		"stat":                       receipt.State,
+		constants.StatusExtraRouteID: routeID,
+		constants.StatusExtraStep:    constants.StatusStepDeliveryReport,
+	}
+	if receipt.Error != "" {
+		extraData["err"] = receipt.Error
+	}
+	if receipt.SubmitDate != "" {
+		extraData["submit_date"] = receipt.SubmitDate
+	}
+	if receipt.DoneDate != "" {
+		extraData["done_date"] = receipt.DoneDate
+	}
+	if status == commonv1.STATUS_FAILED {
+		extraData["error"] = fmt.Sprintf("SMSC reported %s, error %s", receipt.State, receipt.Error)
+	}
+
+	extra, _ := structpb.NewStruct(extraData)
+	_, err := ds.NotificationCli.StatusUpdate(ctx, connect.NewRequest(&commonv1.StatusUpdateRequest{
+		Id:         "",
+		State:      state,
+		Status:     status,
+		ExternalId: receipt.MessageID,
+		Extras:     extra,
+	}))
+	return err
+}
+
+// HandleMessage passes a message a subscriber sent to one of the route's addresses
+// on to the notification service as an inbound notification.
+func (ds *DeliveryServer) HandleMessage(ctx context.Context, routeID string, message *client.InboundMessage) error {
+
+	if message.Source == "" || message.Destination == "" {
+		return fmt.Errorf("incoming message is missing the sender or the destination")
+	}
+
+	source, err := ds.resolveContactLink(ctx, message.Source)
+	if err != nil {
+		return err
+	}
+
+	recipient, err := ds.resolveContactLink(ctx, message.Destination)
+	if err != nil {
+		return err
+	}
+
+	messagePayload, _ := structpb.NewStruct(map[string]any{
+		"from":  message.Source,
+		"to":    message.Destination,
+		"parts": fmt.Sprintf("%d", message.Parts),
+	})
+
+	stream, err := ds.NotificationCli.Receive(ctx, connect.NewRequest(&notificationv1.ReceiveRequest{
+		Data: []*notificationv1.Notification{{
+			Source:    source,
+			Recipient: recipient,
+			Type:      notificationTypeSMS,
+			Data:      message.Text,
+			Payload:   messagePayload,
+			RouteId:   routeID,
+			OutBound:  false,
+		}},
+	}))
+	if err != nil {
+		return err
+	}
+
+	for stream.Receive() {
+		// Only the acknowledgement is needed
+	}
+	return stream.Err()
+}
+
+// resolveContactLink links a phone number or shortcode to the profile that owns it.
+// Numbers not yet known to the profile service are passed on with just their detail.
+func (ds *DeliveryServer) resolveContactLink(ctx context.Context, detail string) (*commonv1.ContactLink, error) {
+
+	link := &commonv1.ContactLink{Detail: detail}
+
+	resp, err := ds.ProfileCli.GetByContact(ctx, connect.NewRequest(&profilev1.GetByContactRequest{Contact: detail}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			return link, nil
+		}
+		return nil, err
+	}
+
+	profile := resp.Msg.GetData()
+	link.ProfileId = profile.GetId()
+	for _, contact := range profile.GetContacts() {
+		if contact.GetDetail() == detail {
+			link.ContactId = contact.GetId()
+			break
+		}
+	}
+	return link, nil
+}
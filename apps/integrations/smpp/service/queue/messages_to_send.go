@@ -0,0 +1,164 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+	"github.com/antinvestor/service-notification/apps/integrations/smpp/service/client"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/antinvestor/service-notification/pkg/events"
+	"github.com/antinvestor/service-notification/pkg/sms"
+	"github.com/linxGnu/gosmpp/data"
+	frameEvents "github.com/pitabwire/frame/v2/events"
+	"github.com/pitabwire/frame/v2/queue"
+	"github.com/pitabwire/util"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type messageToSend struct {
+	eventsMan frameEvents.Manager
+	smppCli   *client.Client
+}
+
+func NewMessageToSend(
+	eventsMan frameEvents.Manager,
+	smppCli *client.Client,
+) queue.SubscribeWorker {
+	return &messageToSend{
+		eventsMan: eventsMan,
+		smppCli:   smppCli,
+	}
+}
+
+func (ms *messageToSend) Handle(ctx context.Context, headers map[string]string, payload []byte) error {
+
+	log := util.Log(ctx).WithField("type", "smpp.message.send")
+	defer log.Release()
+	log.Debug("queue handler started")
+
+	notification := &notificationv1.Notification{}
+
+	err := proto.Unmarshal(payload, notification)
+	if err != nil {
+		log.WithError(err).Error("failed to unmarshal notification")
+		return nil
+	}
+
+	segmentation := sms.Segment(notification.GetData())
+
+	log = log.WithField("notification_id", notification.GetId())
+	log.WithFields(map[string]any{
+		"recipient":      notification.GetRecipient().GetProfileId(),
+		"sender":         notification.GetSource().GetProfileId(),
+		"message_length": len(notification.GetData()),
+		"segments":       segmentation.Segments,
+		"encoding":       segmentation.Encoding,
+	}).Debug("processing SMPP SMS message")
+
+	result, err := ms.smppCli.Send(ctx, headers, notification)
+	if err != nil {
+		log.WithError(err).Error("SMPP submit failed")
+	}
+
+	statusReq, retryErr := submitStatus(notification.GetId(), segmentation, result, err)
+	ms.emitStatus(ctx, statusReq)
+	if retryErr != nil || err != nil {
+		return retryErr
+	}
+
+	log.WithFields(map[string]any{
+		"message_id":     statusReq.GetExternalId(),
+		"command_status": result.Status.String(),
+		"parts":          result.Parts,
+	}).Info("SMS submitted via SMPP")
+	return nil
+}
+
+// submitStatus maps the outcome of submitting a notification onto the status reported for it.
+// A notification is only handed to another route, or submitted again when the SMSC throttles,
+// while none of its parts was accepted, otherwise the recipient would get those parts twice.
+// The returned error has the queue deliver the message again.
+func submitStatus(notificationID string, segmentation sms.Segmentation, result *client.SubmitResult, err error) (*commonv1.StatusUpdateRequest, error) {
+
+	extrasMap := map[string]any{
+		constants.StatusExtraStep:        constants.StatusStepSubmit,
+		constants.StatusExtraSMSSegments: segmentation.Segments,
+		constants.StatusExtraSMSEncoding: string(segmentation.Encoding),
+	}
+
+	externalID := ""
+	accepted := 0
+	if result != nil {
+		accepted = len(result.MessageIDs)
+		extrasMap["parts"] = fmt.Sprintf("%d", result.Parts)
+		if accepted > 0 {
+			externalID = result.MessageIDs[0]
+		}
+		if accepted > 1 {
+			messageIDs := make([]any, 0, accepted)
+			for _, id := range result.MessageIDs {
+				messageIDs = append(messageIDs, id)
+			}
+			extrasMap[constants.StatusExtraMessageIDs] = messageIDs
+		}
+		if err == nil {
+			extrasMap["command_status"] = result.Status.String()
+		}
+	}
+
+	status := func(state commonv1.STATE, status commonv1.STATUS) *commonv1.StatusUpdateRequest {
+		extra, _ := structpb.NewStruct(extrasMap)
+		return &commonv1.StatusUpdateRequest{
+			Id:         notificationID,
+			State:      state,
+			Status:     status,
+			ExternalId: externalID,
+			Extras:     extra,
+		}
+	}
+
+	failure := ""
+	reroutable := false
+	switch {
+	case errors.Is(err, client.ErrSubmitTimeout):
+		// The SMSC may have taken the message, only a delivery receipt can tell.
+		extrasMap["error"] = err.Error()
+		return status(commonv1.STATE_ACTIVE, commonv1.STATUS_UNKNOWN), nil
+
+	case err != nil:
+		// An SMSC that cannot be reached leaves the message for another route.
+		failure = err.Error()
+		reroutable = errors.Is(err, client.ErrSMPPBind)
+
+	case result.Status == data.ESME_ROK:
+		return status(commonv1.STATE_ACTIVE, commonv1.STATUS_QUEUED), nil
+
+	case client.IsThrottled(result.Status) && accepted == 0:
+		return status(commonv1.STATE_ACTIVE, commonv1.STATUS_UNKNOWN),
+			fmt.Errorf("SMSC is throttling submits: %s", result.Status)
+
+	default:
+		failure = result.Status.String()
+		reroutable = client.IsReroutable(result.Status)
+	}
+
+	extrasMap["error"] = failure
+	if accepted > 0 {
+		extrasMap["partial"] = true
+		extrasMap["parts_accepted"] = fmt.Sprintf("%d", accepted)
+	} else if reroutable {
+		extrasMap[constants.StatusExtraReroute] = true
+	}
+	return status(commonv1.STATE_INACTIVE, commonv1.STATUS_FAILED), nil
+}
+
+func (ms *messageToSend) emitStatus(ctx context.Context, statusReq *commonv1.StatusUpdateRequest) {
+	err := ms.eventsMan.Emit(ctx, events.NotificationStatusUpdateEvent, statusReq)
+	if err != nil {
+		util.Log(ctx).WithError(err).Warn("could not update status on notification service")
+	}
+}
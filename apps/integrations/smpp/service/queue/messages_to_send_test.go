@@ -0,0 +1,124 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	"github.com/antinvestor/service-notification/apps/integrations/smpp/service/client"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/antinvestor/service-notification/pkg/sms"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitStatus(t *testing.T) {
+	segmentation := sms.Segment("hello")
+
+	tests := []struct {
+		name       string
+		result     *client.SubmitResult
+		err        error
+		wantState  commonv1.STATE
+		wantStatus commonv1.STATUS
+		wantID     string
+		wantRetry  bool
+		reroute    bool
+		partial    bool
+	}{
+		{
+			name:       "accepted",
+			result:     &client.SubmitResult{MessageIDs: []string{"msg-1"}, Status: data.ESME_ROK, Parts: 1},
+			wantState:  commonv1.STATE_ACTIVE,
+			wantStatus: commonv1.STATUS_QUEUED,
+			wantID:     "msg-1",
+		},
+		{
+			name:       "no phone number",
+			err:        errors.New("SMS recipient has no phone number"),
+			wantState:  commonv1.STATE_INACTIVE,
+			wantStatus: commonv1.STATUS_FAILED,
+		},
+		{
+			name:       "smsc unreachable",
+			result:     &client.SubmitResult{Parts: 2},
+			err:        fmt.Errorf("%w: connection refused", client.ErrSMPPBind),
+			wantState:  commonv1.STATE_INACTIVE,
+			wantStatus: commonv1.STATUS_FAILED,
+			reroute:    true,
+		},
+		{
+			name:       "bind lost after a part was accepted",
+			result:     &client.SubmitResult{MessageIDs: []string{"msg-1"}, Status: data.ESME_ROK, Parts: 2},
+			err:        fmt.Errorf("%w: connection reset", client.ErrSMPPBind),
+			wantState:  commonv1.STATE_INACTIVE,
+			wantStatus: commonv1.STATUS_FAILED,
+			wantID:     "msg-1",
+			partial:    true,
+		},
+		{
+			name:       "submit timed out",
+			result:     &client.SubmitResult{Parts: 1},
+			err:        client.ErrSubmitTimeout,
+			wantState:  commonv1.STATE_ACTIVE,
+			wantStatus: commonv1.STATUS_UNKNOWN,
+		},
+		{
+			name:       "throttled",
+			result:     &client.SubmitResult{Status: data.ESME_RTHROTTLED, Parts: 1},
+			wantState:  commonv1.STATE_ACTIVE,
+			wantStatus: commonv1.STATUS_UNKNOWN,
+			wantRetry:  true,
+		},
+		{
+			name:       "throttled after a part was accepted",
+			result:     &client.SubmitResult{MessageIDs: []string{"msg-1"}, Status: data.ESME_RTHROTTLED, Parts: 2},
+			wantState:  commonv1.STATE_INACTIVE,
+			wantStatus: commonv1.STATUS_FAILED,
+			wantID:     "msg-1",
+			partial:    true,
+		},
+		{
+			name:       "smsc refused",
+			result:     &client.SubmitResult{Status: data.ESME_RSYSERR, Parts: 1},
+			wantState:  commonv1.STATE_INACTIVE,
+			wantStatus: commonv1.STATUS_FAILED,
+			reroute:    true,
+		},
+		{
+			name:       "smsc refused a later part",
+			result:     &client.SubmitResult{MessageIDs: []string{"msg-1", "msg-2"}, Status: data.ESME_RSYSERR, Parts: 3},
+			wantState:  commonv1.STATE_INACTIVE,
+			wantStatus: commonv1.STATUS_FAILED,
+			wantID:     "msg-1",
+			partial:    true,
+		},
+		{
+			name:       "invalid destination",
+			result:     &client.SubmitResult{Status: data.ESME_RINVDSTADR, Parts: 1},
+			wantState:  commonv1.STATE_INACTIVE,
+			wantStatus: commonv1.STATUS_FAILED,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statusReq, retryErr := submitStatus("notification-1", segmentation, tt.result, tt.err)
+			require.Equal(t, tt.wantRetry, retryErr != nil)
+
+			require.Equal(t, "notification-1", statusReq.GetId())
+			require.Equal(t, tt.wantState, statusReq.GetState())
+			require.Equal(t, tt.wantStatus, statusReq.GetStatus())
+			require.Equal(t, tt.wantID, statusReq.GetExternalId())
+
+			extras := statusReq.GetExtras().AsMap()
+			require.Equal(t, constants.StatusStepSubmit, extras[constants.StatusExtraStep])
+			require.Equal(t, tt.reroute, extras[constants.StatusExtraReroute] == true)
+			require.Equal(t, tt.partial, extras["partial"] == true)
+			if tt.partial {
+				require.Equal(t, fmt.Sprintf("%d", len(tt.result.MessageIDs)), extras["parts_accepted"])
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+
+	"buf.build/gen/go/antinvestor/notification/connectrpc/go/notification/v1/notificationv1connect"
+	"buf.build/gen/go/antinvestor/settingz/connectrpc/go/settings/v1/settingsv1connect"
+	apis "github.com/antinvestor/common/v2"
+	"github.com/antinvestor/common/v2/connection"
+	"github.com/antinvestor/common/v2/servicecatalog"
+	aconfig "github.com/antinvestor/service-notification/apps/integrations/webhook/config"
+	"github.com/antinvestor/service-notification/apps/integrations/webhook/service/client"
+	"github.com/antinvestor/service-notification/apps/integrations/webhook/service/queues"
+	"github.com/antinvestor/service-notification/pkg/events"
+	"github.com/pitabwire/frame/v2"
+	"github.com/pitabwire/frame/v2/config"
+	"github.com/pitabwire/util"
+)
+
+func main() {
+
+	ctx := context.Background()
+
+	cfg, err := config.LoadWithOIDC[aconfig.WebhookConfig](ctx)
+	if err != nil {
+		util.Log(ctx).With("err", err).Error("could not process configs")
+		return
+	}
+
+	if cfg.Name() == "" {
+		cfg.ServiceName = "integration_notification_webhook"
+	}
+
+	ctx, svc := frame.NewServiceWithContext(ctx, frame.WithConfig(&cfg))
+	defer svc.Stop(ctx)
+
+	logger := svc.Log(ctx)
+
+	eventsMan := svc.EventsManager()
+
+	notificationCli, err := setupNotificationClient(ctx, cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("could not setup notification client")
+	}
+
+	settingsCli, err := setupSettingsClient(ctx, cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("could not setup settings client")
+	}
+
+	webhookCli, err := client.NewClient(&cfg, settingsCli)
+	if err != nil {
+		logger.WithError(err).Fatal("could not setup webhook client")
+	}
+
+	messageHandler := queues.NewMessageToSend(eventsMan, webhookCli)
+
+	serviceOptions := []frame.Option{
+		frame.WithRegisterEvents(events.NewNotificationStatusUpdate(ctx, notificationCli)),
+		frame.WithRegisterSubscriber(cfg.QueueWebhookDequeueName, cfg.QueueWebhookDequeueURI, messageHandler),
+	}
+
+	svc.Init(ctx, serviceOptions...)
+
+	logger.Info("Initiating webhook integration server operations")
+	err = svc.Run(ctx, "")
+	if err != nil {
+		logger.WithError(err).Error("could not run Server")
+	}
+}
+
+// setupNotificationClient creates and configures the notification client.
+func setupNotificationClient(
+	ctx context.Context,
+	cfg aconfig.WebhookConfig) (notificationv1connect.NotificationServiceClient, error) {
+	return connection.NewServiceClient(ctx, &cfg, apis.ServiceTarget{
+		Endpoint:              cfg.NotificationServiceURI,
+		WorkloadAPITargetPath: cfg.NotificationServiceWorkloadAPITargetPath,
+		ServiceID:             servicecatalog.ServiceNotification,
+	}, notificationv1connect.NewNotificationServiceClient)
+}
+
+// setupSettingsClient creates and configures the settings client.
+func setupSettingsClient(
+	ctx context.Context,
+	cfg aconfig.WebhookConfig) (settingsv1connect.SettingsServiceClient, error) {
+	return connection.NewServiceClient(ctx, &cfg, apis.ServiceTarget{
+		Endpoint:              cfg.SettingsServiceURI,
+		WorkloadAPITargetPath: cfg.SettingsServiceWorkloadAPITargetPath,
+		ServiceID:             servicecatalog.ServiceSettings,
+	}, settingsv1connect.NewSettingsServiceClient)
+}
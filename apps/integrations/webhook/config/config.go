@@ -0,0 +1,37 @@
+package config
+
+import (
+	"time"
+
+	"github.com/pitabwire/frame/v2/config"
+)
+
+type WebhookConfig struct {
+	config.ConfigurationDefault
+
+	SettingsIntegrationName string `envDefault:"Webhook" env:"SETTINGS_INTEGRATION_NAME"`
+	SettingsIntegrationID   string `envDefault:"notification.webhook" env:"SETTINGS_INTEGRATION_ID"`
+
+	SettingsServiceURI                       string `envDefault:"127.0.0.1:7005" env:"SETTINGS_SERVICE_URI"`
+	NotificationServiceURI                   string `envDefault:"127.0.0.1:7005" env:"NOTIFICATION_SERVICE_URI"`
+	SettingsServiceWorkloadAPITargetPath     string `envDefault:"/ns/profile/sa/service-settings" env:"SETTINGS_SERVICE_WORKLOAD_API_TARGET_PATH"`
+	NotificationServiceWorkloadAPITargetPath string `envDefault:"/ns/notifications/sa/service-notification" env:"NOTIFICATION_SERVICE_WORKLOAD_API_TARGET_PATH"`
+
+	// Webhook queue configuration
+	QueueWebhookDequeueName string `envDefault:"webhook.natifications.dequeue" env:"QUEUE_NOTIFICATION_WEBHOOK_DEQUEUE_NAME"`
+	QueueWebhookDequeueURI  string `envDefault:"mem://webhook.natifications.de.queue" env:"QUEUE_NOTIFICATION_WEBHOOK_DEQUEUE_URI"`
+
+	// Endpoints are settings of the partition or of the recipient profile, keyed by this name
+	SettingsWebhookEndpointName string        `envDefault:"webhook_endpoint" env:"SETTINGS_WEBHOOK_ENDPOINT_NAME"`
+	WebhookEndpointCacheTTL     time.Duration `envDefault:"5m" env:"WEBHOOK_ENDPOINT_CACHE_TTL"`
+
+	// Delivery, failed posts answered with a 5xx, 408 or 429 are retried with exponential backoff
+	WebhookRequestTimeout   time.Duration `envDefault:"15s" env:"WEBHOOK_REQUEST_TIMEOUT"`
+	WebhookMaxAttempts      int           `envDefault:"4" env:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookRetryBackoff     time.Duration `envDefault:"1s" env:"WEBHOOK_RETRY_BACKOFF"`
+	WebhookRetryMaxBackoff  time.Duration `envDefault:"30s" env:"WEBHOOK_RETRY_MAX_BACKOFF"`
+	WebhookMaxResponseBytes int           `envDefault:"4096" env:"WEBHOOK_MAX_RESPONSE_BYTES"`
+
+	// Endpoints must be https and public, private networks are allowed for receivers deployed alongside
+	WebhookAllowPrivateNetworks bool `envDefault:"false" env:"WEBHOOK_ALLOW_PRIVATE_NETWORKS"`
+}
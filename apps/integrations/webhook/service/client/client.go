@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+	"buf.build/gen/go/antinvestor/settingz/connectrpc/go/settings/v1/settingsv1connect"
+	settingsv1 "buf.build/gen/go/antinvestor/settingz/protocolbuffers/go/settings/v1"
+	"connectrpc.com/connect"
+	"github.com/antinvestor/service-notification/apps/integrations/webhook/config"
+	"github.com/antinvestor/service-notification/pkg/apperrors"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Endpoint is where notifications are posted and the secret their requests are signed with.
+type Endpoint struct {
+	URL     string            `json:"url"`
+	Secret  string            `json:"secret"`
+	Headers map[string]string `json:"headers"`
+}
+
+// merge lays the fields set on a more specific endpoint over this one. An endpoint posting
+// somewhere else replaces this one whole, the secret and headers meant for one receiver are
+// never sent to another.
+func (e *Endpoint) merge(other *Endpoint) {
+	if other.URL != "" && other.URL != e.URL {
+		*e = Endpoint{URL: other.URL, Secret: other.Secret}
+		for name, value := range other.Headers {
+			if e.Headers == nil {
+				e.Headers = map[string]string{}
+			}
+			e.Headers[name] = value
+		}
+		return
+	}
+	if other.Secret != "" {
+		e.Secret = other.Secret
+	}
+	for name, value := range other.Headers {
+		if e.Headers == nil {
+			e.Headers = map[string]string{}
+		}
+		e.Headers[name] = value
+	}
+}
+
+type cachedEndpoint struct {
+	endpoint  *Endpoint
+	fetchedAt time.Time
+}
+
+type Client struct {
+	cfg         *config.WebhookConfig
+	settingsCli settingsv1connect.SettingsServiceClient
+	httpClient  *http.Client
+	endpoints   sync.Map
+}
+
+func NewClient(cfg *config.WebhookConfig, settingsCli settingsv1connect.SettingsServiceClient) (*Client, error) {
+
+	// Endpoints are checked where they resolve to as they are dialed, a proxy would be dialed instead.
+	dialer := &net.Dialer{
+		Timeout: cfg.WebhookRequestTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			return checkAddress(address, cfg.WebhookAllowPrivateNetworks)
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Client{
+		cfg:         cfg,
+		settingsCli: settingsCli,
+		httpClient: &http.Client{
+			Timeout:   cfg.WebhookRequestTimeout,
+			Transport: transport,
+			// A redirected POST turns into a GET, endpoints have to be configured where they are.
+			CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}, nil
+}
+
+// Send posts the notification as JSON to the endpoint configured for it.
+func (ms *Client) Send(ctx context.Context, headers map[string]string, notification *notificationv1.Notification) (*Delivery, error) {
+
+	endpoint, err := ms.resolveEndpoint(ctx, headers, notification)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := protojson.Marshal(notification)
+	if err != nil {
+		return nil, apperrors.ErrInvalidFormat.Extend(fmt.Sprintf("notification could not be encoded: %v", err))
+	}
+
+	return ms.deliver(ctx, endpoint, notification.GetId(), body)
+}
+
+// resolveEndpoint works out where a notification goes. A connection named on the route is
+// looked up like the other integrations do, otherwise the endpoint of the partition applies
+// with whatever the recipient profile configured on top of it.
+func (ms *Client) resolveEndpoint(ctx context.Context, headers map[string]string, notification *notificationv1.Notification) (*Endpoint, error) {
+
+	partitionID := headers[constants.PartitionIDHeaderName]
+	recipientID := notification.GetRecipient().GetProfileId()
+	connection, named := headers[constants.APIConnectionCredentialsHeaderName]
+
+	key := strings.Join([]string{partitionID, connection, recipientID}, "|")
+	if cached, ok := ms.endpoints.Load(key); ok {
+		entry := cached.(*cachedEndpoint)
+		if time.Since(entry.fetchedAt) < ms.cfg.WebhookEndpointCacheTTL {
+			return entry.endpoint, nil
+		}
+	}
+
+	endpoint := &Endpoint{}
+	if named {
+		connectionEndpoint, err := ms.fetchEndpoint(ctx, connection, ms.cfg.SettingsIntegrationID)
+		if err != nil {
+			return nil, err
+		}
+		if connectionEndpoint == nil {
+			return nil, apperrors.ErrMissingRequiredData.Extend(fmt.Sprintf("webhook connection %s has no settings", connection))
+		}
+		endpoint.merge(connectionEndpoint)
+	} else {
+		for _, objectID := range []string{partitionID, recipientID} {
+			if objectID == "" {
+				continue
+			}
+			objectEndpoint, err := ms.fetchEndpoint(ctx, ms.cfg.SettingsWebhookEndpointName, objectID)
+			if err != nil {
+				return nil, err
+			}
+			if objectEndpoint != nil {
+				endpoint.merge(objectEndpoint)
+			}
+		}
+	}
+
+	err := validateEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	ms.endpoints.Store(key, &cachedEndpoint{endpoint: endpoint, fetchedAt: time.Now()})
+	return endpoint, nil
+}
+
+// fetchEndpoint reads an endpoint setting, nil when there is none.
+func (ms *Client) fetchEndpoint(ctx context.Context, name, objectID string) (*Endpoint, error) {
+
+	settingReq := &settingsv1.GetRequest{
+		Key: &settingsv1.Setting{
+			Name:     name,
+			Object:   ms.cfg.SettingsIntegrationName,
+			ObjectId: objectID,
+			Lang:     "",
+			Module:   ms.cfg.SettingsIntegrationName,
+		},
+	}
+
+	settingResp, err := ms.settingsCli.Get(ctx, connect.NewRequest(settingReq))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	value := settingResp.Msg.GetData().GetValue()
+	if value == "" {
+		return nil, nil
+	}
+
+	endpoint := &Endpoint{}
+	err = json.Unmarshal([]byte(value), endpoint)
+	if err != nil {
+		return nil, apperrors.ErrInvalidFormat.Extend(fmt.Sprintf("webhook setting %s of %s is not valid: %v", name, objectID, err))
+	}
+	return endpoint, nil
+}
+
+func validateEndpoint(endpoint *Endpoint) error {
+	if endpoint.URL == "" {
+		return apperrors.ErrMissingRequiredData.Extend("no webhook endpoint is configured")
+	}
+
+	endpointURL, err := url.Parse(endpoint.URL)
+	if err != nil || endpointURL.Scheme != "https" || endpointURL.Host == "" {
+		return apperrors.ErrInvalidFormat.Extend(fmt.Sprintf("webhook endpoint %q is not an https url", endpoint.URL))
+	}
+
+	if endpoint.Secret == "" {
+		return apperrors.ErrMissingRequiredData.Extend("webhook endpoint has no signing secret")
+	}
+	return nil
+}
+
+// ErrBlockedAddress is returned when a webhook endpoint resolves to an address notifications
+// may not be posted to.
+var ErrBlockedAddress = errors.New("webhook endpoint address is not allowed")
+
+// carrierGradeNAT is the shared address space of RFC 6598, private to the provider network.
+var carrierGradeNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// checkAddress refuses the addresses of this host, of the networks it runs in and of the
+// cloud metadata services, so tenants can not reach them through their endpoints. Private
+// networks are reachable when allowed, link-local ones where metadata services live never are.
+func checkAddress(address string, allowPrivate bool) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+
+	ip := net.ParseIP(host)
+	switch {
+	case ip == nil, ip.IsUnspecified(), ip.IsMulticast(), ip.IsInterfaceLocalMulticast(),
+		ip.IsLinkLocalUnicast(), ip.IsLinkLocalMulticast():
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	case !allowPrivate && (ip.IsLoopback() || ip.IsPrivate() || carrierGradeNAT.Contains(ip)):
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	return nil
+}
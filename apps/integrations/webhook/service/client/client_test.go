@@ -0,0 +1,324 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+	"buf.build/gen/go/antinvestor/settingz/connectrpc/go/settings/v1/settingsv1connect"
+	settingsv1 "buf.build/gen/go/antinvestor/settingz/protocolbuffers/go/settings/v1"
+	"connectrpc.com/connect"
+	"github.com/antinvestor/service-notification/apps/integrations/webhook/config"
+	"github.com/antinvestor/service-notification/pkg/apperrors"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// testSettings answers endpoint lookups from a map of setting values keyed by object id.
+type testSettings struct {
+	settingsv1connect.SettingsServiceClient
+	values map[string]string
+}
+
+func (s *testSettings) Get(_ context.Context, req *connect.Request[settingsv1.GetRequest]) (*connect.Response[settingsv1.GetResponse], error) {
+	value, ok := s.values[req.Msg.GetKey().GetObjectId()]
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("setting not found"))
+	}
+
+	encoded, _ := json.Marshal(map[string]any{"data": map[string]any{"value": value}})
+	resp := &settingsv1.GetResponse{}
+	if err := protojson.Unmarshal(encoded, resp); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func endpointSetting(t *testing.T, endpoint Endpoint) string {
+	value, err := json.Marshal(endpoint)
+	require.NoError(t, err)
+	return string(value)
+}
+
+// newTestClient creates a client trusting the given test servers. They listen on the
+// loopback interface, so private networks are allowed.
+func newTestClient(t *testing.T, values map[string]string, servers ...*httptest.Server) *Client {
+	cli, err := NewClient(&config.WebhookConfig{
+		SettingsIntegrationName:     "Webhook",
+		SettingsIntegrationID:       "notification.webhook",
+		SettingsWebhookEndpointName: "webhook_endpoint",
+		WebhookEndpointCacheTTL:     time.Minute,
+		WebhookRequestTimeout:       5 * time.Second,
+		WebhookMaxAttempts:          3,
+		WebhookRetryBackoff:         time.Millisecond,
+		WebhookRetryMaxBackoff:      10 * time.Millisecond,
+		WebhookMaxResponseBytes:     1024,
+		WebhookAllowPrivateNetworks: true,
+	}, &testSettings{values: values})
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	for _, server := range servers {
+		roots.AddCert(server.Certificate())
+	}
+	cli.httpClient.Transport.(*http.Transport).TLSClientConfig = &tls.Config{RootCAs: roots}
+	return cli
+}
+
+func testNotification() *notificationv1.Notification {
+	return &notificationv1.Notification{
+		Id:        "notification-1",
+		Type:      "webhook",
+		Data:      "order shipped",
+		Recipient: &commonv1.ContactLink{ProfileId: "profile-1"},
+	}
+}
+
+func testHeaders() map[string]string {
+	return map[string]string{constants.PartitionIDHeaderName: "partition-1"}
+}
+
+func TestSendSignsNotification(t *testing.T) {
+	received := make(chan *notificationv1.Notification, 1)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		require.NoError(t, err)
+		require.Equal(t, Sign("partition-secret", timestamp, body), r.Header.Get(HeaderSignature))
+		require.Equal(t, "notification-1", r.Header.Get(HeaderID))
+		require.Equal(t, "1", r.Header.Get(HeaderAttempt))
+		require.Equal(t, "token", r.Header.Get("Authorization"))
+
+		notification := &notificationv1.Notification{}
+		require.NoError(t, protojson.Unmarshal(body, notification))
+		received <- notification
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	cli := newTestClient(t, map[string]string{
+		"partition-1": endpointSetting(t, Endpoint{URL: server.URL, Secret: "partition-secret", Headers: map[string]string{"Authorization": "token"}}),
+	}, server)
+
+	delivery, err := cli.Send(context.Background(), testHeaders(), testNotification())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusAccepted, delivery.StatusCode)
+	require.Equal(t, 1, delivery.Attempts)
+
+	notification := <-received
+	require.Equal(t, "notification-1", notification.GetId())
+	require.Equal(t, "order shipped", notification.GetData())
+}
+
+func TestSendUsesRecipientEndpoint(t *testing.T) {
+	var partitionCalls, recipientCalls atomic.Int32
+
+	partitionServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		partitionCalls.Add(1)
+	}))
+	defer partitionServer.Close()
+
+	recipientServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recipientCalls.Add(1)
+
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		// A recipient posting elsewhere signs with its own secret and gets none of the partition headers.
+		require.Equal(t, Sign("recipient-secret", timestamp, body), r.Header.Get(HeaderSignature))
+		require.Empty(t, r.Header.Get("Authorization"))
+	}))
+	defer recipientServer.Close()
+
+	cli := newTestClient(t, map[string]string{
+		"partition-1": endpointSetting(t, Endpoint{URL: partitionServer.URL, Secret: "partition-secret",
+			Headers: map[string]string{"Authorization": "partition-token"}}),
+		"profile-1": endpointSetting(t, Endpoint{URL: recipientServer.URL, Secret: "recipient-secret"}),
+	}, partitionServer, recipientServer)
+
+	_, err := cli.Send(context.Background(), testHeaders(), testNotification())
+	require.NoError(t, err)
+	require.Equal(t, int32(0), partitionCalls.Load())
+	require.Equal(t, int32(1), recipientCalls.Load())
+}
+
+func TestSendRecipientOverrideNeedsItsOwnSecret(t *testing.T) {
+	cli := newTestClient(t, map[string]string{
+		"partition-1": endpointSetting(t, Endpoint{URL: "https://partner.example.com/hooks", Secret: "partition-secret"}),
+		"profile-1":   endpointSetting(t, Endpoint{URL: "https://recipient.example.com/hooks"}),
+	})
+
+	_, err := cli.Send(context.Background(), testHeaders(), testNotification())
+	var appErr *apperrors.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Contains(t, appErr.Error(), "signing secret")
+}
+
+func TestSendRequiresHTTPS(t *testing.T) {
+	cli := newTestClient(t, map[string]string{
+		"partition-1": endpointSetting(t, Endpoint{URL: "http://partner.example.com/hooks", Secret: "secret"}),
+	})
+
+	_, err := cli.Send(context.Background(), testHeaders(), testNotification())
+	var appErr *apperrors.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.BadRequest, appErr.ErrorCode())
+}
+
+func TestSendRefusesPrivateAddresses(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	cli := newTestClient(t, map[string]string{
+		"partition-1": endpointSetting(t, Endpoint{URL: server.URL, Secret: "secret"}),
+	}, server)
+	cli.cfg.WebhookAllowPrivateNetworks = false
+
+	delivery, err := cli.Send(context.Background(), testHeaders(), testNotification())
+	require.Error(t, err)
+	require.Equal(t, 1, delivery.Attempts, "blocked addresses are not retried")
+	require.Equal(t, int32(0), calls.Load())
+
+	var appErr *apperrors.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.Forbidden, appErr.ErrorCode())
+	require.False(t, appErr.IsRetriable())
+}
+
+func TestCheckAddress(t *testing.T) {
+	tests := []struct {
+		address      string
+		allowPrivate bool
+		blocked      bool
+	}{
+		{address: "93.184.216.34:443"},
+		{address: "[2606:2800:220:1:248:1893:25c8:1946]:443"},
+		{address: "127.0.0.1:443", blocked: true},
+		{address: "[::1]:443", blocked: true},
+		{address: "10.0.0.5:443", blocked: true},
+		{address: "172.16.4.1:443", blocked: true},
+		{address: "192.168.1.10:443", blocked: true},
+		{address: "100.64.0.1:443", blocked: true},
+		{address: "[fd00::1]:443", blocked: true},
+		{address: "[::ffff:10.0.0.5]:443", blocked: true},
+		{address: "0.0.0.0:443", blocked: true},
+		{address: "169.254.169.254:80", blocked: true},
+		{address: "169.254.169.254:80", allowPrivate: true, blocked: true},
+		{address: "[fe80::1]:443", allowPrivate: true, blocked: true},
+		{address: "10.0.0.5:443", allowPrivate: true},
+		{address: "127.0.0.1:443", allowPrivate: true},
+	}
+
+	for _, tt := range tests {
+		err := checkAddress(tt.address, tt.allowPrivate)
+		require.Equal(t, tt.blocked, errors.Is(err, ErrBlockedAddress), "%s allowPrivate=%v", tt.address, tt.allowPrivate)
+	}
+}
+
+func TestSendRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		require.Equal(t, "3", r.Header.Get(HeaderAttempt))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cli := newTestClient(t, map[string]string{
+		"partition-1": endpointSetting(t, Endpoint{URL: server.URL, Secret: "secret"}),
+	}, server)
+
+	delivery, err := cli.Send(context.Background(), testHeaders(), testNotification())
+	require.NoError(t, err)
+	require.Equal(t, 3, delivery.Attempts)
+	require.Equal(t, http.StatusOK, delivery.StatusCode)
+}
+
+func TestSendGivesUpOnServerErrors(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cli := newTestClient(t, map[string]string{
+		"partition-1": endpointSetting(t, Endpoint{URL: server.URL, Secret: "secret"}),
+	}, server)
+
+	delivery, err := cli.Send(context.Background(), testHeaders(), testNotification())
+	require.Error(t, err)
+	require.Equal(t, 3, delivery.Attempts)
+	require.Equal(t, int32(3), calls.Load())
+
+	var appErr *apperrors.Error
+	require.ErrorAs(t, err, &appErr)
+	require.True(t, appErr.IsRetriable())
+}
+
+func TestSendDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte("unknown recipient"))
+	}))
+	defer server.Close()
+
+	cli := newTestClient(t, map[string]string{
+		"partition-1": endpointSetting(t, Endpoint{URL: server.URL, Secret: "secret"}),
+	}, server)
+
+	delivery, err := cli.Send(context.Background(), testHeaders(), testNotification())
+	require.Error(t, err)
+	require.Equal(t, 1, delivery.Attempts)
+	require.Equal(t, int32(1), calls.Load())
+	require.Contains(t, err.Error(), "unknown recipient")
+
+	var appErr *apperrors.Error
+	require.ErrorAs(t, err, &appErr)
+	require.False(t, appErr.IsRetriable())
+	require.Equal(t, http.StatusUnprocessableEntity, appErr.ErrorCode())
+}
+
+func TestSendRequiresSignedEndpoint(t *testing.T) {
+	cli := newTestClient(t, map[string]string{
+		"partition-1": endpointSetting(t, Endpoint{URL: "https://partner.example.com/hooks"}),
+	})
+
+	_, err := cli.Send(context.Background(), testHeaders(), testNotification())
+	require.Error(t, err)
+
+	var appErr *apperrors.Error
+	require.ErrorAs(t, err, &appErr)
+	require.False(t, appErr.IsRetriable())
+
+	_, err = newTestClient(t, map[string]string{}).Send(context.Background(), testHeaders(), testNotification())
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.BadRequest, appErr.ErrorCode())
+}
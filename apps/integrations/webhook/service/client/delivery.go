@@ -0,0 +1,151 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/antinvestor/service-notification/pkg/apperrors"
+)
+
+// Headers every webhook request carries. Receivers verify a request by computing the
+// HMAC-SHA256 of "<timestamp>.<body>" with their secret and comparing it to the signature.
+const (
+	HeaderID        = "X-Webhook-Id"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderAttempt   = "X-Webhook-Attempt"
+
+	signaturePrefix = "sha256="
+)
+
+// Delivery reports how a notification was posted to its endpoint.
+type Delivery struct {
+	StatusCode int
+	Attempts   int
+}
+
+// Sign returns the signature of a request body sent at the given unix time.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = fmt.Fprintf(mac, "%d.", timestamp)
+	_, _ = mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// retriable says whether posting again may succeed, endpoints asking us to slow down
+// are retried even though their 429 is not a server error.
+func retriable(err error) bool {
+	var appErr *apperrors.Error
+	if !errors.As(err, &appErr) {
+		return false
+	}
+	return appErr.IsRetriable() || appErr.ErrorCode() == apperrors.TooManyRequests
+}
+
+// backoff is the wait before the given retry, doubling from the configured backoff up to
+// its maximum. An endpoint's Retry-After is honoured as long as it stays under the maximum.
+func (ms *Client) backoff(retry int, retryAfter time.Duration) time.Duration {
+	wait := ms.cfg.WebhookRetryBackoff
+	for i := 1; i < retry && wait < ms.cfg.WebhookRetryMaxBackoff; i++ {
+		wait *= 2
+	}
+	if retryAfter > wait {
+		wait = retryAfter
+	}
+	if wait > ms.cfg.WebhookRetryMaxBackoff {
+		wait = ms.cfg.WebhookRetryMaxBackoff
+	}
+	return wait
+}
+
+// deliver posts the body to the endpoint, retrying failures that may pass on a later attempt.
+func (ms *Client) deliver(ctx context.Context, endpoint *Endpoint, id string, body []byte) (*Delivery, error) {
+	delivery := &Delivery{}
+
+	maxAttempts := max(ms.cfg.WebhookMaxAttempts, 1)
+	for {
+		delivery.Attempts++
+
+		statusCode, retryAfter, err := ms.post(ctx, endpoint, id, body, delivery.Attempts)
+		delivery.StatusCode = statusCode
+		if err == nil {
+			return delivery, nil
+		}
+
+		if !retriable(err) || delivery.Attempts >= maxAttempts {
+			return delivery, err
+		}
+
+		timer := time.NewTimer(ms.backoff(delivery.Attempts, retryAfter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return delivery, err
+		case <-timer.C:
+		}
+	}
+}
+
+// post makes a single signed request, classifying a failure by what it says about retrying.
+func (ms *Client) post(ctx context.Context, endpoint *Endpoint, id string, body []byte, attempt int) (int, time.Duration, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, 0, apperrors.ErrInvalidInput.Extend(fmt.Sprintf("webhook endpoint is not usable: %v", err))
+	}
+
+	timestamp := time.Now().Unix()
+	for name, value := range endpoint.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", id)
+	req.Header.Set(HeaderID, id)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, timestamp, body))
+	req.Header.Set(HeaderAttempt, strconv.Itoa(attempt))
+
+	resp, err := ms.httpClient.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrBlockedAddress) {
+			return 0, 0, apperrors.ErrForbiddenAccess.Extend(err.Error())
+		}
+		var netErr interface{ Timeout() bool }
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return 0, 0, apperrors.ErrIntegrationTimeout.Extend(err.Error())
+		}
+		return 0, 0, apperrors.ErrIntegrationUnreachable.Extend(err.Error())
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, 0, nil
+	}
+
+	// Part of the response is kept to explain the failure, the rest is drained for the connection to be reused.
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, int64(ms.cfg.WebhookMaxResponseBytes)))
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	message := fmt.Sprintf("webhook endpoint responded %s: %s", resp.Status, bytes.TrimSpace(snippet))
+
+	var retryAfter time.Duration
+	if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && seconds > 0 {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+
+	if resp.StatusCode == http.StatusRequestTimeout {
+		return resp.StatusCode, retryAfter, apperrors.ErrIntegrationTimeout.Extend(message)
+	}
+	return resp.StatusCode, retryAfter, apperrors.NewError(resp.StatusCode, message)
+}
@@ -0,0 +1,111 @@
+package queues
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	commonv1 "buf.build/gen/go/antinvestor/common/protocolbuffers/go/common/v1"
+	notificationv1 "buf.build/gen/go/antinvestor/notification/protocolbuffers/go/notification/v1"
+	"github.com/antinvestor/service-notification/apps/integrations/webhook/service/client"
+	"github.com/antinvestor/service-notification/pkg/apperrors"
+	"github.com/antinvestor/service-notification/pkg/constants"
+	"github.com/antinvestor/service-notification/pkg/events"
+	frameEvents "github.com/pitabwire/frame/v2/events"
+	"github.com/pitabwire/frame/v2/queue"
+	"github.com/pitabwire/util"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type messageToSend struct {
+	eventsMan  frameEvents.Manager
+	webhookCli *client.Client
+}
+
+func NewMessageToSend(
+	eventsMan frameEvents.Manager,
+	webhookCli *client.Client,
+) queue.SubscribeWorker {
+	return &messageToSend{
+		eventsMan:  eventsMan,
+		webhookCli: webhookCli,
+	}
+}
+
+func (ms *messageToSend) Handle(ctx context.Context, headers map[string]string, payload []byte) error {
+
+	log := util.Log(ctx).WithField("type", "webhook.message.send")
+	defer log.Release()
+	log.Debug("queue handler started")
+
+	notification := &notificationv1.Notification{}
+
+	err := proto.Unmarshal(payload, notification)
+	if err != nil {
+		log.WithError(err).Error("failed to unmarshal notification")
+		return nil
+	}
+
+	log = log.WithField("notification_id", notification.GetId())
+	log.WithFields(map[string]any{
+		"recipient_profile_id": notification.GetRecipient().GetProfileId(),
+		"partition_id":         headers[constants.PartitionIDHeaderName],
+	}).Debug("processing webhook message")
+
+	delivery, err := ms.webhookCli.Send(ctx, headers, notification)
+	if err != nil {
+		log.WithError(err).Error("webhook delivery failed")
+
+		extrasMap := map[string]any{
+			"error":                   err.Error(),
+			constants.StatusExtraStep: constants.StatusStepSubmit,
+		}
+		if delivery != nil {
+			extrasMap["attempts"] = delivery.Attempts
+			if delivery.StatusCode != 0 {
+				extrasMap["http_status"] = delivery.StatusCode
+			}
+		}
+
+		var appErr *apperrors.Error
+		if !errors.As(err, &appErr) {
+			// The endpoint could not be resolved, the queue delivers the message again for the
+			// settings service to answer later.
+			ms.emitStatus(ctx, notification.GetId(), commonv1.STATE_ACTIVE, commonv1.STATUS_UNKNOWN, extrasMap)
+			return err
+		}
+
+		extrasMap["errcode"] = fmt.Sprintf("%v", appErr.ErrorCode())
+		if appErr.IsRetriable() {
+			// The endpoint kept failing through every retry, another route may still deliver it.
+			extrasMap[constants.StatusExtraReroute] = true
+		}
+		ms.emitStatus(ctx, notification.GetId(), commonv1.STATE_INACTIVE, commonv1.STATUS_FAILED, extrasMap)
+		return nil
+	}
+
+	ms.emitStatus(ctx, notification.GetId(), commonv1.STATE_INACTIVE, commonv1.STATUS_SUCCESSFUL, map[string]any{
+		"attempts":    delivery.Attempts,
+		"http_status": delivery.StatusCode,
+	})
+
+	log.WithField("attempts", delivery.Attempts).Info("notification delivered to webhook")
+	return nil
+}
+
+func (ms *messageToSend) emitStatus(ctx context.Context, notificationID string, state commonv1.STATE, status commonv1.STATUS, extrasMap map[string]any) {
+	extra, _ := structpb.NewStruct(extrasMap)
+
+	err := ms.eventsMan.Emit(ctx, events.NotificationStatusUpdateEvent,
+		&commonv1.StatusUpdateRequest{
+			Id:         notificationID,
+			State:      state,
+			Status:     status,
+			ExternalId: "",
+			Extras:     extra,
+		})
+	if err != nil {
+		util.Log(ctx).WithError(err).Warn("could not update status on notification service")
+	}
+}
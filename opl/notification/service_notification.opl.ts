@@ -25,6 +25,8 @@ class service_notification implements Namespace {
     granted_notification_status_update: (profile_user | service_notification)[]
     granted_template_manage: (profile_user | service_notification)[]
     granted_template_view: (profile_user | service_notification)[]
+    granted_suppression_manage: (profile_user | service_notification)[]
+    granted_suppression_view: (profile_user | service_notification)[]
   }
 
   permits = {
@@ -79,5 +81,19 @@ class service_notification implements Namespace {
       this.related.service.includes(ctx.subject) ||
       this.related.viewer.includes(ctx.subject) ||
       this.related.granted_template_view.includes(ctx.subject),
+
+    suppression_manage: (ctx: Context): boolean =>
+      this.related.admin.includes(ctx.subject) ||
+      this.related.owner.includes(ctx.subject) ||
+      this.related.service.includes(ctx.subject) ||
+      this.related.granted_suppression_manage.includes(ctx.subject),
+
+    suppression_view: (ctx: Context): boolean =>
+      this.related.admin.includes(ctx.subject) ||
+      this.related.operator.includes(ctx.subject) ||
+      this.related.owner.includes(ctx.subject) ||
+      this.related.service.includes(ctx.subject) ||
+      this.related.viewer.includes(ctx.subject) ||
+      this.related.granted_suppression_view.includes(ctx.subject),
   }
 }
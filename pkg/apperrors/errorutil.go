@@ -5,6 +5,8 @@ import (
 
 	"connectrpc.com/connect"
 	"github.com/pitabwire/frame/v2/data"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func CleanErr(err error) *connect.Error {
@@ -18,5 +20,22 @@ func CleanErr(err error) *connect.Error {
 		return cerr
 	}
 
+	// Business errors are grpc statuses, their code and details such as field violations
+	// are kept rather than reported as internal failures.
+	if st, isStatus := status.FromError(err); isStatus {
+		cerr = connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+		for _, detail := range st.Details() {
+			message, isMessage := detail.(proto.Message)
+			if !isMessage {
+				continue
+			}
+			errDetail, detailErr := connect.NewErrorDetail(message)
+			if detailErr == nil {
+				cerr.AddDetail(errDetail)
+			}
+		}
+		return cerr
+	}
+
 	return data.ErrorConvertToAPI(err)
 }
@@ -10,3 +10,35 @@ const (
 	APISenderIDHeaderName              = "X-API_SENDER_ID"
 	APIUserNameHeaderName              = "X-API_USERNAME_ID"
 )
+
+// Status extra keys shared between the notification service and its integrations.
+const (
+	// StatusExtraReroute marks a failed delivery as worth retrying on another route.
+	StatusExtraReroute = "reroute"
+	// StatusExtraRouteID records the route a status was reported against.
+	StatusExtraRouteID = "route_id"
+	// StatusExtraRouteAttempts lists the routes tried so far and why each failed.
+	StatusExtraRouteAttempts = "route_attempts"
+	// StatusExtraSMSSegments records how many billable segments an SMS went out in.
+	StatusExtraSMSSegments = "sms_segments"
+	// StatusExtraSMSEncoding records whether an SMS went out in GSM-7 or UCS-2.
+	StatusExtraSMSEncoding = "sms_encoding"
+	// StatusExtraSMSOversized flags an SMS sent with more segments than its template allows.
+	StatusExtraSMSOversized = "sms_oversized"
+	// StatusExtraSMSTruncated flags an SMS cut down to the segments its template allows.
+	StatusExtraSMSTruncated = "sms_truncated"
+	// StatusExtraStep names the processing step a status was recorded at.
+	StatusExtraStep = "step"
+	// StatusExtraMessageIDs lists the gateway identifiers of every part a message was sent in,
+	// delivery reports for any of them are matched back to the notification.
+	StatusExtraMessageIDs = "message_ids"
+)
+
+// Steps integrations record terminal delivery failures under. A notification falls back to
+// the next channel its sender prefers only after one of these.
+const (
+	// StatusStepSubmit is a message the gateway refused to accept.
+	StatusStepSubmit = "submit"
+	// StatusStepDeliveryReport is a message the gateway accepted and later reported undelivered.
+	StatusStepDeliveryReport = "delivery_report"
+)
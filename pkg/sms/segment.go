@@ -0,0 +1,177 @@
+package sms
+
+import "strings"
+
+// Encoding is the character set a message goes out in.
+type Encoding string
+
+const (
+	// EncodingGSM7 packs each character of the GSM 03.38 alphabet in seven bits.
+	EncodingGSM7 Encoding = "GSM-7"
+	// EncodingUCS2 carries any other text as UTF-16, at two bytes a character.
+	EncodingUCS2 Encoding = "UCS-2"
+)
+
+// Message capacities in encoding units, septets for GSM-7 and code units for UCS-2.
+// A message sent in parts loses room in every part to the concatenation header.
+const (
+	GSM7SingleSegment = 160
+	GSM7MultiSegment  = 153
+	UCS2SingleSegment = 70
+	UCS2MultiSegment  = 67
+)
+
+// The GSM 03.38 alphabet, extension table characters are sent behind an escape septet.
+const (
+	gsm7BasicCharacters    = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsm7ExtendedCharacters = "\f^{}\\[~]|€"
+)
+
+// OversizePolicy says what to do with a message needing more segments than allowed.
+type OversizePolicy string
+
+const (
+	// OversizeWarn sends the message as it is and only flags it.
+	OversizeWarn OversizePolicy = "warn"
+	// OversizeTruncate cuts the message down to the allowed segments.
+	OversizeTruncate OversizePolicy = "truncate"
+	// OversizeReject fails the message without sending it.
+	OversizeReject OversizePolicy = "reject"
+)
+
+// ParseOversizePolicy reads a policy name, unknown names fall back to a warning.
+func ParseOversizePolicy(name string) OversizePolicy {
+	switch OversizePolicy(strings.ToLower(strings.TrimSpace(name))) {
+	case OversizeTruncate:
+		return OversizeTruncate
+	case OversizeReject:
+		return OversizeReject
+	default:
+		return OversizeWarn
+	}
+}
+
+// Segmentation describes how a text is carried over SMS.
+type Segmentation struct {
+	Encoding Encoding
+	// Units is the text length in septets for GSM-7 and in UTF-16 code units for UCS-2.
+	Units    int
+	Segments int
+}
+
+var (
+	gsm7Basic    = runeSet(gsm7BasicCharacters)
+	gsm7Extended = runeSet(gsm7ExtendedCharacters)
+)
+
+func runeSet(characters string) map[rune]bool {
+	set := make(map[rune]bool, len(characters))
+	for _, r := range characters {
+		set[r] = true
+	}
+	return set
+}
+
+// DetectEncoding returns GSM-7 when every character of the text is in the GSM alphabet
+// or its extension table, and UCS-2 otherwise.
+func DetectEncoding(text string) Encoding {
+	for _, r := range text {
+		if !gsm7Basic[r] && !gsm7Extended[r] {
+			return EncodingUCS2
+		}
+	}
+	return EncodingGSM7
+}
+
+// unitCost is the room a character takes: extension characters are escaped into two
+// septets and characters outside the basic plane need a UTF-16 surrogate pair.
+func unitCost(r rune, encoding Encoding) int {
+	if encoding == EncodingGSM7 {
+		if gsm7Extended[r] {
+			return 2
+		}
+		return 1
+	}
+
+	if r > 0xFFFF {
+		return 2
+	}
+	return 1
+}
+
+func capacities(encoding Encoding) (single, multi int) {
+	if encoding == EncodingGSM7 {
+		return GSM7SingleSegment, GSM7MultiSegment
+	}
+	return UCS2SingleSegment, UCS2MultiSegment
+}
+
+// segmenter counts segments as characters are added. A character is never split
+// across two parts, so a part can end short of its capacity.
+type segmenter struct {
+	encoding Encoding
+	single   int
+	multi    int
+	units    int
+	parts    int
+	current  int
+}
+
+func newSegmenter(encoding Encoding) *segmenter {
+	single, multi := capacities(encoding)
+	return &segmenter{encoding: encoding, single: single, multi: multi, parts: 1}
+}
+
+func (s *segmenter) add(r rune) {
+	cost := unitCost(r, s.encoding)
+	s.units += cost
+
+	if s.current+cost > s.multi {
+		s.parts++
+		s.current = 0
+	}
+	s.current += cost
+}
+
+func (s *segmenter) segments() int {
+	if s.units == 0 {
+		return 0
+	}
+	if s.units <= s.single {
+		return 1
+	}
+	return s.parts
+}
+
+// Segment works out the encoding of a text and the number of segments it is sent in.
+func Segment(text string) Segmentation {
+	encoding := DetectEncoding(text)
+
+	s := newSegmenter(encoding)
+	for _, r := range text {
+		s.add(r)
+	}
+
+	return Segmentation{
+		Encoding: encoding,
+		Units:    s.units,
+		Segments: s.segments(),
+	}
+}
+
+// Truncate cuts a text down to what fits in the given number of segments, keeping the
+// encoding of the whole text.
+func Truncate(text string, maxSegments int) string {
+	if maxSegments <= 0 || Segment(text).Segments <= maxSegments {
+		return text
+	}
+
+	s := newSegmenter(DetectEncoding(text))
+	for i, r := range text {
+		s.add(r)
+		if s.segments() > maxSegments {
+			return text[:i]
+		}
+	}
+	return text
+}
@@ -0,0 +1,58 @@
+package sms
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegment(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		encoding Encoding
+		units    int
+		segments int
+	}{
+		{name: "empty", text: "", encoding: EncodingGSM7, units: 0, segments: 0},
+		{name: "single gsm", text: strings.Repeat("a", 160), encoding: EncodingGSM7, units: 160, segments: 1},
+		{name: "two gsm parts", text: strings.Repeat("a", 161), encoding: EncodingGSM7, units: 161, segments: 2},
+		{name: "full gsm parts", text: strings.Repeat("a", 306), encoding: EncodingGSM7, units: 306, segments: 2},
+		{name: "extension characters count twice", text: strings.Repeat("€", 80), encoding: EncodingGSM7, units: 160, segments: 1},
+		{name: "escapes are not split", text: strings.Repeat("a", 152) + "€" + "a", encoding: EncodingGSM7, units: 155, segments: 1},
+		{name: "escape pushed to next part", text: strings.Repeat("a", 152) + "€" + strings.Repeat("a", 152), encoding: EncodingGSM7, units: 306, segments: 3},
+		{name: "emoji switches to ucs2", text: strings.Repeat("a", 69) + "😀", encoding: EncodingUCS2, units: 71, segments: 2},
+		{name: "single ucs2", text: strings.Repeat("ж", 70), encoding: EncodingUCS2, units: 70, segments: 1},
+		{name: "three ucs2 parts", text: strings.Repeat("ж", 135), encoding: EncodingUCS2, units: 135, segments: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Segment(tt.text)
+			require.Equal(t, tt.encoding, got.Encoding)
+			require.Equal(t, tt.units, got.Units)
+			require.Equal(t, tt.segments, got.Segments)
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	text := strings.Repeat("a", 400)
+	require.Len(t, Truncate(text, 1), GSM7SingleSegment)
+	require.Len(t, Truncate(text, 2), 2*GSM7MultiSegment)
+	require.Equal(t, text, Truncate(text, 3))
+	require.Equal(t, text, Truncate(text, 0), "no limit keeps the text")
+
+	emoji := strings.Repeat("😀", 40)
+	truncated := Truncate(emoji, 1)
+	require.Equal(t, strings.Repeat("😀", 35), truncated, "surrogate pairs are not cut in half")
+	require.Equal(t, 1, Segment(truncated).Segments)
+}
+
+func TestParseOversizePolicy(t *testing.T) {
+	require.Equal(t, OversizeReject, ParseOversizePolicy(" Reject "))
+	require.Equal(t, OversizeTruncate, ParseOversizePolicy("truncate"))
+	require.Equal(t, OversizeWarn, ParseOversizePolicy(""))
+	require.Equal(t, OversizeWarn, ParseOversizePolicy("drop"))
+}
@@ -0,0 +1,171 @@
+package templating
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// VariableType is the kind of value a template variable holds.
+type VariableType string
+
+const (
+	VariableString VariableType = "string"
+	VariableNumber VariableType = "number"
+	// VariableDate is an RFC 3339 timestamp or a plain 2006-01-02 date.
+	VariableDate VariableType = "date"
+	// VariableMoney is a number or a decimal amount, optionally led by its ISO 4217 currency code.
+	VariableMoney VariableType = "money"
+)
+
+var moneyPattern = regexp.MustCompile(`^(?:[A-Z]{3} ?)?-?\d{1,3}(?:,?\d{3})*(?:\.\d+)?$`)
+
+// Variable declares a value a template reads from the payload of a notification.
+type Variable struct {
+	Type     VariableType `json:"type"`
+	Required bool         `json:"required"`
+}
+
+// Schema declares the variables of a template by name.
+type Schema map[string]Variable
+
+// FieldError describes a payload variable that does not match its declaration.
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+func (fe FieldError) Error() string {
+	return fmt.Sprintf("%s %s", fe.Field, fe.Reason)
+}
+
+// ParseSchema reads a schema as it is kept in template extras, nil when none is declared.
+// Variables declared without a type are strings.
+func ParseSchema(raw any) (Schema, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := Schema{}
+	err = json.Unmarshal(encoded, &schema)
+	if err != nil {
+		return nil, fmt.Errorf("variables have to map names to declarations: %w", err)
+	}
+
+	for name, variable := range schema {
+		switch variable.Type {
+		case "":
+			variable.Type = VariableString
+			schema[name] = variable
+		case VariableString, VariableNumber, VariableDate, VariableMoney:
+		default:
+			return nil, fmt.Errorf("variable %s has unknown type %q", name, variable.Type)
+		}
+	}
+	return schema, nil
+}
+
+// Undeclared lists the variables a message template reads that the schema does not declare.
+func (s Schema) Undeclared(detail string) ([]string, error) {
+	variables, err := Variables(detail)
+	if err != nil {
+		return nil, err
+	}
+
+	var undeclared []string
+	for _, name := range variables {
+		if _, ok := s[name]; !ok {
+			undeclared = append(undeclared, name)
+		}
+	}
+	return undeclared, nil
+}
+
+// Validate checks a payload against the schema, reporting required variables it lacks and
+// supplied variables of the wrong type, ordered by variable name.
+func (s Schema) Validate(payload map[string]any) []FieldError {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var fieldErrors []FieldError
+	for _, name := range names {
+		variable := s[name]
+
+		value, ok := payload[name]
+		if !ok || value == nil || value == "" {
+			if variable.Required {
+				fieldErrors = append(fieldErrors, FieldError{Field: name, Reason: "is required"})
+			}
+			continue
+		}
+
+		if !variable.Type.accepts(value) {
+			fieldErrors = append(fieldErrors, FieldError{Field: name, Reason: fmt.Sprintf("is not a %s", variable.Type)})
+		}
+	}
+	return fieldErrors
+}
+
+// WithDefaults returns a copy of the payload in which optional variables it does not supply
+// are blank, so they render as nothing instead of "<no value>".
+func (s Schema) WithDefaults(payload map[string]any) map[string]any {
+	completed := make(map[string]any, len(payload)+len(s))
+	for name, value := range payload {
+		completed[name] = value
+	}
+	for name, variable := range s {
+		if !variable.Required && completed[name] == nil {
+			completed[name] = ""
+		}
+	}
+	return completed
+}
+
+func (vt VariableType) accepts(value any) bool {
+	switch vt {
+	case VariableNumber:
+		switch v := value.(type) {
+		case float64, float32, int, int32, int64:
+			return true
+		case string:
+			_, err := strconv.ParseFloat(v, 64)
+			return err == nil
+		}
+		return false
+
+	case VariableDate:
+		v, ok := value.(string)
+		if !ok {
+			return false
+		}
+		if _, err := time.Parse(time.RFC3339, v); err == nil {
+			return true
+		}
+		_, err := time.Parse(time.DateOnly, v)
+		return err == nil
+
+	case VariableMoney:
+		switch v := value.(type) {
+		case float64, float32, int, int32, int64:
+			return true
+		case string:
+			return moneyPattern.MatchString(v)
+		}
+		return false
+
+	default:
+		_, ok := value.(string)
+		return ok
+	}
+}
@@ -0,0 +1,118 @@
+package templating
+
+import (
+	"bytes"
+	"slices"
+	"text/template"
+	"text/template/parse"
+)
+
+// templateName is what message templates are parsed under, it shows up in render errors.
+const templateName = "message_out"
+
+// Render executes a message template against the payload of a notification.
+func Render(detail string, payload map[string]any) (string, error) {
+	tmpl, err := template.New(templateName).Parse(detail)
+	if err != nil {
+		return "", err
+	}
+
+	var rendered bytes.Buffer
+	err = tmpl.Execute(&rendered, payload)
+	if err != nil {
+		return "", err
+	}
+	return rendered.String(), nil
+}
+
+// Variables lists the payload keys a message template reads, sorted and without repeats.
+// Fields read inside range and with blocks belong to the element in scope there rather than
+// the payload, so only those reached through $ are counted.
+func Variables(detail string) ([]string, error) {
+	tmpl, err := template.New(templateName).Parse(detail)
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]struct{}{}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			collect(t.Root, true, found)
+		}
+	}
+
+	variables := make([]string, 0, len(found))
+	for name := range found {
+		variables = append(variables, name)
+	}
+	slices.Sort(variables)
+	return variables, nil
+}
+
+// Missing lists the variables of a message template the payload does not supply.
+func Missing(detail string, payload map[string]any) ([]string, error) {
+	variables, err := Variables(detail)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, name := range variables {
+		if _, ok := payload[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing, nil
+}
+
+// collect walks a template tree recording the payload keys it reads, rootDot says whether
+// dot still refers to the payload at that point.
+func collect(node parse.Node, rootDot bool, found map[string]struct{}) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collect(child, rootDot, found)
+		}
+	case *parse.ActionNode:
+		collect(n.Pipe, rootDot, found)
+	case *parse.TemplateNode:
+		collect(n.Pipe, rootDot, found)
+	case *parse.IfNode:
+		collectBranch(&n.BranchNode, rootDot, rootDot, found)
+	case *parse.RangeNode:
+		collectBranch(&n.BranchNode, rootDot, false, found)
+	case *parse.WithNode:
+		collectBranch(&n.BranchNode, rootDot, false, found)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collect(cmd, rootDot, found)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collect(arg, rootDot, found)
+		}
+	case *parse.ChainNode:
+		collect(n.Node, rootDot, found)
+	case *parse.FieldNode:
+		if rootDot && len(n.Ident) > 0 {
+			found[n.Ident[0]] = struct{}{}
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			found[n.Ident[1]] = struct{}{}
+		}
+	}
+}
+
+func collectBranch(branch *parse.BranchNode, rootDot, bodyRootDot bool, found map[string]struct{}) {
+	collect(branch.Pipe, rootDot, found)
+	collect(branch.List, bodyRootDot, found)
+	// The else branch of range and with runs with dot unchanged.
+	collect(branch.ElseList, rootDot, found)
+}
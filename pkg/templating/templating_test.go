@@ -0,0 +1,124 @@
+package templating
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariables(t *testing.T) {
+	tests := []struct {
+		name      string
+		detail    string
+		variables []string
+	}{
+		{name: "plain text", detail: "hello there", variables: []string{}},
+		{name: "fields", detail: "code {{.code}} expires {{.expiryDate}}, again {{.code}}", variables: []string{"code", "expiryDate"}},
+		{name: "nested field", detail: "hi {{.customer.name}}", variables: []string{"customer"}},
+		{name: "conditions", detail: "{{if .vip}}dear {{.name}}{{else}}hi{{end}}", variables: []string{"name", "vip"}},
+		{name: "range scope", detail: "{{range .items}}{{.sku}} for {{$.currency}}{{else}}{{.empty}}{{end}}", variables: []string{"currency", "empty", "items"}},
+		{name: "with scope", detail: "{{with .order}}{{.id}}{{end}}", variables: []string{"order"}},
+		{name: "function arguments", detail: `{{printf "%s-%s" .first .last}}`, variables: []string{"first", "last"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variables, err := Variables(tt.detail)
+			require.NoError(t, err)
+			require.Equal(t, tt.variables, variables)
+		})
+	}
+
+	_, err := Variables("{{.code")
+	require.Error(t, err)
+}
+
+func TestMissing(t *testing.T) {
+	missing, err := Missing("code {{.code}} expires {{.expiryDate}}", map[string]any{"code": "1234"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"expiryDate"}, missing)
+
+	missing, err = Missing("code {{.code}}", map[string]any{"code": "1234"})
+	require.NoError(t, err)
+	require.Empty(t, missing)
+}
+
+func TestRender(t *testing.T) {
+	rendered, err := Render("code {{.code}} expires {{.expiryDate}}", map[string]any{"code": "1234", "expiryDate": "tomorrow"})
+	require.NoError(t, err)
+	require.Equal(t, "code 1234 expires tomorrow", rendered)
+}
+
+func TestParseSchema(t *testing.T) {
+	schema, err := ParseSchema(nil)
+	require.NoError(t, err)
+	require.Nil(t, schema)
+
+	schema, err = ParseSchema(map[string]any{
+		"code":   map[string]any{"required": true},
+		"amount": map[string]any{"type": "money"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, Schema{
+		"code":   {Type: VariableString, Required: true},
+		"amount": {Type: VariableMoney},
+	}, schema)
+
+	_, err = ParseSchema(map[string]any{"code": map[string]any{"type": "uuid"}})
+	require.Error(t, err)
+
+	_, err = ParseSchema([]any{"code"})
+	require.Error(t, err)
+}
+
+func TestSchemaUndeclared(t *testing.T) {
+	schema := Schema{"code": {Type: VariableString}}
+
+	undeclared, err := schema.Undeclared("code {{.code}} for {{.name}}")
+	require.NoError(t, err)
+	require.Equal(t, []string{"name"}, undeclared)
+}
+
+func TestSchemaValidate(t *testing.T) {
+	schema := Schema{
+		"code":    {Type: VariableString, Required: true},
+		"count":   {Type: VariableNumber},
+		"due":     {Type: VariableDate, Required: true},
+		"balance": {Type: VariableMoney},
+	}
+
+	require.Empty(t, schema.Validate(map[string]any{
+		"code":    "1234",
+		"count":   float64(3),
+		"due":     "2026-10-17",
+		"balance": "KES 1,200.50",
+	}))
+	require.Empty(t, schema.Validate(map[string]any{
+		"code":    "1234",
+		"due":     "2026-10-17T08:00:00Z",
+		"balance": float64(12),
+	}), "optional variables may be left out")
+
+	require.Equal(t, []FieldError{
+		{Field: "balance", Reason: "is not a money"},
+		{Field: "code", Reason: "is required"},
+		{Field: "count", Reason: "is not a number"},
+		{Field: "due", Reason: "is not a date"},
+	}, schema.Validate(map[string]any{
+		"code":    "",
+		"count":   "three",
+		"due":     "tomorrow",
+		"balance": "lots",
+	}))
+}
+
+func TestSchemaWithDefaults(t *testing.T) {
+	schema := Schema{"code": {Type: VariableString, Required: true}, "note": {Type: VariableString}}
+	payload := map[string]any{"code": "1234"}
+
+	rendered, err := Render("code {{.code}}{{.note}}", schema.WithDefaults(payload))
+	require.NoError(t, err)
+	require.Equal(t, "code 1234", rendered)
+	require.NotContains(t, payload, "note", "the payload itself is left alone")
+	require.NotContains(t, schema.WithDefaults(map[string]any{}), "code", "required variables are never made up")
+}
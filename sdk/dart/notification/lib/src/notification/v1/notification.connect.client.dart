@@ -30,6 +30,45 @@ extension type NotificationServiceClient (connect.Transport _transport) {
     );
   }
 
+  /// Broadcast sends one templated notification to a list of profiles or a partition wide audience.
+  /// The audience is expanded server side in pages and progress is streamed back, expansion
+  /// carries on after the stream closes and is followed with BroadcastStatus.
+  Stream<notificationv1notification.BroadcastResponse> broadcast(
+    notificationv1notification.BroadcastRequest input, {
+    connect.Headers? headers,
+    connect.AbortSignal? signal,
+    Function(connect.Headers)? onHeader,
+    Function(connect.Headers)? onTrailer,
+  }) {
+    return connect.Client(_transport).server(
+      specs.NotificationService.broadcast,
+      input,
+      signal: signal,
+      headers: headers,
+      onHeader: onHeader,
+      onTrailer: onTrailer,
+    );
+  }
+
+  /// BroadcastStatus reports aggregate delivery progress of a broadcast.
+  /// Returns child counts by status and failures grouped by the step they happened at.
+  Future<notificationv1notification.BroadcastStatusResponse> broadcastStatus(
+    commonv1common.StatusRequest input, {
+    connect.Headers? headers,
+    connect.AbortSignal? signal,
+    Function(connect.Headers)? onHeader,
+    Function(connect.Headers)? onTrailer,
+  }) {
+    return connect.Client(_transport).unary(
+      specs.NotificationService.broadcastStatus,
+      input,
+      signal: signal,
+      headers: headers,
+      onHeader: onHeader,
+      onTrailer: onTrailer,
+    );
+  }
+
   /// Release triggers delivery of queued notifications.
   /// Used for batch processing where notifications are queued first, then released together.
   Stream<notificationv1notification.ReleaseResponse> release(
@@ -49,6 +88,25 @@ extension type NotificationServiceClient (connect.Transport _transport) {
     );
   }
 
+  /// Cancel withdraws queued or scheduled notifications before they are dispatched.
+  /// Notifications already published to a route cannot be recalled and are reported as too late.
+  Stream<notificationv1notification.CancelResponse> cancel(
+    notificationv1notification.CancelRequest input, {
+    connect.Headers? headers,
+    connect.AbortSignal? signal,
+    Function(connect.Headers)? onHeader,
+    Function(connect.Headers)? onTrailer,
+  }) {
+    return connect.Client(_transport).server(
+      specs.NotificationService.cancel,
+      input,
+      signal: signal,
+      headers: headers,
+      onHeader: onHeader,
+      onTrailer: onTrailer,
+    );
+  }
+
   /// Receive acknowledges receipt of notifications by the client.
   /// Used for tracking delivery confirmation and read receipts.
   Stream<notificationv1notification.ReceiveResponse> receive(
@@ -144,7 +202,7 @@ extension type NotificationServiceClient (connect.Transport _transport) {
     );
   }
 
-  /// TemplateSave creates or updates a notification template.
+  /// TemplateSave creates a new draft version of a notification template.
   /// Templates enable consistent, reusable notification formatting with localization.
   Future<notificationv1notification.TemplateSaveResponse> templateSave(
     notificationv1notification.TemplateSaveRequest input, {
@@ -162,4 +220,148 @@ extension type NotificationServiceClient (connect.Transport _transport) {
       onTrailer: onTrailer,
     );
   }
+
+  /// TemplatePublish makes a template version the one notifications are rendered with.
+  Future<notificationv1notification.TemplatePublishResponse> templatePublish(
+    notificationv1notification.TemplatePublishRequest input, {
+    connect.Headers? headers,
+    connect.AbortSignal? signal,
+    Function(connect.Headers)? onHeader,
+    Function(connect.Headers)? onTrailer,
+  }) {
+    return connect.Client(_transport).unary(
+      specs.NotificationService.templatePublish,
+      input,
+      signal: signal,
+      headers: headers,
+      onHeader: onHeader,
+      onTrailer: onTrailer,
+    );
+  }
+
+  /// TemplateRollback publishes again a template version that was published before.
+  Future<notificationv1notification.TemplateRollbackResponse> templateRollback(
+    notificationv1notification.TemplateRollbackRequest input, {
+    connect.Headers? headers,
+    connect.AbortSignal? signal,
+    Function(connect.Headers)? onHeader,
+    Function(connect.Headers)? onTrailer,
+  }) {
+    return connect.Client(_transport).unary(
+      specs.NotificationService.templateRollback,
+      input,
+      signal: signal,
+      headers: headers,
+      onHeader: onHeader,
+      onTrailer: onTrailer,
+    );
+  }
+
+  /// TemplateUpdate adds, replaces or removes the content of a draft template version.
+  Future<notificationv1notification.TemplateUpdateResponse> templateUpdate(
+    notificationv1notification.TemplateUpdateRequest input, {
+    connect.Headers? headers,
+    connect.AbortSignal? signal,
+    Function(connect.Headers)? onHeader,
+    Function(connect.Headers)? onTrailer,
+  }) {
+    return connect.Client(_transport).unary(
+      specs.NotificationService.templateUpdate,
+      input,
+      signal: signal,
+      headers: headers,
+      onHeader: onHeader,
+      onTrailer: onTrailer,
+    );
+  }
+
+  /// TemplateDelete removes a template version and its content.
+  Future<notificationv1notification.TemplateDeleteResponse> templateDelete(
+    notificationv1notification.TemplateDeleteRequest input, {
+    connect.Headers? headers,
+    connect.AbortSignal? signal,
+    Function(connect.Headers)? onHeader,
+    Function(connect.Headers)? onTrailer,
+  }) {
+    return connect.Client(_transport).unary(
+      specs.NotificationService.templateDelete,
+      input,
+      signal: signal,
+      headers: headers,
+      onHeader: onHeader,
+      onTrailer: onTrailer,
+    );
+  }
+
+  /// TemplatePreview renders a template against a sample payload without creating a notification.
+  Future<notificationv1notification.TemplatePreviewResponse> templatePreview(
+    notificationv1notification.TemplatePreviewRequest input, {
+    connect.Headers? headers,
+    connect.AbortSignal? signal,
+    Function(connect.Headers)? onHeader,
+    Function(connect.Headers)? onTrailer,
+  }) {
+    return connect.Client(_transport).unary(
+      specs.NotificationService.templatePreview,
+      input,
+      signal: signal,
+      headers: headers,
+      onHeader: onHeader,
+      onTrailer: onTrailer,
+    );
+  }
+
+  /// SuppressionSearch lists the contacts suppressed in the partition.
+  Stream<notificationv1notification.SuppressionSearchResponse> suppressionSearch(
+    notificationv1notification.SuppressionSearchRequest input, {
+    connect.Headers? headers,
+    connect.AbortSignal? signal,
+    Function(connect.Headers)? onHeader,
+    Function(connect.Headers)? onTrailer,
+  }) {
+    return connect.Client(_transport).server(
+      specs.NotificationService.suppressionSearch,
+      input,
+      signal: signal,
+      headers: headers,
+      onHeader: onHeader,
+      onTrailer: onTrailer,
+    );
+  }
+
+  /// SuppressionAdd records a contact that must no longer receive notifications.
+  Future<notificationv1notification.SuppressionAddResponse> suppressionAdd(
+    notificationv1notification.SuppressionAddRequest input, {
+    connect.Headers? headers,
+    connect.AbortSignal? signal,
+    Function(connect.Headers)? onHeader,
+    Function(connect.Headers)? onTrailer,
+  }) {
+    return connect.Client(_transport).unary(
+      specs.NotificationService.suppressionAdd,
+      input,
+      signal: signal,
+      headers: headers,
+      onHeader: onHeader,
+      onTrailer: onTrailer,
+    );
+  }
+
+  /// SuppressionRemove lifts a suppression so the contact can receive notifications again.
+  Future<notificationv1notification.SuppressionRemoveResponse> suppressionRemove(
+    notificationv1notification.SuppressionRemoveRequest input, {
+    connect.Headers? headers,
+    connect.AbortSignal? signal,
+    Function(connect.Headers)? onHeader,
+    Function(connect.Headers)? onTrailer,
+  }) {
+    return connect.Client(_transport).unary(
+      specs.NotificationService.suppressionRemove,
+      input,
+      signal: signal,
+      headers: headers,
+      onHeader: onHeader,
+      onTrailer: onTrailer,
+    );
+  }
 }
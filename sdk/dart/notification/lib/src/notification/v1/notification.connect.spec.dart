@@ -22,6 +22,26 @@ abstract final class NotificationService {
     notificationv1notification.SendResponse.new,
   );
 
+  /// Broadcast sends one templated notification to a list of profiles or a partition wide audience.
+  /// The audience is expanded server side in pages and progress is streamed back, expansion
+  /// carries on after the stream closes and is followed with BroadcastStatus.
+  static const broadcast = connect.Spec(
+    '/$name/Broadcast',
+    connect.StreamType.server,
+    notificationv1notification.BroadcastRequest.new,
+    notificationv1notification.BroadcastResponse.new,
+  );
+
+  /// BroadcastStatus reports aggregate delivery progress of a broadcast.
+  /// Returns child counts by status and failures grouped by the step they happened at.
+  static const broadcastStatus = connect.Spec(
+    '/$name/BroadcastStatus',
+    connect.StreamType.unary,
+    commonv1common.StatusRequest.new,
+    notificationv1notification.BroadcastStatusResponse.new,
+    idempotency: connect.Idempotency.noSideEffects,
+  );
+
   /// Release triggers delivery of queued notifications.
   /// Used for batch processing where notifications are queued first, then released together.
   static const release = connect.Spec(
@@ -31,6 +51,15 @@ abstract final class NotificationService {
     notificationv1notification.ReleaseResponse.new,
   );
 
+  /// Cancel withdraws queued or scheduled notifications before they are dispatched.
+  /// Notifications already published to a route cannot be recalled and are reported as too late.
+  static const cancel = connect.Spec(
+    '/$name/Cancel',
+    connect.StreamType.server,
+    notificationv1notification.CancelRequest.new,
+    notificationv1notification.CancelResponse.new,
+  );
+
   /// Receive acknowledges receipt of notifications by the client.
   /// Used for tracking delivery confirmation and read receipts.
   static const receive = connect.Spec(
@@ -79,7 +108,7 @@ abstract final class NotificationService {
     idempotency: connect.Idempotency.noSideEffects,
   );
 
-  /// TemplateSave creates or updates a notification template.
+  /// TemplateSave creates a new draft version of a notification template.
   /// Templates enable consistent, reusable notification formatting with localization.
   static const templateSave = connect.Spec(
     '/$name/TemplateSave',
@@ -87,4 +116,69 @@ abstract final class NotificationService {
     notificationv1notification.TemplateSaveRequest.new,
     notificationv1notification.TemplateSaveResponse.new,
   );
+
+  /// TemplatePublish makes a template version the one notifications are rendered with.
+  static const templatePublish = connect.Spec(
+    '/$name/TemplatePublish',
+    connect.StreamType.unary,
+    notificationv1notification.TemplatePublishRequest.new,
+    notificationv1notification.TemplatePublishResponse.new,
+  );
+
+  /// TemplateRollback publishes again a template version that was published before.
+  static const templateRollback = connect.Spec(
+    '/$name/TemplateRollback',
+    connect.StreamType.unary,
+    notificationv1notification.TemplateRollbackRequest.new,
+    notificationv1notification.TemplateRollbackResponse.new,
+  );
+
+  /// TemplateUpdate adds, replaces or removes the content of a draft template version.
+  static const templateUpdate = connect.Spec(
+    '/$name/TemplateUpdate',
+    connect.StreamType.unary,
+    notificationv1notification.TemplateUpdateRequest.new,
+    notificationv1notification.TemplateUpdateResponse.new,
+  );
+
+  /// TemplateDelete removes a template version and its content.
+  static const templateDelete = connect.Spec(
+    '/$name/TemplateDelete',
+    connect.StreamType.unary,
+    notificationv1notification.TemplateDeleteRequest.new,
+    notificationv1notification.TemplateDeleteResponse.new,
+  );
+
+  /// TemplatePreview renders a template against a sample payload without creating a notification.
+  static const templatePreview = connect.Spec(
+    '/$name/TemplatePreview',
+    connect.StreamType.unary,
+    notificationv1notification.TemplatePreviewRequest.new,
+    notificationv1notification.TemplatePreviewResponse.new,
+  );
+
+  /// SuppressionSearch lists the contacts suppressed in the partition.
+  static const suppressionSearch = connect.Spec(
+    '/$name/SuppressionSearch',
+    connect.StreamType.server,
+    notificationv1notification.SuppressionSearchRequest.new,
+    notificationv1notification.SuppressionSearchResponse.new,
+    idempotency: connect.Idempotency.noSideEffects,
+  );
+
+  /// SuppressionAdd records a contact that must no longer receive notifications.
+  static const suppressionAdd = connect.Spec(
+    '/$name/SuppressionAdd',
+    connect.StreamType.unary,
+    notificationv1notification.SuppressionAddRequest.new,
+    notificationv1notification.SuppressionAddResponse.new,
+  );
+
+  /// SuppressionRemove lifts a suppression so the contact can receive notifications again.
+  static const suppressionRemove = connect.Spec(
+    '/$name/SuppressionRemove',
+    connect.StreamType.unary,
+    notificationv1notification.SuppressionRemoveRequest.new,
+    notificationv1notification.SuppressionRemoveResponse.new,
+  );
 }
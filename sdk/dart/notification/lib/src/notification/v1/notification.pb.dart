@@ -220,6 +220,9 @@ class Template extends $pb.GeneratedMessage {
     $core.String? name,
     $core.Iterable<TemplateData>? data,
     $6.Struct? extra,
+    $core.int? version,
+    $core.String? state,
+    $core.String? publishedAt,
   }) {
     final $result = create();
     if (id != null) {
@@ -234,6 +237,15 @@ class Template extends $pb.GeneratedMessage {
     if (extra != null) {
       $result.extra = extra;
     }
+    if (version != null) {
+      $result.version = version;
+    }
+    if (state != null) {
+      $result.state = state;
+    }
+    if (publishedAt != null) {
+      $result.publishedAt = publishedAt;
+    }
     return $result;
   }
   Template._() : super();
@@ -245,6 +257,9 @@ class Template extends $pb.GeneratedMessage {
     ..aOS(2, _omitFieldNames ? '' : 'name')
     ..pc<TemplateData>(4, _omitFieldNames ? '' : 'data', $pb.PbFieldType.PM, subBuilder: TemplateData.create)
     ..aOM<$6.Struct>(5, _omitFieldNames ? '' : 'extra', subBuilder: $6.Struct.create)
+    ..a<$core.int>(6, _omitFieldNames ? '' : 'version', $pb.PbFieldType.O3)
+    ..aOS(7, _omitFieldNames ? '' : 'state')
+    ..aOS(8, _omitFieldNames ? '' : 'publishedAt')
     ..hasRequiredFields = false
   ;
 
@@ -300,6 +315,33 @@ class Template extends $pb.GeneratedMessage {
   void clearExtra() => clearField(5);
   @$pb.TagNumber(5)
   $6.Struct ensureExtra() => $_ensure(3);
+
+  @$pb.TagNumber(6)
+  $core.int get version => $_getIZ(4);
+  @$pb.TagNumber(6)
+  set version($core.int v) { $_setSignedInt32(4, v); }
+  @$pb.TagNumber(6)
+  $core.bool hasVersion() => $_has(4);
+  @$pb.TagNumber(6)
+  void clearVersion() => clearField(6);
+
+  @$pb.TagNumber(7)
+  $core.String get state => $_getSZ(5);
+  @$pb.TagNumber(7)
+  set state($core.String v) { $_setString(5, v); }
+  @$pb.TagNumber(7)
+  $core.bool hasState() => $_has(5);
+  @$pb.TagNumber(7)
+  void clearState() => clearField(7);
+
+  @$pb.TagNumber(8)
+  $core.String get publishedAt => $_getSZ(6);
+  @$pb.TagNumber(8)
+  set publishedAt($core.String v) { $_setString(6, v); }
+  @$pb.TagNumber(8)
+  $core.bool hasPublishedAt() => $_has(6);
+  @$pb.TagNumber(8)
+  void clearPublishedAt() => clearField(8);
 }
 
 /// Notification represents a notification to be sent or received.
@@ -321,6 +363,7 @@ class Notification extends $pb.GeneratedMessage {
     $7.StatusResponse? status,
     $6.Struct? extras,
     PRIORITY? priority,
+    $core.String? idempotencyKey,
   }) {
     final $result = create();
     if (id != null) {
@@ -368,6 +411,9 @@ class Notification extends $pb.GeneratedMessage {
     if (priority != null) {
       $result.priority = priority;
     }
+    if (idempotencyKey != null) {
+      $result.idempotencyKey = idempotencyKey;
+    }
     return $result;
   }
   Notification._() : super();
@@ -390,6 +436,7 @@ class Notification extends $pb.GeneratedMessage {
     ..aOM<$7.StatusResponse>(14, _omitFieldNames ? '' : 'status', subBuilder: $7.StatusResponse.create)
     ..aOM<$6.Struct>(15, _omitFieldNames ? '' : 'extras', subBuilder: $6.Struct.create)
     ..e<PRIORITY>(16, _omitFieldNames ? '' : 'priority', $pb.PbFieldType.OE, defaultOrMaker: PRIORITY.HIGH, valueOf: PRIORITY.valueOf, enumValues: PRIORITY.values)
+    ..aOS(17, _omitFieldNames ? '' : 'idempotencyKey')
     ..hasRequiredFields = false
   ;
 
@@ -558,6 +605,15 @@ class Notification extends $pb.GeneratedMessage {
   $core.bool hasPriority() => $_has(14);
   @$pb.TagNumber(16)
   void clearPriority() => clearField(16);
+
+  @$pb.TagNumber(17)
+  $core.String get idempotencyKey => $_getSZ(15);
+  @$pb.TagNumber(17)
+  set idempotencyKey($core.String v) { $_setString(15, v); }
+  @$pb.TagNumber(17)
+  $core.bool hasIdempotencyKey() => $_has(15);
+  @$pb.TagNumber(17)
+  void clearIdempotencyKey() => clearField(17);
 }
 
 /// SearchResponse returns notifications matching search criteria.
@@ -695,6 +751,268 @@ class SendResponse extends $pb.GeneratedMessage {
   $core.List<$7.StatusResponse> get data => $_getList(0);
 }
 
+/// BroadcastRequest sends one notification to a whole audience.
+/// The service expands it into child notifications, one per recipient, each linked
+/// to a parent broadcast record through parent_id.
+class BroadcastRequest extends $pb.GeneratedMessage {
+  factory BroadcastRequest({
+    Notification? data,
+    $core.Iterable<$core.String>? profileIds,
+    $core.bool? partitionAudience,
+  }) {
+    final $result = create();
+    if (data != null) {
+      $result.data = data;
+    }
+    if (profileIds != null) {
+      $result.profileIds.addAll(profileIds);
+    }
+    if (partitionAudience != null) {
+      $result.partitionAudience = partitionAudience;
+    }
+    return $result;
+  }
+  BroadcastRequest._() : super();
+  factory BroadcastRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory BroadcastRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'BroadcastRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOM<Notification>(1, _omitFieldNames ? '' : 'data', subBuilder: Notification.create)
+    ..pPS(2, _omitFieldNames ? '' : 'profileIds')
+    ..aOB(3, _omitFieldNames ? '' : 'partitionAudience')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  BroadcastRequest clone() => BroadcastRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  BroadcastRequest copyWith(void Function(BroadcastRequest) updates) => super.copyWith((message) => updates(message as BroadcastRequest)) as BroadcastRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static BroadcastRequest create() => BroadcastRequest._();
+  BroadcastRequest createEmptyInstance() => create();
+  static $pb.PbList<BroadcastRequest> createRepeated() => $pb.PbList<BroadcastRequest>();
+  @$core.pragma('dart2js:noInline')
+  static BroadcastRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<BroadcastRequest>(create);
+  static BroadcastRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  Notification get data => $_getN(0);
+  @$pb.TagNumber(1)
+  set data(Notification v) { setField(1, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasData() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearData() => clearField(1);
+  @$pb.TagNumber(1)
+  Notification ensureData() => $_ensure(0);
+
+  @$pb.TagNumber(2)
+  $core.List<$core.String> get profileIds => $_getList(1);
+
+  @$pb.TagNumber(3)
+  $core.bool get partitionAudience => $_getBF(2);
+  @$pb.TagNumber(3)
+  set partitionAudience($core.bool v) { $_setBool(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasPartitionAudience() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearPartitionAudience() => clearField(3);
+}
+
+/// BroadcastResponse reports the expansion progress of a broadcast.
+class BroadcastResponse extends $pb.GeneratedMessage {
+  factory BroadcastResponse({
+    $7.StatusResponse? data,
+  }) {
+    final $result = create();
+    if (data != null) {
+      $result.data = data;
+    }
+    return $result;
+  }
+  BroadcastResponse._() : super();
+  factory BroadcastResponse.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory BroadcastResponse.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'BroadcastResponse', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOM<$7.StatusResponse>(1, _omitFieldNames ? '' : 'data', subBuilder: $7.StatusResponse.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  BroadcastResponse clone() => BroadcastResponse()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  BroadcastResponse copyWith(void Function(BroadcastResponse) updates) => super.copyWith((message) => updates(message as BroadcastResponse)) as BroadcastResponse;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static BroadcastResponse create() => BroadcastResponse._();
+  BroadcastResponse createEmptyInstance() => create();
+  static $pb.PbList<BroadcastResponse> createRepeated() => $pb.PbList<BroadcastResponse>();
+  @$core.pragma('dart2js:noInline')
+  static BroadcastResponse getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<BroadcastResponse>(create);
+  static BroadcastResponse? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $7.StatusResponse get data => $_getN(0);
+  @$pb.TagNumber(1)
+  set data($7.StatusResponse v) { setField(1, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasData() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearData() => clearField(1);
+  @$pb.TagNumber(1)
+  $7.StatusResponse ensureData() => $_ensure(0);
+}
+
+/// BroadcastStatusResponse summarises delivery of every child of a broadcast.
+/// Counts are kept up to date as child statuses change, so reading them stays cheap for large campaigns.
+class BroadcastStatusResponse extends $pb.GeneratedMessage {
+  factory BroadcastStatusResponse({
+    $core.String? id,
+    $fixnum.Int64? queued,
+    $fixnum.Int64? inProcess,
+    $fixnum.Int64? successful,
+    $fixnum.Int64? failed,
+    $core.Map<$core.String, $fixnum.Int64>? failuresByStep,
+    $7.StatusResponse? status,
+  }) {
+    final $result = create();
+    if (id != null) {
+      $result.id = id;
+    }
+    if (queued != null) {
+      $result.queued = queued;
+    }
+    if (inProcess != null) {
+      $result.inProcess = inProcess;
+    }
+    if (successful != null) {
+      $result.successful = successful;
+    }
+    if (failed != null) {
+      $result.failed = failed;
+    }
+    if (failuresByStep != null) {
+      $result.failuresByStep.addAll(failuresByStep);
+    }
+    if (status != null) {
+      $result.status = status;
+    }
+    return $result;
+  }
+  BroadcastStatusResponse._() : super();
+  factory BroadcastStatusResponse.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory BroadcastStatusResponse.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'BroadcastStatusResponse', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'id')
+    ..aInt64(2, _omitFieldNames ? '' : 'queued')
+    ..aInt64(3, _omitFieldNames ? '' : 'inProcess')
+    ..aInt64(4, _omitFieldNames ? '' : 'successful')
+    ..aInt64(5, _omitFieldNames ? '' : 'failed')
+    ..m<$core.String, $fixnum.Int64>(6, _omitFieldNames ? '' : 'failuresByStep', entryClassName: 'BroadcastStatusResponse.FailuresByStepEntry', keyFieldType: $pb.PbFieldType.OS, valueFieldType: $pb.PbFieldType.O6, packageName: const $pb.PackageName('notification.v1'))
+    ..aOM<$7.StatusResponse>(7, _omitFieldNames ? '' : 'status', subBuilder: $7.StatusResponse.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  BroadcastStatusResponse clone() => BroadcastStatusResponse()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  BroadcastStatusResponse copyWith(void Function(BroadcastStatusResponse) updates) => super.copyWith((message) => updates(message as BroadcastStatusResponse)) as BroadcastStatusResponse;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static BroadcastStatusResponse create() => BroadcastStatusResponse._();
+  BroadcastStatusResponse createEmptyInstance() => create();
+  static $pb.PbList<BroadcastStatusResponse> createRepeated() => $pb.PbList<BroadcastStatusResponse>();
+  @$core.pragma('dart2js:noInline')
+  static BroadcastStatusResponse getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<BroadcastStatusResponse>(create);
+  static BroadcastStatusResponse? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get id => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set id($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasId() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearId() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $fixnum.Int64 get queued => $_getI64(1);
+  @$pb.TagNumber(2)
+  set queued($fixnum.Int64 v) { $_setInt64(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasQueued() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearQueued() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $fixnum.Int64 get inProcess => $_getI64(2);
+  @$pb.TagNumber(3)
+  set inProcess($fixnum.Int64 v) { $_setInt64(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasInProcess() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearInProcess() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $fixnum.Int64 get successful => $_getI64(3);
+  @$pb.TagNumber(4)
+  set successful($fixnum.Int64 v) { $_setInt64(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasSuccessful() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearSuccessful() => clearField(4);
+
+  @$pb.TagNumber(5)
+  $fixnum.Int64 get failed => $_getI64(4);
+  @$pb.TagNumber(5)
+  set failed($fixnum.Int64 v) { $_setInt64(4, v); }
+  @$pb.TagNumber(5)
+  $core.bool hasFailed() => $_has(4);
+  @$pb.TagNumber(5)
+  void clearFailed() => clearField(5);
+
+  @$pb.TagNumber(6)
+  $core.Map<$core.String, $fixnum.Int64> get failuresByStep => $_getMap(5);
+
+  @$pb.TagNumber(7)
+  $7.StatusResponse get status => $_getN(6);
+  @$pb.TagNumber(7)
+  set status($7.StatusResponse v) { setField(7, v); }
+  @$pb.TagNumber(7)
+  $core.bool hasStatus() => $_has(6);
+  @$pb.TagNumber(7)
+  void clearStatus() => clearField(7);
+  @$pb.TagNumber(7)
+  $7.StatusResponse ensureStatus() => $_ensure(6);
+}
+
 /// ReleaseRequest releases queued notifications for immediate delivery.
 /// Used for batch processing where notifications are queued first, then released together.
 class ReleaseRequest extends $pb.GeneratedMessage {
@@ -800,6 +1118,112 @@ class ReleaseResponse extends $pb.GeneratedMessage {
   $core.List<$7.StatusResponse> get data => $_getList(0);
 }
 
+/// CancelRequest withdraws queued notifications before they are dispatched.
+/// Takes the same ID list as ReleaseRequest.
+class CancelRequest extends $pb.GeneratedMessage {
+  factory CancelRequest({
+    $core.Iterable<$core.String>? id,
+    $core.String? comment,
+  }) {
+    final $result = create();
+    if (id != null) {
+      $result.id.addAll(id);
+    }
+    if (comment != null) {
+      $result.comment = comment;
+    }
+    return $result;
+  }
+  CancelRequest._() : super();
+  factory CancelRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory CancelRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'CancelRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..pPS(1, _omitFieldNames ? '' : 'id')
+    ..aOS(2, _omitFieldNames ? '' : 'comment')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  CancelRequest clone() => CancelRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  CancelRequest copyWith(void Function(CancelRequest) updates) => super.copyWith((message) => updates(message as CancelRequest)) as CancelRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static CancelRequest create() => CancelRequest._();
+  CancelRequest createEmptyInstance() => create();
+  static $pb.PbList<CancelRequest> createRepeated() => $pb.PbList<CancelRequest>();
+  @$core.pragma('dart2js:noInline')
+  static CancelRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<CancelRequest>(create);
+  static CancelRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.List<$core.String> get id => $_getList(0);
+
+  @$pb.TagNumber(2)
+  $core.String get comment => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set comment($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasComment() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearComment() => clearField(2);
+}
+
+/// CancelResponse returns the outcome of canceling each notification.
+/// Notifications already published to a route keep their status and carry a too_late cancel result in the extras.
+class CancelResponse extends $pb.GeneratedMessage {
+  factory CancelResponse({
+    $core.Iterable<$7.StatusResponse>? data,
+  }) {
+    final $result = create();
+    if (data != null) {
+      $result.data.addAll(data);
+    }
+    return $result;
+  }
+  CancelResponse._() : super();
+  factory CancelResponse.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory CancelResponse.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'CancelResponse', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..pc<$7.StatusResponse>(1, _omitFieldNames ? '' : 'data', $pb.PbFieldType.PM, subBuilder: $7.StatusResponse.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  CancelResponse clone() => CancelResponse()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  CancelResponse copyWith(void Function(CancelResponse) updates) => super.copyWith((message) => updates(message as CancelResponse)) as CancelResponse;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static CancelResponse create() => CancelResponse._();
+  CancelResponse createEmptyInstance() => create();
+  static $pb.PbList<CancelResponse> createRepeated() => $pb.PbList<CancelResponse>();
+  @$core.pragma('dart2js:noInline')
+  static CancelResponse getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<CancelResponse>(create);
+  static CancelResponse? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.List<$7.StatusResponse> get data => $_getList(0);
+}
+
 /// ReceiveRequest acknowledges receipt of notifications by the client.
 /// Used for tracking delivery confirmation.
 class ReceiveRequest extends $pb.GeneratedMessage {
@@ -1029,13 +1453,14 @@ class TemplateSearchResponse extends $pb.GeneratedMessage {
   $core.List<Template> get data => $_getList(0);
 }
 
-/// TemplateSaveRequest creates or updates a notification template.
+/// TemplateSaveRequest saves a new draft version of a notification template.
 class TemplateSaveRequest extends $pb.GeneratedMessage {
   factory TemplateSaveRequest({
     $core.String? name,
     $core.String? languageCode,
     $6.Struct? data,
     $6.Struct? extra,
+    $core.bool? publish,
   }) {
     final $result = create();
     if (name != null) {
@@ -1050,6 +1475,9 @@ class TemplateSaveRequest extends $pb.GeneratedMessage {
     if (extra != null) {
       $result.extra = extra;
     }
+    if (publish != null) {
+      $result.publish = publish;
+    }
     return $result;
   }
   TemplateSaveRequest._() : super();
@@ -1061,6 +1489,7 @@ class TemplateSaveRequest extends $pb.GeneratedMessage {
     ..aOS(2, _omitFieldNames ? '' : 'languageCode')
     ..aOM<$6.Struct>(3, _omitFieldNames ? '' : 'data', subBuilder: $6.Struct.create)
     ..aOM<$6.Struct>(4, _omitFieldNames ? '' : 'extra', subBuilder: $6.Struct.create)
+    ..aOB(5, _omitFieldNames ? '' : 'publish')
     ..hasRequiredFields = false
   ;
 
@@ -1124,6 +1553,15 @@ class TemplateSaveRequest extends $pb.GeneratedMessage {
   void clearExtra() => clearField(4);
   @$pb.TagNumber(4)
   $6.Struct ensureExtra() => $_ensure(3);
+
+  @$pb.TagNumber(5)
+  $core.bool get publish => $_getBF(4);
+  @$pb.TagNumber(5)
+  set publish($core.bool v) { $_setBool(4, v); }
+  @$pb.TagNumber(5)
+  $core.bool hasPublish() => $_has(4);
+  @$pb.TagNumber(5)
+  void clearPublish() => clearField(5);
 }
 
 /// TemplateSaveResponse returns the saved template.
@@ -1179,16 +1617,1364 @@ class TemplateSaveResponse extends $pb.GeneratedMessage {
   Template ensureData() => $_ensure(0);
 }
 
-class NotificationServiceApi {
-  $pb.RpcClient _client;
-  NotificationServiceApi(this._client);
-
+/// TemplatePublishRequest makes a template version the one rendered for its name.
+class TemplatePublishRequest extends $pb.GeneratedMessage {
+  factory TemplatePublishRequest({
+    $core.String? id,
+  }) {
+    final $result = create();
+    if (id != null) {
+      $result.id = id;
+    }
+    return $result;
+  }
+  TemplatePublishRequest._() : super();
+  factory TemplatePublishRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory TemplatePublishRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'TemplatePublishRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'id')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  TemplatePublishRequest clone() => TemplatePublishRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  TemplatePublishRequest copyWith(void Function(TemplatePublishRequest) updates) => super.copyWith((message) => updates(message as TemplatePublishRequest)) as TemplatePublishRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static TemplatePublishRequest create() => TemplatePublishRequest._();
+  TemplatePublishRequest createEmptyInstance() => create();
+  static $pb.PbList<TemplatePublishRequest> createRepeated() => $pb.PbList<TemplatePublishRequest>();
+  @$core.pragma('dart2js:noInline')
+  static TemplatePublishRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<TemplatePublishRequest>(create);
+  static TemplatePublishRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get id => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set id($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasId() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearId() => clearField(1);
+}
+
+/// TemplatePublishResponse returns the published template version.
+class TemplatePublishResponse extends $pb.GeneratedMessage {
+  factory TemplatePublishResponse({
+    Template? data,
+  }) {
+    final $result = create();
+    if (data != null) {
+      $result.data = data;
+    }
+    return $result;
+  }
+  TemplatePublishResponse._() : super();
+  factory TemplatePublishResponse.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory TemplatePublishResponse.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'TemplatePublishResponse', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOM<Template>(1, _omitFieldNames ? '' : 'data', subBuilder: Template.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  TemplatePublishResponse clone() => TemplatePublishResponse()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  TemplatePublishResponse copyWith(void Function(TemplatePublishResponse) updates) => super.copyWith((message) => updates(message as TemplatePublishResponse)) as TemplatePublishResponse;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static TemplatePublishResponse create() => TemplatePublishResponse._();
+  TemplatePublishResponse createEmptyInstance() => create();
+  static $pb.PbList<TemplatePublishResponse> createRepeated() => $pb.PbList<TemplatePublishResponse>();
+  @$core.pragma('dart2js:noInline')
+  static TemplatePublishResponse getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<TemplatePublishResponse>(create);
+  static TemplatePublishResponse? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  Template get data => $_getN(0);
+  @$pb.TagNumber(1)
+  set data(Template v) { setField(1, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasData() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearData() => clearField(1);
+  @$pb.TagNumber(1)
+  Template ensureData() => $_ensure(0);
+}
+
+/// TemplateRollbackRequest publishes a version of a template that was published before.
+class TemplateRollbackRequest extends $pb.GeneratedMessage {
+  factory TemplateRollbackRequest({
+    $core.String? name,
+    $core.int? version,
+  }) {
+    final $result = create();
+    if (name != null) {
+      $result.name = name;
+    }
+    if (version != null) {
+      $result.version = version;
+    }
+    return $result;
+  }
+  TemplateRollbackRequest._() : super();
+  factory TemplateRollbackRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory TemplateRollbackRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'TemplateRollbackRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'name')
+    ..a<$core.int>(2, _omitFieldNames ? '' : 'version', $pb.PbFieldType.O3)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  TemplateRollbackRequest clone() => TemplateRollbackRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  TemplateRollbackRequest copyWith(void Function(TemplateRollbackRequest) updates) => super.copyWith((message) => updates(message as TemplateRollbackRequest)) as TemplateRollbackRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static TemplateRollbackRequest create() => TemplateRollbackRequest._();
+  TemplateRollbackRequest createEmptyInstance() => create();
+  static $pb.PbList<TemplateRollbackRequest> createRepeated() => $pb.PbList<TemplateRollbackRequest>();
+  @$core.pragma('dart2js:noInline')
+  static TemplateRollbackRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<TemplateRollbackRequest>(create);
+  static TemplateRollbackRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get name => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set name($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasName() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearName() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.int get version => $_getIZ(1);
+  @$pb.TagNumber(2)
+  set version($core.int v) { $_setSignedInt32(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasVersion() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearVersion() => clearField(2);
+}
+
+/// TemplateRollbackResponse returns the template version published again.
+class TemplateRollbackResponse extends $pb.GeneratedMessage {
+  factory TemplateRollbackResponse({
+    Template? data,
+  }) {
+    final $result = create();
+    if (data != null) {
+      $result.data = data;
+    }
+    return $result;
+  }
+  TemplateRollbackResponse._() : super();
+  factory TemplateRollbackResponse.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory TemplateRollbackResponse.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'TemplateRollbackResponse', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOM<Template>(1, _omitFieldNames ? '' : 'data', subBuilder: Template.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  TemplateRollbackResponse clone() => TemplateRollbackResponse()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  TemplateRollbackResponse copyWith(void Function(TemplateRollbackResponse) updates) => super.copyWith((message) => updates(message as TemplateRollbackResponse)) as TemplateRollbackResponse;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static TemplateRollbackResponse create() => TemplateRollbackResponse._();
+  TemplateRollbackResponse createEmptyInstance() => create();
+  static $pb.PbList<TemplateRollbackResponse> createRepeated() => $pb.PbList<TemplateRollbackResponse>();
+  @$core.pragma('dart2js:noInline')
+  static TemplateRollbackResponse getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<TemplateRollbackResponse>(create);
+  static TemplateRollbackResponse? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  Template get data => $_getN(0);
+  @$pb.TagNumber(1)
+  set data(Template v) { setField(1, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasData() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearData() => clearField(1);
+  @$pb.TagNumber(1)
+  Template ensureData() => $_ensure(0);
+}
+
+/// TemplateDataChange adds, replaces or removes the content of one type in one language.
+class TemplateDataChange extends $pb.GeneratedMessage {
+  factory TemplateDataChange({
+    $core.String? languageCode,
+    $core.String? type,
+    $core.String? detail,
+    $core.bool? remove,
+  }) {
+    final $result = create();
+    if (languageCode != null) {
+      $result.languageCode = languageCode;
+    }
+    if (type != null) {
+      $result.type = type;
+    }
+    if (detail != null) {
+      $result.detail = detail;
+    }
+    if (remove != null) {
+      $result.remove = remove;
+    }
+    return $result;
+  }
+  TemplateDataChange._() : super();
+  factory TemplateDataChange.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory TemplateDataChange.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'TemplateDataChange', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'languageCode')
+    ..aOS(2, _omitFieldNames ? '' : 'type')
+    ..aOS(3, _omitFieldNames ? '' : 'detail')
+    ..aOB(4, _omitFieldNames ? '' : 'remove')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  TemplateDataChange clone() => TemplateDataChange()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  TemplateDataChange copyWith(void Function(TemplateDataChange) updates) => super.copyWith((message) => updates(message as TemplateDataChange)) as TemplateDataChange;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static TemplateDataChange create() => TemplateDataChange._();
+  TemplateDataChange createEmptyInstance() => create();
+  static $pb.PbList<TemplateDataChange> createRepeated() => $pb.PbList<TemplateDataChange>();
+  @$core.pragma('dart2js:noInline')
+  static TemplateDataChange getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<TemplateDataChange>(create);
+  static TemplateDataChange? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get languageCode => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set languageCode($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasLanguageCode() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearLanguageCode() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get type => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set type($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasType() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearType() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get detail => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set detail($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasDetail() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearDetail() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.bool get remove => $_getBF(3);
+  @$pb.TagNumber(4)
+  set remove($core.bool v) { $_setBool(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasRemove() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearRemove() => clearField(4);
+}
+
+/// TemplateUpdateRequest changes a draft template version.
+class TemplateUpdateRequest extends $pb.GeneratedMessage {
+  factory TemplateUpdateRequest({
+    $core.String? id,
+    $6.Struct? extra,
+    $core.Iterable<TemplateDataChange>? data,
+  }) {
+    final $result = create();
+    if (id != null) {
+      $result.id = id;
+    }
+    if (extra != null) {
+      $result.extra = extra;
+    }
+    if (data != null) {
+      $result.data.addAll(data);
+    }
+    return $result;
+  }
+  TemplateUpdateRequest._() : super();
+  factory TemplateUpdateRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory TemplateUpdateRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'TemplateUpdateRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'id')
+    ..aOM<$6.Struct>(2, _omitFieldNames ? '' : 'extra', subBuilder: $6.Struct.create)
+    ..pc<TemplateDataChange>(3, _omitFieldNames ? '' : 'data', $pb.PbFieldType.PM, subBuilder: TemplateDataChange.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  TemplateUpdateRequest clone() => TemplateUpdateRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  TemplateUpdateRequest copyWith(void Function(TemplateUpdateRequest) updates) => super.copyWith((message) => updates(message as TemplateUpdateRequest)) as TemplateUpdateRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static TemplateUpdateRequest create() => TemplateUpdateRequest._();
+  TemplateUpdateRequest createEmptyInstance() => create();
+  static $pb.PbList<TemplateUpdateRequest> createRepeated() => $pb.PbList<TemplateUpdateRequest>();
+  @$core.pragma('dart2js:noInline')
+  static TemplateUpdateRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<TemplateUpdateRequest>(create);
+  static TemplateUpdateRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get id => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set id($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasId() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearId() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $6.Struct get extra => $_getN(1);
+  @$pb.TagNumber(2)
+  set extra($6.Struct v) { setField(2, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasExtra() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearExtra() => clearField(2);
+  @$pb.TagNumber(2)
+  $6.Struct ensureExtra() => $_ensure(1);
+
+  @$pb.TagNumber(3)
+  $core.List<TemplateDataChange> get data => $_getList(2);
+}
+
+/// TemplateUpdateResponse returns the updated template version.
+class TemplateUpdateResponse extends $pb.GeneratedMessage {
+  factory TemplateUpdateResponse({
+    Template? data,
+  }) {
+    final $result = create();
+    if (data != null) {
+      $result.data = data;
+    }
+    return $result;
+  }
+  TemplateUpdateResponse._() : super();
+  factory TemplateUpdateResponse.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory TemplateUpdateResponse.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'TemplateUpdateResponse', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOM<Template>(1, _omitFieldNames ? '' : 'data', subBuilder: Template.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  TemplateUpdateResponse clone() => TemplateUpdateResponse()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  TemplateUpdateResponse copyWith(void Function(TemplateUpdateResponse) updates) => super.copyWith((message) => updates(message as TemplateUpdateResponse)) as TemplateUpdateResponse;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static TemplateUpdateResponse create() => TemplateUpdateResponse._();
+  TemplateUpdateResponse createEmptyInstance() => create();
+  static $pb.PbList<TemplateUpdateResponse> createRepeated() => $pb.PbList<TemplateUpdateResponse>();
+  @$core.pragma('dart2js:noInline')
+  static TemplateUpdateResponse getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<TemplateUpdateResponse>(create);
+  static TemplateUpdateResponse? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  Template get data => $_getN(0);
+  @$pb.TagNumber(1)
+  set data(Template v) { setField(1, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasData() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearData() => clearField(1);
+  @$pb.TagNumber(1)
+  Template ensureData() => $_ensure(0);
+}
+
+/// TemplateDeleteRequest removes a template version and its content.
+class TemplateDeleteRequest extends $pb.GeneratedMessage {
+  factory TemplateDeleteRequest({
+    $core.String? id,
+  }) {
+    final $result = create();
+    if (id != null) {
+      $result.id = id;
+    }
+    return $result;
+  }
+  TemplateDeleteRequest._() : super();
+  factory TemplateDeleteRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory TemplateDeleteRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'TemplateDeleteRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'id')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  TemplateDeleteRequest clone() => TemplateDeleteRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  TemplateDeleteRequest copyWith(void Function(TemplateDeleteRequest) updates) => super.copyWith((message) => updates(message as TemplateDeleteRequest)) as TemplateDeleteRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static TemplateDeleteRequest create() => TemplateDeleteRequest._();
+  TemplateDeleteRequest createEmptyInstance() => create();
+  static $pb.PbList<TemplateDeleteRequest> createRepeated() => $pb.PbList<TemplateDeleteRequest>();
+  @$core.pragma('dart2js:noInline')
+  static TemplateDeleteRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<TemplateDeleteRequest>(create);
+  static TemplateDeleteRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get id => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set id($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasId() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearId() => clearField(1);
+}
+
+/// TemplateDeleteResponse confirms the removal.
+class TemplateDeleteResponse extends $pb.GeneratedMessage {
+  factory TemplateDeleteResponse({
+    $core.Iterable<$core.String>? id,
+  }) {
+    final $result = create();
+    if (id != null) {
+      $result.id.addAll(id);
+    }
+    return $result;
+  }
+  TemplateDeleteResponse._() : super();
+  factory TemplateDeleteResponse.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory TemplateDeleteResponse.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'TemplateDeleteResponse', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..pPS(1, _omitFieldNames ? '' : 'id')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  TemplateDeleteResponse clone() => TemplateDeleteResponse()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  TemplateDeleteResponse copyWith(void Function(TemplateDeleteResponse) updates) => super.copyWith((message) => updates(message as TemplateDeleteResponse)) as TemplateDeleteResponse;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static TemplateDeleteResponse create() => TemplateDeleteResponse._();
+  TemplateDeleteResponse createEmptyInstance() => create();
+  static $pb.PbList<TemplateDeleteResponse> createRepeated() => $pb.PbList<TemplateDeleteResponse>();
+  @$core.pragma('dart2js:noInline')
+  static TemplateDeleteResponse getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<TemplateDeleteResponse>(create);
+  static TemplateDeleteResponse? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.List<$core.String> get id => $_getList(0);
+}
+
+/// TemplatePreviewRequest renders a template against a sample payload without sending anything.
+class TemplatePreviewRequest extends $pb.GeneratedMessage {
+  factory TemplatePreviewRequest({
+    $core.String? template,
+    $core.String? languageCode,
+    $6.Struct? payload,
+    $core.String? partitionId,
+  }) {
+    final $result = create();
+    if (template != null) {
+      $result.template = template;
+    }
+    if (languageCode != null) {
+      $result.languageCode = languageCode;
+    }
+    if (payload != null) {
+      $result.payload = payload;
+    }
+    if (partitionId != null) {
+      $result.partitionId = partitionId;
+    }
+    return $result;
+  }
+  TemplatePreviewRequest._() : super();
+  factory TemplatePreviewRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory TemplatePreviewRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'TemplatePreviewRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'template')
+    ..aOS(2, _omitFieldNames ? '' : 'languageCode')
+    ..aOM<$6.Struct>(3, _omitFieldNames ? '' : 'payload', subBuilder: $6.Struct.create)
+    ..aOS(4, _omitFieldNames ? '' : 'partitionId')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  TemplatePreviewRequest clone() => TemplatePreviewRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  TemplatePreviewRequest copyWith(void Function(TemplatePreviewRequest) updates) => super.copyWith((message) => updates(message as TemplatePreviewRequest)) as TemplatePreviewRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static TemplatePreviewRequest create() => TemplatePreviewRequest._();
+  TemplatePreviewRequest createEmptyInstance() => create();
+  static $pb.PbList<TemplatePreviewRequest> createRepeated() => $pb.PbList<TemplatePreviewRequest>();
+  @$core.pragma('dart2js:noInline')
+  static TemplatePreviewRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<TemplatePreviewRequest>(create);
+  static TemplatePreviewRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get template => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set template($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasTemplate() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearTemplate() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get languageCode => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set languageCode($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasLanguageCode() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearLanguageCode() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $6.Struct get payload => $_getN(2);
+  @$pb.TagNumber(3)
+  set payload($6.Struct v) { setField(3, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasPayload() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearPayload() => clearField(3);
+  @$pb.TagNumber(3)
+  $6.Struct ensurePayload() => $_ensure(2);
+
+  @$pb.TagNumber(4)
+  $core.String get partitionId => $_getSZ(3);
+  @$pb.TagNumber(4)
+  set partitionId($core.String v) { $_setString(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasPartitionId() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearPartitionId() => clearField(4);
+}
+
+/// TemplatePreviewError describes why the content of one type could not be rendered.
+class TemplatePreviewError extends $pb.GeneratedMessage {
+  factory TemplatePreviewError({
+    $core.String? type,
+    $core.String? variable,
+    $core.String? message,
+  }) {
+    final $result = create();
+    if (type != null) {
+      $result.type = type;
+    }
+    if (variable != null) {
+      $result.variable = variable;
+    }
+    if (message != null) {
+      $result.message = message;
+    }
+    return $result;
+  }
+  TemplatePreviewError._() : super();
+  factory TemplatePreviewError.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory TemplatePreviewError.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'TemplatePreviewError', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'type')
+    ..aOS(2, _omitFieldNames ? '' : 'variable')
+    ..aOS(3, _omitFieldNames ? '' : 'message')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  TemplatePreviewError clone() => TemplatePreviewError()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  TemplatePreviewError copyWith(void Function(TemplatePreviewError) updates) => super.copyWith((message) => updates(message as TemplatePreviewError)) as TemplatePreviewError;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static TemplatePreviewError create() => TemplatePreviewError._();
+  TemplatePreviewError createEmptyInstance() => create();
+  static $pb.PbList<TemplatePreviewError> createRepeated() => $pb.PbList<TemplatePreviewError>();
+  @$core.pragma('dart2js:noInline')
+  static TemplatePreviewError getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<TemplatePreviewError>(create);
+  static TemplatePreviewError? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get type => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set type($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasType() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearType() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get variable => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set variable($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasVariable() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearVariable() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get message => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set message($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasMessage() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearMessage() => clearField(3);
+}
+
+/// TemplatePreviewResponse returns the content a notification using the template would carry.
+class TemplatePreviewResponse extends $pb.GeneratedMessage {
+  factory TemplatePreviewResponse({
+    Template? template,
+    $core.Map<$core.String, $core.String>? rendered,
+    $core.Map<$core.String, $core.String>? supportContacts,
+    $core.Iterable<TemplatePreviewError>? errors,
+  }) {
+    final $result = create();
+    if (template != null) {
+      $result.template = template;
+    }
+    if (rendered != null) {
+      $result.rendered.addAll(rendered);
+    }
+    if (supportContacts != null) {
+      $result.supportContacts.addAll(supportContacts);
+    }
+    if (errors != null) {
+      $result.errors.addAll(errors);
+    }
+    return $result;
+  }
+  TemplatePreviewResponse._() : super();
+  factory TemplatePreviewResponse.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory TemplatePreviewResponse.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'TemplatePreviewResponse', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOM<Template>(1, _omitFieldNames ? '' : 'template', subBuilder: Template.create)
+    ..m<$core.String, $core.String>(2, _omitFieldNames ? '' : 'rendered', entryClassName: 'TemplatePreviewResponse.RenderedEntry', keyFieldType: $pb.PbFieldType.OS, valueFieldType: $pb.PbFieldType.OS, packageName: const $pb.PackageName('notification.v1'))
+    ..m<$core.String, $core.String>(3, _omitFieldNames ? '' : 'supportContacts', entryClassName: 'TemplatePreviewResponse.SupportContactsEntry', keyFieldType: $pb.PbFieldType.OS, valueFieldType: $pb.PbFieldType.OS, packageName: const $pb.PackageName('notification.v1'))
+    ..pc<TemplatePreviewError>(4, _omitFieldNames ? '' : 'errors', $pb.PbFieldType.PM, subBuilder: TemplatePreviewError.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  TemplatePreviewResponse clone() => TemplatePreviewResponse()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  TemplatePreviewResponse copyWith(void Function(TemplatePreviewResponse) updates) => super.copyWith((message) => updates(message as TemplatePreviewResponse)) as TemplatePreviewResponse;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static TemplatePreviewResponse create() => TemplatePreviewResponse._();
+  TemplatePreviewResponse createEmptyInstance() => create();
+  static $pb.PbList<TemplatePreviewResponse> createRepeated() => $pb.PbList<TemplatePreviewResponse>();
+  @$core.pragma('dart2js:noInline')
+  static TemplatePreviewResponse getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<TemplatePreviewResponse>(create);
+  static TemplatePreviewResponse? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  Template get template => $_getN(0);
+  @$pb.TagNumber(1)
+  set template(Template v) { setField(1, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasTemplate() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearTemplate() => clearField(1);
+  @$pb.TagNumber(1)
+  Template ensureTemplate() => $_ensure(0);
+
+  @$pb.TagNumber(2)
+  $core.Map<$core.String, $core.String> get rendered => $_getMap(1);
+
+  @$pb.TagNumber(3)
+  $core.Map<$core.String, $core.String> get supportContacts => $_getMap(2);
+
+  @$pb.TagNumber(4)
+  $core.List<TemplatePreviewError> get errors => $_getList(3);
+}
+
+/// Suppression stops outbound notifications from reaching a contact that opted out.
+/// Empty channel or sender_id fields widen the suppression to every value.
+class Suppression extends $pb.GeneratedMessage {
+  factory Suppression({
+    $core.String? id,
+    $core.String? contact,
+    $core.String? channel,
+    $core.String? senderId,
+    $core.String? routeId,
+    $core.String? reason,
+    $core.String? source,
+    $6.Struct? extras,
+    $core.String? createdAt,
+  }) {
+    final $result = create();
+    if (id != null) {
+      $result.id = id;
+    }
+    if (contact != null) {
+      $result.contact = contact;
+    }
+    if (channel != null) {
+      $result.channel = channel;
+    }
+    if (senderId != null) {
+      $result.senderId = senderId;
+    }
+    if (routeId != null) {
+      $result.routeId = routeId;
+    }
+    if (reason != null) {
+      $result.reason = reason;
+    }
+    if (source != null) {
+      $result.source = source;
+    }
+    if (extras != null) {
+      $result.extras = extras;
+    }
+    if (createdAt != null) {
+      $result.createdAt = createdAt;
+    }
+    return $result;
+  }
+  Suppression._() : super();
+  factory Suppression.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory Suppression.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'Suppression', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'id')
+    ..aOS(2, _omitFieldNames ? '' : 'contact')
+    ..aOS(3, _omitFieldNames ? '' : 'channel')
+    ..aOS(4, _omitFieldNames ? '' : 'senderId')
+    ..aOS(5, _omitFieldNames ? '' : 'routeId')
+    ..aOS(6, _omitFieldNames ? '' : 'reason')
+    ..aOS(7, _omitFieldNames ? '' : 'source')
+    ..aOM<$6.Struct>(8, _omitFieldNames ? '' : 'extras', subBuilder: $6.Struct.create)
+    ..aOS(9, _omitFieldNames ? '' : 'createdAt')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  Suppression clone() => Suppression()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  Suppression copyWith(void Function(Suppression) updates) => super.copyWith((message) => updates(message as Suppression)) as Suppression;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static Suppression create() => Suppression._();
+  Suppression createEmptyInstance() => create();
+  static $pb.PbList<Suppression> createRepeated() => $pb.PbList<Suppression>();
+  @$core.pragma('dart2js:noInline')
+  static Suppression getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<Suppression>(create);
+  static Suppression? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get id => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set id($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasId() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearId() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get contact => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set contact($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasContact() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearContact() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get channel => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set channel($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasChannel() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearChannel() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.String get senderId => $_getSZ(3);
+  @$pb.TagNumber(4)
+  set senderId($core.String v) { $_setString(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasSenderId() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearSenderId() => clearField(4);
+
+  @$pb.TagNumber(5)
+  $core.String get routeId => $_getSZ(4);
+  @$pb.TagNumber(5)
+  set routeId($core.String v) { $_setString(4, v); }
+  @$pb.TagNumber(5)
+  $core.bool hasRouteId() => $_has(4);
+  @$pb.TagNumber(5)
+  void clearRouteId() => clearField(5);
+
+  @$pb.TagNumber(6)
+  $core.String get reason => $_getSZ(5);
+  @$pb.TagNumber(6)
+  set reason($core.String v) { $_setString(5, v); }
+  @$pb.TagNumber(6)
+  $core.bool hasReason() => $_has(5);
+  @$pb.TagNumber(6)
+  void clearReason() => clearField(6);
+
+  @$pb.TagNumber(7)
+  $core.String get source => $_getSZ(6);
+  @$pb.TagNumber(7)
+  set source($core.String v) { $_setString(6, v); }
+  @$pb.TagNumber(7)
+  $core.bool hasSource() => $_has(6);
+  @$pb.TagNumber(7)
+  void clearSource() => clearField(7);
+
+  @$pb.TagNumber(8)
+  $6.Struct get extras => $_getN(7);
+  @$pb.TagNumber(8)
+  set extras($6.Struct v) { setField(8, v); }
+  @$pb.TagNumber(8)
+  $core.bool hasExtras() => $_has(7);
+  @$pb.TagNumber(8)
+  void clearExtras() => clearField(8);
+  @$pb.TagNumber(8)
+  $6.Struct ensureExtras() => $_ensure(7);
+
+  @$pb.TagNumber(9)
+  $core.String get createdAt => $_getSZ(8);
+  @$pb.TagNumber(9)
+  set createdAt($core.String v) { $_setString(8, v); }
+  @$pb.TagNumber(9)
+  $core.bool hasCreatedAt() => $_has(8);
+  @$pb.TagNumber(9)
+  void clearCreatedAt() => clearField(9);
+}
+
+/// SuppressionAddRequest records a contact that must no longer receive notifications.
+class SuppressionAddRequest extends $pb.GeneratedMessage {
+  factory SuppressionAddRequest({
+    Suppression? data,
+  }) {
+    final $result = create();
+    if (data != null) {
+      $result.data = data;
+    }
+    return $result;
+  }
+  SuppressionAddRequest._() : super();
+  factory SuppressionAddRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory SuppressionAddRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'SuppressionAddRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOM<Suppression>(1, _omitFieldNames ? '' : 'data', subBuilder: Suppression.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  SuppressionAddRequest clone() => SuppressionAddRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  SuppressionAddRequest copyWith(void Function(SuppressionAddRequest) updates) => super.copyWith((message) => updates(message as SuppressionAddRequest)) as SuppressionAddRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static SuppressionAddRequest create() => SuppressionAddRequest._();
+  SuppressionAddRequest createEmptyInstance() => create();
+  static $pb.PbList<SuppressionAddRequest> createRepeated() => $pb.PbList<SuppressionAddRequest>();
+  @$core.pragma('dart2js:noInline')
+  static SuppressionAddRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<SuppressionAddRequest>(create);
+  static SuppressionAddRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  Suppression get data => $_getN(0);
+  @$pb.TagNumber(1)
+  set data(Suppression v) { setField(1, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasData() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearData() => clearField(1);
+  @$pb.TagNumber(1)
+  Suppression ensureData() => $_ensure(0);
+}
+
+/// SuppressionAddResponse returns the recorded suppression.
+class SuppressionAddResponse extends $pb.GeneratedMessage {
+  factory SuppressionAddResponse({
+    Suppression? data,
+  }) {
+    final $result = create();
+    if (data != null) {
+      $result.data = data;
+    }
+    return $result;
+  }
+  SuppressionAddResponse._() : super();
+  factory SuppressionAddResponse.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory SuppressionAddResponse.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'SuppressionAddResponse', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOM<Suppression>(1, _omitFieldNames ? '' : 'data', subBuilder: Suppression.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  SuppressionAddResponse clone() => SuppressionAddResponse()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  SuppressionAddResponse copyWith(void Function(SuppressionAddResponse) updates) => super.copyWith((message) => updates(message as SuppressionAddResponse)) as SuppressionAddResponse;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static SuppressionAddResponse create() => SuppressionAddResponse._();
+  SuppressionAddResponse createEmptyInstance() => create();
+  static $pb.PbList<SuppressionAddResponse> createRepeated() => $pb.PbList<SuppressionAddResponse>();
+  @$core.pragma('dart2js:noInline')
+  static SuppressionAddResponse getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<SuppressionAddResponse>(create);
+  static SuppressionAddResponse? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  Suppression get data => $_getN(0);
+  @$pb.TagNumber(1)
+  set data(Suppression v) { setField(1, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasData() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearData() => clearField(1);
+  @$pb.TagNumber(1)
+  Suppression ensureData() => $_ensure(0);
+}
+
+/// SuppressionRemoveRequest lifts a suppression, either by its ID or by its exact
+/// contact, channel and sender.
+class SuppressionRemoveRequest extends $pb.GeneratedMessage {
+  factory SuppressionRemoveRequest({
+    $core.String? id,
+    $core.String? contact,
+    $core.String? channel,
+    $core.String? senderId,
+  }) {
+    final $result = create();
+    if (id != null) {
+      $result.id = id;
+    }
+    if (contact != null) {
+      $result.contact = contact;
+    }
+    if (channel != null) {
+      $result.channel = channel;
+    }
+    if (senderId != null) {
+      $result.senderId = senderId;
+    }
+    return $result;
+  }
+  SuppressionRemoveRequest._() : super();
+  factory SuppressionRemoveRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory SuppressionRemoveRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'SuppressionRemoveRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'id')
+    ..aOS(2, _omitFieldNames ? '' : 'contact')
+    ..aOS(3, _omitFieldNames ? '' : 'channel')
+    ..aOS(4, _omitFieldNames ? '' : 'senderId')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  SuppressionRemoveRequest clone() => SuppressionRemoveRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  SuppressionRemoveRequest copyWith(void Function(SuppressionRemoveRequest) updates) => super.copyWith((message) => updates(message as SuppressionRemoveRequest)) as SuppressionRemoveRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static SuppressionRemoveRequest create() => SuppressionRemoveRequest._();
+  SuppressionRemoveRequest createEmptyInstance() => create();
+  static $pb.PbList<SuppressionRemoveRequest> createRepeated() => $pb.PbList<SuppressionRemoveRequest>();
+  @$core.pragma('dart2js:noInline')
+  static SuppressionRemoveRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<SuppressionRemoveRequest>(create);
+  static SuppressionRemoveRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get id => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set id($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasId() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearId() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get contact => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set contact($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasContact() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearContact() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $core.String get channel => $_getSZ(2);
+  @$pb.TagNumber(3)
+  set channel($core.String v) { $_setString(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasChannel() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearChannel() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.String get senderId => $_getSZ(3);
+  @$pb.TagNumber(4)
+  set senderId($core.String v) { $_setString(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasSenderId() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearSenderId() => clearField(4);
+}
+
+/// SuppressionRemoveResponse lists the suppressions that were lifted.
+class SuppressionRemoveResponse extends $pb.GeneratedMessage {
+  factory SuppressionRemoveResponse({
+    $core.Iterable<$core.String>? id,
+  }) {
+    final $result = create();
+    if (id != null) {
+      $result.id.addAll(id);
+    }
+    return $result;
+  }
+  SuppressionRemoveResponse._() : super();
+  factory SuppressionRemoveResponse.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory SuppressionRemoveResponse.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'SuppressionRemoveResponse', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..pPS(1, _omitFieldNames ? '' : 'id')
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  SuppressionRemoveResponse clone() => SuppressionRemoveResponse()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  SuppressionRemoveResponse copyWith(void Function(SuppressionRemoveResponse) updates) => super.copyWith((message) => updates(message as SuppressionRemoveResponse)) as SuppressionRemoveResponse;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static SuppressionRemoveResponse create() => SuppressionRemoveResponse._();
+  SuppressionRemoveResponse createEmptyInstance() => create();
+  static $pb.PbList<SuppressionRemoveResponse> createRepeated() => $pb.PbList<SuppressionRemoveResponse>();
+  @$core.pragma('dart2js:noInline')
+  static SuppressionRemoveResponse getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<SuppressionRemoveResponse>(create);
+  static SuppressionRemoveResponse? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.List<$core.String> get id => $_getList(0);
+}
+
+/// SuppressionSearchRequest finds suppressions for compliance review.
+class SuppressionSearchRequest extends $pb.GeneratedMessage {
+  factory SuppressionSearchRequest({
+    $core.String? query,
+    $core.String? channel,
+    $fixnum.Int64? page,
+    $core.int? count,
+  }) {
+    final $result = create();
+    if (query != null) {
+      $result.query = query;
+    }
+    if (channel != null) {
+      $result.channel = channel;
+    }
+    if (page != null) {
+      $result.page = page;
+    }
+    if (count != null) {
+      $result.count = count;
+    }
+    return $result;
+  }
+  SuppressionSearchRequest._() : super();
+  factory SuppressionSearchRequest.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory SuppressionSearchRequest.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'SuppressionSearchRequest', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..aOS(1, _omitFieldNames ? '' : 'query')
+    ..aOS(2, _omitFieldNames ? '' : 'channel')
+    ..aInt64(3, _omitFieldNames ? '' : 'page')
+    ..a<$core.int>(4, _omitFieldNames ? '' : 'count', $pb.PbFieldType.O3)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  SuppressionSearchRequest clone() => SuppressionSearchRequest()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  SuppressionSearchRequest copyWith(void Function(SuppressionSearchRequest) updates) => super.copyWith((message) => updates(message as SuppressionSearchRequest)) as SuppressionSearchRequest;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static SuppressionSearchRequest create() => SuppressionSearchRequest._();
+  SuppressionSearchRequest createEmptyInstance() => create();
+  static $pb.PbList<SuppressionSearchRequest> createRepeated() => $pb.PbList<SuppressionSearchRequest>();
+  @$core.pragma('dart2js:noInline')
+  static SuppressionSearchRequest getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<SuppressionSearchRequest>(create);
+  static SuppressionSearchRequest? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.String get query => $_getSZ(0);
+  @$pb.TagNumber(1)
+  set query($core.String v) { $_setString(0, v); }
+  @$pb.TagNumber(1)
+  $core.bool hasQuery() => $_has(0);
+  @$pb.TagNumber(1)
+  void clearQuery() => clearField(1);
+
+  @$pb.TagNumber(2)
+  $core.String get channel => $_getSZ(1);
+  @$pb.TagNumber(2)
+  set channel($core.String v) { $_setString(1, v); }
+  @$pb.TagNumber(2)
+  $core.bool hasChannel() => $_has(1);
+  @$pb.TagNumber(2)
+  void clearChannel() => clearField(2);
+
+  @$pb.TagNumber(3)
+  $fixnum.Int64 get page => $_getI64(2);
+  @$pb.TagNumber(3)
+  set page($fixnum.Int64 v) { $_setInt64(2, v); }
+  @$pb.TagNumber(3)
+  $core.bool hasPage() => $_has(2);
+  @$pb.TagNumber(3)
+  void clearPage() => clearField(3);
+
+  @$pb.TagNumber(4)
+  $core.int get count => $_getIZ(3);
+  @$pb.TagNumber(4)
+  set count($core.int v) { $_setSignedInt32(3, v); }
+  @$pb.TagNumber(4)
+  $core.bool hasCount() => $_has(3);
+  @$pb.TagNumber(4)
+  void clearCount() => clearField(4);
+}
+
+/// SuppressionSearchResponse returns matching suppressions.
+class SuppressionSearchResponse extends $pb.GeneratedMessage {
+  factory SuppressionSearchResponse({
+    $core.Iterable<Suppression>? data,
+  }) {
+    final $result = create();
+    if (data != null) {
+      $result.data.addAll(data);
+    }
+    return $result;
+  }
+  SuppressionSearchResponse._() : super();
+  factory SuppressionSearchResponse.fromBuffer($core.List<$core.int> i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromBuffer(i, r);
+  factory SuppressionSearchResponse.fromJson($core.String i, [$pb.ExtensionRegistry r = $pb.ExtensionRegistry.EMPTY]) => create()..mergeFromJson(i, r);
+
+  static final $pb.BuilderInfo _i = $pb.BuilderInfo(_omitMessageNames ? '' : 'SuppressionSearchResponse', package: const $pb.PackageName(_omitMessageNames ? '' : 'notification.v1'), createEmptyInstance: create)
+    ..pc<Suppression>(1, _omitFieldNames ? '' : 'data', $pb.PbFieldType.PM, subBuilder: Suppression.create)
+    ..hasRequiredFields = false
+  ;
+
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.deepCopy] instead. '
+  'Will be removed in next major version')
+  SuppressionSearchResponse clone() => SuppressionSearchResponse()..mergeFromMessage(this);
+  @$core.Deprecated(
+  'Using this can add significant overhead to your binary. '
+  'Use [GeneratedMessageGenericExtensions.rebuild] instead. '
+  'Will be removed in next major version')
+  SuppressionSearchResponse copyWith(void Function(SuppressionSearchResponse) updates) => super.copyWith((message) => updates(message as SuppressionSearchResponse)) as SuppressionSearchResponse;
+
+  $pb.BuilderInfo get info_ => _i;
+
+  @$core.pragma('dart2js:noInline')
+  static SuppressionSearchResponse create() => SuppressionSearchResponse._();
+  SuppressionSearchResponse createEmptyInstance() => create();
+  static $pb.PbList<SuppressionSearchResponse> createRepeated() => $pb.PbList<SuppressionSearchResponse>();
+  @$core.pragma('dart2js:noInline')
+  static SuppressionSearchResponse getDefault() => _defaultInstance ??= $pb.GeneratedMessage.$_defaultFor<SuppressionSearchResponse>(create);
+  static SuppressionSearchResponse? _defaultInstance;
+
+  @$pb.TagNumber(1)
+  $core.List<Suppression> get data => $_getList(0);
+}
+
+class NotificationServiceApi {
+  $pb.RpcClient _client;
+  NotificationServiceApi(this._client);
+
   $async.Future<SendResponse> send($pb.ClientContext? ctx, SendRequest request) =>
     _client.invoke<SendResponse>(ctx, 'NotificationService', 'Send', request, SendResponse())
   ;
+  $async.Future<BroadcastResponse> broadcast($pb.ClientContext? ctx, BroadcastRequest request) =>
+    _client.invoke<BroadcastResponse>(ctx, 'NotificationService', 'Broadcast', request, BroadcastResponse())
+  ;
+  $async.Future<BroadcastStatusResponse> broadcastStatus($pb.ClientContext? ctx, $7.StatusRequest request) =>
+    _client.invoke<BroadcastStatusResponse>(ctx, 'NotificationService', 'BroadcastStatus', request, BroadcastStatusResponse())
+  ;
   $async.Future<ReleaseResponse> release($pb.ClientContext? ctx, ReleaseRequest request) =>
     _client.invoke<ReleaseResponse>(ctx, 'NotificationService', 'Release', request, ReleaseResponse())
   ;
+  $async.Future<CancelResponse> cancel($pb.ClientContext? ctx, CancelRequest request) =>
+    _client.invoke<CancelResponse>(ctx, 'NotificationService', 'Cancel', request, CancelResponse())
+  ;
   $async.Future<ReceiveResponse> receive($pb.ClientContext? ctx, ReceiveRequest request) =>
     _client.invoke<ReceiveResponse>(ctx, 'NotificationService', 'Receive', request, ReceiveResponse())
   ;
@@ -1207,6 +2993,30 @@ class NotificationServiceApi {
   $async.Future<TemplateSaveResponse> templateSave($pb.ClientContext? ctx, TemplateSaveRequest request) =>
     _client.invoke<TemplateSaveResponse>(ctx, 'NotificationService', 'TemplateSave', request, TemplateSaveResponse())
   ;
+  $async.Future<TemplatePublishResponse> templatePublish($pb.ClientContext? ctx, TemplatePublishRequest request) =>
+    _client.invoke<TemplatePublishResponse>(ctx, 'NotificationService', 'TemplatePublish', request, TemplatePublishResponse())
+  ;
+  $async.Future<TemplateRollbackResponse> templateRollback($pb.ClientContext? ctx, TemplateRollbackRequest request) =>
+    _client.invoke<TemplateRollbackResponse>(ctx, 'NotificationService', 'TemplateRollback', request, TemplateRollbackResponse())
+  ;
+  $async.Future<TemplateUpdateResponse> templateUpdate($pb.ClientContext? ctx, TemplateUpdateRequest request) =>
+    _client.invoke<TemplateUpdateResponse>(ctx, 'NotificationService', 'TemplateUpdate', request, TemplateUpdateResponse())
+  ;
+  $async.Future<TemplateDeleteResponse> templateDelete($pb.ClientContext? ctx, TemplateDeleteRequest request) =>
+    _client.invoke<TemplateDeleteResponse>(ctx, 'NotificationService', 'TemplateDelete', request, TemplateDeleteResponse())
+  ;
+  $async.Future<TemplatePreviewResponse> templatePreview($pb.ClientContext? ctx, TemplatePreviewRequest request) =>
+    _client.invoke<TemplatePreviewResponse>(ctx, 'NotificationService', 'TemplatePreview', request, TemplatePreviewResponse())
+  ;
+  $async.Future<SuppressionSearchResponse> suppressionSearch($pb.ClientContext? ctx, SuppressionSearchRequest request) =>
+    _client.invoke<SuppressionSearchResponse>(ctx, 'NotificationService', 'SuppressionSearch', request, SuppressionSearchResponse())
+  ;
+  $async.Future<SuppressionAddResponse> suppressionAdd($pb.ClientContext? ctx, SuppressionAddRequest request) =>
+    _client.invoke<SuppressionAddResponse>(ctx, 'NotificationService', 'SuppressionAdd', request, SuppressionAddResponse())
+  ;
+  $async.Future<SuppressionRemoveResponse> suppressionRemove($pb.ClientContext? ctx, SuppressionRemoveRequest request) =>
+    _client.invoke<SuppressionRemoveResponse>(ctx, 'NotificationService', 'SuppressionRemove', request, SuppressionRemoveResponse())
+  ;
 }
 
 
@@ -72,6 +72,9 @@ const Template$json = {
     {'1': 'name', '3': 2, '4': 1, '5': 9, '10': 'name'},
     {'1': 'data', '3': 4, '4': 3, '5': 11, '6': '.notification.v1.TemplateData', '10': 'data'},
     {'1': 'extra', '3': 5, '4': 1, '5': 11, '6': '.google.protobuf.Struct', '10': 'extra'},
+    {'1': 'version', '3': 6, '4': 1, '5': 5, '8': {}, '10': 'version'},
+    {'1': 'state', '3': 7, '4': 1, '5': 9, '8': {}, '10': 'state'},
+    {'1': 'published_at', '3': 8, '4': 1, '5': 9, '8': {}, '10': 'publishedAt'},
   ],
 };
 
@@ -80,7 +83,9 @@ final $typed_data.Uint8List templateDescriptor = $convert.base64Decode(
     'CghUZW1wbGF0ZRIuCgJpZBgBIAEoCUIeukgb2AEBchYQAxgoMhBbMC05YS16Xy1dezMsNDB9Ug'
     'JpZBISCgRuYW1lGAIgASgJUgRuYW1lEjEKBGRhdGEYBCADKAsyHS5ub3RpZmljYXRpb24udjEu'
     'VGVtcGxhdGVEYXRhUgRkYXRhEi0KBWV4dHJhGAUgASgLMhcuZ29vZ2xlLnByb3RvYnVmLlN0cn'
-    'VjdFIFZXh0cmE=');
+    'VjdFIFZXh0cmESIAoHdmVyc2lvbhgGIAEoBUIGukgD2AEDUgd2ZXJzaW9uEhwKBXN0YXRlGAcg'
+    'ASgJQga6SAPYAQNSBXN0YXRlEikKDHB1Ymxpc2hlZF9hdBgIIAEoCUIGukgD2AEDUgtwdWJsaX'
+    'NoZWRBdA==');
 
 @$core.Deprecated('Use notificationDescriptor instead')
 const Notification$json = {
@@ -101,6 +106,7 @@ const Notification$json = {
     {'1': 'status', '3': 14, '4': 1, '5': 11, '6': '.common.v1.StatusResponse', '8': {}, '10': 'status'},
     {'1': 'extras', '3': 15, '4': 1, '5': 11, '6': '.google.protobuf.Struct', '10': 'extras'},
     {'1': 'priority', '3': 16, '4': 1, '5': 14, '6': '.notification.v1.PRIORITY', '10': 'priority'},
+    {'1': 'idempotency_key', '3': 17, '4': 1, '5': 9, '8': {}, '10': 'idempotencyKey'},
   ],
 };
 
@@ -117,7 +123,8 @@ final $typed_data.Uint8List notificationDescriptor = $convert.base64Decode(
     'ZV9pZBgNIAEoCUIeukgb2AEBchYQAxgoMhBbMC05YS16Xy1dezMsNDB9Ugdyb3V0ZUlkEjkKBn'
     'N0YXR1cxgOIAEoCzIZLmNvbW1vbi52MS5TdGF0dXNSZXNwb25zZUIGukgD2AEDUgZzdGF0dXMS'
     'LwoGZXh0cmFzGA8gASgLMhcuZ29vZ2xlLnByb3RvYnVmLlN0cnVjdFIGZXh0cmFzEjUKCHByaW'
-    '9yaXR5GBAgASgOMhkubm90aWZpY2F0aW9uLnYxLlBSSU9SSVRZUghwcmlvcml0eQ==');
+    '9yaXR5GBAgASgOMhkubm90aWZpY2F0aW9uLnYxLlBSSU9SSVRZUghwcmlvcml0eRIzCg9pZGVt'
+    'cG90ZW5jeV9rZXkYESABKAlCCrpIB9gBAXICGGRSDmlkZW1wb3RlbmN5S2V5');
 
 @$core.Deprecated('Use searchResponseDescriptor instead')
 const SearchResponse$json = {
@@ -158,6 +165,71 @@ final $typed_data.Uint8List sendResponseDescriptor = $convert.base64Decode(
     'CgxTZW5kUmVzcG9uc2USLQoEZGF0YRgBIAMoCzIZLmNvbW1vbi52MS5TdGF0dXNSZXNwb25zZV'
     'IEZGF0YQ==');
 
+@$core.Deprecated('Use broadcastRequestDescriptor instead')
+const BroadcastRequest$json = {
+  '1': 'BroadcastRequest',
+  '2': [
+    {'1': 'data', '3': 1, '4': 1, '5': 11, '6': '.notification.v1.Notification', '8': {}, '10': 'data'},
+    {'1': 'profile_ids', '3': 2, '4': 3, '5': 9, '8': {}, '10': 'profileIds'},
+    {'1': 'partition_audience', '3': 3, '4': 1, '5': 8, '10': 'partitionAudience'},
+  ],
+};
+
+/// Descriptor for `BroadcastRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List broadcastRequestDescriptor = $convert.base64Decode(
+    'ChBCcm9hZGNhc3RSZXF1ZXN0EjkKBGRhdGEYASABKAsyHS5ub3RpZmljYXRpb24udjEuTm90aW'
+    'ZpY2F0aW9uQga6SAPIAQFSBGRhdGESQQoLcHJvZmlsZV9pZHMYAiADKAlCILpIHZIBGiIYchYQ'
+    'AxgoMhBbMC05YS16Xy1dezMsNDB9Ugpwcm9maWxlSWRzEi0KEnBhcnRpdGlvbl9hdWRpZW5jZR'
+    'gDIAEoCFIRcGFydGl0aW9uQXVkaWVuY2U=');
+
+@$core.Deprecated('Use broadcastResponseDescriptor instead')
+const BroadcastResponse$json = {
+  '1': 'BroadcastResponse',
+  '2': [
+    {'1': 'data', '3': 1, '4': 1, '5': 11, '6': '.common.v1.StatusResponse', '10': 'data'},
+  ],
+};
+
+/// Descriptor for `BroadcastResponse`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List broadcastResponseDescriptor = $convert.base64Decode(
+    'ChFCcm9hZGNhc3RSZXNwb25zZRItCgRkYXRhGAEgASgLMhkuY29tbW9uLnYxLlN0YXR1c1Jlc3'
+    'BvbnNlUgRkYXRh');
+
+@$core.Deprecated('Use broadcastStatusResponseDescriptor instead')
+const BroadcastStatusResponse$json = {
+  '1': 'BroadcastStatusResponse',
+  '2': [
+    {'1': 'id', '3': 1, '4': 1, '5': 9, '10': 'id'},
+    {'1': 'queued', '3': 2, '4': 1, '5': 3, '10': 'queued'},
+    {'1': 'in_process', '3': 3, '4': 1, '5': 3, '10': 'inProcess'},
+    {'1': 'successful', '3': 4, '4': 1, '5': 3, '10': 'successful'},
+    {'1': 'failed', '3': 5, '4': 1, '5': 3, '10': 'failed'},
+    {'1': 'failures_by_step', '3': 6, '4': 3, '5': 11, '6': '.notification.v1.BroadcastStatusResponse.FailuresByStepEntry', '10': 'failuresByStep'},
+    {'1': 'status', '3': 7, '4': 1, '5': 11, '6': '.common.v1.StatusResponse', '10': 'status'},
+  ],
+  '3': [BroadcastStatusResponse_FailuresByStepEntry$json],
+};
+
+@$core.Deprecated('Use broadcastStatusResponseDescriptor instead')
+const BroadcastStatusResponse_FailuresByStepEntry$json = {
+  '1': 'FailuresByStepEntry',
+  '2': [
+    {'1': 'key', '3': 1, '4': 1, '5': 9, '10': 'key'},
+    {'1': 'value', '3': 2, '4': 1, '5': 3, '10': 'value'},
+  ],
+  '7': {'7': true},
+};
+
+/// Descriptor for `BroadcastStatusResponse`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List broadcastStatusResponseDescriptor = $convert.base64Decode(
+    'ChdCcm9hZGNhc3RTdGF0dXNSZXNwb25zZRIOCgJpZBgBIAEoCVICaWQSFgoGcXVldWVkGAIgAS'
+    'gDUgZxdWV1ZWQSHQoKaW5fcHJvY2VzcxgDIAEoA1IJaW5Qcm9jZXNzEh4KCnN1Y2Nlc3NmdWwY'
+    'BCABKANSCnN1Y2Nlc3NmdWwSFgoGZmFpbGVkGAUgASgDUgZmYWlsZWQSZgoQZmFpbHVyZXNfYn'
+    'lfc3RlcBgGIAMoCzI8Lm5vdGlmaWNhdGlvbi52MS5Ccm9hZGNhc3RTdGF0dXNSZXNwb25zZS5G'
+    'YWlsdXJlc0J5U3RlcEVudHJ5Ug5mYWlsdXJlc0J5U3RlcBIxCgZzdGF0dXMYByABKAsyGS5jb2'
+    '1tb24udjEuU3RhdHVzUmVzcG9uc2VSBnN0YXR1cxpBChNGYWlsdXJlc0J5U3RlcEVudHJ5EhAK'
+    'A2tleRgBIAEoCVIDa2V5EhQKBXZhbHVlGAIgASgDUgV2YWx1ZToCOAE=');
+
 @$core.Deprecated('Use releaseRequestDescriptor instead')
 const ReleaseRequest$json = {
   '1': 'ReleaseRequest',
@@ -185,6 +257,33 @@ final $typed_data.Uint8List releaseResponseDescriptor = $convert.base64Decode(
     'Cg9SZWxlYXNlUmVzcG9uc2USLQoEZGF0YRgBIAMoCzIZLmNvbW1vbi52MS5TdGF0dXNSZXNwb2'
     '5zZVIEZGF0YQ==');
 
+@$core.Deprecated('Use cancelRequestDescriptor instead')
+const CancelRequest$json = {
+  '1': 'CancelRequest',
+  '2': [
+    {'1': 'id', '3': 1, '4': 3, '5': 9, '8': {}, '10': 'id'},
+    {'1': 'comment', '3': 2, '4': 1, '5': 9, '10': 'comment'},
+  ],
+};
+
+/// Descriptor for `CancelRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List cancelRequestDescriptor = $convert.base64Decode(
+    'Cg1DYW5jZWxSZXF1ZXN0EjAKAmlkGAEgAygJQiC6SB2SARoiGHIWEAMYKDIQWzAtOWEtel8tXX'
+    'szLDIwfVICaWQSGAoHY29tbWVudBgCIAEoCVIHY29tbWVudA==');
+
+@$core.Deprecated('Use cancelResponseDescriptor instead')
+const CancelResponse$json = {
+  '1': 'CancelResponse',
+  '2': [
+    {'1': 'data', '3': 1, '4': 3, '5': 11, '6': '.common.v1.StatusResponse', '10': 'data'},
+  ],
+};
+
+/// Descriptor for `CancelResponse`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List cancelResponseDescriptor = $convert.base64Decode(
+    'Cg5DYW5jZWxSZXNwb25zZRItCgRkYXRhGAEgAygLMhkuY29tbW9uLnYxLlN0YXR1c1Jlc3Bvbn'
+    'NlUgRkYXRh');
+
 @$core.Deprecated('Use receiveRequestDescriptor instead')
 const ReceiveRequest$json = {
   '1': 'ReceiveRequest',
@@ -249,6 +348,7 @@ const TemplateSaveRequest$json = {
     {'1': 'language_code', '3': 2, '4': 1, '5': 9, '10': 'languageCode'},
     {'1': 'data', '3': 3, '4': 1, '5': 11, '6': '.google.protobuf.Struct', '10': 'data'},
     {'1': 'extra', '3': 4, '4': 1, '5': 11, '6': '.google.protobuf.Struct', '10': 'extra'},
+    {'1': 'publish', '3': 5, '4': 1, '5': 8, '10': 'publish'},
   ],
 };
 
@@ -257,7 +357,7 @@ final $typed_data.Uint8List templateSaveRequestDescriptor = $convert.base64Decod
     'ChNUZW1wbGF0ZVNhdmVSZXF1ZXN0EhIKBG5hbWUYASABKAlSBG5hbWUSIwoNbGFuZ3VhZ2VfY2'
     '9kZRgCIAEoCVIMbGFuZ3VhZ2VDb2RlEisKBGRhdGEYAyABKAsyFy5nb29nbGUucHJvdG9idWYu'
     'U3RydWN0UgRkYXRhEi0KBWV4dHJhGAQgASgLMhcuZ29vZ2xlLnByb3RvYnVmLlN0cnVjdFIFZX'
-    'h0cmE=');
+    'h0cmESGAoHcHVibGlzaBgFIAEoCFIHcHVibGlzaA==');
 
 @$core.Deprecated('Use templateSaveResponseDescriptor instead')
 const TemplateSaveResponse$json = {
@@ -272,11 +372,328 @@ final $typed_data.Uint8List templateSaveResponseDescriptor = $convert.base64Deco
     'ChRUZW1wbGF0ZVNhdmVSZXNwb25zZRItCgRkYXRhGAEgASgLMhkubm90aWZpY2F0aW9uLnYxLl'
     'RlbXBsYXRlUgRkYXRh');
 
+@$core.Deprecated('Use templatePublishRequestDescriptor instead')
+const TemplatePublishRequest$json = {
+  '1': 'TemplatePublishRequest',
+  '2': [
+    {'1': 'id', '3': 1, '4': 1, '5': 9, '8': {}, '10': 'id'},
+  ],
+};
+
+/// Descriptor for `TemplatePublishRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List templatePublishRequestDescriptor = $convert.base64Decode(
+    'ChZUZW1wbGF0ZVB1Ymxpc2hSZXF1ZXN0EhcKAmlkGAEgASgJQge6SARyAhADUgJpZA==');
+
+@$core.Deprecated('Use templatePublishResponseDescriptor instead')
+const TemplatePublishResponse$json = {
+  '1': 'TemplatePublishResponse',
+  '2': [
+    {'1': 'data', '3': 1, '4': 1, '5': 11, '6': '.notification.v1.Template', '10': 'data'},
+  ],
+};
+
+/// Descriptor for `TemplatePublishResponse`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List templatePublishResponseDescriptor = $convert.base64Decode(
+    'ChdUZW1wbGF0ZVB1Ymxpc2hSZXNwb25zZRItCgRkYXRhGAEgASgLMhkubm90aWZpY2F0aW9uLn'
+    'YxLlRlbXBsYXRlUgRkYXRh');
+
+@$core.Deprecated('Use templateRollbackRequestDescriptor instead')
+const TemplateRollbackRequest$json = {
+  '1': 'TemplateRollbackRequest',
+  '2': [
+    {'1': 'name', '3': 1, '4': 1, '5': 9, '8': {}, '10': 'name'},
+    {'1': 'version', '3': 2, '4': 1, '5': 5, '10': 'version'},
+  ],
+};
+
+/// Descriptor for `TemplateRollbackRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List templateRollbackRequestDescriptor = $convert.base64Decode(
+    'ChdUZW1wbGF0ZVJvbGxiYWNrUmVxdWVzdBIbCgRuYW1lGAEgASgJQge6SARyAhABUgRuYW1lEh'
+    'gKB3ZlcnNpb24YAiABKAVSB3ZlcnNpb24=');
+
+@$core.Deprecated('Use templateRollbackResponseDescriptor instead')
+const TemplateRollbackResponse$json = {
+  '1': 'TemplateRollbackResponse',
+  '2': [
+    {'1': 'data', '3': 1, '4': 1, '5': 11, '6': '.notification.v1.Template', '10': 'data'},
+  ],
+};
+
+/// Descriptor for `TemplateRollbackResponse`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List templateRollbackResponseDescriptor = $convert.base64Decode(
+    'ChhUZW1wbGF0ZVJvbGxiYWNrUmVzcG9uc2USLQoEZGF0YRgBIAEoCzIZLm5vdGlmaWNhdGlvbi'
+    '52MS5UZW1wbGF0ZVIEZGF0YQ==');
+
+@$core.Deprecated('Use templateDataChangeDescriptor instead')
+const TemplateDataChange$json = {
+  '1': 'TemplateDataChange',
+  '2': [
+    {'1': 'language_code', '3': 1, '4': 1, '5': 9, '8': {}, '10': 'languageCode'},
+    {'1': 'type', '3': 2, '4': 1, '5': 9, '8': {}, '10': 'type'},
+    {'1': 'detail', '3': 3, '4': 1, '5': 9, '10': 'detail'},
+    {'1': 'remove', '3': 4, '4': 1, '5': 8, '10': 'remove'},
+  ],
+};
+
+/// Descriptor for `TemplateDataChange`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List templateDataChangeDescriptor = $convert.base64Decode(
+    'ChJUZW1wbGF0ZURhdGFDaGFuZ2USLAoNbGFuZ3VhZ2VfY29kZRgBIAEoCUIHukgEcgIQAlIMbG'
+    'FuZ3VhZ2VDb2RlEhsKBHR5cGUYAiABKAlCB7pIBHICEAFSBHR5cGUSFgoGZGV0YWlsGAMgASgJ'
+    'UgZkZXRhaWwSFgoGcmVtb3ZlGAQgASgIUgZyZW1vdmU=');
+
+@$core.Deprecated('Use templateUpdateRequestDescriptor instead')
+const TemplateUpdateRequest$json = {
+  '1': 'TemplateUpdateRequest',
+  '2': [
+    {'1': 'id', '3': 1, '4': 1, '5': 9, '8': {}, '10': 'id'},
+    {'1': 'extra', '3': 2, '4': 1, '5': 11, '6': '.google.protobuf.Struct', '10': 'extra'},
+    {'1': 'data', '3': 3, '4': 3, '5': 11, '6': '.notification.v1.TemplateDataChange', '10': 'data'},
+  ],
+};
+
+/// Descriptor for `TemplateUpdateRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List templateUpdateRequestDescriptor = $convert.base64Decode(
+    'ChVUZW1wbGF0ZVVwZGF0ZVJlcXVlc3QSFwoCaWQYASABKAlCB7pIBHICEANSAmlkEi0KBWV4dH'
+    'JhGAIgASgLMhcuZ29vZ2xlLnByb3RvYnVmLlN0cnVjdFIFZXh0cmESNwoEZGF0YRgDIAMoCzIj'
+    'Lm5vdGlmaWNhdGlvbi52MS5UZW1wbGF0ZURhdGFDaGFuZ2VSBGRhdGE=');
+
+@$core.Deprecated('Use templateUpdateResponseDescriptor instead')
+const TemplateUpdateResponse$json = {
+  '1': 'TemplateUpdateResponse',
+  '2': [
+    {'1': 'data', '3': 1, '4': 1, '5': 11, '6': '.notification.v1.Template', '10': 'data'},
+  ],
+};
+
+/// Descriptor for `TemplateUpdateResponse`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List templateUpdateResponseDescriptor = $convert.base64Decode(
+    'ChZUZW1wbGF0ZVVwZGF0ZVJlc3BvbnNlEi0KBGRhdGEYASABKAsyGS5ub3RpZmljYXRpb24udj'
+    'EuVGVtcGxhdGVSBGRhdGE=');
+
+@$core.Deprecated('Use templateDeleteRequestDescriptor instead')
+const TemplateDeleteRequest$json = {
+  '1': 'TemplateDeleteRequest',
+  '2': [
+    {'1': 'id', '3': 1, '4': 1, '5': 9, '8': {}, '10': 'id'},
+  ],
+};
+
+/// Descriptor for `TemplateDeleteRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List templateDeleteRequestDescriptor = $convert.base64Decode(
+    'ChVUZW1wbGF0ZURlbGV0ZVJlcXVlc3QSFwoCaWQYASABKAlCB7pIBHICEANSAmlk');
+
+@$core.Deprecated('Use templateDeleteResponseDescriptor instead')
+const TemplateDeleteResponse$json = {
+  '1': 'TemplateDeleteResponse',
+  '2': [
+    {'1': 'id', '3': 1, '4': 3, '5': 9, '10': 'id'},
+  ],
+};
+
+/// Descriptor for `TemplateDeleteResponse`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List templateDeleteResponseDescriptor = $convert.base64Decode(
+    'ChZUZW1wbGF0ZURlbGV0ZVJlc3BvbnNlEg4KAmlkGAEgAygJUgJpZA==');
+
+@$core.Deprecated('Use templatePreviewRequestDescriptor instead')
+const TemplatePreviewRequest$json = {
+  '1': 'TemplatePreviewRequest',
+  '2': [
+    {'1': 'template', '3': 1, '4': 1, '5': 9, '8': {}, '10': 'template'},
+    {'1': 'language_code', '3': 2, '4': 1, '5': 9, '10': 'languageCode'},
+    {'1': 'payload', '3': 3, '4': 1, '5': 11, '6': '.google.protobuf.Struct', '10': 'payload'},
+    {'1': 'partition_id', '3': 4, '4': 1, '5': 9, '8': {}, '10': 'partitionId'},
+  ],
+};
+
+/// Descriptor for `TemplatePreviewRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List templatePreviewRequestDescriptor = $convert.base64Decode(
+    'ChZUZW1wbGF0ZVByZXZpZXdSZXF1ZXN0EiMKCHRlbXBsYXRlGAEgASgJQge6SARyAhABUgh0ZW'
+    '1wbGF0ZRIjCg1sYW5ndWFnZV9jb2RlGAIgASgJUgxsYW5ndWFnZUNvZGUSMQoHcGF5bG9hZBgD'
+    'IAEoCzIXLmdvb2dsZS5wcm90b2J1Zi5TdHJ1Y3RSB3BheWxvYWQSQQoMcGFydGl0aW9uX2lkGA'
+    'QgASgJQh66SBvYAQFyFhADGCgyEFswLTlhLXpfLV17Myw0MH1SC3BhcnRpdGlvbklk');
+
+@$core.Deprecated('Use templatePreviewErrorDescriptor instead')
+const TemplatePreviewError$json = {
+  '1': 'TemplatePreviewError',
+  '2': [
+    {'1': 'type', '3': 1, '4': 1, '5': 9, '10': 'type'},
+    {'1': 'variable', '3': 2, '4': 1, '5': 9, '10': 'variable'},
+    {'1': 'message', '3': 3, '4': 1, '5': 9, '10': 'message'},
+  ],
+};
+
+/// Descriptor for `TemplatePreviewError`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List templatePreviewErrorDescriptor = $convert.base64Decode(
+    'ChRUZW1wbGF0ZVByZXZpZXdFcnJvchISCgR0eXBlGAEgASgJUgR0eXBlEhoKCHZhcmlhYmxlGA'
+    'IgASgJUgh2YXJpYWJsZRIYCgdtZXNzYWdlGAMgASgJUgdtZXNzYWdl');
+
+@$core.Deprecated('Use templatePreviewResponseDescriptor instead')
+const TemplatePreviewResponse$json = {
+  '1': 'TemplatePreviewResponse',
+  '2': [
+    {'1': 'template', '3': 1, '4': 1, '5': 11, '6': '.notification.v1.Template', '10': 'template'},
+    {'1': 'rendered', '3': 2, '4': 3, '5': 11, '6': '.notification.v1.TemplatePreviewResponse.RenderedEntry', '10': 'rendered'},
+    {'1': 'support_contacts', '3': 3, '4': 3, '5': 11, '6': '.notification.v1.TemplatePreviewResponse.SupportContactsEntry', '10': 'supportContacts'},
+    {'1': 'errors', '3': 4, '4': 3, '5': 11, '6': '.notification.v1.TemplatePreviewError', '10': 'errors'},
+  ],
+  '3': [TemplatePreviewResponse_RenderedEntry$json, TemplatePreviewResponse_SupportContactsEntry$json],
+};
+
+@$core.Deprecated('Use templatePreviewResponseDescriptor instead')
+const TemplatePreviewResponse_RenderedEntry$json = {
+  '1': 'RenderedEntry',
+  '2': [
+    {'1': 'key', '3': 1, '4': 1, '5': 9, '10': 'key'},
+    {'1': 'value', '3': 2, '4': 1, '5': 9, '10': 'value'},
+  ],
+  '7': {'7': true},
+};
+
+@$core.Deprecated('Use templatePreviewResponseDescriptor instead')
+const TemplatePreviewResponse_SupportContactsEntry$json = {
+  '1': 'SupportContactsEntry',
+  '2': [
+    {'1': 'key', '3': 1, '4': 1, '5': 9, '10': 'key'},
+    {'1': 'value', '3': 2, '4': 1, '5': 9, '10': 'value'},
+  ],
+  '7': {'7': true},
+};
+
+/// Descriptor for `TemplatePreviewResponse`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List templatePreviewResponseDescriptor = $convert.base64Decode(
+    'ChdUZW1wbGF0ZVByZXZpZXdSZXNwb25zZRI1Cgh0ZW1wbGF0ZRgBIAEoCzIZLm5vdGlmaWNhdG'
+    'lvbi52MS5UZW1wbGF0ZVIIdGVtcGxhdGUSUgoIcmVuZGVyZWQYAiADKAsyNi5ub3RpZmljYXRp'
+    'b24udjEuVGVtcGxhdGVQcmV2aWV3UmVzcG9uc2UuUmVuZGVyZWRFbnRyeVIIcmVuZGVyZWQSaA'
+    'oQc3VwcG9ydF9jb250YWN0cxgDIAMoCzI9Lm5vdGlmaWNhdGlvbi52MS5UZW1wbGF0ZVByZXZp'
+    'ZXdSZXNwb25zZS5TdXBwb3J0Q29udGFjdHNFbnRyeVIPc3VwcG9ydENvbnRhY3RzEj0KBmVycm'
+    '9ycxgEIAMoCzIlLm5vdGlmaWNhdGlvbi52MS5UZW1wbGF0ZVByZXZpZXdFcnJvclIGZXJyb3Jz'
+    'GjsKDVJlbmRlcmVkRW50cnkSEAoDa2V5GAEgASgJUgNrZXkSFAoFdmFsdWUYAiABKAlSBXZhbH'
+    'VlOgI4ARpCChRTdXBwb3J0Q29udGFjdHNFbnRyeRIQCgNrZXkYASABKAlSA2tleRIUCgV2YWx1'
+    'ZRgCIAEoCVIFdmFsdWU6AjgB');
+
+@$core.Deprecated('Use suppressionDescriptor instead')
+const Suppression$json = {
+  '1': 'Suppression',
+  '2': [
+    {'1': 'id', '3': 1, '4': 1, '5': 9, '8': {}, '10': 'id'},
+    {'1': 'contact', '3': 2, '4': 1, '5': 9, '8': {}, '10': 'contact'},
+    {'1': 'channel', '3': 3, '4': 1, '5': 9, '10': 'channel'},
+    {'1': 'sender_id', '3': 4, '4': 1, '5': 9, '10': 'senderId'},
+    {'1': 'route_id', '3': 5, '4': 1, '5': 9, '10': 'routeId'},
+    {'1': 'reason', '3': 6, '4': 1, '5': 9, '10': 'reason'},
+    {'1': 'source', '3': 7, '4': 1, '5': 9, '10': 'source'},
+    {'1': 'extras', '3': 8, '4': 1, '5': 11, '6': '.google.protobuf.Struct', '10': 'extras'},
+    {'1': 'created_at', '3': 9, '4': 1, '5': 9, '8': {}, '10': 'createdAt'},
+  ],
+};
+
+/// Descriptor for `Suppression`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List suppressionDescriptor = $convert.base64Decode(
+    'CgtTdXBwcmVzc2lvbhIWCgJpZBgBIAEoCUIGukgD2AEBUgJpZBIkCgdjb250YWN0GAIgASgJQg'
+    'q6SAdyBRADGP8BUgdjb250YWN0EhgKB2NoYW5uZWwYAyABKAlSB2NoYW5uZWwSGwoJc2VuZGVy'
+    'X2lkGAQgASgJUghzZW5kZXJJZBIZCghyb3V0ZV9pZBgFIAEoCVIHcm91dGVJZBIWCgZyZWFzb2'
+    '4YBiABKAlSBnJlYXNvbhIWCgZzb3VyY2UYByABKAlSBnNvdXJjZRIvCgZleHRyYXMYCCABKAsy'
+    'Fy5nb29nbGUucHJvdG9idWYuU3RydWN0UgZleHRyYXMSJQoKY3JlYXRlZF9hdBgJIAEoCUIGuk'
+    'gD2AEDUgljcmVhdGVkQXQ=');
+
+@$core.Deprecated('Use suppressionAddRequestDescriptor instead')
+const SuppressionAddRequest$json = {
+  '1': 'SuppressionAddRequest',
+  '2': [
+    {'1': 'data', '3': 1, '4': 1, '5': 11, '6': '.notification.v1.Suppression', '8': {}, '10': 'data'},
+  ],
+};
+
+/// Descriptor for `SuppressionAddRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List suppressionAddRequestDescriptor = $convert.base64Decode(
+    'ChVTdXBwcmVzc2lvbkFkZFJlcXVlc3QSOAoEZGF0YRgBIAEoCzIcLm5vdGlmaWNhdGlvbi52MS'
+    '5TdXBwcmVzc2lvbkIGukgDyAEBUgRkYXRh');
+
+@$core.Deprecated('Use suppressionAddResponseDescriptor instead')
+const SuppressionAddResponse$json = {
+  '1': 'SuppressionAddResponse',
+  '2': [
+    {'1': 'data', '3': 1, '4': 1, '5': 11, '6': '.notification.v1.Suppression', '10': 'data'},
+  ],
+};
+
+/// Descriptor for `SuppressionAddResponse`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List suppressionAddResponseDescriptor = $convert.base64Decode(
+    'ChZTdXBwcmVzc2lvbkFkZFJlc3BvbnNlEjAKBGRhdGEYASABKAsyHC5ub3RpZmljYXRpb24udj'
+    'EuU3VwcHJlc3Npb25SBGRhdGE=');
+
+@$core.Deprecated('Use suppressionRemoveRequestDescriptor instead')
+const SuppressionRemoveRequest$json = {
+  '1': 'SuppressionRemoveRequest',
+  '2': [
+    {'1': 'id', '3': 1, '4': 1, '5': 9, '10': 'id'},
+    {'1': 'contact', '3': 2, '4': 1, '5': 9, '10': 'contact'},
+    {'1': 'channel', '3': 3, '4': 1, '5': 9, '10': 'channel'},
+    {'1': 'sender_id', '3': 4, '4': 1, '5': 9, '10': 'senderId'},
+  ],
+};
+
+/// Descriptor for `SuppressionRemoveRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List suppressionRemoveRequestDescriptor = $convert.base64Decode(
+    'ChhTdXBwcmVzc2lvblJlbW92ZVJlcXVlc3QSDgoCaWQYASABKAlSAmlkEhgKB2NvbnRhY3QYAi'
+    'ABKAlSB2NvbnRhY3QSGAoHY2hhbm5lbBgDIAEoCVIHY2hhbm5lbBIbCglzZW5kZXJfaWQYBCAB'
+    'KAlSCHNlbmRlcklk');
+
+@$core.Deprecated('Use suppressionRemoveResponseDescriptor instead')
+const SuppressionRemoveResponse$json = {
+  '1': 'SuppressionRemoveResponse',
+  '2': [
+    {'1': 'id', '3': 1, '4': 3, '5': 9, '10': 'id'},
+  ],
+};
+
+/// Descriptor for `SuppressionRemoveResponse`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List suppressionRemoveResponseDescriptor = $convert.base64Decode(
+    'ChlTdXBwcmVzc2lvblJlbW92ZVJlc3BvbnNlEg4KAmlkGAEgAygJUgJpZA==');
+
+@$core.Deprecated('Use suppressionSearchRequestDescriptor instead')
+const SuppressionSearchRequest$json = {
+  '1': 'SuppressionSearchRequest',
+  '2': [
+    {'1': 'query', '3': 1, '4': 1, '5': 9, '10': 'query'},
+    {'1': 'channel', '3': 2, '4': 1, '5': 9, '10': 'channel'},
+    {'1': 'page', '3': 3, '4': 1, '5': 3, '10': 'page'},
+    {'1': 'count', '3': 4, '4': 1, '5': 5, '10': 'count'},
+  ],
+};
+
+/// Descriptor for `SuppressionSearchRequest`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List suppressionSearchRequestDescriptor = $convert.base64Decode(
+    'ChhTdXBwcmVzc2lvblNlYXJjaFJlcXVlc3QSFAoFcXVlcnkYASABKAlSBXF1ZXJ5EhgKB2NoYW'
+    '5uZWwYAiABKAlSB2NoYW5uZWwSEgoEcGFnZRgDIAEoA1IEcGFnZRIUCgVjb3VudBgEIAEoBVIF'
+    'Y291bnQ=');
+
+@$core.Deprecated('Use suppressionSearchResponseDescriptor instead')
+const SuppressionSearchResponse$json = {
+  '1': 'SuppressionSearchResponse',
+  '2': [
+    {'1': 'data', '3': 1, '4': 3, '5': 11, '6': '.notification.v1.Suppression', '10': 'data'},
+  ],
+};
+
+/// Descriptor for `SuppressionSearchResponse`. Decode as a `google.protobuf.DescriptorProto`.
+final $typed_data.Uint8List suppressionSearchResponseDescriptor = $convert.base64Decode(
+    'ChlTdXBwcmVzc2lvblNlYXJjaFJlc3BvbnNlEjAKBGRhdGEYASADKAsyHC5ub3RpZmljYXRpb2'
+    '4udjEuU3VwcHJlc3Npb25SBGRhdGE=');
+
 const $core.Map<$core.String, $core.dynamic> NotificationServiceBase$json = {
   '1': 'NotificationService',
   '2': [
     {'1': 'Send', '2': '.notification.v1.SendRequest', '3': '.notification.v1.SendResponse', '4': {}, '6': true},
+    {'1': 'Broadcast', '2': '.notification.v1.BroadcastRequest', '3': '.notification.v1.BroadcastResponse', '4': {}, '6': true},
+    {
+      '1': 'BroadcastStatus',
+      '2': '.common.v1.StatusRequest',
+      '3': '.notification.v1.BroadcastStatusResponse',
+      '4': {'34': 1},
+    },
     {'1': 'Release', '2': '.notification.v1.ReleaseRequest', '3': '.notification.v1.ReleaseResponse', '4': {}, '6': true},
+    {'1': 'Cancel', '2': '.notification.v1.CancelRequest', '3': '.notification.v1.CancelResponse', '4': {}, '6': true},
     {'1': 'Receive', '2': '.notification.v1.ReceiveRequest', '3': '.notification.v1.ReceiveResponse', '4': {}, '6': true},
     {
       '1': 'Search',
@@ -300,6 +717,20 @@ const $core.Map<$core.String, $core.dynamic> NotificationServiceBase$json = {
       '6': true,
     },
     {'1': 'TemplateSave', '2': '.notification.v1.TemplateSaveRequest', '3': '.notification.v1.TemplateSaveResponse', '4': {}},
+    {'1': 'TemplatePublish', '2': '.notification.v1.TemplatePublishRequest', '3': '.notification.v1.TemplatePublishResponse', '4': {}},
+    {'1': 'TemplateRollback', '2': '.notification.v1.TemplateRollbackRequest', '3': '.notification.v1.TemplateRollbackResponse', '4': {}},
+    {'1': 'TemplateUpdate', '2': '.notification.v1.TemplateUpdateRequest', '3': '.notification.v1.TemplateUpdateResponse', '4': {}},
+    {'1': 'TemplateDelete', '2': '.notification.v1.TemplateDeleteRequest', '3': '.notification.v1.TemplateDeleteResponse', '4': {}},
+    {'1': 'TemplatePreview', '2': '.notification.v1.TemplatePreviewRequest', '3': '.notification.v1.TemplatePreviewResponse', '4': {}},
+    {
+      '1': 'SuppressionSearch',
+      '2': '.notification.v1.SuppressionSearchRequest',
+      '3': '.notification.v1.SuppressionSearchResponse',
+      '4': {'34': 1},
+      '6': true,
+    },
+    {'1': 'SuppressionAdd', '2': '.notification.v1.SuppressionAddRequest', '3': '.notification.v1.SuppressionAddResponse', '4': {}},
+    {'1': 'SuppressionRemove', '2': '.notification.v1.SuppressionRemoveRequest', '3': '.notification.v1.SuppressionRemoveResponse', '4': {}},
   ],
   '3': {},
 };
@@ -315,14 +746,20 @@ const $core.Map<$core.String, $core.Map<$core.String, $core.dynamic>> Notificati
   '.google.protobuf.ListValue': $6.ListValue$json,
   '.common.v1.StatusResponse': $7.StatusResponse$json,
   '.notification.v1.SendResponse': SendResponse$json,
+  '.notification.v1.BroadcastRequest': BroadcastRequest$json,
+  '.notification.v1.BroadcastResponse': BroadcastResponse$json,
+  '.common.v1.StatusRequest': $7.StatusRequest$json,
+  '.notification.v1.BroadcastStatusResponse': BroadcastStatusResponse$json,
+  '.notification.v1.BroadcastStatusResponse.FailuresByStepEntry': BroadcastStatusResponse_FailuresByStepEntry$json,
   '.notification.v1.ReleaseRequest': ReleaseRequest$json,
   '.notification.v1.ReleaseResponse': ReleaseResponse$json,
+  '.notification.v1.CancelRequest': CancelRequest$json,
+  '.notification.v1.CancelResponse': CancelResponse$json,
   '.notification.v1.ReceiveRequest': ReceiveRequest$json,
   '.notification.v1.ReceiveResponse': ReceiveResponse$json,
   '.common.v1.SearchRequest': $7.SearchRequest$json,
   '.common.v1.PageCursor': $7.PageCursor$json,
   '.notification.v1.SearchResponse': SearchResponse$json,
-  '.common.v1.StatusRequest': $7.StatusRequest$json,
   '.common.v1.StatusUpdateRequest': $7.StatusUpdateRequest$json,
   '.common.v1.StatusUpdateResponse': $7.StatusUpdateResponse$json,
   '.notification.v1.TemplateSearchRequest': TemplateSearchRequest$json,
@@ -332,6 +769,27 @@ const $core.Map<$core.String, $core.Map<$core.String, $core.dynamic>> Notificati
   '.notification.v1.Language': Language$json,
   '.notification.v1.TemplateSaveRequest': TemplateSaveRequest$json,
   '.notification.v1.TemplateSaveResponse': TemplateSaveResponse$json,
+  '.notification.v1.TemplatePublishRequest': TemplatePublishRequest$json,
+  '.notification.v1.TemplatePublishResponse': TemplatePublishResponse$json,
+  '.notification.v1.TemplateRollbackRequest': TemplateRollbackRequest$json,
+  '.notification.v1.TemplateRollbackResponse': TemplateRollbackResponse$json,
+  '.notification.v1.TemplateUpdateRequest': TemplateUpdateRequest$json,
+  '.notification.v1.TemplateDataChange': TemplateDataChange$json,
+  '.notification.v1.TemplateUpdateResponse': TemplateUpdateResponse$json,
+  '.notification.v1.TemplateDeleteRequest': TemplateDeleteRequest$json,
+  '.notification.v1.TemplateDeleteResponse': TemplateDeleteResponse$json,
+  '.notification.v1.TemplatePreviewRequest': TemplatePreviewRequest$json,
+  '.notification.v1.TemplatePreviewResponse': TemplatePreviewResponse$json,
+  '.notification.v1.TemplatePreviewResponse.RenderedEntry': TemplatePreviewResponse_RenderedEntry$json,
+  '.notification.v1.TemplatePreviewResponse.SupportContactsEntry': TemplatePreviewResponse_SupportContactsEntry$json,
+  '.notification.v1.TemplatePreviewError': TemplatePreviewError$json,
+  '.notification.v1.SuppressionSearchRequest': SuppressionSearchRequest$json,
+  '.notification.v1.SuppressionSearchResponse': SuppressionSearchResponse$json,
+  '.notification.v1.Suppression': Suppression$json,
+  '.notification.v1.SuppressionAddRequest': SuppressionAddRequest$json,
+  '.notification.v1.SuppressionAddResponse': SuppressionAddResponse$json,
+  '.notification.v1.SuppressionRemoveRequest': SuppressionRemoveRequest$json,
+  '.notification.v1.SuppressionRemoveResponse': SuppressionRemoveResponse$json,
 };
 
 /// Descriptor for `NotificationService`. Decode as a `google.protobuf.ServiceDescriptorProto`.
@@ -342,64 +800,146 @@ final $typed_data.Uint8List notificationServiceDescriptor = $convert.base64Decod
     'JlIG5vdGlmaWNhdGlvbnMgZm9yIGRlbGl2ZXJ5LiBOb3RpZmljYXRpb25zIGNhbiBiZSBjb25m'
     'aWd1cmVkIHRvIGF1dG8tcmVsZWFzZSBvciByZXF1aXJlIG1hbnVhbCByZWxlYXNlIHZpYSB0aG'
     'UgUmVsZWFzZSBSUEMuIFN1cHBvcnRzIGJhdGNoIHF1ZXVpbmcgZm9yIGVmZmljaWVuY3kuKhFz'
-    'ZW5kTm90aWZpY2F0aW9uc4K1GBMKEW5vdGlmaWNhdGlvbl9zZW5kMAES+QIKB1JlbGVhc2USHy'
-    '5ub3RpZmljYXRpb24udjEuUmVsZWFzZVJlcXVlc3QaIC5ub3RpZmljYXRpb24udjEuUmVsZWFz'
-    'ZVJlc3BvbnNlIqgCukeKAgoNTm90aWZpY2F0aW9ucxIcUmVsZWFzZSBxdWV1ZWQgbm90aWZpY2'
-    'F0aW9ucxrEAVJlbGVhc2VzIHF1ZXVlZCBub3RpZmljYXRpb25zIGZvciBpbW1lZGlhdGUgZGVs'
-    'aXZlcnkuIFVzZWQgZm9yIGJhdGNoIHByb2Nlc3Npbmcgc2NlbmFyaW9zIHdoZXJlIG5vdGlmaW'
-    'NhdGlvbnMgYXJlIHF1ZXVlZCBmaXJzdCBhbmQgcmVsZWFzZWQgdG9nZXRoZXIuIFJldHVybnMg'
-    'ZGVsaXZlcnkgc3RhdHVzIGZvciBlYWNoIG5vdGlmaWNhdGlvbi4qFHJlbGVhc2VOb3RpZmljYX'
-    'Rpb25zgrUYFgoUbm90aWZpY2F0aW9uX3JlbGVhc2UwARK6AgoHUmVjZWl2ZRIfLm5vdGlmaWNh'
-    'dGlvbi52MS5SZWNlaXZlUmVxdWVzdBogLm5vdGlmaWNhdGlvbi52MS5SZWNlaXZlUmVzcG9uc2'
-    'Ui6QG6R84BCg1Ob3RpZmljYXRpb25zEiBBY2tub3dsZWRnZSBub3RpZmljYXRpb24gcmVjZWlw'
-    'dBqEAUFja25vd2xlZGdlcyByZWNlaXB0IG9mIG5vdGlmaWNhdGlvbnMgYnkgdGhlIGNsaWVudC'
-    '4gVXNlZCBmb3IgdHJhY2tpbmcgZGVsaXZlcnkgY29uZmlybWF0aW9uLCByZWFkIHJlY2VpcHRz'
-    'LCBhbmQgZW5nYWdlbWVudCBtZXRyaWNzLioUcmVjZWl2ZU5vdGlmaWNhdGlvbnOCtRgTChFub3'
-    'RpZmljYXRpb25fc2VuZDABEs0CCgZTZWFyY2gSGC5jb21tb24udjEuU2VhcmNoUmVxdWVzdBof'
-    'Lm5vdGlmaWNhdGlvbi52MS5TZWFyY2hSZXNwb25zZSKFApACAbpH5QEKDU5vdGlmaWNhdGlvbn'
-    'MSFFNlYXJjaCBub3RpZmljYXRpb25zGqgBU2VhcmNoZXMgZm9yIG5vdGlmaWNhdGlvbnMgbWF0'
-    'Y2hpbmcgc3BlY2lmaWVkIGNyaXRlcmlhIGluY2x1ZGluZyBkYXRlIHJhbmdlLCB0eXBlLCBzdG'
-    'F0dXMsIHJlY2lwaWVudCwgYW5kIGN1c3RvbSBwcm9wZXJ0aWVzLiBSZXR1cm5zIGEgc3RyZWFt'
-    'IG9mIG1hdGNoaW5nIG5vdGlmaWNhdGlvbnMuKhNzZWFyY2hOb3RpZmljYXRpb25zgrUYFQoTbm'
-    '90aWZpY2F0aW9uX3NlYXJjaDABErgCCgZTdGF0dXMSGC5jb21tb24udjEuU3RhdHVzUmVxdWVz'
-    'dBoZLmNvbW1vbi52MS5TdGF0dXNSZXNwb25zZSL4AZACAbpH0wEKDU5vdGlmaWNhdGlvbnMSF0'
-    'dldCBub3RpZmljYXRpb24gc3RhdHVzGpEBUmV0cmlldmVzIHRoZSBjdXJyZW50IHN0YXR1cyBv'
-    'ZiBhIG5vdGlmaWNhdGlvbiBpbmNsdWRpbmcgZGVsaXZlcnkgc3RhdGUsIHRpbWVzdGFtcHMsIG'
-    'FuZCBlcnJvciBpbmZvcm1hdGlvbiBpZiB0aGUgbm90aWZpY2F0aW9uIGZhaWxlZCB0byBkZWxp'
-    'dmVyLioVZ2V0Tm90aWZpY2F0aW9uU3RhdHVzgrUYGgoYbm90aWZpY2F0aW9uX3N0YXR1c192aW'
-    'V3Et4CCgxTdGF0dXNVcGRhdGUSHi5jb21tb24udjEuU3RhdHVzVXBkYXRlUmVxdWVzdBofLmNv'
-    'bW1vbi52MS5TdGF0dXNVcGRhdGVSZXNwb25zZSKMArpH6AEKDU5vdGlmaWNhdGlvbnMSGlVwZG'
-    'F0ZSBub3RpZmljYXRpb24gc3RhdHVzGqABVXBkYXRlcyB0aGUgc3RhdHVzIG9mIGEgbm90aWZp'
-    'Y2F0aW9uLiBUeXBpY2FsbHkgdXNlZCBieSBkZWxpdmVyeSB3b3JrZXJzIHRvIHVwZGF0ZSBub3'
-    'RpZmljYXRpb24gc3RhdGUgZHVyaW5nIHByb2Nlc3NpbmcgKHF1ZXVlZCwgaW4tcHJvY2Vzcywg'
-    'ZGVsaXZlcmVkLCBmYWlsZWQpLioYdXBkYXRlTm90aWZpY2F0aW9uU3RhdHVzgrUYHAoabm90aW'
-    'ZpY2F0aW9uX3N0YXR1c191cGRhdGUS2QIKDlRlbXBsYXRlU2VhcmNoEiYubm90aWZpY2F0aW9u'
-    'LnYxLlRlbXBsYXRlU2VhcmNoUmVxdWVzdBonLm5vdGlmaWNhdGlvbi52MS5UZW1wbGF0ZVNlYX'
-    'JjaFJlc3BvbnNlIvMBkAIBukfZAQoJVGVtcGxhdGVzEh1TZWFyY2ggbm90aWZpY2F0aW9uIHRl'
-    'bXBsYXRlcxqbAVNlYXJjaGVzIGZvciBub3RpZmljYXRpb24gdGVtcGxhdGVzIG1hdGNoaW5nIH'
-    'NwZWNpZmllZCBjcml0ZXJpYSBpbmNsdWRpbmcgdGVtcGxhdGUgbmFtZSwgbGFuZ3VhZ2UsIGFu'
-    'ZCBjb250ZW50LiBVc2VkIGZvciB0ZW1wbGF0ZSBtYW5hZ2VtZW50IGFuZCBzZWxlY3Rpb24uKg'
-    '9zZWFyY2hUZW1wbGF0ZXOCtRgPCg10ZW1wbGF0ZV92aWV3MAES5AIKDFRlbXBsYXRlU2F2ZRIk'
-    'Lm5vdGlmaWNhdGlvbi52MS5UZW1wbGF0ZVNhdmVSZXF1ZXN0GiUubm90aWZpY2F0aW9uLnYxLl'
-    'RlbXBsYXRlU2F2ZVJlc3BvbnNlIoYCukftAQoJVGVtcGxhdGVzEhlDcmVhdGUgb3IgdXBkYXRl'
-    'IHRlbXBsYXRlGrYBQ3JlYXRlcyBvciB1cGRhdGVzIGEgbm90aWZpY2F0aW9uIHRlbXBsYXRlLi'
-    'BUZW1wbGF0ZXMgZW5hYmxlIGNvbnNpc3RlbnQsIHJldXNhYmxlIG5vdGlmaWNhdGlvbiBmb3Jt'
-    'YXR0aW5nIHdpdGggc3VwcG9ydCBmb3IgbXVsdGlwbGUgbGFuZ3VhZ2VzIGFuZCBjaGFubmVscy'
-    'AoZW1haWwsIFNNUywgcHVzaCwgaW4tYXBwKS4qDHNhdmVUZW1wbGF0ZYK1GBEKD3RlbXBsYXRl'
-    'X21hbmFnZRraBoK1GNUGChRzZXJ2aWNlX25vdGlmaWNhdGlvbhIRbm90aWZpY2F0aW9uX3Nlbm'
-    'QSFG5vdGlmaWNhdGlvbl9yZWxlYXNlEhNub3RpZmljYXRpb25fc2VhcmNoEhhub3RpZmljYXRp'
-    'b25fc3RhdHVzX3ZpZXcSGm5vdGlmaWNhdGlvbl9zdGF0dXNfdXBkYXRlEg90ZW1wbGF0ZV9tYW'
-    '5hZ2USDXRlbXBsYXRlX3ZpZXcalgEIARIRbm90aWZpY2F0aW9uX3NlbmQSFG5vdGlmaWNhdGlv'
-    'bl9yZWxlYXNlEhNub3RpZmljYXRpb25fc2VhcmNoEhhub3RpZmljYXRpb25fc3RhdHVzX3ZpZX'
-    'cSGm5vdGlmaWNhdGlvbl9zdGF0dXNfdXBkYXRlEg90ZW1wbGF0ZV9tYW5hZ2USDXRlbXBsYXRl'
-    'X3ZpZXcalgEIAhIRbm90aWZpY2F0aW9uX3NlbmQSFG5vdGlmaWNhdGlvbl9yZWxlYXNlEhNub3'
-    'RpZmljYXRpb25fc2VhcmNoEhhub3RpZmljYXRpb25fc3RhdHVzX3ZpZXcSGm5vdGlmaWNhdGlv'
-    'bl9zdGF0dXNfdXBkYXRlEg90ZW1wbGF0ZV9tYW5hZ2USDXRlbXBsYXRlX3ZpZXcaaQgDEhFub3'
-    'RpZmljYXRpb25fc2VuZBIUbm90aWZpY2F0aW9uX3JlbGVhc2USE25vdGlmaWNhdGlvbl9zZWFy'
-    'Y2gSGG5vdGlmaWNhdGlvbl9zdGF0dXNfdmlldxINdGVtcGxhdGVfdmlldxpACAQSE25vdGlmaW'
-    'NhdGlvbl9zZWFyY2gSGG5vdGlmaWNhdGlvbl9zdGF0dXNfdmlldxINdGVtcGxhdGVfdmlldxox'
-    'CAUSE25vdGlmaWNhdGlvbl9zZWFyY2gSGG5vdGlmaWNhdGlvbl9zdGF0dXNfdmlldxqWAQgGEh'
-    'Fub3RpZmljYXRpb25fc2VuZBIUbm90aWZpY2F0aW9uX3JlbGVhc2USE25vdGlmaWNhdGlvbl9z'
-    'ZWFyY2gSGG5vdGlmaWNhdGlvbl9zdGF0dXNfdmlldxIabm90aWZpY2F0aW9uX3N0YXR1c191cG'
-    'RhdGUSD3RlbXBsYXRlX21hbmFnZRINdGVtcGxhdGVfdmlldw==');
+    'ZW5kTm90aWZpY2F0aW9uc4K1GBMKEW5vdGlmaWNhdGlvbl9zZW5kMAESxwMKCUJyb2FkY2FzdB'
+    'IhLm5vdGlmaWNhdGlvbi52MS5Ccm9hZGNhc3RSZXF1ZXN0GiIubm90aWZpY2F0aW9uLnYxLkJy'
+    'b2FkY2FzdFJlc3BvbnNlIvACukfVAgoNTm90aWZpY2F0aW9ucxInQnJvYWRjYXN0IGEgbm90aW'
+    'ZpY2F0aW9uIHRvIGFuIGF1ZGllbmNlGoMCRXhwYW5kcyBhIHNpbmdsZSB0ZW1wbGF0ZWQgbm90'
+    'aWZpY2F0aW9uIGludG8gb25lIGNoaWxkIG5vdGlmaWNhdGlvbiBwZXIgcmVjaXBpZW50LCBlaX'
+    'RoZXIgZm9yIGFuIGV4cGxpY2l0IGxpc3Qgb2YgcHJvZmlsZXMgb3IgZm9yIGV2ZXJ5IHByb2Zp'
+    'bGUgaW4gdGhlIHBhcnRpdGlvbi4gQ2hpbGRyZW4gbGluayBiYWNrIHRvIGEgcGFyZW50IGJyb2'
+    'FkY2FzdCByZWNvcmQgYW5kIGV4cGFuc2lvbiBwcm9ncmVzcyBpcyBzdHJlYW1lZCBhcyBpdCBo'
+    'YXBwZW5zLioVYnJvYWRjYXN0Tm90aWZpY2F0aW9ugrUYEwoRbm90aWZpY2F0aW9uX3NlbmQwAR'
+    'L3AgoPQnJvYWRjYXN0U3RhdHVzEhguY29tbW9uLnYxLlN0YXR1c1JlcXVlc3QaKC5ub3RpZmlj'
+    'YXRpb24udjEuQnJvYWRjYXN0U3RhdHVzUmVzcG9uc2UinwKQAgG6R/oBCg1Ob3RpZmljYXRpb2'
+    '5zEhZHZXQgYnJvYWRjYXN0IHByb2dyZXNzGrwBUmV0cmlldmVzIGFnZ3JlZ2F0ZSBkZWxpdmVy'
+    'eSBwcm9ncmVzcyBmb3IgYSBicm9hZGNhc3QsIGNvdW50aW5nIGl0cyBjaGlsZCBub3RpZmljYX'
+    'Rpb25zIGJ5IHN0YXR1cyAocXVldWVkLCBpbiBwcm9jZXNzLCBzdWNjZXNzZnVsLCBmYWlsZWQp'
+    'IGFuZCBncm91cGluZyBmYWlsdXJlcyBieSB0aGUgc3RlcCB0aGV5IGZhaWxlZCBhdC4qEmdldE'
+    'Jyb2FkY2FzdFN0YXR1c4K1GBoKGG5vdGlmaWNhdGlvbl9zdGF0dXNfdmlldxL5AgoHUmVsZWFz'
+    'ZRIfLm5vdGlmaWNhdGlvbi52MS5SZWxlYXNlUmVxdWVzdBogLm5vdGlmaWNhdGlvbi52MS5SZW'
+    'xlYXNlUmVzcG9uc2UiqAK6R4oCCg1Ob3RpZmljYXRpb25zEhxSZWxlYXNlIHF1ZXVlZCBub3Rp'
+    'ZmljYXRpb25zGsQBUmVsZWFzZXMgcXVldWVkIG5vdGlmaWNhdGlvbnMgZm9yIGltbWVkaWF0ZS'
+    'BkZWxpdmVyeS4gVXNlZCBmb3IgYmF0Y2ggcHJvY2Vzc2luZyBzY2VuYXJpb3Mgd2hlcmUgbm90'
+    'aWZpY2F0aW9ucyBhcmUgcXVldWVkIGZpcnN0IGFuZCByZWxlYXNlZCB0b2dldGhlci4gUmV0dX'
+    'JucyBkZWxpdmVyeSBzdGF0dXMgZm9yIGVhY2ggbm90aWZpY2F0aW9uLioUcmVsZWFzZU5vdGlm'
+    'aWNhdGlvbnOCtRgWChRub3RpZmljYXRpb25fcmVsZWFzZTABEpwDCgZDYW5jZWwSHi5ub3RpZm'
+    'ljYXRpb24udjEuQ2FuY2VsUmVxdWVzdBofLm5vdGlmaWNhdGlvbi52MS5DYW5jZWxSZXNwb25z'
+    'ZSLOArpHsAIKDU5vdGlmaWNhdGlvbnMSG0NhbmNlbCBxdWV1ZWQgbm90aWZpY2F0aW9ucxrsAV'
+    'dpdGhkcmF3cyB1bnJlbGVhc2VkIG9yIHNjaGVkdWxlZCBub3RpZmljYXRpb25zLCBhbmQgcmVs'
+    'ZWFzZWQgbm90aWZpY2F0aW9ucyBub3QgeWV0IHB1Ymxpc2hlZCB0byBhIHJvdXRlLCBzbyB0aG'
+    'V5IGFyZSBuZXZlciBkaXNwYXRjaGVkLiBOb3RpZmljYXRpb25zIGFscmVhZHkgcHVibGlzaGVk'
+    'IGFyZSByZXBvcnRlZCBhcyB0b28gbGF0ZS4gUmV0dXJucyB0aGUgb3V0Y29tZSBmb3IgZWFjaC'
+    'Bub3RpZmljYXRpb24uKhNjYW5jZWxOb3RpZmljYXRpb25zgrUYFgoUbm90aWZpY2F0aW9uX3Jl'
+    'bGVhc2UwARK6AgoHUmVjZWl2ZRIfLm5vdGlmaWNhdGlvbi52MS5SZWNlaXZlUmVxdWVzdBogLm'
+    '5vdGlmaWNhdGlvbi52MS5SZWNlaXZlUmVzcG9uc2Ui6QG6R84BCg1Ob3RpZmljYXRpb25zEiBB'
+    'Y2tub3dsZWRnZSBub3RpZmljYXRpb24gcmVjZWlwdBqEAUFja25vd2xlZGdlcyByZWNlaXB0IG'
+    '9mIG5vdGlmaWNhdGlvbnMgYnkgdGhlIGNsaWVudC4gVXNlZCBmb3IgdHJhY2tpbmcgZGVsaXZl'
+    'cnkgY29uZmlybWF0aW9uLCByZWFkIHJlY2VpcHRzLCBhbmQgZW5nYWdlbWVudCBtZXRyaWNzLi'
+    'oUcmVjZWl2ZU5vdGlmaWNhdGlvbnOCtRgTChFub3RpZmljYXRpb25fc2VuZDABEs0CCgZTZWFy'
+    'Y2gSGC5jb21tb24udjEuU2VhcmNoUmVxdWVzdBofLm5vdGlmaWNhdGlvbi52MS5TZWFyY2hSZX'
+    'Nwb25zZSKFApACAbpH5QEKDU5vdGlmaWNhdGlvbnMSFFNlYXJjaCBub3RpZmljYXRpb25zGqgB'
+    'U2VhcmNoZXMgZm9yIG5vdGlmaWNhdGlvbnMgbWF0Y2hpbmcgc3BlY2lmaWVkIGNyaXRlcmlhIG'
+    'luY2x1ZGluZyBkYXRlIHJhbmdlLCB0eXBlLCBzdGF0dXMsIHJlY2lwaWVudCwgYW5kIGN1c3Rv'
+    'bSBwcm9wZXJ0aWVzLiBSZXR1cm5zIGEgc3RyZWFtIG9mIG1hdGNoaW5nIG5vdGlmaWNhdGlvbn'
+    'MuKhNzZWFyY2hOb3RpZmljYXRpb25zgrUYFQoTbm90aWZpY2F0aW9uX3NlYXJjaDABErgCCgZT'
+    'dGF0dXMSGC5jb21tb24udjEuU3RhdHVzUmVxdWVzdBoZLmNvbW1vbi52MS5TdGF0dXNSZXNwb2'
+    '5zZSL4AZACAbpH0wEKDU5vdGlmaWNhdGlvbnMSF0dldCBub3RpZmljYXRpb24gc3RhdHVzGpEB'
+    'UmV0cmlldmVzIHRoZSBjdXJyZW50IHN0YXR1cyBvZiBhIG5vdGlmaWNhdGlvbiBpbmNsdWRpbm'
+    'cgZGVsaXZlcnkgc3RhdGUsIHRpbWVzdGFtcHMsIGFuZCBlcnJvciBpbmZvcm1hdGlvbiBpZiB0'
+    'aGUgbm90aWZpY2F0aW9uIGZhaWxlZCB0byBkZWxpdmVyLioVZ2V0Tm90aWZpY2F0aW9uU3RhdH'
+    'VzgrUYGgoYbm90aWZpY2F0aW9uX3N0YXR1c192aWV3Et4CCgxTdGF0dXNVcGRhdGUSHi5jb21t'
+    'b24udjEuU3RhdHVzVXBkYXRlUmVxdWVzdBofLmNvbW1vbi52MS5TdGF0dXNVcGRhdGVSZXNwb2'
+    '5zZSKMArpH6AEKDU5vdGlmaWNhdGlvbnMSGlVwZGF0ZSBub3RpZmljYXRpb24gc3RhdHVzGqAB'
+    'VXBkYXRlcyB0aGUgc3RhdHVzIG9mIGEgbm90aWZpY2F0aW9uLiBUeXBpY2FsbHkgdXNlZCBieS'
+    'BkZWxpdmVyeSB3b3JrZXJzIHRvIHVwZGF0ZSBub3RpZmljYXRpb24gc3RhdGUgZHVyaW5nIHBy'
+    'b2Nlc3NpbmcgKHF1ZXVlZCwgaW4tcHJvY2VzcywgZGVsaXZlcmVkLCBmYWlsZWQpLioYdXBkYX'
+    'RlTm90aWZpY2F0aW9uU3RhdHVzgrUYHAoabm90aWZpY2F0aW9uX3N0YXR1c191cGRhdGUS2QIK'
+    'DlRlbXBsYXRlU2VhcmNoEiYubm90aWZpY2F0aW9uLnYxLlRlbXBsYXRlU2VhcmNoUmVxdWVzdB'
+    'onLm5vdGlmaWNhdGlvbi52MS5UZW1wbGF0ZVNlYXJjaFJlc3BvbnNlIvMBkAIBukfZAQoJVGVt'
+    'cGxhdGVzEh1TZWFyY2ggbm90aWZpY2F0aW9uIHRlbXBsYXRlcxqbAVNlYXJjaGVzIGZvciBub3'
+    'RpZmljYXRpb24gdGVtcGxhdGVzIG1hdGNoaW5nIHNwZWNpZmllZCBjcml0ZXJpYSBpbmNsdWRp'
+    'bmcgdGVtcGxhdGUgbmFtZSwgbGFuZ3VhZ2UsIGFuZCBjb250ZW50LiBVc2VkIGZvciB0ZW1wbG'
+    'F0ZSBtYW5hZ2VtZW50IGFuZCBzZWxlY3Rpb24uKg9zZWFyY2hUZW1wbGF0ZXOCtRgPCg10ZW1w'
+    'bGF0ZV92aWV3MAESsQMKDFRlbXBsYXRlU2F2ZRIkLm5vdGlmaWNhdGlvbi52MS5UZW1wbGF0ZV'
+    'NhdmVSZXF1ZXN0GiUubm90aWZpY2F0aW9uLnYxLlRlbXBsYXRlU2F2ZVJlc3BvbnNlItMCuke6'
+    'AgoJVGVtcGxhdGVzEhlDcmVhdGUgb3IgdXBkYXRlIHRlbXBsYXRlGoMCU2F2ZXMgYSBuZXcgZH'
+    'JhZnQgdmVyc2lvbiBvZiBhIG5vdGlmaWNhdGlvbiB0ZW1wbGF0ZSwgbGVhdmluZyB0aGUgcHVi'
+    'bGlzaGVkIHZlcnNpb24gaW4gdXNlIHVudGlsIHRoZSBkcmFmdCBpcyBwdWJsaXNoZWQuIFRlbX'
+    'BsYXRlcyBlbmFibGUgY29uc2lzdGVudCwgcmV1c2FibGUgbm90aWZpY2F0aW9uIGZvcm1hdHRp'
+    'bmcgd2l0aCBzdXBwb3J0IGZvciBtdWx0aXBsZSBsYW5ndWFnZXMgYW5kIGNoYW5uZWxzIChlbW'
+    'FpbCwgU01TLCBwdXNoLCBpbi1hcHApLioMc2F2ZVRlbXBsYXRlgrUYEQoPdGVtcGxhdGVfbWFu'
+    'YWdlEt0CCg9UZW1wbGF0ZVB1Ymxpc2gSJy5ub3RpZmljYXRpb24udjEuVGVtcGxhdGVQdWJsaX'
+    'NoUmVxdWVzdBooLm5vdGlmaWNhdGlvbi52MS5UZW1wbGF0ZVB1Ymxpc2hSZXNwb25zZSL2AbpH'
+    '3QEKCVRlbXBsYXRlcxIaUHVibGlzaCBhIHRlbXBsYXRlIHZlcnNpb24aogFNYWtlcyBhIHRlbX'
+    'BsYXRlIHZlcnNpb24gdGhlIG9uZSByZW5kZXJlZCBmb3IgaXRzIG5hbWUgYW5kIHJldGlyZXMg'
+    'dGhlIHZlcnNpb24gcHVibGlzaGVkIGJlZm9yZSBpdC4gTm90aWZpY2F0aW9ucyBwaW5uZWQgdG'
+    '8gYSB2ZXJzaW9uIGtlZXAgcmVuZGVyaW5nIHRoYXQgdmVyc2lvbi4qD3B1Ymxpc2hUZW1wbGF0'
+    'ZYK1GBEKD3RlbXBsYXRlX21hbmFnZRKuAgoQVGVtcGxhdGVSb2xsYmFjaxIoLm5vdGlmaWNhdG'
+    'lvbi52MS5UZW1wbGF0ZVJvbGxiYWNrUmVxdWVzdBopLm5vdGlmaWNhdGlvbi52MS5UZW1wbGF0'
+    'ZVJvbGxiYWNrUmVzcG9uc2UixAG6R6sBCglUZW1wbGF0ZXMSFFJvbGwgYmFjayBhIHRlbXBsYX'
+    'RlGnZQdWJsaXNoZXMgYWdhaW4gYSBwcmV2aW91c2x5IHB1Ymxpc2hlZCB2ZXJzaW9uIG9mIGEg'
+    'dGVtcGxhdGUsIGJ5IGRlZmF1bHQgdGhlIG9uZSBwdWJsaXNoZWQgYmVmb3JlIHRoZSBjdXJyZW'
+    '50IHZlcnNpb24uKhByb2xsYmFja1RlbXBsYXRlgrUYEQoPdGVtcGxhdGVfbWFuYWdlEosDCg5U'
+    'ZW1wbGF0ZVVwZGF0ZRImLm5vdGlmaWNhdGlvbi52MS5UZW1wbGF0ZVVwZGF0ZVJlcXVlc3QaJy'
+    '5ub3RpZmljYXRpb24udjEuVGVtcGxhdGVVcGRhdGVSZXNwb25zZSKnArpHjgIKCVRlbXBsYXRl'
+    'cxIXVXBkYXRlIGEgZHJhZnQgdGVtcGxhdGUa1wFBZGRzLCByZXBsYWNlcyBvciByZW1vdmVzIH'
+    'RoZSBjb250ZW50IG9mIGEgZHJhZnQgdGVtcGxhdGUgdmVyc2lvbiBvbmUgbGFuZ3VhZ2UgYW5k'
+    'IHR5cGUgYXQgYSB0aW1lLCBhbmQgb3B0aW9uYWxseSByZXBsYWNlcyBpdHMgbWV0YWRhdGEuIF'
+    'B1Ymxpc2hlZCBhbmQgcmV0aXJlZCB2ZXJzaW9ucyBjYW4gbm90IGJlIGNoYW5nZWQsIHNhdmUg'
+    'YSBuZXcgdmVyc2lvbiBpbnN0ZWFkLioOdXBkYXRlVGVtcGxhdGWCtRgRCg90ZW1wbGF0ZV9tYW'
+    '5hZ2US0QIKDlRlbXBsYXRlRGVsZXRlEiYubm90aWZpY2F0aW9uLnYxLlRlbXBsYXRlRGVsZXRl'
+    'UmVxdWVzdBonLm5vdGlmaWNhdGlvbi52MS5UZW1wbGF0ZURlbGV0ZVJlc3BvbnNlIu0BukfUAQ'
+    'oJVGVtcGxhdGVzEhlEZWxldGUgYSB0ZW1wbGF0ZSB2ZXJzaW9uGpsBU29mdCBkZWxldGVzIGEg'
+    'dGVtcGxhdGUgdmVyc2lvbiB0b2dldGhlciB3aXRoIGl0cyBjb250ZW50LiBWZXJzaW9ucyB0aG'
+    'F0IHVucmVsZWFzZWQgbm90aWZpY2F0aW9ucyBhcmUgc3RpbGwgd2FpdGluZyB0byBiZSByZW5k'
+    'ZXJlZCB3aXRoIGNhbiBub3QgYmUgZGVsZXRlZC4qDmRlbGV0ZVRlbXBsYXRlgrUYEQoPdGVtcG'
+    'xhdGVfbWFuYWdlEuADCg9UZW1wbGF0ZVByZXZpZXcSJy5ub3RpZmljYXRpb24udjEuVGVtcGxh'
+    'dGVQcmV2aWV3UmVxdWVzdBooLm5vdGlmaWNhdGlvbi52MS5UZW1wbGF0ZVByZXZpZXdSZXNwb2'
+    '5zZSL5ArpH4gIKCVRlbXBsYXRlcxISUHJldmlldyBhIHRlbXBsYXRlGq8CUmVuZGVycyBldmVy'
+    'eSBjb250ZW50IHR5cGUgb2YgYSB0ZW1wbGF0ZSBpbiBvbmUgbGFuZ3VhZ2UgYWdhaW5zdCBhIH'
+    'NhbXBsZSBwYXlsb2FkLCB0aGUgd2F5IGEgbm90aWZpY2F0aW9uIHVzaW5nIGl0IHdvdWxkIGJl'
+    'IHJlbmRlcmVkLCB0b2dldGhlciB3aXRoIHRoZSBzdXBwb3J0IGNvbnRhY3RzIG9mIHRoZSBwYX'
+    'J0aXRpb24uIFZhcmlhYmxlcyB0aGUgcGF5bG9hZCBkb2VzIG5vdCBzdXBwbHkgYXJlIHJlcG9y'
+    'dGVkIHBlciBjb250ZW50IHR5cGUuIE5vdGhpbmcgaXMgc2VudCBhbmQgbm8gbm90aWZpY2F0aW'
+    '9uIGlzIGNyZWF0ZWQuKg9wcmV2aWV3VGVtcGxhdGWCtRgPCg10ZW1wbGF0ZV92aWV3EuwCChFT'
+    'dXBwcmVzc2lvblNlYXJjaBIpLm5vdGlmaWNhdGlvbi52MS5TdXBwcmVzc2lvblNlYXJjaFJlcX'
+    'Vlc3QaKi5ub3RpZmljYXRpb24udjEuU3VwcHJlc3Npb25TZWFyY2hSZXNwb25zZSL9AZACAbpH'
+    '4AEKDFN1cHByZXNzaW9ucxITU2VhcmNoIHN1cHByZXNzaW9ucxqmAUxpc3RzIGNvbnRhY3RzIH'
+    'RoYXQgb3B0ZWQgb3V0IG9yIHdlcmUgc3VwcHJlc3NlZCwgb3B0aW9uYWxseSBmaWx0ZXJlZCBi'
+    'eSBjb250YWN0IGFuZCBjaGFubmVsLiBVc2VkIGJ5IGNvbXBsaWFuY2UgdGVhbXMgdG8gcmV2aW'
+    'V3IHdobyBubyBsb25nZXIgcmVjZWl2ZXMgbm90aWZpY2F0aW9ucy4qEnNlYXJjaFN1cHByZXNz'
+    'aW9uc4K1GBIKEHN1cHByZXNzaW9uX3ZpZXcwARLAAwoOU3VwcHJlc3Npb25BZGQSJi5ub3RpZm'
+    'ljYXRpb24udjEuU3VwcHJlc3Npb25BZGRSZXF1ZXN0Gicubm90aWZpY2F0aW9uLnYxLlN1cHBy'
+    'ZXNzaW9uQWRkUmVzcG9uc2Ui3AK6R8ACCgxTdXBwcmVzc2lvbnMSEUFkZCBhIHN1cHByZXNzaW'
+    '9uGowCUmVjb3JkcyBhIGNvbnRhY3QgdGhhdCBtdXN0IG5vIGxvbmdlciByZWNlaXZlIG5vdGlm'
+    'aWNhdGlvbnMgb24gYSBjaGFubmVsIG9yIGZyb20gYSBzZW5kZXIgSUQsIHdoaWNoZXZlciByb3'
+    'V0ZSBjYXJyaWVzIHRoZW0uIE91dGJvdW5kIG5vdGlmaWNhdGlvbnMgdG8gYSBzdXBwcmVzc2Vk'
+    'IGNvbnRhY3QgZmFpbCBhdCByb3V0aW5nIHdpdGggdGhlIHN1cHByZXNzZWQgc3RlcC4gSW50ZW'
+    'dyYXRpb25zIGZlZWQgY2FycmllciBvcHQgb3V0cyB0aHJvdWdoIHRoaXMgbWV0aG9kLioOYWRk'
+    'U3VwcHJlc3Npb26CtRgUChJzdXBwcmVzc2lvbl9tYW5hZ2USwAIKEVN1cHByZXNzaW9uUmVtb3'
+    'ZlEikubm90aWZpY2F0aW9uLnYxLlN1cHByZXNzaW9uUmVtb3ZlUmVxdWVzdBoqLm5vdGlmaWNh'
+    'dGlvbi52MS5TdXBwcmVzc2lvblJlbW92ZVJlc3BvbnNlItMBuke3AQoMU3VwcHJlc3Npb25zEh'
+    'RSZW1vdmUgYSBzdXBwcmVzc2lvbhp+TGlmdHMgYSBzdXBwcmVzc2lvbiBieSBpdHMgSUQsIG9y'
+    'IGJ5IGl0cyBleGFjdCBjb250YWN0LCBjaGFubmVsIGFuZCBzZW5kZXIgSUQsIHNvIHRoZSBjb2'
+    '50YWN0IGNhbiByZWNlaXZlIG5vdGlmaWNhdGlvbnMgYWdhaW4uKhFyZW1vdmVTdXBwcmVzc2lv'
+    'boK1GBQKEnN1cHByZXNzaW9uX21hbmFnZRqWCIK1GJEIChRzZXJ2aWNlX25vdGlmaWNhdGlvbh'
+    'IRbm90aWZpY2F0aW9uX3NlbmQSFG5vdGlmaWNhdGlvbl9yZWxlYXNlEhNub3RpZmljYXRpb25f'
+    'c2VhcmNoEhhub3RpZmljYXRpb25fc3RhdHVzX3ZpZXcSGm5vdGlmaWNhdGlvbl9zdGF0dXNfdX'
+    'BkYXRlEg90ZW1wbGF0ZV9tYW5hZ2USDXRlbXBsYXRlX3ZpZXcSEnN1cHByZXNzaW9uX21hbmFn'
+    'ZRIQc3VwcHJlc3Npb25fdmlldxq8AQgBEhFub3RpZmljYXRpb25fc2VuZBIUbm90aWZpY2F0aW'
+    '9uX3JlbGVhc2USE25vdGlmaWNhdGlvbl9zZWFyY2gSGG5vdGlmaWNhdGlvbl9zdGF0dXNfdmll'
+    'dxIabm90aWZpY2F0aW9uX3N0YXR1c191cGRhdGUSD3RlbXBsYXRlX21hbmFnZRINdGVtcGxhdG'
+    'VfdmlldxISc3VwcHJlc3Npb25fbWFuYWdlEhBzdXBwcmVzc2lvbl92aWV3GrwBCAISEW5vdGlm'
+    'aWNhdGlvbl9zZW5kEhRub3RpZmljYXRpb25fcmVsZWFzZRITbm90aWZpY2F0aW9uX3NlYXJjaB'
+    'IYbm90aWZpY2F0aW9uX3N0YXR1c192aWV3Ehpub3RpZmljYXRpb25fc3RhdHVzX3VwZGF0ZRIP'
+    'dGVtcGxhdGVfbWFuYWdlEg10ZW1wbGF0ZV92aWV3EhJzdXBwcmVzc2lvbl9tYW5hZ2USEHN1cH'
+    'ByZXNzaW9uX3ZpZXcaewgDEhFub3RpZmljYXRpb25fc2VuZBIUbm90aWZpY2F0aW9uX3JlbGVh'
+    'c2USE25vdGlmaWNhdGlvbl9zZWFyY2gSGG5vdGlmaWNhdGlvbl9zdGF0dXNfdmlldxINdGVtcG'
+    'xhdGVfdmlldxIQc3VwcHJlc3Npb25fdmlldxpSCAQSE25vdGlmaWNhdGlvbl9zZWFyY2gSGG5v'
+    'dGlmaWNhdGlvbl9zdGF0dXNfdmlldxINdGVtcGxhdGVfdmlldxIQc3VwcHJlc3Npb25fdmlldx'
+    'oxCAUSE25vdGlmaWNhdGlvbl9zZWFyY2gSGG5vdGlmaWNhdGlvbl9zdGF0dXNfdmlldxq8AQgG'
+    'EhFub3RpZmljYXRpb25fc2VuZBIUbm90aWZpY2F0aW9uX3JlbGVhc2USE25vdGlmaWNhdGlvbl'
+    '9zZWFyY2gSGG5vdGlmaWNhdGlvbl9zdGF0dXNfdmlldxIabm90aWZpY2F0aW9uX3N0YXR1c191'
+    'cGRhdGUSD3RlbXBsYXRlX21hbmFnZRINdGVtcGxhdGVfdmlldxISc3VwcHJlc3Npb25fbWFuYW'
+    'dlEhBzdXBwcmVzc2lvbl92aWV3');
 
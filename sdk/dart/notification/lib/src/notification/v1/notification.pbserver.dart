@@ -23,24 +23,46 @@ export 'notification.pb.dart';
 
 abstract class NotificationServiceBase extends $pb.GeneratedService {
   $async.Future<$8.SendResponse> send($pb.ServerContext ctx, $8.SendRequest request);
+  $async.Future<$8.BroadcastResponse> broadcast($pb.ServerContext ctx, $8.BroadcastRequest request);
+  $async.Future<$8.BroadcastStatusResponse> broadcastStatus($pb.ServerContext ctx, $7.StatusRequest request);
   $async.Future<$8.ReleaseResponse> release($pb.ServerContext ctx, $8.ReleaseRequest request);
+  $async.Future<$8.CancelResponse> cancel($pb.ServerContext ctx, $8.CancelRequest request);
   $async.Future<$8.ReceiveResponse> receive($pb.ServerContext ctx, $8.ReceiveRequest request);
   $async.Future<$8.SearchResponse> search($pb.ServerContext ctx, $7.SearchRequest request);
   $async.Future<$7.StatusResponse> status($pb.ServerContext ctx, $7.StatusRequest request);
   $async.Future<$7.StatusUpdateResponse> statusUpdate($pb.ServerContext ctx, $7.StatusUpdateRequest request);
   $async.Future<$8.TemplateSearchResponse> templateSearch($pb.ServerContext ctx, $8.TemplateSearchRequest request);
   $async.Future<$8.TemplateSaveResponse> templateSave($pb.ServerContext ctx, $8.TemplateSaveRequest request);
+  $async.Future<$8.TemplatePublishResponse> templatePublish($pb.ServerContext ctx, $8.TemplatePublishRequest request);
+  $async.Future<$8.TemplateRollbackResponse> templateRollback($pb.ServerContext ctx, $8.TemplateRollbackRequest request);
+  $async.Future<$8.TemplateUpdateResponse> templateUpdate($pb.ServerContext ctx, $8.TemplateUpdateRequest request);
+  $async.Future<$8.TemplateDeleteResponse> templateDelete($pb.ServerContext ctx, $8.TemplateDeleteRequest request);
+  $async.Future<$8.TemplatePreviewResponse> templatePreview($pb.ServerContext ctx, $8.TemplatePreviewRequest request);
+  $async.Future<$8.SuppressionSearchResponse> suppressionSearch($pb.ServerContext ctx, $8.SuppressionSearchRequest request);
+  $async.Future<$8.SuppressionAddResponse> suppressionAdd($pb.ServerContext ctx, $8.SuppressionAddRequest request);
+  $async.Future<$8.SuppressionRemoveResponse> suppressionRemove($pb.ServerContext ctx, $8.SuppressionRemoveRequest request);
 
   $pb.GeneratedMessage createRequest($core.String methodName) {
     switch (methodName) {
       case 'Send': return $8.SendRequest();
+      case 'Broadcast': return $8.BroadcastRequest();
+      case 'BroadcastStatus': return $7.StatusRequest();
       case 'Release': return $8.ReleaseRequest();
+      case 'Cancel': return $8.CancelRequest();
       case 'Receive': return $8.ReceiveRequest();
       case 'Search': return $7.SearchRequest();
       case 'Status': return $7.StatusRequest();
       case 'StatusUpdate': return $7.StatusUpdateRequest();
       case 'TemplateSearch': return $8.TemplateSearchRequest();
       case 'TemplateSave': return $8.TemplateSaveRequest();
+      case 'TemplatePublish': return $8.TemplatePublishRequest();
+      case 'TemplateRollback': return $8.TemplateRollbackRequest();
+      case 'TemplateUpdate': return $8.TemplateUpdateRequest();
+      case 'TemplateDelete': return $8.TemplateDeleteRequest();
+      case 'TemplatePreview': return $8.TemplatePreviewRequest();
+      case 'SuppressionSearch': return $8.SuppressionSearchRequest();
+      case 'SuppressionAdd': return $8.SuppressionAddRequest();
+      case 'SuppressionRemove': return $8.SuppressionRemoveRequest();
       default: throw $core.ArgumentError('Unknown method: $methodName');
     }
   }
@@ -48,13 +70,24 @@ abstract class NotificationServiceBase extends $pb.GeneratedService {
   $async.Future<$pb.GeneratedMessage> handleCall($pb.ServerContext ctx, $core.String methodName, $pb.GeneratedMessage request) {
     switch (methodName) {
       case 'Send': return this.send(ctx, request as $8.SendRequest);
+      case 'Broadcast': return this.broadcast(ctx, request as $8.BroadcastRequest);
+      case 'BroadcastStatus': return this.broadcastStatus(ctx, request as $7.StatusRequest);
       case 'Release': return this.release(ctx, request as $8.ReleaseRequest);
+      case 'Cancel': return this.cancel(ctx, request as $8.CancelRequest);
       case 'Receive': return this.receive(ctx, request as $8.ReceiveRequest);
       case 'Search': return this.search(ctx, request as $7.SearchRequest);
       case 'Status': return this.status(ctx, request as $7.StatusRequest);
       case 'StatusUpdate': return this.statusUpdate(ctx, request as $7.StatusUpdateRequest);
       case 'TemplateSearch': return this.templateSearch(ctx, request as $8.TemplateSearchRequest);
       case 'TemplateSave': return this.templateSave(ctx, request as $8.TemplateSaveRequest);
+      case 'TemplatePublish': return this.templatePublish(ctx, request as $8.TemplatePublishRequest);
+      case 'TemplateRollback': return this.templateRollback(ctx, request as $8.TemplateRollbackRequest);
+      case 'TemplateUpdate': return this.templateUpdate(ctx, request as $8.TemplateUpdateRequest);
+      case 'TemplateDelete': return this.templateDelete(ctx, request as $8.TemplateDeleteRequest);
+      case 'TemplatePreview': return this.templatePreview(ctx, request as $8.TemplatePreviewRequest);
+      case 'SuppressionSearch': return this.suppressionSearch(ctx, request as $8.SuppressionSearchRequest);
+      case 'SuppressionAdd': return this.suppressionAdd(ctx, request as $8.SuppressionAddRequest);
+      case 'SuppressionRemove': return this.suppressionRemove(ctx, request as $8.SuppressionRemoveRequest);
       default: throw $core.ArgumentError('Unknown method: $methodName');
     }
   }